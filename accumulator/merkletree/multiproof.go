@@ -0,0 +1,207 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkletree
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"math/bits"
+	"sort"
+)
+
+var (
+	errNotRetained  = errors.New("proofs for arbitrary indices require a retained tree, see NewRetained")
+	errOutOfBounds  = errors.New("index out of bounds")
+	errEmptyIndices = errors.New("no index to prove")
+)
+
+// MultiProof is a proof that the leaves at several indices are elements of a
+// Merkle tree. The siblings shared by the paths of the leaves appear once,
+// and the nodes which can be computed from the leaves don't appear at all.
+type MultiProof struct {
+	// Indices of the proven leaves, in increasing order.
+	Indices []uint64
+	// Leaves are the data of the leaves at Indices.
+	Leaves [][]byte
+	// Siblings are the roots of the subtrees with no proven leaf adjacent to
+	// the paths of the leaves, in depth-first, left to right, order.
+	Siblings [][]byte
+	// NumLeaves is the number of leaves of the Merkle tree.
+	NumLeaves uint64
+}
+
+// ProveIndex creates a proof that the leaf at index i is an element of the
+// Merkle tree, in the format of Prove (the proof set starts with the data of
+// the leaf), to be checked with VerifyProof. The tree must be retained.
+func (t *Tree) ProveIndex(i uint64) (merkleRoot []byte, proofSet [][]byte, numLeaves uint64, err error) {
+	if !t.retained {
+		return nil, nil, 0, errNotRetained
+	}
+	numLeaves = t.currentIndex
+	if i >= numLeaves {
+		return nil, nil, 0, errOutOfBounds
+	}
+
+	// walk down from the root, the siblings are then reversed
+	proofSet = append(proofSet, t.leaves[i])
+	var siblings [][]byte
+	start, end := uint64(0), numLeaves
+	for end-start > 1 {
+		mid := start + split(end-start)
+		if i < mid {
+			siblings = append(siblings, t.rangeSum(mid, end))
+			end = mid
+		} else {
+			siblings = append(siblings, t.rangeSum(start, mid))
+			start = mid
+		}
+	}
+	for j := len(siblings) - 1; j >= 0; j-- {
+		proofSet = append(proofSet, siblings[j])
+	}
+	return t.Root(), proofSet, numLeaves, nil
+}
+
+// MultiProve creates a proof that the leaves at the given indices are
+// elements of the Merkle tree, to be checked with VerifyMultiProof. The
+// indices may be in any order, duplicates are ignored. The tree must be
+// retained.
+func (t *Tree) MultiProve(indices []uint64) (merkleRoot []byte, proof MultiProof, err error) {
+	if !t.retained {
+		return nil, MultiProof{}, errNotRetained
+	}
+	proof.NumLeaves = t.currentIndex
+	proof.Indices = sortedUnique(indices)
+	if len(proof.Indices) == 0 {
+		return nil, MultiProof{}, errEmptyIndices
+	}
+	if proof.Indices[len(proof.Indices)-1] >= proof.NumLeaves {
+		return nil, MultiProof{}, errOutOfBounds
+	}
+	proof.Leaves = make([][]byte, len(proof.Indices))
+	for j, i := range proof.Indices {
+		proof.Leaves[j] = t.leaves[i]
+	}
+	t.multiProve(&proof, 0, proof.NumLeaves, proof.Indices)
+	return t.Root(), proof, nil
+}
+
+// multiProve appends to proof the siblings needed to compute the root of the
+// subtree holding the leaves start to end-1, given the leaves at indices.
+func (t *Tree) multiProve(proof *MultiProof, start, end uint64, indices []uint64) {
+	if len(indices) == 0 {
+		proof.Siblings = append(proof.Siblings, t.rangeSum(start, end))
+		return
+	}
+	if end-start == 1 {
+		return
+	}
+	mid := start + split(end-start)
+	k := sort.Search(len(indices), func(j int) bool { return indices[j] >= mid })
+	t.multiProve(proof, start, mid, indices[:k])
+	t.multiProve(proof, mid, end, indices[k:])
+}
+
+// rangeSum returns the root of the subtree holding the leaves start to end-1,
+// as retained if it is complete, and recomputed otherwise.
+func (t *Tree) rangeSum(start, end uint64) []byte {
+	size := end - start
+	if size&(size-1) == 0 {
+		height := bits.TrailingZeros64(size)
+		return t.nodes[height][start>>height]
+	}
+	mid := start + split(size)
+	return nodeSum(t.hash, t.rangeSum(start, mid), t.rangeSum(mid, end))
+}
+
+// VerifyMultiProof takes a Merkle root and a multiproof, and returns true if
+// the leaves of the proof are elements of the Merkle tree at the given
+// indices.
+func VerifyMultiProof(h hash.Hash, merkleRoot []byte, proof MultiProof) bool {
+	if merkleRoot == nil || len(proof.Indices) == 0 || len(proof.Indices) != len(proof.Leaves) {
+		return false
+	}
+	for j := 1; j < len(proof.Indices); j++ {
+		if proof.Indices[j] <= proof.Indices[j-1] {
+			return false
+		}
+	}
+	if proof.Indices[len(proof.Indices)-1] >= proof.NumLeaves {
+		return false
+	}
+
+	v := multiVerifier{h: h, proof: &proof}
+	sum, ok := v.sum(0, proof.NumLeaves, proof.Indices)
+	if !ok || v.nextLeaf != len(proof.Leaves) || v.nextSibling != len(proof.Siblings) {
+		return false
+	}
+	return bytes.Equal(sum, merkleRoot)
+}
+
+// multiVerifier consumes the leaves and siblings of a multiproof, in the order
+// they were appended by multiProve.
+type multiVerifier struct {
+	h                     hash.Hash
+	proof                 *MultiProof
+	nextLeaf, nextSibling int
+}
+
+// sum returns the root of the subtree holding the leaves start to end-1,
+// given the leaves at indices.
+func (v *multiVerifier) sum(start, end uint64, indices []uint64) ([]byte, bool) {
+	if len(indices) == 0 {
+		if v.nextSibling >= len(v.proof.Siblings) {
+			return nil, false
+		}
+		v.nextSibling++
+		return v.proof.Siblings[v.nextSibling-1], true
+	}
+	if end-start == 1 {
+		v.nextLeaf++
+		return leafSum(v.h, v.proof.Leaves[v.nextLeaf-1]), true
+	}
+	mid := start + split(end-start)
+	k := sort.Search(len(indices), func(j int) bool { return indices[j] >= mid })
+	left, ok := v.sum(start, mid, indices[:k])
+	if !ok {
+		return nil, false
+	}
+	right, ok := v.sum(mid, end, indices[k:])
+	if !ok {
+		return nil, false
+	}
+	return nodeSum(v.h, left, right), true
+}
+
+// split returns the number of leaves of the left subtree of a tree of size
+// leaves, that is the largest power of two smaller than size (size > 1).
+func split(size uint64) uint64 {
+	return 1 << (bits.Len64(size-1) - 1)
+}
+
+// sortedUnique returns the indices in increasing order, without duplicates.
+func sortedUnique(indices []uint64) []uint64 {
+	res := append([]uint64(nil), indices...)
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	n := 0
+	for i := range res {
+		if i == 0 || res[i] != res[n-1] {
+			res[n] = res[i]
+			n++
+		}
+	}
+	return res[:n]
+}
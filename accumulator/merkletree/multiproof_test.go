@@ -0,0 +1,139 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkletree
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+func newRetainedTree(n int) *Tree {
+	t := NewRetained(sha256.New())
+	for i := 0; i < n; i++ {
+		t.Push([]byte{byte(i), byte(i >> 8)})
+	}
+	return t
+}
+
+func TestProveIndex(t *testing.T) {
+	for n := 1; n <= 33; n++ {
+		tree := newRetainedTree(n)
+		for i := 0; i < n; i++ {
+			root, proofSet, numLeaves, err := tree.ProveIndex(uint64(i))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// compare with the proof of a tree built with SetIndex
+			ref := New(sha256.New())
+			if err := ref.SetIndex(uint64(i)); err != nil {
+				t.Fatal(err)
+			}
+			for j := 0; j < n; j++ {
+				ref.Push([]byte{byte(j), byte(j >> 8)})
+			}
+			refRoot, refProofSet, _, _ := ref.Prove()
+			if !bytes.Equal(root, refRoot) || len(proofSet) != len(refProofSet) {
+				t.Fatalf("n=%d i=%d: proof mismatch", n, i)
+			}
+			for j := range proofSet {
+				if !bytes.Equal(proofSet[j], refProofSet[j]) {
+					t.Fatalf("n=%d i=%d: proof mismatch", n, i)
+				}
+			}
+
+			if !VerifyProof(sha256.New(), root, proofSet, uint64(i), numLeaves) {
+				t.Fatalf("n=%d i=%d: proof rejected", n, i)
+			}
+		}
+		if _, _, _, err := tree.ProveIndex(uint64(n)); err == nil {
+			t.Fatal("out of bounds index should be rejected")
+		}
+	}
+
+	if _, _, _, err := New(sha256.New()).ProveIndex(0); err == nil {
+		t.Fatal("non retained tree should be rejected")
+	}
+}
+
+func TestMultiProof(t *testing.T) {
+	for _, n := range []int{1, 2, 7, 8, 13, 32, 100} {
+		tree := newRetainedTree(n)
+		for _, indices := range [][]uint64{
+			{0},
+			{uint64(n - 1)},
+			{uint64(n - 1), 0, uint64(n / 2), 0},
+			{uint64(n / 3), uint64(n / 3), uint64(n / 2)},
+		} {
+			root, proof, err := tree.MultiProve(indices)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !VerifyMultiProof(sha256.New(), root, proof) {
+				t.Fatalf("n=%d %v: multiproof rejected", n, indices)
+			}
+
+			// the siblings are shared, there can't be more than in separate proofs
+			nbSiblings := 0
+			for _, i := range proof.Indices {
+				_, proofSet, _, _ := tree.ProveIndex(i)
+				nbSiblings += len(proofSet) - 1
+			}
+			if len(proof.Siblings) > nbSiblings {
+				t.Fatalf("n=%d %v: %d siblings, more than %d", n, indices, len(proof.Siblings), nbSiblings)
+			}
+
+			// tamper with the proof
+			tampered := proof
+			tampered.Leaves = append([][]byte{{0xff}}, proof.Leaves[1:]...)
+			if VerifyMultiProof(sha256.New(), root, tampered) {
+				t.Fatal("tampered leaf should be rejected")
+			}
+			if len(proof.Siblings) > 0 {
+				tampered = proof
+				tampered.Siblings = proof.Siblings[1:]
+				if VerifyMultiProof(sha256.New(), root, tampered) {
+					t.Fatal("missing sibling should be rejected")
+				}
+				tampered.Siblings = append(append([][]byte(nil), proof.Siblings...), proof.Siblings[0])
+				if VerifyMultiProof(sha256.New(), root, tampered) {
+					t.Fatal("extra sibling should be rejected")
+				}
+			}
+		}
+
+		if _, _, err := tree.MultiProve([]uint64{0, uint64(n)}); err == nil {
+			t.Fatal("out of bounds index should be rejected")
+		}
+	}
+
+	// all the leaves: no sibling is needed
+	tree := newRetainedTree(10)
+	root, proof, err := tree.MultiProve([]uint64{9, 8, 7, 6, 5, 4, 3, 2, 1, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proof.Siblings) != 0 || !VerifyMultiProof(sha256.New(), root, proof) {
+		t.Fatal("multiproof of all the leaves should have no sibling")
+	}
+}
+
+func TestRetainedPushSubTree(t *testing.T) {
+	tree := NewRetained(sha256.New())
+	if err := tree.PushSubTree(0, make([]byte, 32)); err == nil {
+		t.Fatal("pushing a cached subtree in a retained tree should fail")
+	}
+}
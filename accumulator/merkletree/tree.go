@@ -59,6 +59,14 @@ type Tree struct {
 	// this flag is somewhat gross, but eliminates needing to duplicate the
 	// entire 'Push' function when writing the cached tree.
 	cachedTree bool
+
+	// A retained tree keeps the data of the leaves and all the nodes of the
+	// complete subtrees, so that proofs can be built for any index after
+	// construction. nodes[h][i] is the root of the complete subtree of height
+	// h holding the leaves i⋅2ʰ to (i+1)⋅2ʰ-1.
+	retained bool
+	leaves   [][]byte
+	nodes    [][][]byte
 }
 
 // A subTree contains the Merkle root of a complete (2^height leaves) subTree
@@ -130,6 +138,17 @@ func New(h hash.Hash) *Tree {
 	}
 }
 
+// NewRetained creates a new Tree which keeps all its nodes, so that proofs
+// can be built for any index (see ProveIndex) or set of indices (see
+// MultiProve) once the data has been pushed. The memory footprint of such a
+// Tree grows in O(n) in the number of leaves.
+func NewRetained(h hash.Hash) *Tree {
+	return &Tree{
+		hash:     h,
+		retained: true,
+	}
+}
+
 // Prove creates a proof that the leaf at the established index (established by
 // SetIndex) is an element of the Merkle tree. Prove will return a nil proof
 // set if used incorrectly. Prove does not modify the Tree. Prove can only be
@@ -223,6 +242,10 @@ func (t *Tree) Push(data []byte) {
 	} else {
 		t.head.sum = leafSum(t.hash, data)
 	}
+	if t.retained {
+		t.leaves = append(t.leaves, append([]byte(nil), data...))
+		t.retain(t.head)
+	}
 
 	// Join subTrees if possible.
 	t.joinAllSubTrees()
@@ -252,6 +275,11 @@ func (t *Tree) Push(data []byte) {
 // trees. Therefore an unbalanced tree will cause silent errors, pain and
 // misery for the person who wants to debug the resulting error.
 func (t *Tree) PushSubTree(height int, sum []byte) error {
+	// The nodes of a cached tree are unknown, so it can't be retained.
+	if t.retained {
+		return errors.New("can't push a cached subtree in a retained tree")
+	}
+
 	// Check if the cached tree that is pushed contains the element at
 	// proofIndex. This is not allowed.
 	newIndex := t.currentIndex + 1<<uint64(height)
@@ -361,5 +389,18 @@ func (t *Tree) joinAllSubTrees() {
 		// Join the two subTrees into one subTree with a greater height. Then
 		// compare the new subTree to the next subTree.
 		t.head = joinSubTrees(t.hash, t.head.next, t.head)
+		if t.retained {
+			t.retain(t.head)
+		}
+	}
+}
+
+// retain stores the root of a new complete subtree of a retained tree. Complete
+// subtrees of a given height are created from left to right, so that the root
+// is appended at its index.
+func (t *Tree) retain(s *subTree) {
+	for len(t.nodes) <= s.height {
+		t.nodes = append(t.nodes, nil)
 	}
+	t.nodes[s.height] = append(t.nodes[s.height], s.sum)
 }
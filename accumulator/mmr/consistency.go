@@ -0,0 +1,171 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mmr
+
+import (
+	"bytes"
+	"hash"
+)
+
+// ConsistencyProof shows that an MMR of NewSize leaves extends the MMR of its
+// first OldSize leaves.
+//
+// Each peak of the old MMR is the root of a complete subtree of the new one.
+// The new peaks are computed from the old peaks and from the roots of the
+// subtrees holding only new leaves.
+type ConsistencyProof struct {
+	OldSize, NewSize uint64
+	// OldPeaks are the peaks of the old MMR, from left to right.
+	OldPeaks [][]byte
+	// Nodes are the roots of the subtrees holding only new leaves needed to
+	// compute the new peaks, in depth-first, left to right, order.
+	Nodes [][]byte
+}
+
+// ProveConsistency creates a proof that the MMR extends the MMR it was when it
+// held oldSize leaves.
+func (m *MMR) ProveConsistency(oldSize uint64) (ConsistencyProof, error) {
+	if oldSize == 0 || oldSize > m.size {
+		return ConsistencyProof{}, errInvalidSize
+	}
+	proof := ConsistencyProof{
+		OldSize:  oldSize,
+		NewSize:  m.size,
+		OldPeaks: m.peaks(oldSize),
+	}
+	start := uint64(0)
+	for _, height := range mountains(m.size) {
+		m.proveConsistency(&proof, start, height)
+		start += 1 << height
+	}
+	return proof, nil
+}
+
+// proveConsistency appends to proof the nodes needed to compute the root of
+// the subtree of the given height whose first leaf is at index start.
+func (m *MMR) proveConsistency(proof *ConsistencyProof, start uint64, height int) {
+	end := start + 1<<height
+	switch {
+	case end <= proof.OldSize:
+		// an old peak
+		return
+	case start >= proof.OldSize:
+		proof.Nodes = append(proof.Nodes, m.nodes[height][start>>height])
+		return
+	}
+	half := uint64(1) << (height - 1)
+	m.proveConsistency(proof, start, height-1)
+	m.proveConsistency(proof, start+half, height-1)
+}
+
+// VerifyConsistency returns true if proof shows that the MMR with root
+// newRoot extends the MMR with root oldRoot.
+func VerifyConsistency(h hash.Hash, oldRoot, newRoot []byte, proof ConsistencyProof) bool {
+	if proof.OldSize == 0 || proof.NewSize < proof.OldSize {
+		return false
+	}
+	if len(proof.OldPeaks) != len(mountains(proof.OldSize)) {
+		return false
+	}
+	// the hash errors, on non-canonical peaks or nodes for a hash over a
+	// field, reject the proof
+	computed, err := BagPeaks(h, proof.OldSize, proof.OldPeaks)
+	if err != nil || !bytes.Equal(computed, oldRoot) {
+		return false
+	}
+	peaks, err := newConsistencyVerifier(h, &proof).newPeaks()
+	if err != nil {
+		return false
+	}
+	computed, err = BagPeaks(h, proof.NewSize, peaks)
+	return err == nil && bytes.Equal(computed, newRoot)
+}
+
+// consistencyVerifier computes the new peaks of a consistency proof,
+// consuming its old peaks and nodes in the order they were appended by
+// proveConsistency. It may also collect the siblings of the path from the old
+// peak starting at trackStart to its new peak.
+type consistencyVerifier struct {
+	h                  hash.Hash
+	proof              *ConsistencyProof
+	nextPeak, nextNode int
+	tracking           bool
+	trackStart         uint64
+	trackHeight        int
+	siblings           [][]byte
+}
+
+func newConsistencyVerifier(h hash.Hash, proof *ConsistencyProof) *consistencyVerifier {
+	return &consistencyVerifier{h: h, proof: proof}
+}
+
+// newPeaks returns the peaks of the new MMR, from left to right, or an error if
+// the proof is malformed.
+func (v *consistencyVerifier) newPeaks() ([][]byte, error) {
+	heights := mountains(v.proof.NewSize)
+	peaks := make([][]byte, len(heights))
+	start := uint64(0)
+	for k, height := range heights {
+		var err error
+		if peaks[k], err = v.sum(start, height); err != nil {
+			return nil, err
+		}
+		start += 1 << height
+	}
+	if v.nextPeak != len(v.proof.OldPeaks) || v.nextNode != len(v.proof.Nodes) {
+		return nil, errInvalidProof
+	}
+	return peaks, nil
+}
+
+// sum returns the root of the subtree of the given height whose first leaf is
+// at index start.
+func (v *consistencyVerifier) sum(start uint64, height int) ([]byte, error) {
+	end := start + 1<<height
+	switch {
+	case end <= v.proof.OldSize:
+		if v.nextPeak >= len(v.proof.OldPeaks) {
+			return nil, errInvalidProof
+		}
+		v.nextPeak++
+		return v.proof.OldPeaks[v.nextPeak-1], nil
+	case start >= v.proof.OldSize:
+		if v.nextNode >= len(v.proof.Nodes) {
+			return nil, errInvalidProof
+		}
+		v.nextNode++
+		return v.proof.Nodes[v.nextNode-1], nil
+	}
+
+	half := uint64(1) << (height - 1)
+	left, err := v.sum(start, height-1)
+	if err != nil {
+		return nil, err
+	}
+	right, err := v.sum(start+half, height-1)
+	if err != nil {
+		return nil, err
+	}
+	if v.tracking && height > v.trackHeight && start <= v.trackStart && v.trackStart < end {
+		// the children are computed first, so that the siblings are
+		// collected bottom-up
+		if v.trackStart < start+half {
+			v.siblings = append(v.siblings, right)
+		} else {
+			v.siblings = append(v.siblings, left)
+		}
+	}
+	return hashNode(v.h, left, right)
+}
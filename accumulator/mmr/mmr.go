@@ -0,0 +1,214 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mmr provides a Merkle Mountain Range: an append-only accumulator
+// whose leaves are grouped in perfect binary trees (the mountains) of
+// decreasing sizes, one per bit set in the number of leaves.
+//
+// Leaves, nodes and the root are domain separated by a tag, written as a first
+// block of Size() bytes: a leaf is Hash(0 || data) and a node is
+// Hash(1 || left || right). The root is Hash(2 || size || bag), where bag is
+// obtained by bagging the peaks of the mountains from right to left, so that
+// it commits to the number of leaves. The tag and the size are encoded in
+// big-endian order, which is a canonical encoding of a field element: the MMR
+// can be used with hashes over a field such as MiMC, the data must then be a
+// canonical encoding of field elements.
+//
+// Inclusion proofs are made of the path from the leaf to the peak of its
+// mountain, which only grows as leaves are appended, and of the other peaks.
+// Consistency proofs show that an MMR extends an older one, and can be used to
+// update inclusion proofs without access to the MMR.
+package mmr
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+)
+
+// tags of the hashed values
+const (
+	tagLeaf = iota
+	tagNode
+	tagRoot
+)
+
+var (
+	errOutOfRange   = errors.New("index out of range")
+	errInvalidSize  = errors.New("size must be between 1 and the number of leaves")
+	errInvalidProof = errors.New("invalid proof")
+)
+
+// An MMR is a Merkle Mountain Range. It keeps the nodes of all the complete
+// subtrees, so that proofs can be built for any leaf and any former size.
+//
+// An MMR is not safe for concurrent use.
+type MMR struct {
+	hash hash.Hash
+	size uint64
+
+	// nodes[h][i] is the root of the complete subtree of height h holding
+	// the leaves i⋅2ʰ to (i+1)⋅2ʰ-1
+	nodes [][][]byte
+}
+
+// New creates a new, empty, MMR. The provided hash will be used for all
+// hashing operations within the MMR.
+func New(h hash.Hash) *MMR {
+	return &MMR{hash: h}
+}
+
+// Append adds a leaf holding data to the MMR, and returns its index. It
+// returns an error if data can't be hashed, for instance if it is not a
+// canonical encoding of field elements for a hash over a field.
+func (m *MMR) Append(data []byte) (uint64, error) {
+	index := m.size
+	node, err := hashLeaf(m.hash, data)
+	if err != nil {
+		return 0, err
+	}
+	// the nodes are only updated once all the hashes are computed
+	path := make([][]byte, 0, bits.TrailingZeros64(^index)+1)
+	path = append(path, node)
+	for height, i := 0, index; i&1 == 1; height++ {
+		// the subtree is the right child of a new complete subtree
+		if node, err = hashNode(m.hash, m.nodes[height][i-1], node); err != nil {
+			return 0, err
+		}
+		path = append(path, node)
+		i >>= 1
+	}
+	for height, node := range path {
+		if len(m.nodes) <= height {
+			m.nodes = append(m.nodes, nil)
+		}
+		m.nodes[height] = append(m.nodes[height], node)
+	}
+	m.size++
+	return index, nil
+}
+
+// Size returns the number of leaves of the MMR.
+func (m *MMR) Size() uint64 {
+	return m.size
+}
+
+// Peaks returns the roots of the mountains of the MMR, from left (the
+// largest) to right.
+func (m *MMR) Peaks() [][]byte {
+	return m.peaks(m.size)
+}
+
+// Root returns the root of the MMR, or nil if it is empty.
+func (m *MMR) Root() ([]byte, error) {
+	return BagPeaks(m.hash, m.size, m.Peaks())
+}
+
+// RootAt returns the root the MMR had when it held size leaves.
+func (m *MMR) RootAt(size uint64) ([]byte, error) {
+	if size == 0 || size > m.size {
+		return nil, errInvalidSize
+	}
+	return BagPeaks(m.hash, size, m.peaks(size))
+}
+
+// peaks returns the peaks of the MMR of the first size leaves.
+func (m *MMR) peaks(size uint64) [][]byte {
+	heights := mountains(size)
+	res := make([][]byte, len(heights))
+	start := uint64(0)
+	for k, height := range heights {
+		res[k] = m.nodes[height][start>>height]
+		start += 1 << height
+	}
+	return res
+}
+
+// BagPeaks returns the root of an MMR of size leaves from its peaks, ordered
+// from left to right:
+//
+//	Hash(2 || size || Node(P₁, Node(P₂, … Node(Pₖ₋₁, Pₖ))))
+//
+// It returns nil if there is no peak, and an error if the peaks can't be
+// hashed.
+func BagPeaks(h hash.Hash, size uint64, peaks [][]byte) ([]byte, error) {
+	if len(peaks) == 0 {
+		return nil, nil
+	}
+	bag := peaks[len(peaks)-1]
+	for k := len(peaks) - 2; k >= 0; k-- {
+		var err error
+		if bag, err = hashNode(h, peaks[k], bag); err != nil {
+			return nil, err
+		}
+	}
+	return sum(h, block(h, tagRoot), block(h, size), bag)
+}
+
+// mountains returns the heights of the mountains of an MMR of size leaves,
+// from left to right.
+func mountains(size uint64) []int {
+	res := make([]int, 0, bits.OnesCount64(size))
+	for height := bits.Len64(size) - 1; height >= 0; height-- {
+		if size>>height&1 == 1 {
+			res = append(res, height)
+		}
+	}
+	return res
+}
+
+// mountainOf returns the position k among the mountains of an MMR of size
+// leaves, the index of the first leaf and the height of the mountain holding
+// the leaf at index (index < size).
+func mountainOf(size, index uint64) (k int, start uint64, height int) {
+	for k, height = range mountains(size) {
+		if index < start+1<<height {
+			return
+		}
+		start += 1 << height
+	}
+	panic("index out of range")
+}
+
+// hashLeaf returns the hash of a leaf holding data.
+func hashLeaf(h hash.Hash, data []byte) ([]byte, error) {
+	return sum(h, block(h, tagLeaf), data)
+}
+
+// hashNode returns the hash of the node with the given children.
+func hashNode(h hash.Hash, left, right []byte) ([]byte, error) {
+	return sum(h, block(h, tagNode), left, right)
+}
+
+// block returns v encoded in big-endian order on h.Size() bytes, or 8 bytes
+// if the digests are shorter.
+func block(h hash.Hash, v uint64) []byte {
+	res := make([]byte, max(h.Size(), 8))
+	binary.BigEndian.PutUint64(res[len(res)-8:], v)
+	return res
+}
+
+// sum returns the hash of the input data using the specified algorithm. Hashes
+// over a field, such as MiMC, return an error if the data is not a canonical
+// encoding of field elements.
+func sum(h hash.Hash, data ...[]byte) ([]byte, error) {
+	h.Reset()
+	for _, d := range data {
+		if _, err := h.Write(d); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,292 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mmr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+)
+
+// leaf returns the data of the i-th leaf, a canonical field element so that
+// it can be hashed with MiMC.
+func leaf(i uint64) []byte {
+	var e fr.Element
+	e.SetUint64(i)
+	b := e.Bytes()
+	return b[:]
+}
+
+func newMMR(h hash.Hash, size uint64) *MMR {
+	m := New(h)
+	for i := uint64(0); i < size; i++ {
+		if index, err := m.Append(leaf(i)); err != nil || index != i {
+			panic("unexpected index")
+		}
+	}
+	return m
+}
+
+// root returns the root of m, failing the test on errors.
+func root(t *testing.T, m *MMR) []byte {
+	t.Helper()
+	r, err := m.Root()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// appendLeaf appends the i-th leaf to m, failing the test on errors.
+func appendLeaf(t *testing.T, m *MMR, i uint64) {
+	t.Helper()
+	if _, err := m.Append(leaf(i)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func hashes() map[string]func() hash.Hash {
+	return map[string]func() hash.Hash{
+		"SHA256": sha256.New,
+		"MiMC":   func() hash.Hash { return mimc.NewMiMC() },
+	}
+}
+
+func TestRoot(t *testing.T) {
+	for name, h := range hashes() {
+		m := New(h())
+		if r, err := m.Root(); r != nil || err != nil {
+			t.Fatal("empty MMR should have a nil root")
+		}
+		roots := make(map[string]uint64)
+		for i := uint64(0); i < 40; i++ {
+			appendLeaf(t, m, i)
+			if size, ok := roots[string(root(t, m))]; ok {
+				t.Fatalf("%s: size %d: same root as size %d", name, i+1, size)
+			}
+			roots[string(root(t, m))] = i + 1
+			if len(m.Peaks()) != len(mountains(m.Size())) {
+				t.Fatalf("%s: size %d: wrong number of peaks", name, i+1)
+			}
+		}
+	}
+}
+
+func TestInclusionProof(t *testing.T) {
+	for name, h := range hashes() {
+		m := New(h())
+		var roots [][]byte
+		var proofs []Proof
+		for size := uint64(1); size <= 33; size++ {
+			appendLeaf(t, m, size-1)
+			roots = append(roots, root(t, m))
+			for i := uint64(0); i < size; i++ {
+				proof, err := m.Prove(i)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !Verify(h(), root(t, m), leaf(i), proof) {
+					t.Fatalf("%s: size %d, index %d: proof rejected", name, size, i)
+				}
+				if Verify(h(), root(t, m), leaf(i+1), proof) {
+					t.Fatalf("%s: size %d, index %d: wrong leaf accepted", name, size, i)
+				}
+				if size == 17 {
+					proofs = append(proofs, proof)
+				}
+			}
+			if _, err := m.Prove(size); err == nil {
+				t.Fatal("out of range index should be rejected")
+			}
+		}
+
+		// the proofs remain valid for the root they were built for
+		for i, proof := range proofs {
+			if !Verify(h(), roots[16], leaf(uint64(i)), proof) {
+				t.Fatalf("%s: index %d: former proof rejected", name, i)
+			}
+		}
+	}
+}
+
+// TestForgery checks that a node can't be opened as a leaf by lying about the
+// size of the MMR.
+func TestForgery(t *testing.T) {
+	for name, h := range hashes() {
+		m := newMMR(h(), 2)
+		// the root of the mountain holding the two leaves, opened as a leaf
+		// of an MMR of a single leaf
+		left, _ := hashLeaf(h(), leaf(0))
+		right, _ := hashLeaf(h(), leaf(1))
+		data := append(left, right...)
+		if Verify(h(), root(t, m), data, Proof{Index: 0, Size: 1}) {
+			t.Fatalf("%s: node opened as a leaf", name)
+		}
+
+		// a proof of the same leaf in a smaller MMR with the same peak
+		m = newMMR(h(), 3)
+		proof, _ := m.Prove(0)
+		proof.Size, proof.Peaks = 2, nil
+		if Verify(h(), root(t, m), leaf(0), proof) {
+			t.Fatalf("%s: proof with a wrong size accepted", name)
+		}
+
+		old := newMMR(h(), 2)
+		consistency, _ := m.ProveConsistency(2)
+		consistency.NewSize = 2
+		consistency.Nodes = nil
+		if VerifyConsistency(h(), root(t, old), root(t, m), consistency) {
+			t.Fatalf("%s: consistency proof with a wrong size accepted", name)
+		}
+	}
+}
+
+func TestConsistencyProof(t *testing.T) {
+	for name, h := range hashes() {
+		m := New(h())
+		for newSize := uint64(1); newSize <= 20; newSize++ {
+			appendLeaf(t, m, newSize-1)
+			for oldSize := uint64(1); oldSize <= newSize; oldSize++ {
+				oldRoot, err := m.RootAt(oldSize)
+				if err != nil {
+					t.Fatal(err)
+				}
+				proof, err := m.ProveConsistency(oldSize)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !VerifyConsistency(h(), oldRoot, root(t, m), proof) {
+					t.Fatalf("%s: %d -> %d: consistency proof rejected", name, oldSize, newSize)
+				}
+				if oldSize < newSize {
+					previous, _ := m.RootAt(newSize - 1)
+					if VerifyConsistency(h(), oldRoot, previous, proof) {
+						t.Fatalf("%s: %d -> %d: wrong new root accepted", name, oldSize, newSize)
+					}
+				}
+				if len(proof.Nodes) > 0 {
+					tampered := proof
+					tampered.Nodes = proof.Nodes[1:]
+					if VerifyConsistency(h(), oldRoot, root(t, m), tampered) {
+						t.Fatalf("%s: %d -> %d: missing node accepted", name, oldSize, newSize)
+					}
+				}
+
+				// update the inclusion proofs of the old MMR
+				old := newMMR(h(), oldSize)
+				for i := uint64(0); i < oldSize; i++ {
+					oldProof, _ := old.Prove(i)
+					updated, err := UpdateProof(h(), oldProof, proof)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !Verify(h(), root(t, m), leaf(i), updated) {
+						t.Fatalf("%s: %d -> %d, index %d: updated proof rejected", name, oldSize, newSize, i)
+					}
+				}
+			}
+		}
+		if _, err := m.ProveConsistency(0); err == nil {
+			t.Fatal("empty old MMR should be rejected")
+		}
+		if _, err := m.ProveConsistency(m.Size() + 1); err == nil {
+			t.Fatal("old MMR larger than the MMR should be rejected")
+		}
+	}
+}
+
+func TestUpdateProofErrors(t *testing.T) {
+	h := sha256.New()
+	m := newMMR(h, 11)
+	proof, _ := m.Prove(9)
+	appendLeaf(t, m, 11)
+	consistency, _ := m.ProveConsistency(10)
+	if _, err := UpdateProof(h, proof, consistency); err == nil {
+		t.Fatal("consistency proof from another size should be rejected")
+	}
+
+	consistency, _ = m.ProveConsistency(11)
+	consistency.OldPeaks = append([][]byte{make([]byte, 32)}, consistency.OldPeaks[1:]...)
+	if _, err := UpdateProof(h, proof, consistency); err == nil {
+		t.Fatal("consistency proof with other peaks should be rejected")
+	}
+}
+
+// TestNonCanonical checks that the errors of a hash over a field on
+// non-canonical inputs are returned, instead of panicking.
+func TestNonCanonical(t *testing.T) {
+	h := func() hash.Hash { return mimc.NewMiMC() }
+	m := newMMR(h(), 5)
+	r := root(t, m)
+
+	nonCanonical := bytes.Repeat([]byte{0xff}, fr.Bytes)
+	if _, err := m.Append(nonCanonical); err == nil {
+		t.Fatal("non-canonical leaf should be rejected")
+	}
+	if m.Size() != 5 || !bytes.Equal(root(t, m), r) {
+		t.Fatal("a rejected leaf should not change the MMR")
+	}
+	if _, err := BagPeaks(h(), 2, [][]byte{nonCanonical, leaf(0)}); err == nil {
+		t.Fatal("non-canonical peak should be rejected")
+	}
+
+	proof, _ := m.Prove(1)
+	if Verify(h(), r, nonCanonical, proof) {
+		t.Fatal("non-canonical leaf should not verify")
+	}
+	tampered := proof
+	tampered.Siblings = append([][]byte{nonCanonical}, proof.Siblings[1:]...)
+	if Verify(h(), r, leaf(1), tampered) {
+		t.Fatal("non-canonical sibling should not verify")
+	}
+	tampered = proof
+	tampered.Peaks = [][]byte{nonCanonical}
+	if Verify(h(), r, leaf(1), tampered) {
+		t.Fatal("non-canonical peak should not verify")
+	}
+
+	old := newMMR(h(), 3)
+	consistency, err := m.ProveConsistency(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tamperedConsistency := consistency
+	tamperedConsistency.Nodes = [][]byte{nonCanonical}
+	if VerifyConsistency(h(), root(t, old), r, tamperedConsistency) {
+		t.Fatal("non-canonical node should not verify")
+	}
+	oldProof, _ := old.Prove(0)
+	if _, err := UpdateProof(h(), oldProof, tamperedConsistency); err == nil {
+		t.Fatal("non-canonical node should be rejected")
+	}
+	tamperedConsistency = consistency
+	tamperedConsistency.OldPeaks = [][]byte{consistency.OldPeaks[0], nonCanonical}
+	if VerifyConsistency(h(), root(t, old), r, tamperedConsistency) {
+		t.Fatal("non-canonical old peak should not verify")
+	}
+}
+
+func BenchmarkAppend(b *testing.B) {
+	m := New(sha256.New())
+	data := leaf(42)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = m.Append(data)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mmr
+
+import (
+	"bytes"
+	"hash"
+)
+
+// Proof is an inclusion proof of a leaf in an MMR.
+//
+// As leaves are appended, the path from the leaf to the peak of its mountain
+// only grows: a proof remains valid for the root of the MMR it was built for,
+// and UpdateProof extends it to a larger MMR.
+type Proof struct {
+	// Index of the leaf.
+	Index uint64
+	// Size is the number of leaves of the MMR.
+	Size uint64
+	// Siblings are the siblings of the nodes on the path from the leaf to the
+	// peak of its mountain, starting with the sibling of the leaf.
+	Siblings [][]byte
+	// Peaks are the peaks of the other mountains, from left to right.
+	Peaks [][]byte
+}
+
+// Prove creates an inclusion proof of the leaf at index in the MMR.
+func (m *MMR) Prove(index uint64) (Proof, error) {
+	if index >= m.size {
+		return Proof{}, errOutOfRange
+	}
+	k, _, height := mountainOf(m.size, index)
+	proof := Proof{
+		Index:    index,
+		Size:     m.size,
+		Siblings: make([][]byte, height),
+	}
+	for level := 0; level < height; level++ {
+		proof.Siblings[level] = m.nodes[level][(index>>level)^1]
+	}
+	peaks := m.Peaks()
+	proof.Peaks = append(peaks[:k:k], peaks[k+1:]...)
+	return proof, nil
+}
+
+// Verify returns true if proof shows that data is the leaf at proof.Index in
+// the MMR with the given root. As the root commits to the number of leaves, a
+// proof with another Size is rejected.
+func Verify(h hash.Hash, root, data []byte, proof Proof) bool {
+	if root == nil || proof.Index >= proof.Size {
+		return false
+	}
+	k, _, height := mountainOf(proof.Size, proof.Index)
+	if len(proof.Siblings) != height || len(proof.Peaks) != len(mountains(proof.Size))-1 {
+		return false
+	}
+
+	// the data and the proof may not be canonical encodings for a hash over a
+	// field: the hash errors reject the proof
+	node, err := hashLeaf(h, data)
+	if err != nil {
+		return false
+	}
+	for level, sibling := range proof.Siblings {
+		if (proof.Index>>level)&1 == 0 {
+			node, err = hashNode(h, node, sibling)
+		} else {
+			node, err = hashNode(h, sibling, node)
+		}
+		if err != nil {
+			return false
+		}
+	}
+
+	peaks := make([][]byte, 0, len(proof.Peaks)+1)
+	peaks = append(peaks, proof.Peaks[:k]...)
+	peaks = append(peaks, node)
+	peaks = append(peaks, proof.Peaks[k:]...)
+	computed, err := BagPeaks(h, proof.Size, peaks)
+	return err == nil && bytes.Equal(computed, root)
+}
+
+// UpdateProof extends an inclusion proof in an MMR of c.OldSize leaves to the
+// MMR of c.NewSize leaves, using the consistency proof c between them. It
+// does not check c against the roots: the updated proof only verifies if c
+// does.
+func UpdateProof(h hash.Hash, proof Proof, c ConsistencyProof) (Proof, error) {
+	if proof.Size != c.OldSize || c.NewSize < c.OldSize || proof.Index >= proof.Size {
+		return Proof{}, errInvalidProof
+	}
+	k, start, height := mountainOf(c.OldSize, proof.Index)
+	if len(c.OldPeaks) != len(mountains(c.OldSize)) || len(proof.Peaks) != len(c.OldPeaks)-1 {
+		return Proof{}, errInvalidProof
+	}
+	for j, peak := range proof.Peaks {
+		if j >= k {
+			j++
+		}
+		if !bytes.Equal(peak, c.OldPeaks[j]) {
+			return Proof{}, errInvalidProof
+		}
+	}
+
+	// rebuild the new peaks, collecting the siblings of the path from the
+	// old peak of the leaf to its new one
+	v := newConsistencyVerifier(h, &c)
+	v.trackStart, v.trackHeight, v.tracking = start, height, true
+	peaks, err := v.newPeaks()
+	if err != nil {
+		return Proof{}, err
+	}
+
+	k, _, _ = mountainOf(c.NewSize, proof.Index)
+	res := Proof{
+		Index:    proof.Index,
+		Size:     c.NewSize,
+		Siblings: append(append([][]byte(nil), proof.Siblings...), v.siblings...),
+		Peaks:    append(peaks[:k:k], peaks[k+1:]...),
+	}
+	return res, nil
+}
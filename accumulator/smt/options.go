@@ -0,0 +1,54 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package smt
+
+// Option defines option for altering the behavior of a Tree.
+// See the descriptions of functions returning instances of this type for
+// particular options.
+type Option func(*treeConfig)
+
+type treeConfig struct {
+	depth int
+	store Store
+	root  []byte
+}
+
+// WithDepth sets the depth of the tree, that is the number of bits of the keys
+// used as a path from the root to the leaves. The keys must then be smaller
+// than 2ᵈᵉᵖᵗʰ. It defaults to 8 times the size of the hash.
+//
+// For instance, with a MiMC hash on a field of b bits, a depth of b-1 ensures
+// that any key is a valid field element.
+func WithDepth(depth int) Option {
+	return func(opt *treeConfig) {
+		opt.depth = depth
+	}
+}
+
+// WithStore sets the store holding the nodes of the tree. It defaults to a
+// new MemoryStore.
+func WithStore(store Store) Option {
+	return func(opt *treeConfig) {
+		opt.store = store
+	}
+}
+
+// WithRoot opens the tree of the given root, whose nodes must be in the store
+// (see WithStore). It defaults to the root of the empty tree.
+func WithRoot(root []byte) Option {
+	return func(opt *treeConfig) {
+		opt.root = root
+	}
+}
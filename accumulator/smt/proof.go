@@ -0,0 +1,101 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package smt
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+)
+
+var errInvalidProof = errors.New("invalid proof")
+
+// Proof is a Merkle proof for a key of a Tree: of membership if the key is
+// present, and of non-membership otherwise.
+type Proof struct {
+	// Siblings are the siblings of the nodes on the path from the leaf of the
+	// key to the root, starting with the sibling of the leaf. The roots of
+	// empty subtrees are nil.
+	Siblings [][]byte
+}
+
+// ComputeRoot returns the root of the tree of the given depth in which key
+// holds value, or is absent if value is nil, according to proof. The proof
+// must have exactly depth siblings.
+func ComputeRoot(h hash.Hash, depth int, proof Proof, key, value []byte) ([]byte, error) {
+	size := h.Size()
+	if depth < 1 || depth > 8*size {
+		return nil, errInvalidDepth
+	}
+	if len(proof.Siblings) != depth {
+		return nil, errInvalidProof
+	}
+	if err := checkKey(key, size, depth); err != nil {
+		return nil, err
+	}
+
+	var node []byte
+	if value == nil {
+		node = make([]byte, size)
+	} else {
+		if len(value) != size {
+			return nil, errWrongSize
+		}
+		var err error
+		if node, err = hashLeaf(h, key, value); err != nil {
+			return nil, err
+		}
+	}
+	empty := make([]byte, size)
+	for height, sibling := range proof.Siblings {
+		if sibling == nil {
+			sibling = empty
+		} else if len(sibling) != size {
+			return nil, errInvalidProof
+		}
+		var err error
+		if bit(key, height) == 0 {
+			node, err = hashNode(h, node, sibling)
+		} else {
+			node, err = hashNode(h, sibling, node)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if height < depth-1 {
+			if empty, err = hashNode(h, empty, empty); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return node, nil
+}
+
+// VerifyMembership returns true if proof shows that key holds value in the
+// tree of the given root and depth.
+func VerifyMembership(h hash.Hash, depth int, root []byte, proof Proof, key, value []byte) bool {
+	if value == nil {
+		return false
+	}
+	computed, err := ComputeRoot(h, depth, proof, key, value)
+	return err == nil && bytes.Equal(computed, root)
+}
+
+// VerifyNonMembership returns true if proof shows that key is absent from the
+// tree of the given root and depth.
+func VerifyNonMembership(h hash.Hash, depth int, root []byte, proof Proof, key []byte) bool {
+	computed, err := ComputeRoot(h, depth, proof, key, nil)
+	return err == nil && bytes.Equal(computed, root)
+}
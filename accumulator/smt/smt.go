@@ -0,0 +1,333 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package smt provides a sparse Merkle tree: a key-value map committed to by
+// the root of a Merkle tree of fixed depth, whose leaves are indexed by the
+// keys. It supports membership and non-membership proofs.
+//
+// The leaf of a key holding a value is Hash(0 || key || value), the leaf of an
+// absent key is the zero digest, and a node is Hash(1 || left || right), where
+// the tags 0 and 1 are encoded as a block of Size() bytes, so that a leaf can't
+// be passed off as a node. The roots
+// of the empty subtrees are cached, so that only the nodes of the non-empty
+// subtrees are stored, in memory or in a pluggable key-value Store.
+//
+// Keys and values are byte slices of the size of the hash. With hashes over a
+// field, such as the MiMC hashes of the hash package, they must be canonical
+// encodings of field elements.
+package smt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// tags of the hashed values
+const (
+	tagLeaf = iota
+	tagNode
+)
+
+var (
+	errWrongSize    = errors.New("keys, values and roots must be of the size of the hash")
+	errKeyTooLarge  = errors.New("key is larger than 2^depth")
+	errInvalidDepth = errors.New("depth must be between 1 and 8 times the size of the hash")
+)
+
+// A Tree is a sparse Merkle tree. Setting or deleting a key updates the nodes
+// on its path, and removes the former ones from the store: the store only
+// holds the current version of the tree.
+//
+// A Tree is not safe for concurrent use.
+type Tree struct {
+	hash  hash.Hash
+	store Store
+	depth int
+	size  int // size of the digests
+	root  []byte
+
+	// empty[h] is the root of an empty subtree of height h
+	empty [][]byte
+}
+
+// New creates a new Tree. The provided hash will be used for all hashing
+// operations within the Tree.
+func New(h hash.Hash, opts ...Option) (*Tree, error) {
+	size := h.Size()
+	cfg := treeConfig{depth: 8 * size}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.depth < 1 || cfg.depth > 8*size {
+		return nil, errInvalidDepth
+	}
+	if cfg.store == nil {
+		cfg.store = NewMemoryStore()
+	}
+
+	empty, err := emptyRoots(h, cfg.depth)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tree{
+		hash:  h,
+		store: cfg.store,
+		depth: cfg.depth,
+		size:  size,
+		empty: empty,
+	}
+	if cfg.root == nil {
+		t.root = t.empty[t.depth]
+	} else {
+		if len(cfg.root) != size {
+			return nil, errWrongSize
+		}
+		t.root = append([]byte(nil), cfg.root...)
+	}
+	return t, nil
+}
+
+// Root returns the Merkle root of the tree.
+func (t *Tree) Root() []byte {
+	return append([]byte(nil), t.root...)
+}
+
+// Depth returns the depth of the tree.
+func (t *Tree) Depth() int {
+	return t.depth
+}
+
+// Get returns the value of key, or nil if key is absent.
+func (t *Tree) Get(key []byte) ([]byte, error) {
+	if err := checkKey(key, t.size, t.depth); err != nil {
+		return nil, err
+	}
+	_, nodes, err := t.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(nodes[0], t.empty[0]) {
+		return nil, nil
+	}
+	leaf, err := t.get(nodes[0])
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(leaf[:t.size], key) {
+		return nil, fmt.Errorf("corrupted leaf %x", nodes[0])
+	}
+	return append([]byte(nil), leaf[t.size:]...), nil
+}
+
+// Set inserts key with the given value in the tree, or updates its value if
+// key is already present.
+func (t *Tree) Set(key, value []byte) error {
+	if err := checkKey(key, t.size, t.depth); err != nil {
+		return err
+	}
+	if len(value) != t.size {
+		return errWrongSize
+	}
+	leaf, err := hashLeaf(t.hash, key, value)
+	if err != nil {
+		return err
+	}
+	record := make([]byte, 0, 2*t.size)
+	record = append(append(record, key...), value...)
+	return t.update(key, leaf, record)
+}
+
+// Delete removes key from the tree. Deleting an absent key is a no-op.
+func (t *Tree) Delete(key []byte) error {
+	if err := checkKey(key, t.size, t.depth); err != nil {
+		return err
+	}
+	return t.update(key, t.empty[0], nil)
+}
+
+// Prove returns a proof of membership of key if it is present in the tree,
+// and of non-membership otherwise.
+func (t *Tree) Prove(key []byte) (Proof, error) {
+	if err := checkKey(key, t.size, t.depth); err != nil {
+		return Proof{}, err
+	}
+	siblings, _, err := t.path(key)
+	if err != nil {
+		return Proof{}, err
+	}
+	for height := range siblings {
+		if bytes.Equal(siblings[height], t.empty[height]) {
+			siblings[height] = nil
+		} else {
+			siblings[height] = append([]byte(nil), siblings[height]...)
+		}
+	}
+	return Proof{Siblings: siblings}, nil
+}
+
+// update sets the leaf of key, stored as record if not empty, and recomputes
+// the nodes up to the root.
+func (t *Tree) update(key, leaf, record []byte) error {
+	siblings, nodes, err := t.path(key)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(nodes[0], leaf) {
+		return nil
+	}
+
+	if record != nil {
+		if err := t.store.Set(leaf, record); err != nil {
+			return err
+		}
+	}
+	node := leaf
+	children := make([]byte, 2*t.size)
+	for height := 0; height < t.depth; height++ {
+		if bit(key, height) == 0 {
+			copy(children, node)
+			copy(children[t.size:], siblings[height])
+		} else {
+			copy(children, siblings[height])
+			copy(children[t.size:], node)
+		}
+		if node, err = hashNode(t.hash, children[:t.size], children[t.size:]); err != nil {
+			return err
+		}
+		if !bytes.Equal(node, t.empty[height+1]) {
+			if err := t.store.Set(node, children); err != nil {
+				return err
+			}
+		}
+	}
+
+	// all the nodes of the former path changed
+	for height := range nodes {
+		if !bytes.Equal(nodes[height], t.empty[height]) {
+			if err := t.store.Delete(nodes[height]); err != nil {
+				return err
+			}
+		}
+	}
+	t.root = node
+	return nil
+}
+
+// path returns the nodes on the path from the root to the leaf of key, and
+// their siblings. nodes[h] and siblings[h] are at height h.
+func (t *Tree) path(key []byte) (siblings, nodes [][]byte, err error) {
+	siblings = make([][]byte, t.depth)
+	nodes = make([][]byte, t.depth+1)
+	nodes[t.depth] = t.root
+	for height := t.depth; height > 0; height-- {
+		if bytes.Equal(nodes[height], t.empty[height]) {
+			// the rest of the path is in an empty subtree
+			for h := height - 1; h >= 0; h-- {
+				nodes[h] = t.empty[h]
+				siblings[h] = t.empty[h]
+			}
+			break
+		}
+		children, err := t.get(nodes[height])
+		if err != nil {
+			return nil, nil, err
+		}
+		left, right := children[:t.size], children[t.size:]
+		if bit(key, height-1) == 0 {
+			nodes[height-1], siblings[height-1] = left, right
+		} else {
+			nodes[height-1], siblings[height-1] = right, left
+		}
+	}
+	return siblings, nodes, nil
+}
+
+// get returns the children of a node, or the key and value of a leaf.
+func (t *Tree) get(node []byte) ([]byte, error) {
+	res, err := t.store.Get(node)
+	if err != nil {
+		return nil, fmt.Errorf("node %x: %w", node, err)
+	}
+	if len(res) != 2*t.size {
+		return nil, fmt.Errorf("corrupted node %x", node)
+	}
+	return res, nil
+}
+
+// emptyRoots returns the roots of the empty subtrees of heights 0 to depth.
+func emptyRoots(h hash.Hash, depth int) ([][]byte, error) {
+	res := make([][]byte, depth+1)
+	res[0] = make([]byte, h.Size())
+	for height := 1; height <= depth; height++ {
+		var err error
+		if res[height], err = hashNode(h, res[height-1], res[height-1]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// checkKey checks that key has the size of the digests and is smaller than
+// 2ᵈᵉᵖᵗʰ.
+func checkKey(key []byte, size, depth int) error {
+	if len(key) != size {
+		return errWrongSize
+	}
+	for i := depth; i < 8*size; i++ {
+		if bit(key, i) != 0 {
+			return errKeyTooLarge
+		}
+	}
+	return nil
+}
+
+// bit returns the bit of index i of key, interpreted as a big endian integer.
+// It tells which child of the node at height i+1 is on the path to key.
+func bit(key []byte, i int) byte {
+	return (key[len(key)-1-i/8] >> (i % 8)) & 1
+}
+
+// hashLeaf returns the leaf of key holding value.
+func hashLeaf(h hash.Hash, key, value []byte) ([]byte, error) {
+	return sum(h, block(h, tagLeaf), key, value)
+}
+
+// hashNode returns the hash of the node with the given children.
+func hashNode(h hash.Hash, left, right []byte) ([]byte, error) {
+	return sum(h, block(h, tagNode), left, right)
+}
+
+// block returns v encoded in big-endian order on h.Size() bytes, or 8 bytes
+// if the digests are shorter.
+func block(h hash.Hash, v uint64) []byte {
+	res := make([]byte, max(h.Size(), 8))
+	binary.BigEndian.PutUint64(res[len(res)-8:], v)
+	return res
+}
+
+// sum returns the hash of the input data using the specified algorithm. Hashes
+// over a field, such as MiMC, return an error if the data is not a canonical
+// encoding of field elements.
+func sum(h hash.Hash, data ...[]byte) ([]byte, error) {
+	h.Reset()
+	for _, d := range data {
+		if _, err := h.Write(d); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
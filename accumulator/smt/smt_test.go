@@ -0,0 +1,332 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package smt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"hash"
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	gcHash "github.com/consensys/gnark-crypto/hash"
+)
+
+func randomBytes(t *testing.T, size int) []byte {
+	t.Helper()
+	res := make([]byte, size)
+	if _, err := rand.Read(res); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestSetGetDelete(t *testing.T) {
+	t.Parallel()
+	tree, err := New(sha256.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	emptyRoot := tree.Root()
+
+	const n = 50
+	keys := make([][]byte, n)
+	values := make([][]byte, n)
+	for i := range keys {
+		keys[i] = randomBytes(t, sha256.Size)
+		values[i] = randomBytes(t, sha256.Size)
+		if err := tree.Set(keys[i], values[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := range keys {
+		value, err := tree.Get(keys[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(value, values[i]) {
+			t.Fatal("wrong value")
+		}
+	}
+	if value, err := tree.Get(randomBytes(t, sha256.Size)); err != nil || value != nil {
+		t.Fatal("absent key should have no value")
+	}
+
+	// the root does not depend on the order of insertion
+	other, _ := New(sha256.New())
+	for _, i := range mrand.Perm(n) { //#nosec G404 weak rng is fine here
+		if err := other.Set(keys[i], values[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(other.Root(), tree.Root()) {
+		t.Fatal("root should not depend on the order of insertion")
+	}
+
+	// update
+	root := tree.Root()
+	values[0] = randomBytes(t, sha256.Size)
+	if err := tree.Set(keys[0], values[0]); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(root, tree.Root()) {
+		t.Fatal("root should change on update")
+	}
+	if value, _ := tree.Get(keys[0]); !bytes.Equal(value, values[0]) {
+		t.Fatal("wrong updated value")
+	}
+
+	// deleting all the keys gives back the empty tree, with an empty store
+	for i := range keys {
+		if err := tree.Delete(keys[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(tree.Root(), emptyRoot) {
+		t.Fatal("root should be the empty root")
+	}
+	if tree.store.(*MemoryStore).Len() != 0 {
+		t.Fatal("store should be empty")
+	}
+}
+
+func TestProofs(t *testing.T) {
+	t.Parallel()
+	for name, h := range map[string]func() hash.Hash{
+		"sha256": sha256.New,
+		"mimc":   gcHash.MIMC_BN254.New,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// with MiMC, keys and values are field elements, on fr.Bits-1 bits
+			tree, err := New(h(), WithDepth(fr.Bits-1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			bound := new(big.Int).Lsh(big.NewInt(1), fr.Bits-1)
+			randomElement := func() []byte {
+				v, err := rand.Int(rand.Reader, bound)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return v.FillBytes(make([]byte, fr.Bytes))
+			}
+
+			const n = 20
+			keys := make([][]byte, n)
+			values := make([][]byte, n)
+			for i := range keys {
+				keys[i], values[i] = randomElement(), randomElement()
+				if err := tree.Set(keys[i], values[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			root := tree.Root()
+
+			for i := range keys {
+				proof, err := tree.Prove(keys[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !VerifyMembership(h(), tree.Depth(), root, proof, keys[i], values[i]) {
+					t.Fatal("membership proof should verify")
+				}
+				if VerifyMembership(h(), tree.Depth(), root, proof, keys[i], keys[i]) {
+					t.Fatal("membership proof of a wrong value should not verify")
+				}
+				if VerifyNonMembership(h(), tree.Depth(), root, proof, keys[i]) {
+					t.Fatal("non-membership proof of a present key should not verify")
+				}
+			}
+
+			absent := randomElement()
+			proof, err := tree.Prove(absent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !VerifyNonMembership(h(), tree.Depth(), root, proof, absent) {
+				t.Fatal("non-membership proof should verify")
+			}
+
+			// the proof must have exactly depth siblings
+			if VerifyNonMembership(h(), tree.Depth()-1, root, proof, absent) {
+				t.Fatal("proof for a different depth should not verify")
+			}
+			short := Proof{Siblings: proof.Siblings[1:]}
+			if _, err := ComputeRoot(h(), tree.Depth(), short, absent, nil); err != errInvalidProof {
+				t.Fatal("proof with too few siblings should be rejected")
+			}
+			if _, err := ComputeRoot(h(), 0, proof, absent, nil); err != errInvalidDepth {
+				t.Fatal("invalid depth should be rejected")
+			}
+
+			// the proof of non-membership gives the root after insertion
+			value := randomElement()
+			newRoot, err := ComputeRoot(h(), tree.Depth(), proof, absent, value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tree.Set(absent, value); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(newRoot, tree.Root()) {
+				t.Fatal("recomputed root should match the root after insertion")
+			}
+		})
+	}
+}
+
+// TestNonCanonical checks that the errors of a hash over a field on
+// non-canonical inputs are returned, instead of panicking.
+func TestNonCanonical(t *testing.T) {
+	t.Parallel()
+	h := gcHash.MIMC_BN254.New
+	tree, err := New(h(), WithDepth(fr.Bits-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := make([]byte, fr.Bytes)
+	key[fr.Bytes-1] = 1
+	value := make([]byte, fr.Bytes)
+	if err := tree.Set(key, value); err != nil {
+		t.Fatal(err)
+	}
+	root := tree.Root()
+
+	nonCanonical := bytes.Repeat([]byte{0xff}, fr.Bytes)
+	if err := tree.Set(key, nonCanonical); err == nil {
+		t.Fatal("non-canonical value should be rejected")
+	}
+	if !bytes.Equal(root, tree.Root()) {
+		t.Fatal("a rejected update should not change the root")
+	}
+
+	absent := make([]byte, fr.Bytes)
+	absent[fr.Bytes-1] = 2
+	proof, err := tree.Prove(absent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyNonMembership(h(), tree.Depth(), root, proof, absent) {
+		t.Fatal("non-membership proof should verify")
+	}
+	proof.Siblings[0] = nonCanonical
+	if _, err := ComputeRoot(h(), tree.Depth(), proof, absent, nil); err == nil {
+		t.Fatal("non-canonical sibling should be rejected")
+	}
+	if VerifyNonMembership(h(), tree.Depth(), root, proof, absent) {
+		t.Fatal("non-membership proof with a non-canonical sibling should not verify")
+	}
+	if VerifyMembership(h(), tree.Depth(), root, proof, absent, value) {
+		t.Fatal("membership proof with a non-canonical sibling should not verify")
+	}
+	if proof, err = tree.Prove(key); err != nil {
+		t.Fatal(err)
+	}
+	if VerifyMembership(h(), tree.Depth(), root, proof, key, nonCanonical) {
+		t.Fatal("membership proof of a non-canonical value should not verify")
+	}
+}
+
+func TestNaiveRoot(t *testing.T) {
+	t.Parallel()
+	const depth = 8
+	tree, err := New(sha256.New(), WithDepth(depth))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// full tree, with 1 key out of 3 set
+	h := sha256.New()
+	layer := make([][]byte, 1<<depth)
+	for i := range layer {
+		layer[i] = make([]byte, sha256.Size)
+		if i%3 == 0 {
+			key := make([]byte, sha256.Size)
+			key[sha256.Size-1] = byte(i)
+			value := randomBytes(t, sha256.Size)
+			if err := tree.Set(key, value); err != nil {
+				t.Fatal(err)
+			}
+			if layer[i], err = hashLeaf(h, key, value); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for len(layer) > 1 {
+		next := make([][]byte, len(layer)/2)
+		for i := range next {
+			if next[i], err = hashNode(h, layer[2*i], layer[2*i+1]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		layer = next
+	}
+	if !bytes.Equal(layer[0], tree.Root()) {
+		t.Fatal("root should match the one of the full tree")
+	}
+
+	// leaves and nodes are hashed with distinct tags
+	left, right := randomBytes(t, sha256.Size), randomBytes(t, sha256.Size)
+	leaf, _ := hashLeaf(h, left, right)
+	node, _ := hashNode(h, left, right)
+	if bytes.Equal(leaf, node) {
+		t.Fatal("a leaf and a node with the same children should differ")
+	}
+
+	if err := tree.Set(append(make([]byte, sha256.Size-2), 1, 0), make([]byte, sha256.Size)); err != errKeyTooLarge {
+		t.Fatal("key larger than 2^depth should be rejected")
+	}
+}
+
+func TestReopen(t *testing.T) {
+	t.Parallel()
+	store := NewMemoryStore()
+	tree, err := New(sha256.New(), WithStore(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, value := randomBytes(t, sha256.Size), randomBytes(t, sha256.Size)
+	if err := tree.Set(key, value); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := New(sha256.New(), WithStore(store), WithRoot(tree.Root()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := reopened.Get(key); err != nil || !bytes.Equal(got, value) {
+		t.Fatal("reopened tree should hold the same values")
+	}
+
+	// a root whose nodes are not in the store
+	missing, _ := New(sha256.New(), WithStore(NewMemoryStore()), WithRoot(tree.Root()))
+	if _, err := missing.Get(key); err == nil {
+		t.Fatal("missing nodes should be reported")
+	}
+}
+
+func BenchmarkSet(b *testing.B) {
+	tree, _ := New(sha256.New())
+	key, value := make([]byte, sha256.Size), make([]byte, sha256.Size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = rand.Read(key)
+		_ = tree.Set(key, value)
+	}
+}
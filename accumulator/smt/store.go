@@ -0,0 +1,69 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package smt
+
+import "errors"
+
+// ErrKeyNotFound is returned by a Store when a key is not present.
+var ErrKeyNotFound = errors.New("key not found")
+
+// Store is the key-value store holding the nodes of a Tree, indexed by their
+// hash. Implementations can be backed by a database; a Tree only requires
+// Get to return ErrKeyNotFound (or an error wrapping it) on absent keys.
+type Store interface {
+	// Get returns the value stored at key, or ErrKeyNotFound.
+	Get(key []byte) ([]byte, error)
+	// Set stores value at key. The store must not retain value.
+	Set(key, value []byte) error
+	// Delete removes key from the store. Deleting an absent key is not an
+	// error.
+	Delete(key []byte) error
+}
+
+// MemoryStore is an in-memory Store. It is not safe for concurrent use.
+type MemoryStore struct {
+	m map[string][]byte
+}
+
+// NewMemoryStore returns an empty in-memory Store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{m: make(map[string][]byte)}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(key []byte) ([]byte, error) {
+	value, ok := s.m[string(key)]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return value, nil
+}
+
+// Set implements Store.
+func (s *MemoryStore) Set(key, value []byte) error {
+	s.m[string(key)] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(key []byte) error {
+	delete(s.m, string(key))
+	return nil
+}
+
+// Len returns the number of entries in the store.
+func (s *MemoryStore) Len() int {
+	return len(s.m)
+}
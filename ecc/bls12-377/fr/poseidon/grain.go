@@ -0,0 +1,108 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// grain is the Grain LFSR used by the reference implementation of the
+// Poseidon authors to derive the round constants (and Poseidon's MDS matrix)
+// from the parameters of the permutation.
+//
+// https://extgit.iaik.tugraz.at/krypto/hadeshash/-/blob/master/code/generate_parameters_grain.sage
+type grain struct {
+	state [80]uint8
+}
+
+// newGrain initializes the LFSR with the parameters of a permutation of width t
+// over fr, with S-box x^α, rf full rounds and rp partial rounds.
+func newGrain(t, rf, rp int) *grain {
+	g := new(grain)
+	i := 0
+	push := func(v, nbBits int) {
+		for j := nbBits - 1; j >= 0; j-- {
+			g.state[i] = uint8((v >> j) & 1)
+			i++
+		}
+	}
+	push(1, 2) // prime field
+	push(0, 4) // S-box x^α
+	push(fr.Bits, 12)
+	push(t, 12)
+	push(rf, 10)
+	push(rp, 10)
+	push((1<<30)-1, 30)
+
+	// discard the first 160 bits
+	for j := 0; j < 160; j++ {
+		g.step()
+	}
+	return g
+}
+
+func (g *grain) step() uint8 {
+	b := g.state[62] ^ g.state[51] ^ g.state[38] ^ g.state[23] ^ g.state[13] ^ g.state[0]
+	copy(g.state[:], g.state[1:])
+	g.state[79] = b
+	return b
+}
+
+// bit returns the next output bit: bits are produced in pairs, the second
+// one is output if the first one is 1, otherwise both are discarded.
+func (g *grain) bit() uint8 {
+	for {
+		b1 := g.step()
+		b2 := g.step()
+		if b1 == 1 {
+			return b2
+		}
+	}
+}
+
+// bits returns the next fr.Bits bits of the LFSR as an integer, most significant bit first.
+func (g *grain) bits(res *big.Int) *big.Int {
+	res.SetUint64(0)
+	for i := 0; i < fr.Bits; i++ {
+		res.Lsh(res, 1)
+		if g.bit() == 1 {
+			res.SetBit(res, 0, 1)
+		}
+	}
+	return res
+}
+
+// element returns the next field element, sampled by rejection.
+func (g *grain) element() fr.Element {
+	var v big.Int
+	q := fr.Modulus()
+	for g.bits(&v).Cmp(q) >= 0 {
+	}
+	var res fr.Element
+	res.SetBigInt(&v)
+	return res
+}
+
+// elementReduced returns the next fr.Bits bits of the LFSR reduced modulo q.
+func (g *grain) elementReduced() fr.Element {
+	var v big.Int
+	var res fr.Element
+	res.SetBigInt(g.bits(&v))
+	return res
+}
@@ -0,0 +1,133 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+	assert := require.New(t)
+
+	inputs := make([]fr.Element, MaxWidth)
+	for i := range inputs {
+		inputs[i].SetUint64(uint64(i + 1))
+	}
+	seen := make(map[fr.Element]struct{})
+	for n := 1; n < MaxWidth; n++ {
+		h, err := Hash(inputs[:n]...)
+		assert.NoError(err)
+		_, ok := seen[h]
+		assert.False(ok)
+		seen[h] = struct{}{}
+	}
+	_, err := Hash(inputs...)
+	assert.ErrorIs(err, ErrInvalidWidth)
+}
+
+func TestSponge(t *testing.T) {
+	assert := require.New(t)
+
+	var a, b fr.Element
+	a.SetRandom()
+	b.SetRandom()
+	aBytes, bBytes := a.Bytes(), b.Bytes()
+
+	h := NewPoseidon()
+	_, err := h.Write(aBytes[:])
+	assert.NoError(err)
+	s1 := h.Sum(nil)
+	assert.Equal(s1, h.Sum(nil), "Sum must not change the state")
+
+	_, err = h.Write(bBytes[:])
+	assert.NoError(err)
+	s2 := h.Sum(nil)
+	assert.NotEqual(s1, s2)
+
+	h.Reset()
+	_, err = h.Write(append(aBytes[:], bBytes[:]...))
+	assert.NoError(err)
+	assert.Equal(s2, h.Sum(nil))
+
+	sum, err := Sum(append(aBytes[:], bBytes[:]...))
+	assert.NoError(err)
+	assert.Equal(s2, sum)
+
+	// the padding distinguishes trailing zeroes
+	var zero [fr.Bytes]byte
+	_, err = h.Write(zero[:])
+	assert.NoError(err)
+	assert.NotEqual(s2, h.Sum(nil))
+
+	// all the supported widths
+	for width := MinWidth; width <= MaxWidth; width++ {
+		h := NewPoseidon(WithWidth(width))
+		_, err = h.Write(aBytes[:])
+		assert.NoError(err)
+		assert.Equal(BlockSize, len(h.Sum(nil)))
+	}
+
+	// invalid inputs
+	h.Reset()
+	_, err = h.Write(make([]byte, fr.Bytes+1))
+	assert.Error(err)
+	q := fr.Modulus().Bytes()
+	_, err = h.Write(q)
+	assert.Error(err)
+}
+
+func TestPermutationWidth(t *testing.T) {
+	assert := require.New(t)
+
+	_, err := NewPermutation(MinWidth - 1)
+	assert.ErrorIs(err, ErrInvalidWidth)
+	_, err = NewPermutation(MaxWidth + 1)
+	assert.ErrorIs(err, ErrInvalidWidth)
+
+	p, err := NewPermutation(MinWidth)
+	assert.NoError(err)
+	assert.ErrorIs(p.Permutation(make([]fr.Element, MinWidth+1)), ErrInvalidSizebuffer)
+}
+
+func TestFiatShamir(t *testing.T) {
+	fs := fiatshamir.NewTranscript(NewPoseidon(), "c0")
+	zero := make([]byte, BlockSize)
+	err := fs.Bind("c0", zero)
+	require.NoError(t, err)
+	_, err = fs.ComputeChallenge("c0")
+	require.NoError(t, err)
+}
+
+func BenchmarkPermutation(b *testing.B) {
+	for width := MinWidth; width <= MaxWidth; width++ {
+		p, _ := NewPermutation(width)
+		state := make([]fr.Element, width)
+		for i := range state {
+			state[i].SetRandom()
+		}
+		b.Run(fmt.Sprintf("width=%d", width), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = p.Permutation(state)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+const (
+	// MinWidth is the smallest supported width of the permutation.
+	MinWidth = 2
+	// MaxWidth is the largest supported width of the permutation.
+	MaxWidth = 3
+	// SBoxDegree is the exponent α of the S-box x ↦ x^α.
+	SBoxDegree = 11
+)
+
+// number of full and partial rounds, indexed by width - MinWidth
+var (
+	nbFullRounds    = [...]int{8, 8}
+	nbPartialRounds = [...]int{37, 37}
+)
+
+var (
+	ErrInvalidWidth      = fmt.Errorf("width must be between %d and %d", MinWidth, MaxWidth)
+	ErrInvalidSizebuffer = errors.New("the size of the input should match the size of the hash buffer")
+)
+
+// Parameters describes a Poseidon2 permutation instance.
+type Parameters struct {
+	// Width is the number of field elements of the state.
+	Width int
+
+	// NbFullRounds is the number of full (external) rounds, half of them are
+	// performed before the partial rounds.
+	NbFullRounds int
+
+	// NbPartialRounds is the number of partial (internal) rounds.
+	NbPartialRounds int
+
+	// RoundKeys are the round constants, RoundKeys[i] is added to the state
+	// at the beginning of round i. It contains Width elements for a full round
+	// and a single element (added to the first element of the state) for a
+	// partial round.
+	RoundKeys [][]fr.Element
+}
+
+var (
+	parameters     [MaxWidth - MinWidth + 1]*Parameters
+	parametersOnce [MaxWidth - MinWidth + 1]sync.Once
+)
+
+// GetParameters returns the parameters of the permutation of the given width.
+//
+// The round constants are derived with the Grain LFSR as in the reference
+// implementation of the Poseidon2 authors.
+func GetParameters(width int) (*Parameters, error) {
+	if width < MinWidth || width > MaxWidth {
+		return nil, ErrInvalidWidth
+	}
+	i := width - MinWidth
+	parametersOnce[i].Do(func() {
+		parameters[i] = newParameters(width, nbFullRounds[i], nbPartialRounds[i])
+	})
+	return parameters[i], nil
+}
+
+func newParameters(width, rf, rp int) *Parameters {
+	p := &Parameters{
+		Width:           width,
+		NbFullRounds:    rf,
+		NbPartialRounds: rp,
+	}
+	g := newGrain(width, rf, rp)
+
+	p.RoundKeys = make([][]fr.Element, rf+rp)
+	for i := range p.RoundKeys {
+		n := width
+		if i >= rf/2 && i < rf/2+rp {
+			n = 1
+		}
+		p.RoundKeys[i] = make([]fr.Element, n)
+		for j := range p.RoundKeys[i] {
+			p.RoundKeys[i][j] = g.element()
+		}
+	}
+
+	return p
+}
+
+// Permutation is the Poseidon2 permutation of a given width.
+//
+// The external matrix is circ(2, 1) for width 2 and circ(2, 1, 1) for width 3,
+// the internal matrix is 𝟙 + diag(1, 2) for width 2 and 𝟙 + diag(1, 1, 2) for
+// width 3, where 𝟙 is the matrix full of ones.
+type Permutation struct {
+	params *Parameters
+}
+
+// NewPermutation returns the Poseidon2 permutation of the given width.
+func NewPermutation(width int) (*Permutation, error) {
+	params, err := GetParameters(width)
+	if err != nil {
+		return nil, err
+	}
+	return &Permutation{params: params}, nil
+}
+
+// Width returns the number of field elements of the state.
+func (p *Permutation) Width() int {
+	return p.params.Width
+}
+
+// Permutation applies the permutation in place on the state input.
+func (p *Permutation) Permutation(input []fr.Element) error {
+	if len(input) != p.params.Width {
+		return ErrInvalidSizebuffer
+	}
+
+	rf := p.params.NbFullRounds / 2
+
+	// initial linear layer
+	p.matMulExternalInPlace(input)
+
+	for i := range p.params.RoundKeys {
+		if i < rf || i >= rf+p.params.NbPartialRounds {
+			// external round
+			for j := range input {
+				input[j].Add(&input[j], &p.params.RoundKeys[i][j])
+				sBox(&input[j])
+			}
+			p.matMulExternalInPlace(input)
+		} else {
+			// internal round
+			input[0].Add(&input[0], &p.params.RoundKeys[i][0])
+			sBox(&input[0])
+			p.matMulInternalInPlace(input)
+		}
+	}
+	return nil
+}
+
+// matMulExternalInPlace sets input to M_E ⋅ input.
+func (p *Permutation) matMulExternalInPlace(input []fr.Element) {
+	var sum fr.Element
+	switch p.params.Width {
+	case 2:
+		sum.Add(&input[0], &input[1])
+	case 3:
+		sum.Add(&input[0], &input[1]).Add(&sum, &input[2])
+	}
+	for i := range input {
+		input[i].Add(&input[i], &sum)
+	}
+}
+
+// matMulInternalInPlace sets input to M_I ⋅ input.
+func (p *Permutation) matMulInternalInPlace(input []fr.Element) {
+	var sum fr.Element
+	switch p.params.Width {
+	case 2:
+		sum.Add(&input[0], &input[1])
+		input[0].Add(&input[0], &sum)
+		input[1].Double(&input[1]).Add(&input[1], &sum)
+	case 3:
+		sum.Add(&input[0], &input[1]).Add(&sum, &input[2])
+		input[0].Add(&input[0], &sum)
+		input[1].Add(&input[1], &sum)
+		input[2].Double(&input[2]).Add(&input[2], &sum)
+	}
+}
+
+// sBox sets x to x^11
+func sBox(x *fr.Element) {
+	var x2, x8 fr.Element
+	x2.Square(x)
+	x8.Square(&x2).Square(&x8)
+	x.Mul(x, &x2).Mul(x, &x8)
+}
@@ -0,0 +1,298 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidCosetSize  = errors.New("coset size must be a power of 2 smaller than the domain cardinality")
+	ErrFK20NotSingle     = errors.New("single point proofs require a coset size of 1")
+	ErrInvalidCosetIndex = errors.New("coset index must be smaller than the number of cosets")
+)
+
+// FK20 computes the opening proofs of a polynomial on all the points of a
+// domain at once, with the method of Feist and Khovratovich
+// (https://eprint.iacr.org/2023/033).
+//
+// Let n be the cardinality of the domain, ω its generator and ℓ the coset size.
+// The domain is split into the m = n/ℓ cosets ωⁱ⟨ωᵐ⟩ = {ωⁱ⁺ʲᵐ, j<ℓ}, i<m.
+// For each coset, FK20 computes the commitment to the quotient of f by the
+// vanishing polynomial Xˡ - ωⁱˡ of the coset. For ℓ = 1, this is the
+// opening proof of f at ωⁱ, as computed by Open.
+//
+// The quotient commitments are [qᵢ(τ)]G₁ = ∑ₖ(ωⁱˡ)ᵏhₖ, that is the evaluations
+// on the m-th roots of unity of a polynomial with coefficients hₖ in G₁. The
+// hₖ = ∑_{j≥ℓ(k+1)}fⱼ[τʲ⁻ˡ⁽ᵏ⁺¹⁾]G₁ are the product of a Toeplitz matrix made
+// of the coefficients of f with the SRS, computed with FFTs of size 2m.
+// All the proofs are thus obtained with O(n log n) group operations, instead
+// of O(n²) for n calls to Open.
+type FK20 struct {
+	domain    *fft.Domain
+	cosetSize int
+
+	// domainExt domain of size 2m on which the Toeplitz products are computed
+	domainExt *fft.Domain
+
+	// srsFFT[r] is the FFT on domainExt of the part of the SRS multiplied by
+	// the coefficients fₗₛ₊ᵣ of f
+	srsFFT [][]bls12377.G1Affine
+}
+
+// NewFK20 precomputes the data needed to compute the proofs of polynomials of
+// size at most domain.Cardinality on the cosets of size cosetSize of domain.
+//
+// pk must contain at least domain.Cardinality - cosetSize points.
+func NewFK20(pk ProvingKey, domain *fft.Domain, cosetSize int) (*FK20, error) {
+	n := int(domain.Cardinality)
+	if cosetSize < 1 || bits.OnesCount(uint(cosetSize)) != 1 || cosetSize >= n {
+		return nil, ErrInvalidCosetSize
+	}
+	if len(pk.G1) < n-cosetSize {
+		return nil, ErrInvalidPolynomialSize
+	}
+	m := n / cosetSize
+
+	fk := &FK20{
+		domain:    domain,
+		cosetSize: cosetSize,
+		domainExt: fft.NewDomain(uint64(2 * m)),
+		srsFFT:    make([][]bls12377.G1Affine, cosetSize),
+	}
+
+	// for each residue r, the vector Aʳ of size 2m with
+	// Aʳₜ = [τ^{ℓ(m-2-t)+r}]G₁ for t ≤ m-2 and 0 otherwise
+	for r := 0; r < cosetSize; r++ {
+		a := make([]bls12377.G1Jac, 2*m)
+		for t := 0; t < 2*m; t++ {
+			if t <= m-2 {
+				a[t].FromAffine(&pk.G1[cosetSize*(m-2-t)+r])
+			} else {
+				a[t].FromAffine(&bls12377.G1Affine{})
+			}
+		}
+		fftG1(a, fk.domainExt.Generator)
+		fk.srsFFT[r] = bls12377.BatchJacobianToAffineG1(a)
+	}
+
+	return fk, nil
+}
+
+// ComputeProofs returns the opening proofs of p at every point of the domain,
+// in natural order: the i-th proof opens p at ωⁱ and equals Open(p, ωⁱ, pk).
+//
+// p is in canonical form, with len(p) ≤ domain.Cardinality. The coset size of
+// fk must be 1.
+func (fk *FK20) ComputeProofs(p []fr.Element) ([]OpeningProof, error) {
+	if fk.cosetSize != 1 {
+		return nil, ErrFK20NotSingle
+	}
+	hs, err := fk.ComputeMultiProofs(p)
+	if err != nil {
+		return nil, err
+	}
+
+	// claimed values are the evaluations of p on the domain
+	evaluations := make([]fr.Element, fk.domain.Cardinality)
+	copy(evaluations, p)
+	fk.domain.FFT(evaluations, fft.DIF)
+	fft.BitReverse(evaluations)
+
+	proofs := make([]OpeningProof, len(hs))
+	for i := range proofs {
+		proofs[i].H = hs[i]
+		proofs[i].ClaimedValue = evaluations[i]
+	}
+	return proofs, nil
+}
+
+// ComputeMultiProofs returns, for each coset ωⁱ⟨ωᵐ⟩ of size ℓ of the domain,
+// the commitment [qᵢ(τ)]G₁ to the quotient of p by Xˡ - ωⁱˡ. The
+// cosets are in natural order of i.
+//
+// p is in canonical form, with len(p) ≤ domain.Cardinality.
+func (fk *FK20) ComputeMultiProofs(p []fr.Element) ([]Digest, error) {
+	n := int(fk.domain.Cardinality)
+	if len(p) == 0 || len(p) > n {
+		return nil, ErrInvalidPolynomialSize
+	}
+	l := fk.cosetSize
+	m := n / l
+
+	// Fʳ = (fₗₛ₊ᵣ)ₛ padded to 2m, on domainExt. The 1/2m factor of the
+	// inverse FFT over G₁ is applied on the scalars.
+	coefficients := make([][]fr.Element, l)
+	parallel.Execute(l, func(start, end int) {
+		for r := start; r < end; r++ {
+			coefficients[r] = make([]fr.Element, 2*m)
+			for s := 0; s < m && l*s+r < len(p); s++ {
+				coefficients[r][s].Mul(&p[l*s+r], &fk.domainExt.CardinalityInv)
+			}
+			fk.domainExt.FFT(coefficients[r], fft.DIF)
+			fft.BitReverse(coefficients[r])
+		}
+	})
+
+	// ∑ᵣ FFT(Fʳ) ⊙ FFT(Aʳ)
+	h := make([]bls12377.G1Jac, 2*m)
+	parallel.Execute(2*m, func(start, end int) {
+		var tmp bls12377.G1Jac
+		var s big.Int
+		for u := start; u < end; u++ {
+			for r := 0; r < l; r++ {
+				coefficients[r][u].BigInt(&s)
+				tmp.FromAffine(&fk.srsFFT[r][u])
+				tmp.ScalarMultiplication(&tmp, &s)
+				h[u].AddAssign(&tmp)
+			}
+		}
+	})
+
+	// the hₖ are the entries m-1, ..., 2m-3 of the convolution
+	fftG1(h, fk.domainExt.GeneratorInv)
+	h = h[m-1 : 2*m-1]
+	h[m-1].FromAffine(&bls12377.G1Affine{})
+
+	// evaluations on the m-th roots of unity ωˡ
+	var generator fr.Element
+	generator.Exp(fk.domain.Generator, big.NewInt(int64(l)))
+	fftG1(h, generator)
+
+	return bls12377.BatchJacobianToAffineG1(h), nil
+}
+
+// CosetVerifyingKey is used to verify the proofs computed by
+// FK20.ComputeMultiProofs on the cosets of size ℓ of a domain.
+type CosetVerifyingKey struct {
+	G1 []bls12377.G1Affine  // [G₁, [τ]G₁, ..., [τˡ⁻¹]G₁], to commit to the interpolation polynomials
+	G2 [2]bls12377.G2Affine // [G₂, [τˡ]G₂]
+
+	domain    *fft.Domain
+	cosetSize int
+}
+
+// NewCosetVerifyingKey returns the key to verify the proofs on the cosets of
+// size cosetSize of domain. pk must contain at least cosetSize points, and
+// g2TauL must be [τˡ]G₂ for ℓ = cosetSize, in the same setup as pk and vk.
+func NewCosetVerifyingKey(pk ProvingKey, vk VerifyingKey, g2TauL bls12377.G2Affine, domain *fft.Domain, cosetSize int) (*CosetVerifyingKey, error) {
+	if cosetSize < 1 || bits.OnesCount(uint(cosetSize)) != 1 || uint64(cosetSize) >= domain.Cardinality {
+		return nil, ErrInvalidCosetSize
+	}
+	if len(pk.G1) < cosetSize {
+		return nil, ErrInvalidPolynomialSize
+	}
+	cvk := &CosetVerifyingKey{
+		G1:        make([]bls12377.G1Affine, cosetSize),
+		G2:        [2]bls12377.G2Affine{vk.G2[0], g2TauL},
+		domain:    domain,
+		cosetSize: cosetSize,
+	}
+	copy(cvk.G1, pk.G1)
+	return cvk, nil
+}
+
+// VerifyCoset verifies the proof computed by FK20.ComputeMultiProofs on the
+// i-th coset ωⁱ⟨ωᵐ⟩ of size ℓ: evaluations[j] is the claimed value of the
+// committed polynomial f at ωⁱ⁺ʲᵐ, for j<ℓ.
+//
+// The evaluations are interpolated into the polynomial I of degree < ℓ, which
+// is the remainder of f by Xˡ - ωⁱˡ, and the proof is checked with
+// e([f(τ) - I(τ)]G₁, G₂) = e(proof, [τˡ - ωⁱˡ]G₂).
+func VerifyCoset(commitment *Digest, proof *Digest, i int, evaluations []fr.Element, vk *CosetVerifyingKey) error {
+	l := vk.cosetSize
+	m := int(vk.domain.Cardinality) / l
+	if i < 0 || i >= m {
+		return ErrInvalidCosetIndex
+	}
+	if len(evaluations) != l {
+		return ErrInvalidCosetSize
+	}
+
+	// I(ωⁱX) = J(X) where J interpolates the evaluations on the ℓ-th roots of
+	// unity ζ = ωᵐ: Jₖ = 1/ℓ ⋅ ∑ⱼ evaluations[j]ζ⁻ʲᵏ, and Iₖ = Jₖω⁻ⁱᵏ.
+	var zetaInv, shiftInv, lInv fr.Element
+	zetaInv.Exp(vk.domain.GeneratorInv, big.NewInt(int64(m)))
+	shiftInv.Exp(vk.domain.GeneratorInv, big.NewInt(int64(i)))
+	lInv.SetUint64(uint64(l)).Inverse(&lInv)
+	interpolation := make([]fr.Element, l)
+	var zetaInvK, zetaInvJK, shiftInvK, tmp fr.Element
+	zetaInvK.SetOne()
+	shiftInvK.Set(&lInv)
+	for k := range interpolation {
+		zetaInvJK.SetOne()
+		for j := range evaluations {
+			tmp.Mul(&evaluations[j], &zetaInvJK)
+			interpolation[k].Add(&interpolation[k], &tmp)
+			zetaInvJK.Mul(&zetaInvJK, &zetaInvK)
+		}
+		interpolation[k].Mul(&interpolation[k], &shiftInvK)
+		zetaInvK.Mul(&zetaInvK, &zetaInv)
+		shiftInvK.Mul(&shiftInvK, &shiftInv)
+	}
+
+	// [f(τ) - I(τ)]G₁
+	var interpolationCommitment bls12377.G1Affine
+	if _, err := interpolationCommitment.MultiExp(vk.G1, interpolation, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	var left bls12377.G1Affine
+	left.Sub(commitment, &interpolationCommitment)
+
+	// [τˡ - ωⁱˡ]G₂
+	var shiftL fr.Element
+	var shiftLInt big.Int
+	shiftL.Exp(vk.domain.Generator, big.NewInt(int64(i*l))).BigInt(&shiftLInt)
+	var vanishing bls12377.G2Affine
+	vanishing.ScalarMultiplication(&vk.G2[0], &shiftLInt)
+	vanishing.Sub(&vk.G2[1], &vanishing)
+
+	// e([f(τ) - I(τ)]G₁, G₂).e(-proof, [τˡ - ωⁱˡ]G₂) == 1
+	var proofNeg bls12377.G1Affine
+	proofNeg.Neg(proof)
+	check, err := bls12377.PairingCheck(
+		[]bls12377.G1Affine{left, proofNeg},
+		[]bls12377.G2Affine{vk.G2[0], vanishing},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// fftG1 computes in place the FFT of a, in natural order, on the subgroup
+// generated by generator, of order len(a).
+func fftG1(a []bls12377.G1Jac, generator fr.Element) {
+	numCPU := uint64(runtime.NumCPU())
+	maxSplits := bits.TrailingZeros64(ecc.NextPowerOfTwo(numCPU)) << 1
+
+	difFFTG1(a, computeTwiddles(generator, len(a)), 0, maxSplits, nil)
+	bitReverse(a)
+}
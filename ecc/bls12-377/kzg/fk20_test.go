@@ -0,0 +1,203 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFK20ComputeProofs(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 64
+	domain := fft.NewDomain(size)
+	fk, err := NewFK20(testSrs.Pk, domain, 1)
+	assert.NoError(err)
+
+	// full size and smaller polynomials
+	for _, polySize := range []int{size, 37, 2} {
+		p := randomPolynomial(polySize)
+		digest, err := Commit(p, testSrs.Pk)
+		assert.NoError(err)
+
+		proofs, err := fk.ComputeProofs(p)
+		assert.NoError(err)
+		assert.Equal(size, len(proofs))
+
+		var point fr.Element
+		point.SetOne()
+		for i := range proofs {
+			expected, err := Open(p, point, testSrs.Pk)
+			assert.NoError(err)
+			assert.True(expected.ClaimedValue.Equal(&proofs[i].ClaimedValue), "wrong claimed value at ω^%d", i)
+			assert.True(expected.H.Equal(&proofs[i].H), "proof differs from Open at ω^%d", i)
+			point.Mul(&point, &domain.Generator)
+		}
+		assert.NoError(Verify(&digest, &proofs[3], *new(fr.Element).Exp(domain.Generator, big.NewInt(3)), testSrs.Vk))
+	}
+
+	_, err = fk.ComputeProofs(randomPolynomial(size + 1))
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func TestFK20ComputeMultiProofs(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 64
+	domain := fft.NewDomain(size)
+	p := randomPolynomial(size - 3)
+
+	for _, cosetSize := range []int{1, 2, 4, 32} {
+		fk, err := NewFK20(testSrs.Pk, domain, cosetSize)
+		assert.NoError(err)
+
+		proofs, err := fk.ComputeMultiProofs(p)
+		assert.NoError(err)
+		m := size / cosetSize
+		assert.Equal(m, len(proofs))
+
+		// the i-th proof commits to the quotient of p by Xˡ - ωⁱˡ
+		var c, psi fr.Element
+		psi.Exp(domain.Generator, big.NewInt(int64(cosetSize)))
+		c.SetOne()
+		for i := 0; i < m; i++ {
+			q := divideByXlMinusC(p, cosetSize, c)
+			expected, err := Commit(q, testSrs.Pk)
+			assert.NoError(err)
+			assert.True(expected.Equal(&proofs[i]), "wrong proof for coset %d of size %d", i, cosetSize)
+			c.Mul(&c, &psi)
+		}
+
+		if cosetSize != 1 {
+			_, err = fk.ComputeProofs(p)
+			assert.ErrorIs(err, ErrFK20NotSingle)
+		}
+	}
+
+	for _, cosetSize := range []int{0, 3, size} {
+		_, err := NewFK20(testSrs.Pk, domain, cosetSize)
+		assert.ErrorIs(err, ErrInvalidCosetSize)
+	}
+	_, err := NewFK20(ProvingKey{G1: testSrs.Pk.G1[:size-2]}, domain, 1)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func TestFK20VerifyCoset(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 64
+	domain := fft.NewDomain(size)
+	p := randomPolynomial(size - 3)
+	digest, err := Commit(p, testSrs.Pk)
+	assert.NoError(err)
+
+	// evaluations in natural order
+	evaluations := make([]fr.Element, size)
+	copy(evaluations, p)
+	domain.FFT(evaluations, fft.DIF)
+	fft.BitReverse(evaluations)
+
+	for _, cosetSize := range []int{1, 4, 16} {
+		fk, err := NewFK20(testSrs.Pk, domain, cosetSize)
+		assert.NoError(err)
+		proofs, err := fk.ComputeMultiProofs(p)
+		assert.NoError(err)
+
+		// [τˡ]G₂ with the known τ of the test SRS
+		var tauL big.Int
+		tauL.Exp(bAlpha, big.NewInt(int64(cosetSize)), fr.Modulus())
+		var g2TauL bls12377.G2Affine
+		g2TauL.ScalarMultiplication(&testSrs.Vk.G2[0], &tauL)
+		vk, err := NewCosetVerifyingKey(testSrs.Pk, testSrs.Vk, g2TauL, domain, cosetSize)
+		assert.NoError(err)
+
+		// the i-th coset holds the evaluations at ωⁱ⁺ʲᵐ
+		m := size / cosetSize
+		for i := 0; i < m; i++ {
+			coset := make([]fr.Element, cosetSize)
+			for j := range coset {
+				coset[j] = evaluations[i+j*m]
+			}
+			assert.NoError(VerifyCoset(&digest, &proofs[i], i, coset, vk), "coset %d of size %d", i, cosetSize)
+
+			coset[cosetSize-1].Add(&coset[cosetSize-1], new(fr.Element).SetOne())
+			assert.ErrorIs(VerifyCoset(&digest, &proofs[i], i, coset, vk), ErrVerifyOpeningProof, "tampered evaluation on coset %d of size %d", i, cosetSize)
+		}
+
+		assert.ErrorIs(VerifyCoset(&digest, &proofs[0], m, make([]fr.Element, cosetSize), vk), ErrInvalidCosetIndex)
+		assert.ErrorIs(VerifyCoset(&digest, &proofs[0], 0, make([]fr.Element, cosetSize+1), vk), ErrInvalidCosetSize)
+	}
+}
+
+// divideByXlMinusC returns the quotient of p by Xˡ - c.
+func divideByXlMinusC(p []fr.Element, l int, c fr.Element) []fr.Element {
+	if len(p) <= l {
+		return make([]fr.Element, 1)
+	}
+	r := make([]fr.Element, len(p))
+	copy(r, p)
+	q := make([]fr.Element, len(p)-l)
+	var tmp fr.Element
+	for j := len(p) - 1; j >= l; j-- {
+		q[j-l] = r[j]
+		tmp.Mul(&r[j], &c)
+		r[j-l].Add(&r[j-l], &tmp)
+	}
+	return q
+}
+
+func BenchmarkFK20(b *testing.B) {
+	const size = 256
+	domain := fft.NewDomain(size)
+	p := randomPolynomial(size)
+
+	b.Run("NewFK20", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = NewFK20(testSrs.Pk, domain, 1)
+		}
+	})
+
+	fk, err := NewFK20(testSrs.Pk, domain, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("ComputeProofs", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = fk.ComputeProofs(p)
+		}
+	})
+
+	b.Run("Open", func(b *testing.B) {
+		points := make([]fr.Element, size)
+		points[0].SetOne()
+		for i := 1; i < size; i++ {
+			points[i].Mul(&points[i-1], &domain.Generator)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for j := range points {
+				_, _ = Open(p, points[j], testSrs.Pk)
+			}
+		}
+	})
+}
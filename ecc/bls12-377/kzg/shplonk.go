@@ -0,0 +1,418 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/fiat-shamir"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidNbPoints               = errors.New("number of point sets is not the same as the number of polynomials")
+	ErrInvalidPointSet               = errors.New("a set of opening points is empty or contains duplicates")
+	ErrVerifyBatchOpeningMultiPoints = errors.New("can't verify batch opening proof at multiple points")
+)
+
+// MultiPointsOpeningProof opening proof for many polynomials, each opened on its own
+// set of points (Shplonk, see https://eprint.iacr.org/2020/081.pdf, section 4).
+//
+// Its size does not depend on the number of points (besides the claimed values).
+//
+// implements io.ReaderFrom and io.WriterTo
+type MultiPointsOpeningProof struct {
+	// W commitment to ∑ᵢγⁱ(fᵢ-rᵢ)/Z_{Sᵢ} where rᵢ interpolates fᵢ on Sᵢ
+	W bls12377.G1Affine
+
+	// WPrime commitment to L/(X-z) where L = ∑ᵢγⁱZ_{T∖Sᵢ}(z)(fᵢ-rᵢ(z)) - Z_T(z)W
+	WPrime bls12377.G1Affine
+
+	// ClaimedValues purported values, ClaimedValues[i][j] = fᵢ(points[i][j])
+	ClaimedValues [][]fr.Element
+}
+
+// BatchOpenMultiPoints creates a single opening proof of a list of polynomials, the i-th
+// polynomial being opened on the set of points points[i].
+// It's an interactive protocol, made non-interactive using Fiat Shamir.
+//
+// * polynomials is the list of polynomials to open
+// * digests is the list of committed polynomials to open, need to derive the challenges using Fiat Shamir.
+// * points[i] is the set of (distinct) points at which polynomials[i] is opened. The sets may overlap.
+// * dataTranscript extra data that might be needed to derive the challenges
+func BatchOpenMultiPoints(polynomials [][]fr.Element, digests []Digest, points [][]fr.Element, hf hash.Hash, pk ProvingKey, dataTranscript ...[]byte) (MultiPointsOpeningProof, error) {
+
+	// check for invalid sizes
+	nbPolynomials := len(polynomials)
+	if nbPolynomials != len(digests) {
+		return MultiPointsOpeningProof{}, ErrInvalidNbDigests
+	}
+	if nbPolynomials == 0 {
+		return MultiPointsOpeningProof{}, ErrZeroNbDigests
+	}
+	if nbPolynomials != len(points) {
+		return MultiPointsOpeningProof{}, ErrInvalidNbPoints
+	}
+	largestPoly := 0
+	for _, p := range polynomials {
+		if len(p) == 0 || len(p) > len(pk.G1) {
+			return MultiPointsOpeningProof{}, ErrInvalidPolynomialSize
+		}
+		if len(p) > largestPoly {
+			largestPoly = len(p)
+		}
+	}
+	allPoints, err := unionOfPoints(points)
+	if err != nil {
+		return MultiPointsOpeningProof{}, err
+	}
+
+	var res MultiPointsOpeningProof
+
+	// compute the purported values
+	res.ClaimedValues = make([][]fr.Element, nbPolynomials)
+	parallel.Execute(nbPolynomials, func(start, end int) {
+		for i := start; i < end; i++ {
+			res.ClaimedValues[i] = make([]fr.Element, len(points[i]))
+			for j := range points[i] {
+				res.ClaimedValues[i][j] = eval(polynomials[i], points[i][j])
+			}
+		}
+	})
+
+	// derive the challenge γ, binded to the points, the commitments and the claimed values
+	fs := fiatshamir.NewTranscript(hf, "gamma", "z")
+	gamma, err := deriveGammaMultiPoints(fs, digests, points, res.ClaimedValues, dataTranscript...)
+	if err != nil {
+		return MultiPointsOpeningProof{}, err
+	}
+
+	// (fᵢ-rᵢ)/Z_{Sᵢ} is the quotient of the euclidean division of fᵢ by Z_{Sᵢ}, obtained
+	// by dividing successively by (X-s) for s ∈ Sᵢ
+	quotients := make([][]fr.Element, nbPolynomials)
+	parallel.Execute(nbPolynomials, func(start, end int) {
+		for i := start; i < end; i++ {
+			q := make([]fr.Element, len(polynomials[i]))
+			copy(q, polynomials[i])
+			for _, s := range points[i] {
+				if len(q) == 0 {
+					break
+				}
+				q = dividePolyByXminusA(q, eval(q, s), s)
+			}
+			quotients[i] = q
+		}
+	})
+
+	// W = ∑ᵢγⁱ(fᵢ-rᵢ)/Z_{Sᵢ}
+	gammai := make([]fr.Element, nbPolynomials)
+	gammai[0].SetOne()
+	for i := 1; i < nbPolynomials; i++ {
+		gammai[i].Mul(&gammai[i-1], &gamma)
+	}
+	w := make([]fr.Element, largestPoly)
+	parallel.Execute(largestPoly, func(start, end int) {
+		var t fr.Element
+		for i := range quotients {
+			for j := start; j < end && j < len(quotients[i]); j++ {
+				t.Mul(&quotients[i][j], &gammai[i])
+				w[j].Add(&w[j], &t)
+			}
+		}
+	})
+	quotients = nil
+	if res.W, err = Commit(w, pk); err != nil {
+		return MultiPointsOpeningProof{}, err
+	}
+
+	// derive the challenge z, binded to W
+	z, err := deriveZMultiPoints(fs, &res.W)
+	if err != nil {
+		return MultiPointsOpeningProof{}, err
+	}
+
+	// L = ∑ᵢγⁱZ_{T∖Sᵢ}(z)(fᵢ-rᵢ(z)) - Z_T(z)W, which vanishes at z
+	zT, coeffs, constant := foldingCoefficients(allPoints, points, res.ClaimedValues, gammai, z)
+	l := make([]fr.Element, largestPoly)
+	parallel.Execute(largestPoly, func(start, end int) {
+		var t fr.Element
+		for j := start; j < end; j++ {
+			l[j].Mul(&w[j], &zT).Neg(&l[j])
+		}
+		for i := range polynomials {
+			for j := start; j < end && j < len(polynomials[i]); j++ {
+				t.Mul(&polynomials[i][j], &coeffs[i])
+				l[j].Add(&l[j], &t)
+			}
+		}
+	})
+	l[0].Sub(&l[0], &constant)
+
+	// W' = L/(X-z)
+	wPrime := dividePolyByXminusA(l, fr.Element{}, z)
+	if len(wPrime) == 0 {
+		// L is constant, hence zero, and so is W'
+		res.WPrime.X.SetZero()
+		res.WPrime.Y.SetZero()
+		return res, nil
+	}
+	if res.WPrime, err = Commit(wPrime, pk); err != nil {
+		return MultiPointsOpeningProof{}, err
+	}
+
+	return res, nil
+}
+
+// VerifyBatchMultiPoints verifies a batched opening proof of a list of polynomials,
+// the i-th polynomial being opened on the set of points points[i].
+//
+// * digests list of digests on which opening proof is done
+// * proof proof of correct opening on the digests
+// * points[i] set of points at which digests[i] is opened
+// * dataTranscript extra data that might be needed to derive the challenges
+func VerifyBatchMultiPoints(digests []Digest, proof *MultiPointsOpeningProof, points [][]fr.Element, hf hash.Hash, vk VerifyingKey, dataTranscript ...[]byte) error {
+
+	// check consistency between numbers of claims, points and digests
+	nbDigests := len(digests)
+	if nbDigests != len(proof.ClaimedValues) {
+		return ErrInvalidNbDigests
+	}
+	if nbDigests == 0 {
+		return ErrZeroNbDigests
+	}
+	if nbDigests != len(points) {
+		return ErrInvalidNbPoints
+	}
+	for i := range points {
+		if len(points[i]) != len(proof.ClaimedValues[i]) {
+			return ErrInvalidNbPoints
+		}
+	}
+	allPoints, err := unionOfPoints(points)
+	if err != nil {
+		return err
+	}
+
+	// derive the challenges γ and z
+	fs := fiatshamir.NewTranscript(hf, "gamma", "z")
+	gamma, err := deriveGammaMultiPoints(fs, digests, points, proof.ClaimedValues, dataTranscript...)
+	if err != nil {
+		return err
+	}
+	z, err := deriveZMultiPoints(fs, &proof.W)
+	if err != nil {
+		return err
+	}
+
+	gammai := make([]fr.Element, nbDigests)
+	gammai[0].SetOne()
+	for i := 1; i < nbDigests; i++ {
+		gammai[i].Mul(&gammai[i-1], &gamma)
+	}
+	zT, coeffs, constant := foldingCoefficients(allPoints, points, proof.ClaimedValues, gammai, z)
+
+	// F = ∑ᵢγⁱZ_{T∖Sᵢ}(z)[fᵢ(α)]G₁ - [∑ᵢγⁱZ_{T∖Sᵢ}(z)rᵢ(z)]G₁ - Z_T(z)[W]G₁ + z[W']G₁
+	//   = [L(α) + zW'(α)]G₁ = [αW'(α)]G₁
+	bases := make([]bls12377.G1Affine, nbDigests+3)
+	scalars := make([]fr.Element, nbDigests+3)
+	copy(bases, digests)
+	copy(scalars, coeffs)
+	bases[nbDigests] = vk.G1
+	scalars[nbDigests].Neg(&constant)
+	bases[nbDigests+1] = proof.W
+	scalars[nbDigests+1].Neg(&zT)
+	bases[nbDigests+2] = proof.WPrime
+	scalars[nbDigests+2] = z
+
+	var f bls12377.G1Affine
+	if _, err := f.MultiExp(bases, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+
+	// e(F, G₂).e(-[W'(α)]G₁, [α]G₂) == 1
+	var wPrimeNeg bls12377.G1Affine
+	wPrimeNeg.Neg(&proof.WPrime)
+	check, err := bls12377.PairingCheckFixedQ(
+		[]bls12377.G1Affine{f, wPrimeNeg},
+		vk.Lines[:],
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyBatchOpeningMultiPoints
+	}
+	return nil
+}
+
+// unionOfPoints returns T = ∪ᵢSᵢ, the union of the sets of opening points.
+// It returns an error if a set is empty or contains duplicates.
+func unionOfPoints(points [][]fr.Element) ([]fr.Element, error) {
+	var res []fr.Element
+	seen := make(map[fr.Element]struct{})
+	for i := range points {
+		if len(points[i]) == 0 {
+			return nil, ErrInvalidPointSet
+		}
+		set := make(map[fr.Element]struct{}, len(points[i]))
+		for _, s := range points[i] {
+			if _, ok := set[s]; ok {
+				return nil, ErrInvalidPointSet
+			}
+			set[s] = struct{}{}
+			if _, ok := seen[s]; !ok {
+				seen[s] = struct{}{}
+				res = append(res, s)
+			}
+		}
+	}
+	return res, nil
+}
+
+// foldingCoefficients returns, for T = ∪ᵢSᵢ:
+//
+// * Z_T(z)
+// * cᵢ = γⁱZ_{T∖Sᵢ}(z)
+// * ∑ᵢcᵢrᵢ(z) where rᵢ interpolates the claimed values of fᵢ on Sᵢ
+func foldingCoefficients(allPoints []fr.Element, points, claimedValues [][]fr.Element, gammai []fr.Element, z fr.Element) (zT fr.Element, coeffs []fr.Element, constant fr.Element) {
+	// z-t for t ∈ T
+	zMinusT := make(map[fr.Element]fr.Element, len(allPoints))
+	zT.SetOne()
+	for _, t := range allPoints {
+		var d fr.Element
+		d.Sub(&z, &t)
+		zMinusT[t] = d
+		zT.Mul(&zT, &d)
+	}
+
+	coeffs = make([]fr.Element, len(points))
+	parallel.Execute(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			set := make(map[fr.Element]struct{}, len(points[i]))
+			for _, s := range points[i] {
+				set[s] = struct{}{}
+			}
+			coeffs[i] = gammai[i]
+			for _, t := range allPoints {
+				if _, ok := set[t]; !ok {
+					d := zMinusT[t]
+					coeffs[i].Mul(&coeffs[i], &d)
+				}
+			}
+		}
+	})
+
+	var t fr.Element
+	for i := range points {
+		t = interpolateAt(points[i], claimedValues[i], z)
+		t.Mul(&t, &coeffs[i])
+		constant.Add(&constant, &t)
+	}
+
+	return
+}
+
+// interpolateAt returns r(z) where r is the polynomial of degree < len(points)
+// such that r(points[j]) = values[j]. The points must be distinct.
+func interpolateAt(points, values []fr.Element, z fr.Element) fr.Element {
+	// r(z) = ∑ⱼvⱼ∏_{k≠j}(z-sₖ)/(sⱼ-sₖ)
+	n := len(points)
+	num := make([]fr.Element, n)
+	den := make([]fr.Element, n)
+	var t fr.Element
+	for j := 0; j < n; j++ {
+		num[j] = values[j]
+		den[j].SetOne()
+		for k := 0; k < n; k++ {
+			if k == j {
+				continue
+			}
+			t.Sub(&z, &points[k])
+			num[j].Mul(&num[j], &t)
+			t.Sub(&points[j], &points[k])
+			den[j].Mul(&den[j], &t)
+		}
+	}
+	den = fr.BatchInvert(den)
+
+	var res fr.Element
+	for j := 0; j < n; j++ {
+		t.Mul(&num[j], &den[j])
+		res.Add(&res, &t)
+	}
+	return res
+}
+
+// deriveGammaMultiPoints derives the challenge γ using Fiat Shamir, binded to the
+// commitments, the opening points and the claimed values.
+func deriveGammaMultiPoints(fs *fiatshamir.Transcript, digests []Digest, points, claimedValues [][]fr.Element, dataTranscript ...[]byte) (fr.Element, error) {
+	var nbPoints [8]byte
+	for i := range digests {
+		if err := fs.Bind("gamma", digests[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+		// bind the size of the set of points, to separate the sets
+		binary.BigEndian.PutUint64(nbPoints[:], uint64(len(points[i])))
+		if err := fs.Bind("gamma", nbPoints[:]); err != nil {
+			return fr.Element{}, err
+		}
+		for j := range points[i] {
+			if err := fs.Bind("gamma", points[i][j].Marshal()); err != nil {
+				return fr.Element{}, err
+			}
+			if err := fs.Bind("gamma", claimedValues[i][j].Marshal()); err != nil {
+				return fr.Element{}, err
+			}
+		}
+	}
+
+	for i := 0; i < len(dataTranscript); i++ {
+		if err := fs.Bind("gamma", dataTranscript[i]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+
+	gammaByte, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var gamma fr.Element
+	gamma.SetBytes(gammaByte)
+
+	return gamma, nil
+}
+
+// deriveZMultiPoints derives the challenge z using Fiat Shamir, binded to γ and W.
+func deriveZMultiPoints(fs *fiatshamir.Transcript, w *bls12377.G1Affine) (fr.Element, error) {
+	if err := fs.Bind("z", w.Marshal()); err != nil {
+		return fr.Element{}, err
+	}
+	zByte, err := fs.ComputeChallenge("z")
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var z fr.Element
+	z.SetBytes(zByte)
+
+	return z, nil
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
 	"github.com/consensys/gnark-crypto/internal/parallel"
 )
 
@@ -39,13 +40,44 @@ func ToLagrangeG1(coeffs []curve.G1Affine) ([]curve.G1Affine, error) {
 	}
 	size := len(coeffs)
 
-	numCPU := uint64(runtime.NumCPU())
-	maxSplits := bits.TrailingZeros64(ecc.NextPowerOfTwo(numCPU)) << 1
-
-	twiddlesInv, err := computeTwiddlesInv(size)
+	generator, err := fr.Generator(uint64(size))
 	if err != nil {
 		return nil, err
 	}
+	generator.Inverse(&generator)
+
+	var cardinalityInv fr.Element
+	cardinalityInv.SetUint64(uint64(size))
+	cardinalityInv.Inverse(&cardinalityInv)
+
+	return toLagrangeG1(coeffs, generator, cardinalityInv), nil
+}
+
+// ToLagrange returns the proving key in Lagrange form on domain, that is
+// [L₀(τ)]G₁, [L₁(τ)]G₁, ..., [Lₙ₋₁(τ)]G₁ where Lᵢ is the i-th Lagrange polynomial
+// on the subgroup of size n = domain.Cardinality generated by domain.Generator.
+//
+// Only the first n points of pk are used. The result is a regular ProvingKey,
+// meant to be used with CommitLagrange and OpenLagrange.
+func (pk *ProvingKey) ToLagrange(domain *fft.Domain) (ProvingKey, error) {
+	size := domain.Cardinality
+	if size < 2 || uint64(len(pk.G1)) < size {
+		return ProvingKey{}, ErrInvalidPolynomialSize
+	}
+	return ProvingKey{
+		G1: toLagrangeG1(pk.G1[:size], domain.GeneratorInv, domain.CardinalityInv),
+	}, nil
+}
+
+// toLagrangeG1 computes 1/n⋅FFT_inv(coeffs) where the inverse fft uses
+// generatorInv, an n-th root of unity.
+func toLagrangeG1(coeffs []curve.G1Affine, generatorInv, cardinalityInv fr.Element) []curve.G1Affine {
+	size := len(coeffs)
+
+	numCPU := uint64(runtime.NumCPU())
+	maxSplits := bits.TrailingZeros64(ecc.NextPowerOfTwo(numCPU)) << 1
+
+	twiddlesInv := computeTwiddles(generatorInv, size)
 
 	// batch convert to Jacobian
 	jCoeffs := make([]curve.G1Jac, len(coeffs))
@@ -59,10 +91,7 @@ func ToLagrangeG1(coeffs []curve.G1Affine) ([]curve.G1Affine, error) {
 	bitReverse(jCoeffs)
 
 	var invBigint big.Int
-	var frCardinality fr.Element
-	frCardinality.SetUint64(uint64(size))
-	frCardinality.Inverse(&frCardinality)
-	frCardinality.BigInt(&invBigint)
+	cardinalityInv.BigInt(&invBigint)
 
 	parallel.Execute(size, func(start, end int) {
 		for i := start; i < end; i++ {
@@ -71,18 +100,11 @@ func ToLagrangeG1(coeffs []curve.G1Affine) ([]curve.G1Affine, error) {
 	})
 
 	// batch convert to affine
-	return curve.BatchJacobianToAffineG1(jCoeffs), nil
+	return curve.BatchJacobianToAffineG1(jCoeffs)
 }
 
-func computeTwiddlesInv(cardinality int) ([]*big.Int, error) {
-	generator, err := fr.Generator(uint64(cardinality))
-	if err != nil {
-		return nil, err
-	}
-
-	// inverse the generator
-	generator.Inverse(&generator)
-
+// computeTwiddles returns the powers of generator used by difFFTG1
+func computeTwiddles(generator fr.Element, cardinality int) []*big.Int {
 	// nb fft stages
 	nbStages := uint64(bits.TrailingZeros64(uint64(cardinality)))
 
@@ -91,7 +113,7 @@ func computeTwiddlesInv(cardinality int) ([]*big.Int, error) {
 	w := generator
 	r[0] = new(big.Int).SetUint64(1)
 	if len(r) == 1 {
-		return r, nil
+		return r
 	}
 	r[1] = new(big.Int)
 	w.BigInt(r[1])
@@ -101,7 +123,7 @@ func computeTwiddlesInv(cardinality int) ([]*big.Int, error) {
 		w.BigInt(r[j])
 	}
 
-	return r, nil
+	return r
 }
 
 func bitReverse[T any](a []T) {
@@ -264,6 +264,107 @@ func TestEddsaSHA256(t *testing.T) {
 
 }
 
+func TestBatchVerify(t *testing.T) {
+	t.Parallel()
+	const n = 16
+	hFunc := sha256.New()
+	publicKeys := make([]PublicKey, n)
+	signatures := make([][]byte, n)
+	messages := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, err := GenerateKey(crand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKeys[i] = privKey.PublicKey
+		messages[i] = []byte{byte(i)}
+		if signatures[i], err = privKey.Sign(messages[i], hFunc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, opts := range [][]BatchOption{nil, {WithCofactorlessEquation()}} {
+		valid, err := BatchVerify(publicKeys, signatures, messages, hFunc, opts...)
+		if err != nil || !valid {
+			t.Fatal("valid batch should verify")
+		}
+	}
+
+	tampered := append([][]byte{}, messages...)
+	tampered[n/2] = []byte("tampered")
+	for _, opts := range [][]BatchOption{nil, {WithCofactorlessEquation()}} {
+		valid, err := BatchVerify(publicKeys, signatures, tampered, hFunc, opts...)
+		if err != nil || valid {
+			t.Fatal("batch with an invalid signature should not verify")
+		}
+	}
+
+	if _, err := BatchVerify(publicKeys, signatures[1:], messages, hFunc); err != errInvalidBatch {
+		t.Fatal("batch with mismatched lengths should fail")
+	}
+}
+
+func TestBatchVerifyTorsion(t *testing.T) {
+	t.Parallel()
+	hFunc := sha256.New()
+	curveParams := twistededwards.GetEdwardsCurve()
+	privKey, err := GenerateKey(crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("small order component")
+
+	// sign with a nonce commitment R' = R + T, where T = (0, -1) has order 2
+	r, err := crand.Int(crand.Reader, &curveParams.Order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sig Signature
+	var torsion twistededwards.PointAffine
+	torsion.Y.SetOne().Neg(&torsion.Y)
+	sig.R.ScalarMultiplication(&curveParams.Base, r).
+		Add(&sig.R, &torsion)
+
+	hFunc.Reset()
+	sigRX := sig.R.X.Bytes()
+	sigRY := sig.R.Y.Bytes()
+	sigAX := privKey.PublicKey.A.X.Bytes()
+	sigAY := privKey.PublicKey.A.Y.Bytes()
+	for _, bytes := range [][]byte{sigRX[:], sigRY[:], sigAX[:], sigAY[:], msg} {
+		hFunc.Write(bytes)
+	}
+	var k, s big.Int
+	k.SetBytes(hFunc.Sum(nil))
+	s.SetBytes(privKey.scalar[:])
+	s.Mul(&s, &k).Add(&s, r).Mod(&s, &curveParams.Order)
+	s.FillBytes(sig.S[:])
+	sigBin := sig.Bytes()
+
+	// Verify and BatchVerify agree by default
+	valid, err := privKey.PublicKey.Verify(sigBin, msg, hFunc)
+	if err != nil || !valid {
+		t.Fatal("Verify should accept a signature with a small order component")
+	}
+	valid, err = BatchVerify([]PublicKey{privKey.PublicKey}, [][]byte{sigBin}, [][]byte{msg}, hFunc)
+	if err != nil || !valid {
+		t.Fatal("BatchVerify should accept a signature with a small order component")
+	}
+
+	// the cofactorless equation rejects it when the random coefficient is
+	// odd, so with high probability in a few trials
+	rejected := false
+	for i := 0; i < 16 && !rejected; i++ {
+		valid, err = BatchVerify([]PublicKey{privKey.PublicKey}, [][]byte{sigBin}, [][]byte{msg}, hFunc, WithCofactorlessEquation())
+		if err != nil {
+			t.Fatal(err)
+		}
+		rejected = !valid
+	}
+	if !rejected {
+		t.Fatal("cofactorless BatchVerify should reject a signature with a small order component")
+	}
+}
+
 // benchmarks
 
 func BenchmarkVerify(b *testing.B) {
@@ -289,3 +390,22 @@ func BenchmarkVerify(b *testing.B) {
 		pubKey.Verify(signature, msgBin[:], hFunc)
 	}
 }
+
+func BenchmarkBatchVerify64(b *testing.B) {
+	const n = 64
+	hFunc := sha256.New()
+	publicKeys := make([]PublicKey, n)
+	signatures := make([][]byte, n)
+	messages := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, _ := GenerateKey(crand.Reader)
+		publicKeys[i] = privKey.PublicKey
+		messages[i] = []byte{byte(i)}
+		signatures[i], _ = privKey.Sign(messages[i], hFunc)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchVerify(publicKeys, signatures, messages, hFunc)
+	}
+}
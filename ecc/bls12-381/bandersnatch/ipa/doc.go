@@ -0,0 +1,32 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ipa implements the polynomial commitment scheme of Ethereum Verkle
+// trees: Pedersen vector commitments over banderwagon, opened with a
+// Bulletproofs-style inner product argument (IPA).
+//
+// Banderwagon is the prime order subgroup of bandersnatch, quotiented by the
+// 2-torsion point (0, -1), see [Element]. Polynomials have degree less than
+// DomainSize = 256 and are given by their evaluations on 0, 1, ..., 255. A
+// [MultiProof] opens any number of commitments at points of the domain.
+//
+// The generators, the Fiat-Shamir transcript and the serialization follow
+// go-ipa, so that commitments and proofs are compatible with go-verkle and
+// rust-verkle.
+//
+// Documentation:
+//   - banderwagon: https://hackmd.io/@6iQDuIePQjyYBqDChYw_jg/BJ2-L6Nzc
+//   - multiproofs: https://dankradfeist.de/ethereum/2021/06/18/pcs-multiproofs.html
+//   - go-ipa: https://github.com/crate-crypto/go-ipa
+package ipa
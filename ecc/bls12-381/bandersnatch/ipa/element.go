@@ -0,0 +1,142 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	fp "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// SizeElement size in bytes of a serialized Element
+const SizeElement = fp.Bytes
+
+var (
+	ErrInvalidEncoding = errors.New("invalid element encoding")
+	ErrNotInSubgroup   = errors.New("point is not in the banderwagon subgroup")
+)
+
+var curve = bandersnatch.GetEdwardsCurve()
+
+// Element is an element of the banderwagon group, the prime order subgroup
+// of bandersnatch quotiented by the 2-torsion point (0, -1). A point (x, y)
+// and (-x, -y) represent the same Element.
+type Element struct {
+	point bandersnatch.PointAffine
+}
+
+// Bytes returns the 32 bytes big-endian encoding of x⋅sign(y), where sign(y)
+// is -1 if y is not lexicographically largest. It does not depend on the
+// representative of e.
+func (e *Element) Bytes() [SizeElement]byte {
+	x := e.point.X
+	if !e.point.Y.LexicographicallyLargest() {
+		x.Neg(&x)
+	}
+	return x.Bytes()
+}
+
+// SetBytes sets e from its encoding, as returned by Bytes. It returns an error
+// if buf is not the canonical encoding of an element of the subgroup.
+func (e *Element) SetBytes(buf []byte) error {
+	if len(buf) != SizeElement {
+		return ErrInvalidEncoding
+	}
+	var x fp.Element
+	if err := x.SetBytesCanonical(buf); err != nil {
+		return ErrInvalidEncoding
+	}
+
+	// the points of the subgroup are the ones for which 1 - ax² is a square
+	var one, ax2, num, den fp.Element
+	one.SetOne()
+	ax2.Square(&x)
+	den.Mul(&ax2, &curve.D)
+	ax2.Mul(&ax2, &curve.A)
+	num.Sub(&one, &ax2)
+	if num.Legendre() != 1 {
+		return ErrNotInSubgroup
+	}
+
+	// y² = (1 - ax²) / (1 - dx²)
+	den.Sub(&one, &den)
+	num.Div(&num, &den)
+	var y fp.Element
+	if y.Sqrt(&num) == nil {
+		return ErrInvalidEncoding
+	}
+	if !y.LexicographicallyLargest() {
+		y.Neg(&y)
+	}
+	e.point.X = x
+	e.point.Y = y
+	return nil
+}
+
+// Equal returns true if e and e1 are the same element of the group.
+func (e *Element) Equal(e1 *Element) bool {
+	var lhs, rhs fp.Element
+	lhs.Mul(&e.point.X, &e1.point.Y)
+	rhs.Mul(&e1.point.X, &e.point.Y)
+	return lhs.Equal(&rhs)
+}
+
+// IsZero returns true if e is the neutral element.
+func (e *Element) IsZero() bool {
+	return e.point.X.IsZero()
+}
+
+// MapToScalarField returns x/y reduced modulo the order of the group. It is
+// used by Verkle trees to commit to commitments.
+func (e *Element) MapToScalarField() fr.Element {
+	var xy fp.Element
+	xy.Div(&e.point.X, &e.point.Y)
+	b := xy.Bytes()
+	var res fr.Element
+	res.SetBytes(b[:])
+	return res
+}
+
+// Point returns a representative of e on the bandersnatch curve.
+func (e *Element) Point() bandersnatch.PointAffine {
+	return e.point
+}
+
+func (e *Element) fromExtended(p *bandersnatch.PointExtended) *Element {
+	e.point.FromExtended(p)
+	return e
+}
+
+// multiExp returns ∑ᵢ scalars[i]⋅points[i].
+func multiExp(points []Element, scalars []fr.Element) Element {
+	bases := make([]bandersnatch.PointAffine, len(points))
+	for i := range points {
+		bases[i] = points[i].point
+	}
+	bigScalars := make([]big.Int, len(scalars))
+	for i := range scalars {
+		scalars[i].BigInt(&bigScalars[i])
+	}
+	var res bandersnatch.PointExtended
+	if _, err := res.MultiExp(bases, bigScalars, ecc.MultiExpConfig{}); err != nil {
+		panic("multiExp: " + err.Error())
+	}
+	var e Element
+	return *e.fromExtended(&res)
+}
@@ -0,0 +1,316 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	fp "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+const (
+	// DomainSize number of evaluations of a committed polynomial, on the
+	// points 0, 1, ..., DomainSize-1
+	DomainSize = 256
+
+	// nbRounds number of halving rounds of the inner product argument
+	nbRounds = 8
+
+	// srsSeed seed of the generators of the Verkle trees commitment scheme
+	srsSeed = "eth_verkle_oct_2021"
+)
+
+var (
+	ErrInvalidPolynomialSize = errors.New("polynomial must have at most DomainSize evaluations")
+	ErrVerifyOpeningProof    = errors.New("can't verify opening proof")
+)
+
+// Config holds the public parameters of the commitment scheme: the generators
+// Gᵢ of the Pedersen vector commitments, the point Q binding the inner
+// product, and the barycentric weights of the domain 0, 1, ..., DomainSize-1.
+type Config struct {
+	srs []Element
+	q   Element
+
+	// weights[i] = A'(i) = ∏_{j≠i}(i-j), where A = ∏ⱼ(X-j)
+	weights    []fr.Element
+	weightsInv []fr.Element
+
+	// inverses[i] = 1/i, for 0 < i < DomainSize
+	inverses []fr.Element
+}
+
+// Proof inner product argument proving that ⟨a, b⟩ = y, where C = ∑ aᵢGᵢ and
+// b is a public vector.
+type Proof struct {
+	L, R [nbRounds]Element
+	A    fr.Element
+}
+
+// NewConfig returns the parameters used by Verkle trees. The generators are
+// obtained by hashing srsSeed with SHA-256 until DomainSize valid encodings of
+// elements are found.
+func NewConfig() *Config {
+	conf := &Config{
+		srs:        make([]Element, 0, DomainSize),
+		weights:    make([]fr.Element, DomainSize),
+		weightsInv: make([]fr.Element, DomainSize),
+		inverses:   make([]fr.Element, DomainSize),
+	}
+
+	for i := uint64(0); len(conf.srs) < DomainSize; i++ {
+		h := sha256.New()
+		h.Write([]byte(srsSeed))
+		var counter [8]byte
+		binary.BigEndian.PutUint64(counter[:], i)
+		h.Write(counter[:])
+		var x fp.Element
+		x.SetBytes(h.Sum(nil))
+		xBytes := x.Bytes()
+		var e Element
+		if err := e.SetBytes(xBytes[:]); err != nil {
+			continue
+		}
+		conf.srs = append(conf.srs, e)
+	}
+	conf.q.point = curve.Base
+
+	var tmp fr.Element
+	for i := 0; i < DomainSize; i++ {
+		conf.weights[i].SetOne()
+		for j := 0; j < DomainSize; j++ {
+			if j != i {
+				tmp.SetInt64(int64(i - j))
+				conf.weights[i].Mul(&conf.weights[i], &tmp)
+			}
+		}
+		conf.inverses[i].SetUint64(uint64(i))
+	}
+	conf.weightsInv = fr.BatchInvert(conf.weights)
+	conf.inverses = fr.BatchInvert(conf.inverses)
+
+	return conf
+}
+
+// Commit returns the Pedersen commitment ∑ pᵢGᵢ to the polynomial p, given by
+// its evaluations on 0, 1, ..., len(p)-1.
+func Commit(p []fr.Element, conf *Config) (Element, error) {
+	if len(p) > DomainSize {
+		return Element{}, ErrInvalidPolynomialSize
+	}
+	return multiExp(conf.srs[:len(p)], p), nil
+}
+
+// barycentricCoefficients returns the vector b such that ⟨p, b⟩ = p(z) for
+// any polynomial p of degree < DomainSize given by its evaluations on the
+// domain: bᵢ = A(z) / (A'(i)(z-i)).
+func (conf *Config) barycentricCoefficients(z fr.Element) []fr.Element {
+	b := make([]fr.Element, DomainSize)
+	if z.IsUint64() && z.Uint64() < DomainSize {
+		b[z.Uint64()].SetOne()
+		return b
+	}
+
+	var az, zi fr.Element
+	az.SetOne()
+	for i := range b {
+		zi.SetUint64(uint64(i))
+		zi.Sub(&z, &zi)
+		az.Mul(&az, &zi)
+		b[i].Mul(&zi, &conf.weights[i])
+	}
+	b = fr.BatchInvert(b)
+	for i := range b {
+		b[i].Mul(&b[i], &az)
+	}
+	return b
+}
+
+// divideOnDomain returns the evaluations on the domain of the quotient
+// (p - p(m)) / (X - m), for m in the domain.
+//
+// For i ≠ m, qᵢ = (pᵢ - pₘ) / (i - m), and qₘ = -∑_{i≠m} A'(m)/A'(i)⋅qᵢ.
+func (conf *Config) divideOnDomain(p []fr.Element, m int) []fr.Element {
+	q := make([]fr.Element, DomainSize)
+	var tmp fr.Element
+	for i := range q {
+		if i == m {
+			continue
+		}
+		q[i].Sub(&p[i], &p[m])
+		if i > m {
+			q[i].Mul(&q[i], &conf.inverses[i-m])
+		} else {
+			q[i].Mul(&q[i], &conf.inverses[m-i]).Neg(&q[i])
+		}
+		tmp.Mul(&q[i], &conf.weightsInv[i])
+		q[m].Sub(&q[m], &tmp)
+	}
+	q[m].Mul(&q[m], &conf.weights[m])
+	return q
+}
+
+// createProof returns an inner product argument that the polynomial a,
+// committed to in commitment, evaluates to ⟨a, b(z)⟩ at z.
+func createProof(transcript *Transcript, conf *Config, commitment *Element, a []fr.Element, z fr.Element) Proof {
+	b := conf.barycentricCoefficients(z)
+	y := innerProduct(a, b)
+
+	transcript.domainSep("ipa")
+	transcript.appendPoint(commitment, "C")
+	transcript.appendScalar(&z, "input point")
+	transcript.appendScalar(&y, "output point")
+	w := transcript.challengeScalar("w")
+	q := scalarMul(&conf.q, &w)
+
+	a = append([]fr.Element{}, a...)
+	basis := conf.srs
+	var proof Proof
+	for i := 0; i < nbRounds; i++ {
+		half := len(a) / 2
+		aL, aR := a[:half], a[half:]
+		bL, bR := b[:half], b[half:]
+		gL, gR := basis[:half], basis[half:]
+
+		zL := innerProduct(aR, bL)
+		zR := innerProduct(aL, bR)
+		proof.L[i] = multiExp(append(gL[:half:half], q), append(aR[:half:half], zL))
+		proof.R[i] = multiExp(append(gR[:half:half], q), append(aL[:half:half], zR))
+
+		transcript.appendPoint(&proof.L[i], "L")
+		transcript.appendPoint(&proof.R[i], "R")
+		x := transcript.challengeScalar("x")
+		var xInv fr.Element
+		xInv.Inverse(&x)
+
+		a = foldScalars(aL, aR, &x)
+		b = foldScalars(bL, bR, &xInv)
+		basis = foldPoints(gL, gR, &xInv)
+	}
+	proof.A = a[0]
+	return proof
+}
+
+// verifyProof checks that proof is a valid inner product argument that the
+// polynomial committed to in commitment evaluates to y at z.
+//
+// The folded generator is G = ∑ⱼ sⱼGⱼ, with sⱼ the product of the xᵢ⁻¹ for
+// the rounds i that kept the right half of index j. The verifier checks
+//
+//	C + ∑ᵢ(xᵢLᵢ + xᵢ⁻¹Rᵢ) + y⋅wQ = a⋅G + a⋅b⋅wQ
+//
+// with a single multi-exponentiation.
+func verifyProof(transcript *Transcript, conf *Config, commitment *Element, proof *Proof, z, y fr.Element) bool {
+	b := conf.barycentricCoefficients(z)
+
+	transcript.domainSep("ipa")
+	transcript.appendPoint(commitment, "C")
+	transcript.appendScalar(&z, "input point")
+	transcript.appendScalar(&y, "output point")
+	w := transcript.challengeScalar("w")
+
+	x := make([]fr.Element, nbRounds)
+	for i := range x {
+		transcript.appendPoint(&proof.L[i], "L")
+		transcript.appendPoint(&proof.R[i], "R")
+		x[i] = transcript.challengeScalar("x")
+	}
+	xInv := fr.BatchInvert(x)
+
+	// the first round splits on the most significant bit of the index
+	s := make([]fr.Element, 1, DomainSize)
+	s[0].SetOne()
+	for i := nbRounds - 1; i >= 0; i-- {
+		n := len(s)
+		s = s[:2*n]
+		for j := 0; j < n; j++ {
+			s[n+j].Mul(&s[j], &xInv[i])
+		}
+	}
+	foldedB := innerProduct(s, b)
+
+	points := make([]Element, 0, DomainSize+2+2*nbRounds)
+	scalars := make([]fr.Element, 0, cap(points))
+	points = append(points, conf.srs...)
+	for j := range s {
+		s[j].Mul(&s[j], &proof.A)
+	}
+	scalars = append(scalars, s...)
+
+	var minusOne, tmp fr.Element
+	minusOne.SetOne().Neg(&minusOne)
+	tmp.Mul(&proof.A, &foldedB).Sub(&tmp, &y).Mul(&tmp, &w)
+	points = append(points, conf.q, *commitment)
+	scalars = append(scalars, tmp, minusOne)
+	for i := 0; i < nbRounds; i++ {
+		points = append(points, proof.L[i], proof.R[i])
+		scalars = append(scalars, *tmp.Neg(&x[i]), *new(fr.Element).Neg(&xInv[i]))
+	}
+
+	res := multiExp(points, scalars)
+	return res.IsZero()
+}
+
+func innerProduct(a, b []fr.Element) fr.Element {
+	var res, tmp fr.Element
+	for i := range a {
+		tmp.Mul(&a[i], &b[i])
+		res.Add(&res, &tmp)
+	}
+	return res
+}
+
+// foldScalars returns l + x⋅r
+func foldScalars(l, r []fr.Element, x *fr.Element) []fr.Element {
+	res := make([]fr.Element, len(l))
+	for i := range res {
+		res[i].Mul(&r[i], x).Add(&res[i], &l[i])
+	}
+	return res
+}
+
+// foldPoints returns l + x⋅r
+func foldPoints(l, r []Element, x *fr.Element) []Element {
+	var s big.Int
+	x.BigInt(&s)
+	res := make([]Element, len(l))
+	parallel.Execute(len(res), func(start, end int) {
+		var pl, pr bandersnatch.PointExtended
+		for i := start; i < end; i++ {
+			pl.FromAffine(&l[i].point)
+			pr.FromAffine(&r[i].point)
+			pr.ScalarMultiplication(&pr, &s)
+			pl.Add(&pl, &pr)
+			res[i].fromExtended(&pl)
+		}
+	})
+	return res
+}
+
+func scalarMul(e *Element, s *fr.Element) Element {
+	var b big.Int
+	s.BigInt(&b)
+	var p bandersnatch.PointExtended
+	p.FromAffine(&e.point)
+	p.ScalarMultiplication(&p, &b)
+	var res Element
+	return *res.fromExtended(&p)
+}
@@ -0,0 +1,364 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	fp "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+var testConf = NewConfig()
+
+// testPolynomial returns the evaluations 1, 2, ..., 32 repeated 8 times, or
+// 32, 31, ..., 1 if reversed, used by the go-ipa consistency tests.
+func testPolynomial(reversed bool) []fr.Element {
+	p := make([]fr.Element, DomainSize)
+	for i := range p {
+		if reversed {
+			p[i].SetUint64(uint64(32 - i%32))
+		} else {
+			p[i].SetUint64(uint64(i%32 + 1))
+		}
+	}
+	return p
+}
+
+func randomPolynomial() []fr.Element {
+	p := make([]fr.Element, DomainSize)
+	for i := range p {
+		p[i].SetRandom()
+	}
+	return p
+}
+
+func hexBytes(b []byte) string {
+	return hex.EncodeToString(b)
+}
+
+func hexLittleEndian(s fr.Element) string {
+	var b [fr.Bytes]byte
+	fr.LittleEndian.PutElement(&b, s)
+	return hexBytes(b[:])
+}
+
+func TestConfigConsistency(t *testing.T) {
+	first, last := testConf.srs[0].Bytes(), testConf.srs[DomainSize-1].Bytes()
+	if hexBytes(first[:]) != "01587ad1336675eb912550ec2a28eb8923b824b490dd2ba82e48f14590a298a0" {
+		t.Fatal("wrong first generator")
+	}
+	if hexBytes(last[:]) != "3de2be346b539395b0c0de56a5ccca54a317f1b5c80107b0802af9a62276a4d8" {
+		t.Fatal("wrong last generator")
+	}
+	h := sha256.New()
+	for i := range testConf.srs {
+		b := testConf.srs[i].Bytes()
+		h.Write(b[:])
+	}
+	if hexBytes(h.Sum(nil)) != "1fcaea10bf24f750200e06fa473c76ff0468007291fa548e2d99f09ba9256fdb" {
+		t.Fatal("wrong generators")
+	}
+}
+
+func TestIPAConsistency(t *testing.T) {
+	// go-ipa TestIPAConsistencySimpleProof
+	p := testPolynomial(false)
+	commitment, err := Commit(p, testConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := commitment.Bytes()
+	if hexBytes(b[:]) != "1b9dff8f5ebbac250d291dfe90e36283a227c64b113c37f1bfb9e7a743cdb128" {
+		t.Fatal("wrong commitment")
+	}
+
+	z := fr.NewElement(2101)
+	y := innerProduct(p, testConf.barycentricCoefficients(z))
+	if hexLittleEndian(y) != "4a353e70b03c89f161de002e8713beec0d740a5e20722fd5bd68b30540a33208" {
+		t.Fatal("wrong evaluation")
+	}
+
+	transcript := NewTranscript("test")
+	proof := createProof(transcript, testConf, &commitment, p, z)
+	if hexLittleEndian(transcript.challengeScalar("state")) != "0a81881cbfd7d7197a54ebd67ed6a68b5867f3c783706675b34ece43e85e7306" {
+		t.Fatal("wrong transcript state after the proof")
+	}
+	if !verifyProof(NewTranscript("test"), testConf, &commitment, &proof, z, y) {
+		t.Fatal("failed to verify the proof")
+	}
+	y.Add(&y, new(fr.Element).SetOne())
+	if verifyProof(NewTranscript("test"), testConf, &commitment, &proof, z, y) {
+		t.Fatal("verified a proof of a wrong evaluation")
+	}
+}
+
+func TestMultiProofConsistency(t *testing.T) {
+	// go-ipa TestMultiProofConsistency
+	polynomials := [][]fr.Element{testPolynomial(false), testPolynomial(true)}
+	commitments := make([]Element, len(polynomials))
+	for i := range polynomials {
+		var err error
+		if commitments[i], err = Commit(polynomials[i], testConf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	points := []uint8{0, 0}
+
+	transcript := NewTranscript("test")
+	proof, err := CreateMultiProof(polynomials, commitments, points, transcript, testConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hexLittleEndian(transcript.challengeScalar("state")) != "eee8a80357ff74b766eba39db90797d022e8d6dee426ded71234241be504d519" {
+		t.Fatal("wrong transcript state after the proof")
+	}
+
+	// serialized bytes of go-ipa
+	const expected = "4f53588244efaf07a370ee3f9c467f933eed360d4fbf7a19dfc8bc49b67df4711bf1d0a720717cd6a8c75f1a668cb7cbdd63b48c676b89a7aee4298e71bd7f4013d7657146aa9736817da47051ed6a45fc7b5a61d00eb23e5df82a7f285cc10e67d444e91618465ca68d8ae4f2c916d1942201b7e2aae491ef0f809867d00e83468fb7f9af9b42ede76c1e90d89dd789ff22eb09e8b1d062d8a58b6f88b3cbe80136fc68331178cd45a1df9496ded092d976911b5244b85bc3de41e844ec194256b39aeee4ea55538a36139211e9910ad6b7a74e75d45b869d0a67aa4bf600930a5f760dfb8e4df9938d1f47b743d71c78ba8585e3b80aba26d24b1f50b36fa1458e79d54c05f58049245392bc3e2b5c5f9a1b99d43ed112ca82b201fb143d401741713188e47f1d6682b0bf496a5d4182836121efff0fd3b030fc6bfb5e21d6314a200963fe75cb856d444a813426b2084dfdc49dca2e649cb9da8bcb47859a4c629e97898e3547c591e39764110a224150d579c33fb74fa5eb96427036899c04154feab5344873d36a53a5baefd78c132be419f3f3a8dd8f60f72eb78dd5f43c53226f5ceb68947da3e19a750d760fb31fa8d4c7f53bfef11c4b89158aa56b1f4395430e16a3128f88e234ce1df7ef865f2d2c4975e8c82225f578310c31fd41d265fd530cbfa2b8895b228a510b806c31dff3b1fa5c08bffad443d567ed0e628febdd22775776e0cc9cebcaea9c6df9279a5d91dd0ee5e7a0434e989a160005321c97026cb559f71db23360105460d959bcdf74bee22c4ad8805a1d497507"
+	var buf bytes.Buffer
+	if _, err = proof.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if hexBytes(buf.Bytes()) != expected {
+		t.Fatal("serialized proof differs from go-ipa")
+	}
+
+	raw, err := hex.DecodeString(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var read MultiProof
+	if n, err := read.ReadFrom(bytes.NewReader(raw)); err != nil || n != SizeMultiProof {
+		t.Fatal("failed to read the go-ipa proof", n, err)
+	}
+	buf.Reset()
+	if _, err = read.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if hexBytes(buf.Bytes()) != expected {
+		t.Fatal("go-ipa proof should round-trip")
+	}
+
+	evaluations := []fr.Element{polynomials[0][0], polynomials[1][0]}
+	if err = VerifyMultiProof(&read, commitments, points, evaluations, NewTranscript("test"), testConf); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMultiProof(t *testing.T) {
+	const nbQueries = 10
+	polynomials := make([][]fr.Element, nbQueries)
+	commitments := make([]Element, nbQueries)
+	points := make([]uint8, nbQueries)
+	evaluations := make([]fr.Element, nbQueries)
+	for i := range polynomials {
+		polynomials[i] = randomPolynomial()
+		var err error
+		if commitments[i], err = Commit(polynomials[i], testConf); err != nil {
+			t.Fatal(err)
+		}
+		// several queries share the same point
+		points[i] = uint8(i * 97 % 7 * 40)
+		evaluations[i] = polynomials[i][points[i]]
+	}
+
+	proof, err := CreateMultiProof(polynomials, commitments, points, NewTranscript("vt"), testConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = VerifyMultiProof(&proof, commitments, points, evaluations, NewTranscript("vt"), testConf); err != nil {
+		t.Fatal(err)
+	}
+
+	// serialization round trip
+	var buf bytes.Buffer
+	n, err := proof.WriteTo(&buf)
+	if err != nil || n != SizeMultiProof {
+		t.Fatal("failed to write the proof", n, err)
+	}
+	var read MultiProof
+	if n, err = read.ReadFrom(&buf); err != nil || n != SizeMultiProof {
+		t.Fatal("failed to read the proof", n, err)
+	}
+	if err = VerifyMultiProof(&read, commitments, points, evaluations, NewTranscript("vt"), testConf); err != nil {
+		t.Fatal(err)
+	}
+
+	// wrong evaluation, point, transcript or proof
+	evaluations[3].Add(&evaluations[3], new(fr.Element).SetOne())
+	if err = VerifyMultiProof(&proof, commitments, points, evaluations, NewTranscript("vt"), testConf); !errors.Is(err, ErrVerifyOpeningProof) {
+		t.Fatal("expected ErrVerifyOpeningProof, got", err)
+	}
+	evaluations[3] = polynomials[3][points[3]]
+	points[5]++
+	if err = VerifyMultiProof(&proof, commitments, points, evaluations, NewTranscript("vt"), testConf); !errors.Is(err, ErrVerifyOpeningProof) {
+		t.Fatal("expected ErrVerifyOpeningProof, got", err)
+	}
+	points[5]--
+	if err = VerifyMultiProof(&proof, commitments, points, evaluations, NewTranscript("test"), testConf); !errors.Is(err, ErrVerifyOpeningProof) {
+		t.Fatal("expected ErrVerifyOpeningProof, got", err)
+	}
+	proof.IPA.A.SetOne()
+	if err = VerifyMultiProof(&proof, commitments, points, evaluations, NewTranscript("vt"), testConf); !errors.Is(err, ErrVerifyOpeningProof) {
+		t.Fatal("expected ErrVerifyOpeningProof, got", err)
+	}
+
+	if _, err = CreateMultiProof(polynomials, commitments[1:], points, NewTranscript("vt"), testConf); !errors.Is(err, ErrInvalidNumberOfQueries) {
+		t.Fatal("expected ErrInvalidNumberOfQueries, got", err)
+	}
+	if err = VerifyMultiProof(&proof, commitments, points[1:], evaluations, NewTranscript("vt"), testConf); !errors.Is(err, ErrInvalidNumberOfQueries) {
+		t.Fatal("expected ErrInvalidNumberOfQueries, got", err)
+	}
+}
+
+func TestDivideOnDomain(t *testing.T) {
+	p := randomPolynomial()
+	var z fr.Element
+	z.SetRandom()
+	b := testConf.barycentricCoefficients(z)
+	pz := innerProduct(p, b)
+
+	for _, m := range []int{0, 1, 100, DomainSize - 1} {
+		// q(z)(z - m) = p(z) - p(m)
+		q := testConf.divideOnDomain(p, m)
+		var lhs, rhs fr.Element
+		lhs.SetUint64(uint64(m))
+		lhs.Sub(&z, &lhs)
+		qz := innerProduct(q, b)
+		lhs.Mul(&lhs, &qz)
+		rhs.Sub(&pz, &p[m])
+		if !lhs.Equal(&rhs) {
+			t.Fatal("wrong quotient for m =", m)
+		}
+	}
+}
+
+func TestElement(t *testing.T) {
+	base := testConf.q.Point()
+
+	// (x, y) and (-x, -y) are the same element
+	var e, e1 Element
+	e.point = base
+	e1.point.X.Neg(&base.X)
+	e1.point.Y.Neg(&base.Y)
+	if !e.Equal(&e1) || e.Bytes() != e1.Bytes() || e.MapToScalarField() != e1.MapToScalarField() {
+		t.Fatal("(x, y) and (-x, -y) should be equal")
+	}
+	b := e.Bytes()
+	var read Element
+	if err := read.SetBytes(b[:]); err != nil || !read.Equal(&e) {
+		t.Fatal("round trip failed", err)
+	}
+
+	// neutral element
+	zero, err := Commit(nil, testConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = zero.Bytes()
+	if !zero.IsZero() || b != [SizeElement]byte{} {
+		t.Fatal("commitment to the empty polynomial should be the neutral element")
+	}
+	if err = read.SetBytes(b[:]); err != nil || !read.IsZero() {
+		t.Fatal("failed to decode the neutral element", err)
+	}
+
+	// points outside the subgroup are rejected
+	nbRejected := 0
+	for i := byte(1); i < 20; i++ {
+		b[SizeElement-1] = i
+		if err = read.SetBytes(b[:]); errors.Is(err, ErrNotInSubgroup) {
+			nbRejected++
+		}
+	}
+	if nbRejected == 0 {
+		t.Fatal("expected points outside the subgroup")
+	}
+	if err = read.SetBytes(b[1:]); !errors.Is(err, ErrInvalidEncoding) {
+		t.Fatal("expected ErrInvalidEncoding, got", err)
+	}
+}
+
+func TestMultiExp(t *testing.T) {
+	for _, n := range []int{1, 2, 17, DomainSize} {
+		scalars := make([]fr.Element, n)
+		for i := range scalars {
+			scalars[i].SetRandom()
+		}
+		scalars[0].SetZero()
+		// repeated points
+		points := make([]Element, n)
+		for i := range points {
+			points[i] = testConf.srs[i%5]
+		}
+
+		var expected, tmp bandersnatch.PointExtended
+		expected.FromAffine(&bandersnatch.PointAffine{Y: fp.One()})
+		var s big.Int
+		for i := range points {
+			tmp.FromAffine(&points[i].point)
+			tmp.ScalarMultiplication(&tmp, scalars[i].BigInt(&s))
+			expected.Add(&expected, &tmp)
+		}
+		var e Element
+		e.fromExtended(&expected)
+		res := multiExp(points, scalars)
+		if !res.Equal(&e) {
+			t.Fatal("wrong multi-exponentiation of size", n)
+		}
+	}
+}
+
+func BenchmarkCommit(b *testing.B) {
+	p := randomPolynomial()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Commit(p, testConf)
+	}
+}
+
+func BenchmarkMultiProof(b *testing.B) {
+	const nbQueries = 16
+	polynomials := make([][]fr.Element, nbQueries)
+	commitments := make([]Element, nbQueries)
+	points := make([]uint8, nbQueries)
+	evaluations := make([]fr.Element, nbQueries)
+	for i := range polynomials {
+		polynomials[i] = randomPolynomial()
+		commitments[i], _ = Commit(polynomials[i], testConf)
+		points[i] = uint8(i)
+		evaluations[i] = polynomials[i][i]
+	}
+	proof, _ := CreateMultiProof(polynomials, commitments, points, NewTranscript("vt"), testConf)
+	b.ResetTimer()
+
+	b.Run("CreateMultiProof", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = CreateMultiProof(polynomials, commitments, points, NewTranscript("vt"), testConf)
+		}
+	})
+	b.Run("VerifyMultiProof", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = VerifyMultiProof(&proof, commitments, points, evaluations, NewTranscript("vt"), testConf)
+		}
+	})
+}
@@ -0,0 +1,221 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+// SizeMultiProof size in bytes of a serialized MultiProof
+const SizeMultiProof = (2*nbRounds+1)*SizeElement + fr.Bytes
+
+var ErrInvalidNumberOfQueries = errors.New("number of commitments, polynomials, points and evaluations must match")
+
+// MultiProof proves the openings of several committed polynomials at points of
+// the domain, with a single inner product argument.
+//
+// With r a random challenge, the prover commits to
+// g = ∑ᵢ rⁱ(fᵢ - fᵢ(zᵢ))/(X - zᵢ) in D. With t another challenge, it proves
+// that E - D commits to h - g, with h = ∑ᵢ rⁱfᵢ/(t - zᵢ) committed in E,
+// evaluates at t to ∑ᵢ rⁱyᵢ/(t - zᵢ). The verifier computes E from the Cᵢ.
+type MultiProof struct {
+	D   Element
+	IPA Proof
+}
+
+// CreateMultiProof returns a proof that the polynomials, committed to in
+// commitments and given by their evaluations on the domain, evaluate to
+// polynomials[i][points[i]] at points[i].
+func CreateMultiProof(polynomials [][]fr.Element, commitments []Element, points []uint8, transcript *Transcript, conf *Config) (MultiProof, error) {
+	if len(polynomials) != len(commitments) || len(polynomials) != len(points) {
+		return MultiProof{}, ErrInvalidNumberOfQueries
+	}
+	for i := range polynomials {
+		if len(polynomials[i]) != DomainSize {
+			return MultiProof{}, ErrInvalidPolynomialSize
+		}
+	}
+
+	transcript.domainSep("multiproof")
+	for i := range polynomials {
+		z := fr.NewElement(uint64(points[i]))
+		transcript.appendPoint(&commitments[i], "C")
+		transcript.appendScalar(&z, "z")
+		transcript.appendScalar(&polynomials[i][points[i]], "y")
+	}
+	r := transcript.challengeScalar("r")
+
+	// fₘ = ∑_{zᵢ = m} rⁱfᵢ, for each queried point m
+	aggregated := make(map[uint8][]fr.Element)
+	var ri, tmp fr.Element
+	ri.SetOne()
+	for i := range polynomials {
+		f, ok := aggregated[points[i]]
+		if !ok {
+			f = make([]fr.Element, DomainSize)
+			aggregated[points[i]] = f
+		}
+		for j := range f {
+			tmp.Mul(&polynomials[i][j], &ri)
+			f[j].Add(&f[j], &tmp)
+		}
+		ri.Mul(&ri, &r)
+	}
+
+	g := make([]fr.Element, DomainSize)
+	for m, f := range aggregated {
+		q := conf.divideOnDomain(f, int(m))
+		for j := range g {
+			g[j].Add(&g[j], &q[j])
+		}
+	}
+	d, err := Commit(g, conf)
+	if err != nil {
+		return MultiProof{}, err
+	}
+	transcript.appendPoint(&d, "D")
+	t := transcript.challengeScalar("t")
+
+	h := make([]fr.Element, DomainSize)
+	for m, f := range aggregated {
+		var denInv fr.Element
+		denInv.SetUint64(uint64(m))
+		denInv.Sub(&t, &denInv).Inverse(&denInv)
+		for j := range h {
+			tmp.Mul(&f[j], &denInv)
+			h[j].Add(&h[j], &tmp)
+		}
+	}
+	e, err := Commit(h, conf)
+	if err != nil {
+		return MultiProof{}, err
+	}
+	transcript.appendPoint(&e, "E")
+
+	eMinusD := subtract(&e, &d)
+	for j := range h {
+		h[j].Sub(&h[j], &g[j])
+	}
+
+	return MultiProof{
+		D:   d,
+		IPA: createProof(transcript, conf, &eMinusD, h, t),
+	}, nil
+}
+
+// VerifyMultiProof checks that proof proves that the polynomials committed to
+// in commitments evaluate to evaluations[i] at points[i]. It returns
+// ErrVerifyOpeningProof if the proof is invalid.
+func VerifyMultiProof(proof *MultiProof, commitments []Element, points []uint8, evaluations []fr.Element, transcript *Transcript, conf *Config) error {
+	if len(commitments) != len(points) || len(commitments) != len(evaluations) {
+		return ErrInvalidNumberOfQueries
+	}
+
+	transcript.domainSep("multiproof")
+	for i := range commitments {
+		z := fr.NewElement(uint64(points[i]))
+		transcript.appendPoint(&commitments[i], "C")
+		transcript.appendScalar(&z, "z")
+		transcript.appendScalar(&evaluations[i], "y")
+	}
+	r := transcript.challengeScalar("r")
+	transcript.appendPoint(&proof.D, "D")
+	t := transcript.challengeScalar("t")
+
+	// E = ∑ᵢ rⁱ/(t - zᵢ)⋅Cᵢ and g₂(t) = ∑ᵢ rⁱyᵢ/(t - zᵢ)
+	coefficients := make([]fr.Element, len(commitments))
+	for i := range coefficients {
+		coefficients[i].SetUint64(uint64(points[i]))
+		coefficients[i].Sub(&t, &coefficients[i])
+	}
+	coefficients = fr.BatchInvert(coefficients)
+	var ri, g2t, tmp fr.Element
+	ri.SetOne()
+	for i := range coefficients {
+		coefficients[i].Mul(&coefficients[i], &ri)
+		tmp.Mul(&coefficients[i], &evaluations[i])
+		g2t.Add(&g2t, &tmp)
+		ri.Mul(&ri, &r)
+	}
+	e := multiExp(commitments, coefficients)
+	transcript.appendPoint(&e, "E")
+
+	eMinusD := subtract(&e, &proof.D)
+	if !verifyProof(transcript, conf, &eMinusD, &proof.IPA, t, g2t) {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// WriteTo writes the encoding of the proof in the go-verkle format: D, the Lᵢ,
+// the Rᵢ and A in little-endian, on SizeMultiProof bytes.
+func (proof *MultiProof) WriteTo(w io.Writer) (int64, error) {
+	var buf [SizeMultiProof]byte
+	b := buf[:0]
+	d := proof.D.Bytes()
+	b = append(b, d[:]...)
+	for i := range proof.IPA.L {
+		l := proof.IPA.L[i].Bytes()
+		b = append(b, l[:]...)
+	}
+	for i := range proof.IPA.R {
+		r := proof.IPA.R[i].Bytes()
+		b = append(b, r[:]...)
+	}
+	fr.LittleEndian.PutElement((*[fr.Bytes]byte)(buf[len(b):]), proof.IPA.A)
+
+	n, err := w.Write(buf[:])
+	return int64(n), err
+}
+
+// ReadFrom reads the encoding of a proof written by WriteTo.
+func (proof *MultiProof) ReadFrom(r io.Reader) (int64, error) {
+	var buf [SizeMultiProof]byte
+	n, err := io.ReadFull(r, buf[:])
+	if err != nil {
+		return int64(n), err
+	}
+
+	elements := make([]*Element, 0, 2*nbRounds+1)
+	elements = append(elements, &proof.D)
+	for i := range proof.IPA.L {
+		elements = append(elements, &proof.IPA.L[i])
+	}
+	for i := range proof.IPA.R {
+		elements = append(elements, &proof.IPA.R[i])
+	}
+	for i, e := range elements {
+		if err = e.SetBytes(buf[i*SizeElement : (i+1)*SizeElement]); err != nil {
+			return int64(n), err
+		}
+	}
+	proof.IPA.A, err = fr.LittleEndian.Element((*[fr.Bytes]byte)(buf[len(elements)*SizeElement:]))
+	return int64(n), err
+}
+
+// subtract returns a - b
+func subtract(a, b *Element) Element {
+	var pa, pb bandersnatch.PointExtended
+	pa.FromAffine(&a.point)
+	pb.FromAffine(&b.point)
+	pb.Neg(&pb)
+	pa.Add(&pa, &pb)
+	var res Element
+	return *res.fromExtended(&pa)
+}
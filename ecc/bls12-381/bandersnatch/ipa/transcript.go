@@ -0,0 +1,73 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"crypto/sha256"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+// Transcript is the Fiat-Shamir transcript of go-verkle and rust-verkle.
+//
+// Messages are absorbed in a SHA-256 state, each one prefixed with its label.
+// A challenge is the digest of the state, read in little-endian and reduced
+// modulo the group order; the state is then reset and absorbs the challenge.
+type Transcript struct {
+	state hash.Hash
+}
+
+// NewTranscript returns a transcript with the given domain separator. Verkle
+// trees use "vt".
+func NewTranscript(label string) *Transcript {
+	t := &Transcript{state: sha256.New()}
+	t.state.Write([]byte(label))
+	return t
+}
+
+func (t *Transcript) domainSep(label string) {
+	t.state.Write([]byte(label))
+}
+
+func (t *Transcript) appendMessage(message []byte, label string) {
+	t.state.Write([]byte(label))
+	t.state.Write(message)
+}
+
+func (t *Transcript) appendScalar(s *fr.Element, label string) {
+	var b [fr.Bytes]byte
+	fr.LittleEndian.PutElement(&b, *s)
+	t.appendMessage(b[:], label)
+}
+
+func (t *Transcript) appendPoint(e *Element, label string) {
+	b := e.Bytes()
+	t.appendMessage(b[:], label)
+}
+
+func (t *Transcript) challengeScalar(label string) fr.Element {
+	t.domainSep(label)
+	digest := t.state.Sum(nil)
+	for i, j := 0, len(digest)-1; i < j; i, j = i+1, j-1 {
+		digest[i], digest[j] = digest[j], digest[i]
+	}
+	var res fr.Element
+	res.SetBytes(digest)
+
+	t.state.Reset()
+	t.appendScalar(&res, label)
+	return res
+}
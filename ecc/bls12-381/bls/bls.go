@@ -0,0 +1,148 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Mode selects one of the three ciphersuites of the BLS signature draft.
+type Mode uint8
+
+const (
+	// Basic is the basic scheme (NUL ciphersuites). Aggregate verification
+	// requires all messages to be distinct.
+	Basic Mode = iota
+	// MessageAugmentation is the message augmentation scheme (AUG
+	// ciphersuites). The public key is prepended to each signed message.
+	MessageAugmentation
+	// ProofOfPossession is the proof of possession scheme (POP ciphersuites).
+	// Public keys must come with a valid proof of possession, which allows
+	// fast aggregate verification of a single message.
+	ProofOfPossession
+)
+
+// Group selects the group in which the signatures (and the hashed messages) live.
+type Group uint8
+
+const (
+	// G1 is used for signatures of the minimal-signature-size variant.
+	G1 Group = iota
+	// G2 is used for signatures of the minimal-pubkey-size variant.
+	G2
+)
+
+var (
+	ErrInvalidMode       = errors.New("invalid ciphersuite mode")
+	ErrInvalidPublicKey  = errors.New("invalid public key")
+	ErrInvalidSignature  = errors.New("invalid signature")
+	ErrZeroSecret        = errors.New("secret key must be non zero")
+	ErrShortIKM          = errors.New("input key material must be at least 32 bytes")
+	ErrEmptyInput        = errors.New("empty input")
+	ErrLengthMismatch    = errors.New("public keys and messages must have the same length")
+	ErrDuplicateMessages = errors.New("messages must be distinct in the basic scheme")
+	ErrNotPoP            = errors.New("operation requires the proof of possession scheme")
+)
+
+// String returns the suffix of the ciphersuite identifier for m.
+func (m Mode) String() string {
+	switch m {
+	case Basic:
+		return "NUL_"
+	case MessageAugmentation:
+		return "AUG_"
+	case ProofOfPossession:
+		return "POP_"
+	default:
+		return "unknown"
+	}
+}
+
+// Valid reports whether m is one of the known ciphersuites.
+func (m Mode) Valid() bool {
+	return m <= ProofOfPossession
+}
+
+// DST returns the domain separation tag (the ciphersuite identifier) used
+// to hash messages to the signature group g.
+//
+// e.g. BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_
+func (m Mode) DST(g Group) []byte {
+	return []byte("BLS_SIG_" + g.hashToCurveSuite() + m.String())
+}
+
+// PopDST returns the domain separation tag used to hash public keys to
+// the signature group g when proving possession.
+//
+// e.g. BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_
+func PopDST(g Group) []byte {
+	return []byte("BLS_POP_" + g.hashToCurveSuite() + ProofOfPossession.String())
+}
+
+func (g Group) hashToCurveSuite() string {
+	if g == G1 {
+		return "BLS12381G1_XMD:SHA-256_SSWU_RO_"
+	}
+	return "BLS12381G2_XMD:SHA-256_SSWU_RO_"
+}
+
+const keyGenSalt = "BLS-SIG-KEYGEN-SALT-"
+
+// KeyGen derives a secret key from input keying material ikm and optional
+// keyInfo as described in section 2.3 of the draft.
+//
+//	salt = "BLS-SIG-KEYGEN-SALT-"
+//	SK = 0
+//	while SK == 0:
+//	    salt = H(salt)
+//	    PRK = HKDF-Extract(salt, IKM || I2OSP(0, 1))
+//	    OKM = HKDF-Expand(PRK, key_info || I2OSP(L, 2), L)
+//	    SK = OS2IP(OKM) mod r
+//
+// ikm must be at least 32 bytes long.
+func KeyGen(ikm, keyInfo []byte) (fr.Element, error) {
+	var sk fr.Element
+	if len(ikm) < 32 {
+		return sk, ErrShortIKM
+	}
+
+	// L = ceil((3 * ceil(log2(r))) / 16)
+	const L = 48
+
+	secret := make([]byte, len(ikm)+1)
+	copy(secret, ikm)
+
+	info := make([]byte, len(keyInfo)+2)
+	copy(info, keyInfo)
+	info[len(keyInfo)] = L >> 8
+	info[len(keyInfo)+1] = L & 0xff
+
+	salt := []byte(keyGenSalt)
+	okm := make([]byte, L)
+	for sk.IsZero() {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), okm); err != nil {
+			return sk, err
+		}
+		sk.SetBytes(okm)
+	}
+	return sk, nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bls provides the BLS signature scheme on the bls12-381 curve.
+//
+// The implementation follows draft-irtf-cfrg-bls-signature-05. The two
+// variants of the scheme live in sub-packages:
+//   - minpk: public keys in G1 (48 bytes), signatures in G2 (96 bytes). This
+//     is the variant used by the Ethereum consensus layer.
+//   - minsig: public keys in G2 (96 bytes), signatures in G1 (48 bytes).
+//
+// Each variant supports the three ciphersuites of the draft, selected by a
+// [Mode]: basic, message augmentation and proof of possession.
+//
+// This package holds the parts shared by both variants: key generation and
+// ciphersuite identifiers.
+//
+// Documentation:
+//   - draft-irtf-cfrg-bls-signature-05: https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05
+//   - RFC 9380 (hashing to elliptic curves): https://www.rfc-editor.org/rfc/rfc9380
+//
+// # Warning
+//
+// This code has not been audited and is provided as-is. In particular, there is no security guarantees such as constant time implementation or side-channel attack resistance.
+package bls
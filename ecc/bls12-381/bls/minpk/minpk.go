@@ -0,0 +1,349 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package minpk provides the minimal-pubkey-size variant of the BLS signature
+// scheme on bls12-381: public keys are G1 points and signatures are G2 points.
+//
+// See package [github.com/consensys/gnark-crypto/ecc/bls12-381/bls] for the
+// ciphersuites and key generation.
+package minpk
+
+import (
+	"crypto/subtle"
+	"hash"
+	"io"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bls"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/signature"
+)
+
+const (
+	sizeFr         = fr.Bytes
+	sizePublicKey  = bls12381.SizeOfG1AffineCompressed
+	sizePrivateKey = sizePublicKey + sizeFr
+	sizeSignature  = bls12381.SizeOfG2AffineCompressed
+)
+
+// PublicKey represents a BLS public key.
+//
+// Mode selects the ciphersuite used to verify signatures; it is not part
+// of the binary representation of the key.
+type PublicKey struct {
+	A    bls12381.G1Affine
+	Mode bls.Mode
+}
+
+// PrivateKey represents a BLS private key.
+type PrivateKey struct {
+	PublicKey PublicKey
+	scalar    [sizeFr]byte // secret scalar, in big Endian
+}
+
+// GenerateKey generates a public and private key pair for the given
+// ciphersuite, using 32 bytes read from rand as input keying material.
+func GenerateKey(rand io.Reader, mode bls.Mode) (*PrivateKey, error) {
+	ikm := make([]byte, 32)
+	if _, err := io.ReadFull(rand, ikm); err != nil {
+		return nil, err
+	}
+	return KeyGen(ikm, nil, mode)
+}
+
+// KeyGen deterministically derives a key pair from ikm and keyInfo.
+// See [bls.KeyGen].
+func KeyGen(ikm, keyInfo []byte, mode bls.Mode) (*PrivateKey, error) {
+	if !mode.Valid() {
+		return nil, bls.ErrInvalidMode
+	}
+	sk, err := bls.KeyGen(ikm, keyInfo)
+	if err != nil {
+		return nil, err
+	}
+	return newPrivateKey(&sk, mode), nil
+}
+
+func newPrivateKey(sk *fr.Element, mode bls.Mode) *PrivateKey {
+	privateKey := new(PrivateKey)
+	privateKey.scalar = sk.Bytes()
+	var s big.Int
+	sk.BigInt(&s)
+	privateKey.PublicKey.A.ScalarMultiplicationBase(&s)
+	privateKey.PublicKey.Mode = mode
+	return privateKey
+}
+
+// Public returns the public key associated to the private key.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.A.Set(&privKey.PublicKey.A)
+	pub.Mode = privKey.PublicKey.Mode
+	return &pub
+}
+
+// Sign signs message with the ciphersuite of the key.
+//
+//	S = sk ⋅ H(m)
+//
+// where H is the hash to G2 of the ciphersuite, and m is the message,
+// prefixed by the public key in the message augmentation scheme.
+//
+// hFunc is not used: messages are always hashed to the curve.
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	mode := privKey.PublicKey.Mode
+	if !mode.Valid() {
+		return nil, bls.ErrInvalidMode
+	}
+	msg := message
+	if mode == bls.MessageAugmentation {
+		msg = privKey.PublicKey.augment(message)
+	}
+	S, err := privKey.coreSign(msg, mode.DST(bls.G2))
+	if err != nil {
+		return nil, err
+	}
+	res := S.Bytes()
+	return res[:], nil
+}
+
+// PopProve returns a proof of possession of the private key.
+//
+//	π = sk ⋅ H_pop(PK)
+func (privKey *PrivateKey) PopProve() ([]byte, error) {
+	pkBin := privKey.PublicKey.A.Bytes()
+	S, err := privKey.coreSign(pkBin[:], bls.PopDST(bls.G2))
+	if err != nil {
+		return nil, err
+	}
+	res := S.Bytes()
+	return res[:], nil
+}
+
+func (privKey *PrivateKey) coreSign(message, dst []byte) (bls12381.G2Affine, error) {
+	var S bls12381.G2Affine
+	H, err := bls12381.HashToG2(message, dst)
+	if err != nil {
+		return S, err
+	}
+	var s big.Int
+	s.SetBytes(privKey.scalar[:])
+	S.ScalarMultiplication(&H, &s)
+	return S, nil
+}
+
+// Verify checks a signature of message under the public key, using the
+// ciphersuite of the key.
+//
+//	e(PK, H(m)) ?= e(g1, S)
+//
+// hFunc is not used: messages are always hashed to the curve.
+func (publicKey *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+	mode := publicKey.Mode
+	if !mode.Valid() {
+		return false, bls.ErrInvalidMode
+	}
+	msg := message
+	if mode == bls.MessageAugmentation {
+		msg = publicKey.augment(message)
+	}
+	return coreVerify(&publicKey.A, msg, sigBin, mode.DST(bls.G2))
+}
+
+// PopVerify checks a proof of possession of the private key associated
+// to the public key.
+func (publicKey *PublicKey) PopVerify(proof []byte) (bool, error) {
+	pkBin := publicKey.A.Bytes()
+	return coreVerify(&publicKey.A, pkBin[:], proof, bls.PopDST(bls.G2))
+}
+
+// KeyValidate reports whether the public key is a valid, non-identity,
+// point of the prime order subgroup of G1.
+func (publicKey *PublicKey) KeyValidate() bool {
+	return keyValidate(&publicKey.A)
+}
+
+// augment returns PK || message.
+func (publicKey *PublicKey) augment(message []byte) []byte {
+	pkBin := publicKey.A.Bytes()
+	res := make([]byte, 0, len(pkBin)+len(message))
+	res = append(res, pkBin[:]...)
+	return append(res, message...)
+}
+
+// Equal compares 2 public keys
+func (publicKey *PublicKey) Equal(x signature.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	bpk := publicKey.Bytes()
+	bxx := xx.Bytes()
+	return subtle.ConstantTimeCompare(bpk, bxx) == 1
+}
+
+// AggregateSignatures sums the signatures sigs into a single signature.
+func AggregateSignatures(sigs [][]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return nil, bls.ErrEmptyInput
+	}
+	var acc bls12381.G2Jac
+	for i := range sigs {
+		S, err := decodeSignature(sigs[i])
+		if err != nil {
+			return nil, err
+		}
+		acc.AddMixed(&S)
+	}
+	var S bls12381.G2Affine
+	S.FromJacobian(&acc)
+	res := S.Bytes()
+	return res[:], nil
+}
+
+// AggregatePublicKeys sums the public keys into a single public key,
+// with the ciphersuite of the first one.
+func AggregatePublicKeys(publicKeys []PublicKey) (*PublicKey, error) {
+	if len(publicKeys) == 0 {
+		return nil, bls.ErrEmptyInput
+	}
+	var acc bls12381.G1Jac
+	for i := range publicKeys {
+		if !keyValidate(&publicKeys[i].A) {
+			return nil, bls.ErrInvalidPublicKey
+		}
+		acc.AddMixed(&publicKeys[i].A)
+	}
+	var res PublicKey
+	res.A.FromJacobian(&acc)
+	res.Mode = publicKeys[0].Mode
+	return &res, nil
+}
+
+// AggregateVerify checks an aggregate signature of the messages, where
+// messages[i] has been signed by publicKeys[i]. All public keys must use
+// the same ciphersuite.
+//
+//	∏ e(PKᵢ, H(mᵢ)) ?= e(g1, S)
+//
+// In the basic scheme the messages must be distinct.
+func AggregateVerify(publicKeys []PublicKey, messages [][]byte, sig []byte) (bool, error) {
+	if len(publicKeys) == 0 {
+		return false, bls.ErrEmptyInput
+	}
+	if len(publicKeys) != len(messages) {
+		return false, bls.ErrLengthMismatch
+	}
+	mode := publicKeys[0].Mode
+	if !mode.Valid() {
+		return false, bls.ErrInvalidMode
+	}
+	for i := range publicKeys {
+		if publicKeys[i].Mode != mode {
+			return false, bls.ErrInvalidMode
+		}
+	}
+	if mode == bls.Basic {
+		seen := make(map[string]struct{}, len(messages))
+		for i := range messages {
+			if _, ok := seen[string(messages[i])]; ok {
+				return false, bls.ErrDuplicateMessages
+			}
+			seen[string(messages[i])] = struct{}{}
+		}
+	}
+
+	S, err := decodeSignature(sig)
+	if err != nil {
+		return false, nil
+	}
+
+	dst := mode.DST(bls.G2)
+	P := make([]bls12381.G1Affine, len(publicKeys)+1)
+	Q := make([]bls12381.G2Affine, len(publicKeys)+1)
+	for i := range publicKeys {
+		if !keyValidate(&publicKeys[i].A) {
+			return false, nil
+		}
+		msg := messages[i]
+		if mode == bls.MessageAugmentation {
+			msg = publicKeys[i].augment(msg)
+		}
+		if Q[i], err = bls12381.HashToG2(msg, dst); err != nil {
+			return false, err
+		}
+		P[i].Set(&publicKeys[i].A)
+	}
+	_, _, g1, _ := bls12381.Generators()
+	P[len(publicKeys)].Neg(&g1)
+	Q[len(publicKeys)].Set(&S)
+
+	return bls12381.PairingCheck(P, Q)
+}
+
+// FastAggregateVerify checks an aggregate signature of a single message by
+// all the public keys. It is only available in the proof of possession
+// scheme, and assumes the proofs of possession of the keys have been
+// checked with [PublicKey.PopVerify].
+func FastAggregateVerify(publicKeys []PublicKey, message, sig []byte) (bool, error) {
+	if len(publicKeys) == 0 {
+		return false, bls.ErrEmptyInput
+	}
+	for i := range publicKeys {
+		if publicKeys[i].Mode != bls.ProofOfPossession {
+			return false, bls.ErrNotPoP
+		}
+	}
+	aggPk, err := AggregatePublicKeys(publicKeys)
+	if err != nil {
+		return false, nil
+	}
+	return coreVerify(&aggPk.A, message, sig, bls.ProofOfPossession.DST(bls.G2))
+}
+
+func coreVerify(pk *bls12381.G1Affine, message, sigBin, dst []byte) (bool, error) {
+	S, err := decodeSignature(sigBin)
+	if err != nil {
+		return false, nil
+	}
+	if !keyValidate(pk) {
+		return false, nil
+	}
+	H, err := bls12381.HashToG2(message, dst)
+	if err != nil {
+		return false, err
+	}
+	_, _, g1, _ := bls12381.Generators()
+	g1.Neg(&g1)
+
+	return bls12381.PairingCheck([]bls12381.G1Affine{*pk, g1}, []bls12381.G2Affine{H, S})
+}
+
+func keyValidate(pk *bls12381.G1Affine) bool {
+	return !pk.IsInfinity() && pk.IsInSubGroup()
+}
+
+// decodeSignature deserializes a compressed G2 point and checks it is in
+// the prime order subgroup.
+func decodeSignature(sigBin []byte) (bls12381.G2Affine, error) {
+	var S bls12381.G2Affine
+	if len(sigBin) != sizeSignature {
+		return S, bls.ErrInvalidSignature
+	}
+	if _, err := S.SetBytes(sigBin); err != nil {
+		return S, err
+	}
+	return S, nil
+}
@@ -0,0 +1,446 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minpk
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bls"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/hkdf"
+	"gopkg.in/yaml.v2"
+)
+
+// test vectors of the Ethereum consensus specs, which use the proof of
+// possession ciphersuite of the min-pk variant, and known-answer vectors of
+// each ciphersuite under ciphersuites and bls_sigs_ref.
+var testDir = filepath.Join("..", "..", "testing", "bls")
+
+func TestBLS(t *testing.T) {
+
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+	properties := gopter.NewProperties(parameters)
+
+	for _, mode := range []bls.Mode{bls.Basic, bls.MessageAugmentation, bls.ProofOfPossession} {
+		mode := mode
+		properties.Property("[BLS12-381] min-pk "+mode.String()+" test the signing and verification", prop.ForAll(
+			func() bool {
+
+				privKey, _ := GenerateKey(rand.Reader, mode)
+				publicKey := privKey.Public()
+
+				msg := []byte("testing BLS")
+				sig, _ := privKey.Sign(msg, nil)
+				flag, _ := publicKey.Verify(sig, msg, nil)
+				wrong, _ := publicKey.Verify(sig, []byte("testing bls"), nil)
+
+				return flag && !wrong
+			},
+		))
+
+		properties.Property("[BLS12-381] min-pk "+mode.String()+" test aggregate verification", prop.ForAll(
+			func() bool {
+
+				const n = 3
+				publicKeys := make([]PublicKey, n)
+				messages := make([][]byte, n)
+				sigs := make([][]byte, n)
+				for i := 0; i < n; i++ {
+					privKey, _ := GenerateKey(rand.Reader, mode)
+					publicKeys[i] = privKey.PublicKey
+					messages[i] = []byte{byte(i)}
+					sigs[i], _ = privKey.Sign(messages[i], nil)
+				}
+				sig, _ := AggregateSignatures(sigs)
+				flag, _ := AggregateVerify(publicKeys, messages, sig)
+				messages[0], messages[1] = messages[1], messages[0]
+				wrong, _ := AggregateVerify(publicKeys, messages, sig)
+
+				return flag && !wrong
+			},
+		))
+	}
+
+	properties.Property("[BLS12-381] min-pk test proof of possession", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader, bls.ProofOfPossession)
+			other, _ := GenerateKey(rand.Reader, bls.ProofOfPossession)
+			proof, _ := privKey.PopProve()
+			flag, _ := privKey.PublicKey.PopVerify(proof)
+			wrong, _ := other.PublicKey.PopVerify(proof)
+
+			return flag && !wrong
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestAggregateVerifyBasicDistinctMessages(t *testing.T) {
+	assert := require.New(t)
+
+	privKey, err := GenerateKey(rand.Reader, bls.Basic)
+	assert.NoError(err)
+	msg := []byte("same message")
+	sig, err := privKey.Sign(msg, nil)
+	assert.NoError(err)
+	aggSig, err := AggregateSignatures([][]byte{sig, sig})
+	assert.NoError(err)
+
+	publicKeys := []PublicKey{privKey.PublicKey, privKey.PublicKey}
+	_, err = AggregateVerify(publicKeys, [][]byte{msg, msg}, aggSig)
+	assert.ErrorIs(err, bls.ErrDuplicateMessages)
+
+	_, err = FastAggregateVerify(publicKeys, msg, aggSig)
+	assert.ErrorIs(err, bls.ErrNotPoP)
+}
+
+func TestSerialization(t *testing.T) {
+	assert := require.New(t)
+
+	privKey, err := GenerateKey(rand.Reader, bls.ProofOfPossession)
+	assert.NoError(err)
+
+	var privKey2 PrivateKey
+	n, err := privKey2.SetBytes(privKey.Bytes())
+	assert.NoError(err)
+	assert.Equal(sizePrivateKey, n)
+	assert.Equal(privKey.Bytes(), privKey2.Bytes())
+
+	var publicKey PublicKey
+	n, err = publicKey.SetBytes(privKey.PublicKey.Bytes())
+	assert.NoError(err)
+	assert.Equal(sizePublicKey, n)
+	assert.True(publicKey.Equal(&privKey.PublicKey))
+
+	// the identity is not a valid public key
+	infinity := make([]byte, sizePublicKey)
+	infinity[0] = 0xc0
+	_, err = publicKey.SetBytes(infinity)
+	assert.Error(err)
+}
+
+func TestKeyGen(t *testing.T) {
+	assert := require.New(t)
+
+	ikm := make([]byte, 32)
+	_, err := KeyGen(ikm[:31], nil, bls.Basic)
+	assert.ErrorIs(err, bls.ErrShortIKM)
+
+	k1, err := KeyGen(ikm, nil, bls.Basic)
+	assert.NoError(err)
+	k2, err := KeyGen(ikm, nil, bls.Basic)
+	assert.NoError(err)
+	k3, err := KeyGen(ikm, []byte("key info"), bls.Basic)
+	assert.NoError(err)
+	assert.Equal(k1.Bytes(), k2.Bytes())
+	assert.NotEqual(k1.Bytes(), k3.Bytes())
+}
+
+func TestSignVectors(t *testing.T) {
+	type Test struct {
+		Input struct {
+			PrivKey string `yaml:"privkey"`
+			Message string `yaml:"message"`
+		}
+		Output string `yaml:"output"`
+	}
+	tests, err := filepath.Glob(filepath.Join(testDir, "sign", "*"))
+	require.NoError(t, err)
+	require.NotEmpty(t, tests)
+	for _, testPath := range tests {
+		t.Run(filepath.Base(testPath), func(t *testing.T) {
+			var test Test
+			readVector(t, testPath, &test)
+
+			privKey := PrivateKey{PublicKey: PublicKey{Mode: bls.ProofOfPossession}}
+			_, err := privKey.SetSecret(decodeHex(t, test.Input.PrivKey))
+			require.NoError(t, err)
+			sig, err := privKey.Sign(decodeHex(t, test.Input.Message), nil)
+			require.NoError(t, err)
+			require.Equal(t, decodeHex(t, test.Output), sig)
+		})
+	}
+}
+
+func TestVerifyVectors(t *testing.T) {
+	type Test struct {
+		Input struct {
+			PubKey    string `yaml:"pubkey"`
+			Message   string `yaml:"message"`
+			Signature string `yaml:"signature"`
+		}
+		Output bool `yaml:"output"`
+	}
+	tests, err := filepath.Glob(filepath.Join(testDir, "verify", "*"))
+	require.NoError(t, err)
+	require.NotEmpty(t, tests)
+	for _, testPath := range tests {
+		t.Run(filepath.Base(testPath), func(t *testing.T) {
+			var test Test
+			readVector(t, testPath, &test)
+
+			publicKey := PublicKey{Mode: bls.ProofOfPossession}
+			if _, err := publicKey.SetBytes(decodeHex(t, test.Input.PubKey)); err != nil {
+				require.False(t, test.Output)
+				return
+			}
+			ok, err := publicKey.Verify(decodeHex(t, test.Input.Signature), decodeHex(t, test.Input.Message), nil)
+			require.NoError(t, err)
+			require.Equal(t, test.Output, ok)
+		})
+	}
+}
+
+func TestAggregateVectors(t *testing.T) {
+	type Test struct {
+		Input  []string `yaml:"input"`
+		Output string   `yaml:"output"`
+	}
+	tests, err := filepath.Glob(filepath.Join(testDir, "aggregate", "*"))
+	require.NoError(t, err)
+	require.NotEmpty(t, tests)
+	for _, testPath := range tests {
+		t.Run(filepath.Base(testPath), func(t *testing.T) {
+			var test Test
+			readVector(t, testPath, &test)
+
+			sigs := make([][]byte, len(test.Input))
+			for i := range test.Input {
+				sigs[i] = decodeHex(t, test.Input[i])
+			}
+			sig, err := AggregateSignatures(sigs)
+			require.NoError(t, err)
+			require.Equal(t, decodeHex(t, test.Output), sig)
+		})
+	}
+}
+
+func TestAggregateVerifyVectors(t *testing.T) {
+	type Test struct {
+		Input struct {
+			PubKeys   []string `yaml:"pubkeys"`
+			Messages  []string `yaml:"messages"`
+			Message   string   `yaml:"message"`
+			Signature string   `yaml:"signature"`
+		}
+		Output bool `yaml:"output"`
+	}
+	for _, dir := range []string{"aggregate_verify", "fast_aggregate_verify"} {
+		tests, err := filepath.Glob(filepath.Join(testDir, dir, "*"))
+		require.NoError(t, err)
+		require.NotEmpty(t, tests)
+		for _, testPath := range tests {
+			t.Run(filepath.Base(testPath), func(t *testing.T) {
+				var test Test
+				readVector(t, testPath, &test)
+
+				publicKeys := make([]PublicKey, len(test.Input.PubKeys))
+				for i := range publicKeys {
+					publicKeys[i].Mode = bls.ProofOfPossession
+					_, err := publicKeys[i].SetBytes(decodeHex(t, test.Input.PubKeys[i]))
+					require.NoError(t, err)
+				}
+				sig := decodeHex(t, test.Input.Signature)
+
+				var ok bool
+				if dir == "fast_aggregate_verify" {
+					ok, err = FastAggregateVerify(publicKeys, decodeHex(t, test.Input.Message), sig)
+				} else {
+					messages := make([][]byte, len(test.Input.Messages))
+					for i := range messages {
+						messages[i] = decodeHex(t, test.Input.Messages[i])
+					}
+					ok, err = AggregateVerify(publicKeys, messages, sig)
+				}
+				require.NoError(t, err)
+				require.Equal(t, test.Output, ok)
+			})
+		}
+	}
+}
+
+func readVector(t *testing.T, path string, v interface{}) {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, yaml.NewDecoder(f).Decode(v))
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	require.NoError(t, err)
+	return b
+}
+
+// TestCiphersuiteVectors checks known-answer vectors of the Basic, AUG and POP
+// ciphersuites of the min-pk variant, computed with the blst library.
+func TestCiphersuiteVectors(t *testing.T) {
+	type SignTest struct {
+		Input struct {
+			IKM     string `yaml:"ikm"`
+			Message string `yaml:"message"`
+		}
+		Output struct {
+			PrivKey   string `yaml:"privkey"`
+			PubKey    string `yaml:"pubkey"`
+			Signature string `yaml:"signature"`
+			Proof     string `yaml:"proof"`
+		}
+	}
+	type AggregateVerifyTest struct {
+		Input struct {
+			PubKeys   []string `yaml:"pubkeys"`
+			Messages  []string `yaml:"messages"`
+			Signature string   `yaml:"signature"`
+		}
+		Output bool `yaml:"output"`
+	}
+	for _, suite := range []struct {
+		name string
+		mode bls.Mode
+	}{
+		{"minpk_basic", bls.Basic},
+		{"minpk_aug", bls.MessageAugmentation},
+		{"minpk_pop", bls.ProofOfPossession},
+	} {
+		dir := filepath.Join(testDir, "ciphersuites", suite.name)
+
+		tests, err := filepath.Glob(filepath.Join(dir, "sign_*"))
+		require.NoError(t, err)
+		require.NotEmpty(t, tests)
+		for _, testPath := range tests {
+			t.Run(suite.name+"/"+filepath.Base(testPath), func(t *testing.T) {
+				var test SignTest
+				readVector(t, testPath, &test)
+
+				privKey, err := KeyGen(decodeHex(t, test.Input.IKM), nil, suite.mode)
+				require.NoError(t, err)
+				require.Equal(t, decodeHex(t, test.Output.PrivKey), privKey.scalar[:])
+				require.Equal(t, decodeHex(t, test.Output.PubKey), privKey.PublicKey.Bytes())
+
+				message := decodeHex(t, test.Input.Message)
+				sig, err := privKey.Sign(message, nil)
+				require.NoError(t, err)
+				require.Equal(t, decodeHex(t, test.Output.Signature), sig)
+				ok, err := privKey.PublicKey.Verify(sig, message, nil)
+				require.NoError(t, err)
+				require.True(t, ok)
+
+				if suite.mode == bls.ProofOfPossession {
+					proof, err := privKey.PopProve()
+					require.NoError(t, err)
+					require.Equal(t, decodeHex(t, test.Output.Proof), proof)
+					ok, err := privKey.PublicKey.PopVerify(proof)
+					require.NoError(t, err)
+					require.True(t, ok)
+				}
+			})
+		}
+
+		tests, err = filepath.Glob(filepath.Join(dir, "aggregate_verify_*"))
+		require.NoError(t, err)
+		require.NotEmpty(t, tests)
+		for _, testPath := range tests {
+			t.Run(suite.name+"/"+filepath.Base(testPath), func(t *testing.T) {
+				var test AggregateVerifyTest
+				readVector(t, testPath, &test)
+
+				publicKeys := make([]PublicKey, len(test.Input.PubKeys))
+				messages := make([][]byte, len(test.Input.Messages))
+				for i := range publicKeys {
+					publicKeys[i].Mode = suite.mode
+					_, err := publicKeys[i].SetBytes(decodeHex(t, test.Input.PubKeys[i]))
+					require.NoError(t, err)
+					messages[i] = decodeHex(t, test.Input.Messages[i])
+				}
+				ok, err := AggregateVerify(publicKeys, messages, decodeHex(t, test.Input.Signature))
+				require.NoError(t, err)
+				require.Equal(t, test.Output, ok)
+			})
+		}
+	}
+}
+
+// TestBasicReferenceVectors checks the vectors of the Basic ciphersuite
+// of the reference implementation of the draft (github.com/kwantam/bls_sigs_ref,
+// test-vectors/sig_g2_basic). Their secret keys are derived with an earlier
+// version of KeyGen, which doesn't hash the salt before the first iteration.
+func TestBasicReferenceVectors(t *testing.T) {
+	f, err := os.Open(filepath.Join(testDir, "bls_sigs_ref", "sig_g2_basic_P256.txt"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	n := 0
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		require.Len(t, fields, 3)
+		message, ikm := decodeHex(t, fields[0]), decodeHex(t, fields[1])
+
+		okm := make([]byte, 48)
+		_, err := io.ReadFull(hkdf.New(sha256.New, append(ikm, 0), []byte("BLS-SIG-KEYGEN-SALT-"), []byte{0, 48}), okm)
+		require.NoError(t, err)
+		var sk fr.Element
+		sk.SetBytes(okm)
+		skBin := sk.Bytes()
+
+		privKey := PrivateKey{PublicKey: PublicKey{Mode: bls.Basic}}
+		_, err = privKey.SetSecret(skBin[:])
+		require.NoError(t, err)
+		sig, err := privKey.Sign(message, nil)
+		require.NoError(t, err)
+		require.Equal(t, decodeHex(t, fields[2]), sig, "message %s", fields[0])
+		n++
+	}
+	require.NoError(t, scanner.Err())
+	require.NotZero(t, n)
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkSign(b *testing.B) {
+	privKey, _ := GenerateKey(rand.Reader, bls.ProofOfPossession)
+	msg := []byte("benchmarking BLS sign()")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.Sign(msg, nil)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	privKey, _ := GenerateKey(rand.Reader, bls.ProofOfPossession)
+	msg := []byte("benchmarking BLS verify()")
+	sig, _ := privKey.Sign(msg, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.PublicKey.Verify(sig, msg, nil)
+	}
+}
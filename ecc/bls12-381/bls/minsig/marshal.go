@@ -0,0 +1,106 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minsig
+
+import (
+	"crypto/subtle"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bls"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Bytes returns the compressed binary representation of the public key
+// (96 bytes, ZCash serialization of the G2 point).
+func (publicKey *PublicKey) Bytes() []byte {
+	var res [sizePublicKey]byte
+	pkBin := publicKey.A.Bytes()
+	subtle.ConstantTimeCopy(1, res[:sizePublicKey], pkBin[:])
+	return res[:]
+}
+
+// SetBytes sets the public key from its compressed binary representation.
+// The point is checked to be in the prime order subgroup and not to be
+// the identity. The ciphersuite of the key is left unchanged.
+// It returns the number of bytes read from the buffer.
+func (publicKey *PublicKey) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizePublicKey {
+		return 0, io.ErrShortBuffer
+	}
+	if _, err := publicKey.A.SetBytes(buf[:sizePublicKey]); err != nil {
+		return 0, err
+	}
+	if publicKey.A.IsInfinity() {
+		return 0, bls.ErrInvalidPublicKey
+	}
+	return sizePublicKey, nil
+}
+
+// Bytes returns the binary representation of the private key,
+// as byte array publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+func (privKey *PrivateKey) Bytes() []byte {
+	var res [sizePrivateKey]byte
+	pubkBin := privKey.PublicKey.A.Bytes()
+	subtle.ConstantTimeCopy(1, res[:sizePublicKey], pubkBin[:])
+	subtle.ConstantTimeCopy(1, res[sizePublicKey:sizePrivateKey], privKey.scalar[:])
+	return res[:]
+}
+
+// SetBytes sets the private key from buf, where buf is interpreted
+// as publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+// The ciphersuite of the key is left unchanged.
+// It returns the number byte read.
+func (privKey *PrivateKey) SetBytes(buf []byte) (int, error) {
+	n := 0
+	if len(buf) < sizePrivateKey {
+		return n, io.ErrShortBuffer
+	}
+	if _, err := privKey.PublicKey.SetBytes(buf[:sizePublicKey]); err != nil {
+		return 0, err
+	}
+	n += sizePublicKey
+	var sk fr.Element
+	if err := sk.SetBytesCanonical(buf[sizePublicKey:sizePrivateKey]); err != nil {
+		return 0, err
+	}
+	if sk.IsZero() {
+		return 0, bls.ErrZeroSecret
+	}
+	subtle.ConstantTimeCopy(1, privKey.scalar[:], buf[sizePublicKey:sizePrivateKey])
+	n += sizeFr
+	return n, nil
+}
+
+// SetSecret sets the private key from a big endian encoded scalar,
+// recomputing the public key. The ciphersuite of the key is left unchanged.
+// It returns the number byte read.
+func (privKey *PrivateKey) SetSecret(buf []byte) (int, error) {
+	if len(buf) < sizeFr {
+		return 0, io.ErrShortBuffer
+	}
+	var sk fr.Element
+	if err := sk.SetBytesCanonical(buf[:sizeFr]); err != nil {
+		return 0, err
+	}
+	if sk.IsZero() {
+		return 0, bls.ErrZeroSecret
+	}
+	*privKey = *newPrivateKey(&sk, privKey.PublicKey.Mode)
+	return sizeFr, nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fri
+
+import (
+	"errors"
+)
+
+const (
+	defaultBlowup       = rho
+	defaultFoldingArity = 2
+	defaultNbQueries    = 1
+)
+
+var (
+	errBlowup       = errors.New("the blowup factor must be a power of 2 larger than 1")
+	errFoldingArity = errors.New("the folding arity must be 2, 4, 8 or 16")
+	errNbQueries    = errors.New("the number of queries must be positive")
+	errFinalDegree  = errors.New("the degree of the final polynomial must be non-negative")
+	errGrindingBits = errors.New("the number of grinding bits must be between 0 and 63")
+)
+
+// Option defines option for altering the parameters of an Iopp.
+// See the descriptions of functions returning instances of this type for
+// particular options. An Iopp created with parameters other than the default
+// ones builds proofs of proximity made of Steps instead of Rounds.
+type Option func(*friConfig)
+
+type friConfig struct {
+	blowup       int
+	foldingArity int
+	nbQueries    int
+	finalDegree  int
+	grindingBits int
+}
+
+// defaultConfig returns the parameters of an Iopp created without options.
+func defaultConfig() friConfig {
+	return friConfig{
+		blowup:       defaultBlowup,
+		foldingArity: defaultFoldingArity,
+		nbQueries:    defaultNbQueries,
+	}
+}
+
+// default options
+func friOptions(opts ...Option) (friConfig, error) {
+	// apply options
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	// check the parameters
+	switch {
+	case cfg.blowup < 2 || cfg.blowup&(cfg.blowup-1) != 0:
+		return cfg, errBlowup
+	case cfg.foldingArity != 2 && cfg.foldingArity != 4 && cfg.foldingArity != 8 && cfg.foldingArity != 16:
+		return cfg, errFoldingArity
+	case cfg.nbQueries < 1:
+		return cfg, errNbQueries
+	case cfg.finalDegree < 0:
+		return cfg, errFinalDegree
+	case cfg.grindingBits < 0 || cfg.grindingBits > 63:
+		return cfg, errGrindingBits
+	}
+	return cfg, nil
+}
+
+// WithBlowup sets the blowup factor ρ = size_code_word/size_polynomial, a
+// power of 2. A larger blowup factor makes each query more sound, at the cost
+// of a larger evaluation domain for the prover. Default is 8.
+func WithBlowup(rho int) Option {
+	return func(cfg *friConfig) {
+		cfg.blowup = rho
+	}
+}
+
+// WithFoldingArity sets the number of points in a fiber of the folding map
+// x ↦ xᵃʳⁱᵗʸ: 2, 4, 8 or 16. A larger arity reduces the number of
+// commitments and Merkle paths, but each query opens more values. Default is
+// 2.
+func WithFoldingArity(arity int) Option {
+	return func(cfg *friConfig) {
+		cfg.foldingArity = arity
+	}
+}
+
+// WithNbQueries sets the number of queries of the verifier. Each query adds
+// about log₂(ρ) bits of (conjectured) security. Default is 1.
+func WithNbQueries(nbQueries int) Option {
+	return func(cfg *friConfig) {
+		cfg.nbQueries = nbQueries
+	}
+}
+
+// WithFinalDegree sets the degree cutoff of the final polynomial: the folding
+// stops once the folded polynomial is of degree at most finalDegree (rounded
+// up to a power of 2 minus 1), and the prover sends its coefficients. Default
+// is 0, the polynomial is folded down to a constant.
+func WithFinalDegree(finalDegree int) Option {
+	return func(cfg *friConfig) {
+		cfg.finalDegree = finalDegree
+	}
+}
+
+// WithGrindingBits sets the number of bits of the proof of work the prover
+// must solve before the queries are derived. It adds as many bits of security,
+// at the cost of about 2^grindingBits hashes for the prover. Default is 0, no
+// proof of work.
+func WithGrindingBits(grindingBits int) Option {
+	return func(cfg *friConfig) {
+		cfg.grindingBits = grindingBits
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	// DefaultWidth is the width of the permutation used by the sponge when
+	// no width is specified: rate 2, capacity 1.
+	DefaultWidth = 3
+	// BlockSize size that Poseidon2 consumes
+	BlockSize = fr.Bytes
+)
+
+// digest is a sponge construction over the Poseidon2 permutation.
+type digest struct {
+	perm      *Permutation
+	data      []fr.Element // data to hash
+	byteOrder fr.ByteOrder
+}
+
+// NewPoseidon2 returns a Poseidon2 sponge hasher (see package documentation).
+// It panics if the width passed as option is not supported.
+func NewPoseidon2(opts ...Option) hash.Hash {
+	cfg := options(opts...)
+	perm, err := NewPermutation(cfg.width)
+	if err != nil {
+		panic(err)
+	}
+	d := &digest{
+		perm:      perm,
+		byteOrder: cfg.byteOrder,
+	}
+	d.Reset()
+	return d
+}
+
+// Reset resets the Hash to its initial state.
+func (d *digest) Reset() {
+	d.data = d.data[:0]
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (d *digest) Sum(b []byte) []byte {
+	h := d.checksum()
+	bytes := h.Bytes()
+	return append(b, bytes[:]...)
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *digest) Size() int {
+	return BlockSize
+}
+
+// BlockSize returns the hash's underlying block size.
+// The Write method must be able to accept any amount
+// of data, but it may operate more efficiently if all writes
+// are a multiple of the block size.
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+// Write (via the embedded io.Writer interface) adds more data to the running hash.
+//
+// Each []byte block of size BlockSize represents a field element, decoded with
+// the byte order of the hasher (big endian by default).
+//
+// If len(p) is not a multiple of BlockSize and any of the []byte in p represent an integer
+// larger than fr.Modulus, this function returns an error.
+//
+// To hash arbitrary data ([]byte not representing canonical field elements) use fr.Hash first
+func (d *digest) Write(p []byte) (int, error) {
+	// we usually expect multiple of block size. But sometimes we hash short
+	// values (FS transcript). Instead of forcing to hash to field, we left-pad the
+	// input here.
+	if len(p) > 0 && len(p) < BlockSize {
+		pp := make([]byte, BlockSize)
+		copy(pp[len(pp)-len(p):], p)
+		p = pp
+	}
+
+	var start int
+	for start = 0; start < len(p); start += BlockSize {
+		if start+BlockSize > len(p) {
+			break
+		}
+		if elem, err := d.byteOrder.Element((*[BlockSize]byte)(p[start : start+BlockSize])); err == nil {
+			d.data = append(d.data, elem)
+		} else {
+			return 0, err
+		}
+	}
+
+	if start != len(p) {
+		return 0, errors.New("invalid input length: must represent a list of field elements, expects a []byte of len m*BlockSize")
+	}
+	return len(p), nil
+}
+
+// WriteElements adds field elements to the running hash.
+func (d *digest) WriteElements(elems ...fr.Element) {
+	d.data = append(d.data, elems...)
+}
+
+// checksum absorbs the padded data in the rate part of the state, and squeezes
+// a single element.
+func (d *digest) checksum() fr.Element {
+	width := d.perm.Width()
+	rate := width - 1
+
+	state := make([]fr.Element, width)
+	var one fr.Element
+	one.SetOne()
+
+	// pad with a single 1 followed by zeroes, up to a multiple of the rate
+	n := len(d.data) + 1
+	if n%rate != 0 {
+		n += rate - n%rate
+	}
+	for i := 0; i < n; i += rate {
+		for j := 0; j < rate; j++ {
+			switch k := i + j; {
+			case k < len(d.data):
+				state[1+j].Add(&state[1+j], &d.data[k])
+			case k == len(d.data):
+				state[1+j].Add(&state[1+j], &one)
+			}
+		}
+		if err := d.perm.Permutation(state); err != nil {
+			panic(err) // the state has the width of the permutation
+		}
+	}
+
+	return state[1]
+}
+
+// Sum computes the Poseidon2 sponge hash of msg, which must represent a list of
+// big endian encoded field elements.
+func Sum(msg []byte) ([]byte, error) {
+	d := NewPoseidon2()
+	if _, err := d.Write(msg); err != nil {
+		return nil, err
+	}
+	return d.Sum(nil), nil
+}
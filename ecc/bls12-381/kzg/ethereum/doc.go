@@ -0,0 +1,38 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ethereum loads the output of the Ethereum KZG ceremony into a kzg.SRS.
+//
+// The ceremony transcript is distributed in the consensus-specs JSON format,
+// with three arrays of 0x-prefixed hex strings:
+//   - g1_monomial: [τⁱ]G₁ in compressed form (48 bytes),
+//   - g1_lagrange: [Lᵢ(τ)]G₁ in compressed form, in natural order,
+//   - g2_monomial: [τⁱ]G₂ in compressed form (96 bytes).
+//
+// The compressed encoding is the one of the Zcash serialization format, which
+// is also the default encoding of the bls12381 package.
+//
+// The resulting SRS can be used directly with kzg.Commit, kzg.Open and
+// kzg.Verify. The Lagrange points are kept aside for callers that commit to
+// polynomials in evaluation form.
+//
+// The package also implements the KZG functions of EIP-4844 on top of the
+// ceremony output, see [Context]. Blobs are 4096 evaluations of a polynomial
+// on the roots of unity, in bit-reversed order.
+//
+// Documentation:
+//   - consensus-specs: https://github.com/ethereum/consensus-specs/blob/dev/presets/mainnet/trusted_setups/trusted_setup_4096.json
+//   - ceremony: https://github.com/ethereum/kzg-ceremony-specs
+//   - EIP-4844: https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md
+package ethereum
@@ -0,0 +1,443 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+const (
+	// FieldElementsPerBlob number of evaluations in a blob
+	FieldElementsPerBlob = 4096
+	// BytesPerFieldElement size of a big-endian encoded evaluation
+	BytesPerFieldElement = fr.Bytes
+	// BytesPerBlob size of a blob
+	BytesPerBlob = FieldElementsPerBlob * BytesPerFieldElement
+	// BytesPerCommitment size of a compressed commitment
+	BytesPerCommitment = bls12381.SizeOfG1AffineCompressed
+	// BytesPerProof size of a compressed proof
+	BytesPerProof = bls12381.SizeOfG1AffineCompressed
+)
+
+// domain separation tags of the Fiat-Shamir challenges
+const (
+	fiatShamirProtocolDomain      = "FSBLOBVERIFY_V1_"
+	randomChallengeKZGBatchDomain = "RCKZGBATCH___V1_"
+)
+
+var (
+	ErrInvalidSetupSize   = fmt.Errorf("trusted setup must have %d Lagrange points", FieldElementsPerBlob)
+	ErrInvalidBatchLength = errors.New("blobs, commitments and proofs must have the same length")
+)
+
+// Blob FieldElementsPerBlob big-endian encoded evaluations of a polynomial on
+// the roots of unity, in bit-reversed order.
+type Blob [BytesPerBlob]byte
+
+// Commitment compressed KZG commitment to a blob.
+type Commitment [BytesPerCommitment]byte
+
+// Proof compressed KZG opening proof.
+type Proof [BytesPerProof]byte
+
+// Scalar big-endian encoded element of fr.
+type Scalar [BytesPerFieldElement]byte
+
+// Context holds the precomputed data needed by the EIP-4844 functions. It is
+// safe for concurrent use.
+type Context struct {
+	// pk Lagrange points in bit-reversed order, matching the blob layout
+	pk kzg.ProvingKey
+	vk kzg.VerifyingKey
+
+	domain *fft.Domain
+
+	// rootsBRP roots of unity in bit-reversed order
+	rootsBRP []fr.Element
+}
+
+// NewContext returns a context for the EIP-4844 functions from a trusted setup
+// with FieldElementsPerBlob points, such as the output of the Ethereum KZG
+// ceremony.
+func NewContext(ts *TrustedSetup) (*Context, error) {
+	if len(ts.G1Lagrange) != FieldElementsPerBlob {
+		return nil, ErrInvalidSetupSize
+	}
+
+	ctx := &Context{
+		vk:       ts.SRS.Vk,
+		domain:   fft.NewDomain(FieldElementsPerBlob),
+		rootsBRP: make([]fr.Element, FieldElementsPerBlob),
+	}
+
+	ctx.pk.G1 = make([]bls12381.G1Affine, FieldElementsPerBlob)
+	copy(ctx.pk.G1, ts.G1Lagrange)
+	bitReverse(ctx.pk.G1)
+
+	ctx.rootsBRP[0].SetOne()
+	for i := 1; i < FieldElementsPerBlob; i++ {
+		ctx.rootsBRP[i].Mul(&ctx.rootsBRP[i-1], &ctx.domain.Generator)
+	}
+	fft.BitReverse(ctx.rootsBRP)
+
+	return ctx, nil
+}
+
+// BlobToKZGCommitment returns the commitment to the polynomial whose
+// evaluations are in blob.
+//
+// This is blob_to_kzg_commitment from the consensus specs.
+func (ctx *Context) BlobToKZGCommitment(blob *Blob) (Commitment, error) {
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return Commitment{}, err
+	}
+	digest, err := kzg.Commit(polynomial, ctx.pk)
+	if err != nil {
+		return Commitment{}, err
+	}
+	return digest.Bytes(), nil
+}
+
+// ComputeKZGProof returns a proof that the polynomial whose evaluations are
+// in blob evaluates to y at z, along with y.
+//
+// This is compute_kzg_proof from the consensus specs.
+func (ctx *Context) ComputeKZGProof(blob *Blob, z Scalar) (Proof, Scalar, error) {
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return Proof{}, Scalar{}, err
+	}
+	point, err := fr.BigEndian.Element((*[fr.Bytes]byte)(&z))
+	if err != nil {
+		return Proof{}, Scalar{}, err
+	}
+	proof, err := ctx.computeKZGProof(polynomial, point)
+	if err != nil {
+		return Proof{}, Scalar{}, err
+	}
+	return proof.H.Bytes(), proof.ClaimedValue.Bytes(), nil
+}
+
+// ComputeBlobKZGProof returns a proof that the polynomial whose evaluations
+// are in blob is the one committed to by commitment. The proof is an opening
+// proof at a Fiat-Shamir challenge derived from blob and commitment.
+//
+// This is compute_blob_kzg_proof from the consensus specs.
+func (ctx *Context) ComputeBlobKZGProof(blob *Blob, commitment Commitment) (Proof, error) {
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return Proof{}, err
+	}
+	if _, err = decodeG1Point(commitment[:]); err != nil {
+		return Proof{}, err
+	}
+	proof, err := ctx.computeKZGProof(polynomial, computeChallenge(blob, &commitment))
+	if err != nil {
+		return Proof{}, err
+	}
+	return proof.H.Bytes(), nil
+}
+
+// VerifyKZGProof checks that proof opens commitment to y at z.
+//
+// This is verify_kzg_proof from the consensus specs.
+func (ctx *Context) VerifyKZGProof(commitment Commitment, z, y Scalar, proof Proof) error {
+	digest, err := decodeG1Point(commitment[:])
+	if err != nil {
+		return err
+	}
+	var openingProof kzg.OpeningProof
+	if openingProof.H, err = decodeG1Point(proof[:]); err != nil {
+		return err
+	}
+	point, err := fr.BigEndian.Element((*[fr.Bytes]byte)(&z))
+	if err != nil {
+		return err
+	}
+	if openingProof.ClaimedValue, err = fr.BigEndian.Element((*[fr.Bytes]byte)(&y)); err != nil {
+		return err
+	}
+	return kzg.Verify(&digest, &openingProof, point, ctx.vk)
+}
+
+// VerifyBlobKZGProof checks that proof is a valid proof for blob and commitment,
+// as computed by ComputeBlobKZGProof.
+//
+// This is verify_blob_kzg_proof from the consensus specs.
+func (ctx *Context) VerifyBlobKZGProof(blob *Blob, commitment Commitment, proof Proof) error {
+	digest, err := decodeG1Point(commitment[:])
+	if err != nil {
+		return err
+	}
+	var openingProof kzg.OpeningProof
+	if openingProof.H, err = decodeG1Point(proof[:]); err != nil {
+		return err
+	}
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return err
+	}
+	point := computeChallenge(blob, &commitment)
+	openingProof.ClaimedValue = ctx.evaluate(polynomial, point)
+	return kzg.Verify(&digest, &openingProof, point, ctx.vk)
+}
+
+// VerifyBlobKZGProofBatch checks a list of proofs computed by
+// ComputeBlobKZGProof with a single pairing check.
+//
+// This is verify_blob_kzg_proof_batch from the consensus specs. In particular,
+// the random linear combination is derived deterministically from the inputs.
+func (ctx *Context) VerifyBlobKZGProofBatch(blobs []Blob, commitments []Commitment, proofs []Proof) error {
+	n := len(blobs)
+	if len(commitments) != n || len(proofs) != n {
+		return ErrInvalidBatchLength
+	}
+	if n == 0 {
+		return nil
+	}
+
+	digests := make([]bls12381.G1Affine, n)
+	hs := make([]bls12381.G1Affine, n)
+	points := make([]fr.Element, n)
+	claimedValues := make([]fr.Element, n)
+	errs := make([]error, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			if digests[i], errs[i] = decodeG1Point(commitments[i][:]); errs[i] != nil {
+				continue
+			}
+			if hs[i], errs[i] = decodeG1Point(proofs[i][:]); errs[i] != nil {
+				continue
+			}
+			var polynomial []fr.Element
+			if polynomial, errs[i] = blobToPolynomial(&blobs[i]); errs[i] != nil {
+				continue
+			}
+			points[i] = computeChallenge(&blobs[i], &commitments[i])
+			claimedValues[i] = ctx.evaluate(polynomial, points[i])
+		}
+	})
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("blob %d: %w", i, err)
+		}
+	}
+
+	// r = H(RANDOM_CHALLENGE_KZG_BATCH_DOMAIN ‖ FIELD_ELEMENTS_PER_BLOB ‖ n ‖ (Cᵢ ‖ zᵢ ‖ yᵢ ‖ πᵢ)ᵢ)
+	h := sha256.New()
+	var buf [8]byte
+	h.Write([]byte(randomChallengeKZGBatchDomain))
+	binary.BigEndian.PutUint64(buf[:], FieldElementsPerBlob)
+	h.Write(buf[:])
+	binary.BigEndian.PutUint64(buf[:], uint64(n))
+	h.Write(buf[:])
+	for i := 0; i < n; i++ {
+		h.Write(commitments[i][:])
+		z, y := points[i].Bytes(), claimedValues[i].Bytes()
+		h.Write(z[:])
+		h.Write(y[:])
+		h.Write(proofs[i][:])
+	}
+	var r fr.Element
+	r.SetBytes(h.Sum(nil))
+
+	// e(∑ᵢrⁱπᵢ, [τ]G₂) = e(∑ᵢrⁱ(Cᵢ - [yᵢ]G₁ + [zᵢ]πᵢ), G₂)
+	// the right-hand side is computed as one multi-exponentiation on
+	// (Cᵢ)ᵢ ‖ (πᵢ)ᵢ ‖ G₁ with scalars (rⁱ)ᵢ ‖ (rⁱzᵢ)ᵢ ‖ -∑ᵢrⁱyᵢ
+	bases := make([]bls12381.G1Affine, 2*n+1)
+	scalars := make([]fr.Element, 2*n+1)
+	copy(bases, digests)
+	copy(bases[n:], hs)
+	bases[2*n] = ctx.vk.G1
+	var sumY, tmp fr.Element
+	scalars[0].SetOne()
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			scalars[i].Mul(&scalars[i-1], &r)
+		}
+		scalars[n+i].Mul(&scalars[i], &points[i])
+		tmp.Mul(&scalars[i], &claimedValues[i])
+		sumY.Add(&sumY, &tmp)
+	}
+	scalars[2*n].Neg(&sumY)
+
+	config := ecc.MultiExpConfig{}
+	var lhs, rhs bls12381.G1Affine
+	if _, err := lhs.MultiExp(hs, scalars[:n], config); err != nil {
+		return err
+	}
+	if _, err := rhs.MultiExp(bases, scalars, config); err != nil {
+		return err
+	}
+	lhs.Neg(&lhs)
+
+	// the pairing evaluates the precomputed lines in place, so it must work on
+	// a copy of the verifying key
+	vk := ctx.vk
+	check, err := bls12381.PairingCheckFixedQ(
+		[]bls12381.G1Affine{rhs, lhs},
+		vk.Lines[:],
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return kzg.ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// computeKZGProof returns the opening proof at z of the polynomial given by
+// its evaluations on the roots of unity in bit-reversed order.
+//
+// The quotient q(X) = (p(X) - y) / (X - z) is computed in evaluation form. If
+// z is the root ωₘ, its evaluation at ωₘ is obtained from
+// q(ωₘ) = ∑_{i≠m} (p(ωᵢ) - y)ωᵢ / (ωₘ(ωₘ - ωᵢ)).
+func (ctx *Context) computeKZGProof(polynomial []fr.Element, z fr.Element) (kzg.OpeningProof, error) {
+	var res kzg.OpeningProof
+	res.ClaimedValue = ctx.evaluate(polynomial, z)
+
+	quotient := make([]fr.Element, FieldElementsPerBlob)
+	for i := range quotient {
+		quotient[i].Sub(&ctx.rootsBRP[i], &z)
+	}
+	// the denominator vanishes at most once, BatchInvert leaves it to 0
+	denominators := fr.BatchInvert(quotient)
+
+	m := -1
+	for i := range quotient {
+		if ctx.rootsBRP[i].Equal(&z) {
+			m = i
+			continue
+		}
+		quotient[i].Sub(&polynomial[i], &res.ClaimedValue).
+			Mul(&quotient[i], &denominators[i])
+	}
+
+	if m != -1 {
+		// ωᵢ / (ωₘ(ωₘ - ωᵢ)) = -ωᵢ / ωₘ ⋅ 1/(ωᵢ - ωₘ)
+		var zInv, tmp fr.Element
+		zInv.Inverse(&z)
+		quotient[m].SetZero()
+		for i := range polynomial {
+			if i == m {
+				continue
+			}
+			tmp.Sub(&polynomial[i], &res.ClaimedValue).
+				Mul(&tmp, &ctx.rootsBRP[i]).
+				Mul(&tmp, &denominators[i])
+			quotient[m].Sub(&quotient[m], &tmp)
+		}
+		quotient[m].Mul(&quotient[m], &zInv)
+	}
+
+	var err error
+	res.H, err = kzg.Commit(quotient, ctx.pk)
+	return res, err
+}
+
+// evaluate evaluates at z the polynomial given by its evaluations on the roots
+// of unity in bit-reversed order, with the barycentric formula
+// p(z) = (zⁿ - 1)/n ⋅ ∑ᵢ p(ωᵢ)ωᵢ/(z - ωᵢ).
+func (ctx *Context) evaluate(polynomial []fr.Element, z fr.Element) fr.Element {
+	for i := range ctx.rootsBRP {
+		if ctx.rootsBRP[i].Equal(&z) {
+			return polynomial[i]
+		}
+	}
+
+	denominators := make([]fr.Element, FieldElementsPerBlob)
+	for i := range denominators {
+		denominators[i].Sub(&z, &ctx.rootsBRP[i])
+	}
+	denominators = fr.BatchInvert(denominators)
+
+	var res, tmp fr.Element
+	for i := range polynomial {
+		tmp.Mul(&polynomial[i], &ctx.rootsBRP[i]).
+			Mul(&tmp, &denominators[i])
+		res.Add(&res, &tmp)
+	}
+
+	tmp.Exp(z, big.NewInt(FieldElementsPerBlob))
+	tmp.Sub(&tmp, new(fr.Element).SetOne())
+	res.Mul(&res, &tmp).Mul(&res, &ctx.domain.CardinalityInv)
+
+	return res
+}
+
+// blobToPolynomial decodes the evaluations of a blob, which must be canonical.
+func blobToPolynomial(blob *Blob) ([]fr.Element, error) {
+	res := make([]fr.Element, FieldElementsPerBlob)
+	for i := range res {
+		var err error
+		res[i], err = fr.BigEndian.Element((*[fr.Bytes]byte)(blob[i*BytesPerFieldElement : (i+1)*BytesPerFieldElement]))
+		if err != nil {
+			return nil, fmt.Errorf("field element %d: %w", i, err)
+		}
+	}
+	return res, nil
+}
+
+// computeChallenge returns the Fiat-Shamir challenge
+// H(FIAT_SHAMIR_PROTOCOL_DOMAIN ‖ FIELD_ELEMENTS_PER_BLOB ‖ blob ‖ commitment) mod r
+// where FIELD_ELEMENTS_PER_BLOB is encoded on 16 bytes.
+func computeChallenge(blob *Blob, commitment *Commitment) fr.Element {
+	var degree [16]byte
+	binary.BigEndian.PutUint64(degree[8:], FieldElementsPerBlob)
+
+	h := sha256.New()
+	h.Write([]byte(fiatShamirProtocolDomain))
+	h.Write(degree[:])
+	h.Write(blob[:])
+	h.Write(commitment[:])
+
+	var res fr.Element
+	res.SetBytes(h.Sum(nil))
+	return res
+}
+
+// decodeG1Point decodes a compressed point, checking it is in the prime order
+// subgroup. The point at infinity is valid.
+func decodeG1Point(b []byte) (bls12381.G1Affine, error) {
+	var p bls12381.G1Affine
+	_, err := p.SetBytes(b)
+	return p, err
+}
+
+func bitReverse[T any](a []T) {
+	n := uint64(len(a))
+	nn := uint64(64 - bits.TrailingZeros64(n))
+
+	for i := uint64(0); i < n; i++ {
+		irev := bits.Reverse64(i) >> nn
+		if irev > i {
+			a[i], a[irev] = a[irev], a[i]
+		}
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+)
+
+var (
+	testContextOnce sync.Once
+	testContext     *Context
+	testSRS         *kzg.SRS
+)
+
+// getTestContext returns a context built from a toy setup of size
+// FieldElementsPerBlob with τ = testTau.
+func getTestContext(t testing.TB) (*Context, *kzg.SRS) {
+	testContextOnce.Do(func() {
+		srs, err := kzg.NewSRS(FieldElementsPerBlob, big.NewInt(testTau))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Lᵢ(τ) = ωⁱ/n ⋅ (τⁿ - 1)/(τ - ωⁱ), cheaper than kzg.ToLagrangeG1
+		domain := fft.NewDomain(FieldElementsPerBlob)
+		var tau, tauN, omegaI fr.Element
+		tau.SetUint64(testTau)
+		tauN.Exp(tau, big.NewInt(FieldElementsPerBlob)).Sub(&tauN, new(fr.Element).SetOne())
+		tauN.Mul(&tauN, &domain.CardinalityInv)
+		scalars := make([]fr.Element, FieldElementsPerBlob)
+		omegaI.SetOne()
+		for i := range scalars {
+			scalars[i].Sub(&tau, &omegaI)
+			omegaI.Mul(&omegaI, &domain.Generator)
+		}
+		scalars = fr.BatchInvert(scalars)
+		omegaI.SetOne()
+		for i := range scalars {
+			scalars[i].Mul(&scalars[i], &omegaI).Mul(&scalars[i], &tauN)
+			omegaI.Mul(&omegaI, &domain.Generator)
+		}
+		lagrange := bls12381.BatchScalarMultiplicationG1(&srs.Vk.G1, scalars)
+		ts := TrustedSetup{SRS: *srs, G1Lagrange: lagrange, G2: srs.Vk.G2[:]}
+		if testContext, err = NewContext(&ts); err != nil {
+			t.Fatal(err)
+		}
+		testSRS = srs
+	})
+	if testContext == nil {
+		t.Fatal("failed to build the test context")
+	}
+	return testContext, testSRS
+}
+
+// randomBlob returns a random blob, along with the coefficients of its polynomial.
+func randomBlob() (*Blob, []fr.Element) {
+	coefficients := make([]fr.Element, FieldElementsPerBlob)
+	for i := range coefficients {
+		coefficients[i].SetRandom()
+	}
+	evaluations := make([]fr.Element, FieldElementsPerBlob)
+	copy(evaluations, coefficients)
+	// the DIF FFT outputs the evaluations in bit-reversed order, as in a blob
+	fft.NewDomain(FieldElementsPerBlob).FFT(evaluations, fft.DIF)
+
+	var blob Blob
+	for i := range evaluations {
+		fr.BigEndian.PutElement((*[fr.Bytes]byte)(blob[i*BytesPerFieldElement:]), evaluations[i])
+	}
+	return &blob, coefficients
+}
+
+func eval(coefficients []fr.Element, z fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		res.Mul(&res, &z).Add(&res, &coefficients[i])
+	}
+	return res
+}
+
+func TestBlobToKZGCommitment(t *testing.T) {
+	ctx, srs := getTestContext(t)
+
+	var zero Blob
+	commitment, err := ctx.BlobToKZGCommitment(&zero)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var infinity bls12381.G1Affine
+	if commitment != infinity.Bytes() {
+		t.Fatal("commitment to the zero blob should be the point at infinity")
+	}
+
+	blob, coefficients := randomBlob()
+	commitment, err = ctx.BlobToKZGCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := kzg.Commit(coefficients, srs.Pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commitment != digest.Bytes() {
+		t.Fatal("commitment differs from the commitment in monomial form")
+	}
+
+	// non canonical field element
+	copy(blob[BytesPerFieldElement:], fr.Modulus().FillBytes(make([]byte, fr.Bytes)))
+	if _, err = ctx.BlobToKZGCommitment(blob); err == nil {
+		t.Fatal("expected an error on a non canonical field element")
+	}
+}
+
+func TestComputeKZGProof(t *testing.T) {
+	ctx, srs := getTestContext(t)
+
+	blob, coefficients := randomBlob()
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("outside the domain", func(t *testing.T) {
+		var point fr.Element
+		point.SetRandom()
+		proof, y, err := ctx.ComputeKZGProof(blob, point.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := eval(coefficients, point)
+		if y != expected.Bytes() {
+			t.Fatal("wrong evaluation")
+		}
+		openingProof, err := kzg.Open(coefficients, point, srs.Pk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proof != openingProof.H.Bytes() {
+			t.Fatal("proof differs from the proof in monomial form")
+		}
+		if err = ctx.VerifyKZGProof(commitment, point.Bytes(), y, proof); err != nil {
+			t.Fatal(err)
+		}
+
+		expected.Add(&expected, new(fr.Element).SetOne())
+		if err = ctx.VerifyKZGProof(commitment, point.Bytes(), expected.Bytes(), proof); !errors.Is(err, kzg.ErrVerifyOpeningProof) {
+			t.Fatal("expected ErrVerifyOpeningProof, got", err)
+		}
+	})
+
+	t.Run("inside the domain", func(t *testing.T) {
+		const index = 5
+		point := ctx.rootsBRP[index]
+		proof, y, err := ctx.ComputeKZGProof(blob, point.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if [BytesPerFieldElement]byte(blob[index*BytesPerFieldElement:]) != y {
+			t.Fatal("evaluation at a root of unity should be read from the blob")
+		}
+		openingProof, err := kzg.Open(coefficients, point, srs.Pk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proof != openingProof.H.Bytes() {
+			t.Fatal("proof differs from the proof in monomial form")
+		}
+		if err = ctx.VerifyKZGProof(commitment, point.Bytes(), y, proof); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestBlobKZGProof(t *testing.T) {
+	ctx, _ := getTestContext(t)
+
+	const nbBlobs = 3
+	blobs := make([]Blob, nbBlobs)
+	commitments := make([]Commitment, nbBlobs)
+	proofs := make([]Proof, nbBlobs)
+	for i := range blobs {
+		blob, _ := randomBlob()
+		blobs[i] = *blob
+		var err error
+		if commitments[i], err = ctx.BlobToKZGCommitment(blob); err != nil {
+			t.Fatal(err)
+		}
+		if proofs[i], err = ctx.ComputeBlobKZGProof(blob, commitments[i]); err != nil {
+			t.Fatal(err)
+		}
+		if err = ctx.VerifyBlobKZGProof(blob, commitments[i], proofs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.VerifyBlobKZGProofBatch(nil, nil, nil); err != nil {
+		t.Fatal("an empty batch is valid:", err)
+	}
+	if err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs[1:]); !errors.Is(err, ErrInvalidBatchLength) {
+		t.Fatal("expected ErrInvalidBatchLength, got", err)
+	}
+
+	// proofs for another blob
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	if err := ctx.VerifyBlobKZGProof(&blobs[0], commitments[0], proofs[0]); !errors.Is(err, kzg.ErrVerifyOpeningProof) {
+		t.Fatal("expected ErrVerifyOpeningProof, got", err)
+	}
+	if err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs); !errors.Is(err, kzg.ErrVerifyOpeningProof) {
+		t.Fatal("expected ErrVerifyOpeningProof, got", err)
+	}
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+
+	// invalid encoding of a commitment
+	commitments[2][0] &^= 0x80
+	if err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs); err == nil || errors.Is(err, kzg.ErrVerifyOpeningProof) {
+		t.Fatal("expected a decoding error, got", err)
+	}
+	if _, err := ctx.ComputeBlobKZGProof(&blobs[2], commitments[2]); err == nil {
+		t.Fatal("expected a decoding error")
+	}
+}
+
+func BenchmarkBlobToKZGCommitment(b *testing.B) {
+	ctx, _ := getTestContext(b)
+	blob, _ := randomBlob()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ctx.BlobToKZGCommitment(blob)
+	}
+}
+
+func BenchmarkComputeBlobKZGProof(b *testing.B) {
+	ctx, _ := getTestContext(b)
+	blob, _ := randomBlob()
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ctx.ComputeBlobKZGProof(blob, commitment)
+	}
+}
+
+func BenchmarkVerifyBlobKZGProofBatch(b *testing.B) {
+	ctx, _ := getTestContext(b)
+	const nbBlobs = 16
+	blobs := make([]Blob, nbBlobs)
+	commitments := make([]Commitment, nbBlobs)
+	proofs := make([]Proof, nbBlobs)
+	for i := range blobs {
+		blob, _ := randomBlob()
+		blobs[i] = *blob
+		commitments[i], _ = ctx.BlobToKZGCommitment(blob)
+		proofs[i], _ = ctx.ComputeBlobKZGProof(blob, commitments[i])
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	}
+}
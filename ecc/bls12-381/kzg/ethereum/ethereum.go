@@ -0,0 +1,226 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidSize     = errors.New("trusted setup has an invalid number of points")
+	ErrInvalidLagrange = errors.New("lagrange points do not match the monomial points")
+	ErrInvalidG2       = errors.New("G2 points are not successive powers of τ")
+)
+
+// TrustedSetup is the output of the Ethereum KZG ceremony.
+type TrustedSetup struct {
+	// SRS made of the monomial points. Only the first two G2 points are part of
+	// the verifying key.
+	SRS kzg.SRS
+
+	// G1Lagrange [Lᵢ(τ)]G₁ where Lᵢ is the i-th Lagrange polynomial on the
+	// subgroup of size len(G1Lagrange) generated by fr.Generator, in natural order.
+	G1Lagrange []bls12381.G1Affine
+
+	// G2 [G₂, [τ]G₂, [τ²]G₂, ...] as published by the ceremony.
+	G2 []bls12381.G2Affine
+}
+
+type config struct {
+	consistencyCheck bool
+}
+
+// Option allows to configure how a trusted setup is read.
+type Option func(*config)
+
+// WithConsistencyCheck checks that the points are successive powers of a same τ
+// and that the Lagrange points match the monomial ones. This costs a few
+// multi-exponentiations, a pairing check and an FFT in G₁.
+func WithConsistencyCheck() Option {
+	return func(c *config) {
+		c.consistencyCheck = true
+	}
+}
+
+// jsonTrustedSetup mirrors the consensus-specs JSON file.
+type jsonTrustedSetup struct {
+	G1Monomial []string `json:"g1_monomial"`
+	G1Lagrange []string `json:"g1_lagrange"`
+	G2Monomial []string `json:"g2_monomial"`
+}
+
+// ReadTrustedSetup reads a trusted setup in the consensus-specs JSON format.
+//
+// Every point is checked to be on the curve and in the prime order subgroup.
+// The relations between the points are only checked with [WithConsistencyCheck].
+func ReadTrustedSetup(r io.Reader, opts ...Option) (*TrustedSetup, error) {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var raw jsonTrustedSetup
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	n := len(raw.G1Monomial)
+	if n < 2 || bits.OnesCount(uint(n)) != 1 || len(raw.G1Lagrange) != n || len(raw.G2Monomial) < 2 {
+		return nil, ErrInvalidSize
+	}
+
+	var ts TrustedSetup
+	var err error
+	if ts.SRS.Pk.G1, err = decodeG1(raw.G1Monomial); err != nil {
+		return nil, fmt.Errorf("g1_monomial: %w", err)
+	}
+	if ts.G1Lagrange, err = decodeG1(raw.G1Lagrange); err != nil {
+		return nil, fmt.Errorf("g1_lagrange: %w", err)
+	}
+	if ts.G2, err = decodeG2(raw.G2Monomial); err != nil {
+		return nil, fmt.Errorf("g2_monomial: %w", err)
+	}
+
+	ts.SRS.Vk.G1 = ts.SRS.Pk.G1[0]
+	ts.SRS.Vk.G2[0] = ts.G2[0]
+	ts.SRS.Vk.G2[1] = ts.G2[1]
+	ts.SRS.Vk.Lines[0] = bls12381.PrecomputeLines(ts.SRS.Vk.G2[0])
+	ts.SRS.Vk.Lines[1] = bls12381.PrecomputeLines(ts.SRS.Vk.G2[1])
+
+	if cfg.consistencyCheck {
+		if err = ts.CheckConsistency(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ts, nil
+}
+
+// CheckConsistency checks that the monomial points are successive powers of a
+// same τ, in G₁ and G₂, and that the Lagrange points are the Lagrange form of
+// the monomial ones.
+func (ts *TrustedSetup) CheckConsistency() error {
+	if err := ts.SRS.CheckConsistency(); err != nil {
+		return err
+	}
+
+	// e(∑ᵢρⁱ[τ]G₁, [τⁱ]G₂) = e(∑ᵢρⁱG₁, [τⁱ⁺¹]G₂)
+	// Since SRS.CheckConsistency already binds [τ]G₁ and [τ]G₂, this binds
+	// every G₂ point to the same τ.
+	if len(ts.G2) > 2 {
+		n := len(ts.G2) - 1
+		var rho fr.Element
+		if _, err := rho.SetRandom(); err != nil {
+			return err
+		}
+		rhos := make([]fr.Element, n)
+		rhos[0].SetOne()
+		for i := 1; i < n; i++ {
+			rhos[i].Mul(&rhos[i-1], &rho)
+		}
+		var a, b bls12381.G2Affine
+		config := ecc.MultiExpConfig{}
+		if _, err := a.MultiExp(ts.G2[:n], rhos, config); err != nil {
+			return err
+		}
+		if _, err := b.MultiExp(ts.G2[1:], rhos, config); err != nil {
+			return err
+		}
+		var g1 bls12381.G1Affine
+		g1.Neg(&ts.SRS.Pk.G1[0])
+		check, err := bls12381.PairingCheck(
+			[]bls12381.G1Affine{ts.SRS.Pk.G1[1], g1},
+			[]bls12381.G2Affine{a, b},
+		)
+		if err != nil {
+			return err
+		}
+		if !check {
+			return ErrInvalidG2
+		}
+	}
+
+	lagrange, err := kzg.ToLagrangeG1(ts.SRS.Pk.G1)
+	if err != nil {
+		return err
+	}
+	if len(lagrange) != len(ts.G1Lagrange) {
+		return ErrInvalidLagrange
+	}
+	for i := range lagrange {
+		if !lagrange[i].Equal(&ts.G1Lagrange[i]) {
+			return ErrInvalidLagrange
+		}
+	}
+	return nil
+}
+
+// decodeG1 decodes 0x-prefixed hex compressed G1 points in parallel.
+func decodeG1(in []string) ([]bls12381.G1Affine, error) {
+	res := make([]bls12381.G1Affine, len(in))
+	err := decodeAll(len(in), func(i int) error {
+		return decodePoint(in[i], bls12381.SizeOfG1AffineCompressed, res[i].SetBytes)
+	})
+	return res, err
+}
+
+// decodeG2 decodes 0x-prefixed hex compressed G2 points in parallel.
+func decodeG2(in []string) ([]bls12381.G2Affine, error) {
+	res := make([]bls12381.G2Affine, len(in))
+	err := decodeAll(len(in), func(i int) error {
+		return decodePoint(in[i], bls12381.SizeOfG2AffineCompressed, res[i].SetBytes)
+	})
+	return res, err
+}
+
+func decodePoint(s string, size int, setBytes func([]byte) (int, error)) error {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return err
+	}
+	if len(b) != size {
+		return fmt.Errorf("invalid point length %d, expected %d", len(b), size)
+	}
+	_, err = setBytes(b)
+	return err
+}
+
+// decodeAll runs decode on [0, n) in parallel and returns the first error, if any.
+func decodeAll(n int, decode func(i int) error) error {
+	errs := make([]error, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			errs[i] = decode(i)
+		}
+	})
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("point %d: %w", i, err)
+		}
+	}
+	return nil
+}
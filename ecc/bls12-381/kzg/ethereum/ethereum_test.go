@@ -0,0 +1,190 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+)
+
+// testdata/trusted_setup_16.json is a trusted setup of size 16 in the
+// consensus-specs format, computed with the toy secret τ = 123456789.
+const (
+	testSetup = "testdata/trusted_setup_16.json"
+	testTau   = 123456789
+)
+
+func readTestSetup(t *testing.T) []byte {
+	t.Helper()
+	b, err := os.ReadFile(testSetup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestReadTrustedSetup(t *testing.T) {
+	ts, err := ReadTrustedSetup(bytes.NewReader(readTestSetup(t)), WithConsistencyCheck())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := kzg.NewSRS(16, big.NewInt(testTau))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ts.SRS.Pk.G1) != len(expected.Pk.G1) {
+		t.Fatal("wrong number of G1 points")
+	}
+	for i := range expected.Pk.G1 {
+		if !ts.SRS.Pk.G1[i].Equal(&expected.Pk.G1[i]) {
+			t.Fatalf("G1 point %d differs", i)
+		}
+	}
+	if !ts.SRS.Vk.G1.Equal(&expected.Vk.G1) ||
+		!ts.SRS.Vk.G2[0].Equal(&expected.Vk.G2[0]) ||
+		!ts.SRS.Vk.G2[1].Equal(&expected.Vk.G2[1]) {
+		t.Fatal("verifying key differs")
+	}
+	if ts.SRS.Vk.Lines != expected.Vk.Lines {
+		t.Fatal("precomputed lines differ")
+	}
+}
+
+func TestCommitOpenVerify(t *testing.T) {
+	ts, err := ReadTrustedSetup(bytes.NewReader(readTestSetup(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := make([]fr.Element, len(ts.SRS.Pk.G1))
+	for i := range p {
+		p[i].SetRandom()
+	}
+	digest, err := kzg.Commit(p, ts.SRS.Pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// committing in Lagrange form with G1Lagrange gives the same digest
+	evals := make([]fr.Element, len(p))
+	var omega, x fr.Element
+	omega, err = fr.Generator(uint64(len(p)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	x.SetOne()
+	for i := range evals {
+		for j := len(p) - 1; j >= 0; j-- {
+			evals[i].Mul(&evals[i], &x).Add(&evals[i], &p[j])
+		}
+		x.Mul(&x, &omega)
+	}
+	lagrangeDigest, err := kzg.Commit(evals, kzg.ProvingKey{G1: ts.G1Lagrange})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lagrangeDigest.Equal(&digest) {
+		t.Fatal("commitments in monomial and Lagrange form differ")
+	}
+
+	var point fr.Element
+	point.SetRandom()
+	proof, err := kzg.Open(p, point, ts.SRS.Pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = kzg.Verify(&digest, &proof, point, ts.SRS.Vk); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadTrustedSetupInvalid(t *testing.T) {
+	var raw jsonTrustedSetup
+	if err := json.Unmarshal(readTestSetup(t), &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	read := func(raw jsonTrustedSetup, opts ...Option) error {
+		b, err := json.Marshal(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = ReadTrustedSetup(bytes.NewReader(b), opts...)
+		return err
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		corrupted := raw
+		corrupted.G1Monomial = raw.G1Monomial[:15]
+		if err := read(corrupted); !errors.Is(err, ErrInvalidSize) {
+			t.Fatal("expected ErrInvalidSize, got", err)
+		}
+	})
+
+	t.Run("not on curve", func(t *testing.T) {
+		corrupted := raw
+		corrupted.G1Monomial = append([]string{}, raw.G1Monomial...)
+		// flipping the last byte of the x-coordinate leaves the curve
+		// (or the subgroup) with overwhelming probability
+		b := []byte(corrupted.G1Monomial[3])
+		if b[len(b)-1] == '0' {
+			b[len(b)-1] = '1'
+		} else {
+			b[len(b)-1] = '0'
+		}
+		corrupted.G1Monomial[3] = string(b)
+		if err := read(corrupted); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("swapped powers", func(t *testing.T) {
+		corrupted := raw
+		corrupted.G1Monomial = append([]string{}, raw.G1Monomial...)
+		corrupted.G1Monomial[4], corrupted.G1Monomial[5] = corrupted.G1Monomial[5], corrupted.G1Monomial[4]
+		if err := read(corrupted); err != nil {
+			t.Fatal("points are valid, only the consistency check should fail:", err)
+		}
+		if err := read(corrupted, WithConsistencyCheck()); !errors.Is(err, kzg.ErrInvalidSRS) {
+			t.Fatal("expected ErrInvalidSRS, got", err)
+		}
+	})
+
+	t.Run("swapped lagrange", func(t *testing.T) {
+		corrupted := raw
+		corrupted.G1Lagrange = append([]string{}, raw.G1Lagrange...)
+		corrupted.G1Lagrange[1], corrupted.G1Lagrange[2] = corrupted.G1Lagrange[2], corrupted.G1Lagrange[1]
+		if err := read(corrupted, WithConsistencyCheck()); !errors.Is(err, ErrInvalidLagrange) {
+			t.Fatal("expected ErrInvalidLagrange, got", err)
+		}
+	})
+
+	t.Run("swapped G2", func(t *testing.T) {
+		corrupted := raw
+		corrupted.G2Monomial = append([]string{}, raw.G2Monomial...)
+		corrupted.G2Monomial[2], corrupted.G2Monomial[3] = corrupted.G2Monomial[3], corrupted.G2Monomial[2]
+		if err := read(corrupted, WithConsistencyCheck()); !errors.Is(err, ErrInvalidG2) {
+			t.Fatal("expected ErrInvalidG2, got", err)
+		}
+	})
+}
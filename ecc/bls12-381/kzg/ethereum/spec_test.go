@@ -0,0 +1,255 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"compress/gzip"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	"gopkg.in/yaml.v2"
+)
+
+// The consensus-spec test vectors and the mainnet trusted setup are large and
+// are not checked in. To run them, copy the mainnet trusted setup and the
+// general/deneb/kzg directory of https://github.com/ethereum/consensus-spec-tests
+// to:
+const (
+	specTrustedSetup = "testdata/trusted_setup_4096.json"
+	specTestsDir     = "testdata/consensus-spec-tests/tests/general/deneb/kzg"
+)
+
+// The checked-in vectors are in the consensus-spec format. The constant blobs
+// don't depend on the trusted setup: they commit to a multiple of the
+// generator of G1 whatever the setup, and their proofs are the point at
+// infinity. The random blobs are computed for the test setup τ = testTau by an
+// independent implementation over blst, as [p(τ)]G₁ and [(p(τ) - y)/(τ - z)]G₁,
+// with z a root of unity in the root_of_unity cases.
+const testVectorsDir = "testdata/kzg"
+
+func TestConsensusSpecVectors(t *testing.T) {
+	f, err := os.Open(specTrustedSetup)
+	if err != nil {
+		t.Skip("mainnet trusted setup not found:", err)
+	}
+	defer f.Close()
+	if _, err = os.Stat(specTestsDir); err != nil {
+		t.Skip("consensus-spec test vectors not found:", err)
+	}
+
+	ts, err := ReadTrustedSetup(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := NewContext(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runSpecVectors(t, ctx, filepath.Join(specTestsDir, "*", "kzg-mainnet", "*", "data.yaml"))
+}
+
+func TestSpecVectors(t *testing.T) {
+	ctx, _ := getTestContext(t)
+	runSpecVectors(t, ctx, filepath.Join(testVectorsDir, "*", "*", "data.yaml.gz"))
+}
+
+// runSpecVectors runs the test vectors matching pattern, whose path is
+// <function>/.../<case>/data.yaml, gzipped if it ends with .gz. All the spec
+// functions must be covered.
+func runSpecVectors(t *testing.T, ctx *Context, pattern string) {
+	// each handler returns the output to compare with the expected one, nil on
+	// invalid inputs
+	handlers := map[string]func(input map[string]interface{}) interface{}{
+		"blob_to_kzg_commitment": func(input map[string]interface{}) interface{} {
+			var blob Blob
+			if !decodeHex(input["blob"], blob[:]) {
+				return nil
+			}
+			commitment, err := ctx.BlobToKZGCommitment(&blob)
+			if err != nil {
+				return nil
+			}
+			return encodeHex(commitment[:])
+		},
+		"compute_kzg_proof": func(input map[string]interface{}) interface{} {
+			var blob Blob
+			var z Scalar
+			if !decodeHex(input["blob"], blob[:]) || !decodeHex(input["z"], z[:]) {
+				return nil
+			}
+			proof, y, err := ctx.ComputeKZGProof(&blob, z)
+			if err != nil {
+				return nil
+			}
+			return []interface{}{encodeHex(proof[:]), encodeHex(y[:])}
+		},
+		"compute_blob_kzg_proof": func(input map[string]interface{}) interface{} {
+			var blob Blob
+			var commitment Commitment
+			if !decodeHex(input["blob"], blob[:]) || !decodeHex(input["commitment"], commitment[:]) {
+				return nil
+			}
+			proof, err := ctx.ComputeBlobKZGProof(&blob, commitment)
+			if err != nil {
+				return nil
+			}
+			return encodeHex(proof[:])
+		},
+		"verify_kzg_proof": func(input map[string]interface{}) interface{} {
+			var commitment Commitment
+			var z, y Scalar
+			var proof Proof
+			if !decodeHex(input["commitment"], commitment[:]) || !decodeHex(input["z"], z[:]) ||
+				!decodeHex(input["y"], y[:]) || !decodeHex(input["proof"], proof[:]) {
+				return nil
+			}
+			return verifyOutput(ctx.VerifyKZGProof(commitment, z, y, proof))
+		},
+		"verify_blob_kzg_proof": func(input map[string]interface{}) interface{} {
+			var blob Blob
+			var commitment Commitment
+			var proof Proof
+			if !decodeHex(input["blob"], blob[:]) || !decodeHex(input["commitment"], commitment[:]) ||
+				!decodeHex(input["proof"], proof[:]) {
+				return nil
+			}
+			return verifyOutput(ctx.VerifyBlobKZGProof(&blob, commitment, proof))
+		},
+		"verify_blob_kzg_proof_batch": func(input map[string]interface{}) interface{} {
+			rawBlobs, ok1 := input["blobs"].([]interface{})
+			rawCommitments, ok2 := input["commitments"].([]interface{})
+			rawProofs, ok3 := input["proofs"].([]interface{})
+			if !ok1 || !ok2 || !ok3 {
+				return nil
+			}
+			blobs := make([]Blob, len(rawBlobs))
+			commitments := make([]Commitment, len(rawCommitments))
+			proofs := make([]Proof, len(rawProofs))
+			for i := range blobs {
+				if !decodeHex(rawBlobs[i], blobs[i][:]) {
+					return nil
+				}
+			}
+			for i := range commitments {
+				if !decodeHex(rawCommitments[i], commitments[i][:]) {
+					return nil
+				}
+			}
+			for i := range proofs {
+				if !decodeHex(rawProofs[i], proofs[i][:]) {
+					return nil
+				}
+			}
+			return verifyOutput(ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+		},
+	}
+
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := pattern[:strings.Index(pattern, "*")]
+	covered := make(map[string]bool)
+	for _, file := range files {
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := strings.Split(filepath.ToSlash(rel), "/")[0]
+		handler, ok := handlers[name]
+		if !ok {
+			t.Errorf("%s: unknown spec function", name)
+			continue
+		}
+		covered[name] = true
+		t.Run(name+"/"+filepath.Base(filepath.Dir(file)), func(t *testing.T) {
+			data, err := readSpecVector(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, expected := fmt.Sprint(handler(data.Input)), fmt.Sprint(data.Output)
+			if got != expected {
+				t.Fatalf("expected %s, got %s", expected, got)
+			}
+		})
+	}
+	for name := range handlers {
+		if !covered[name] {
+			t.Errorf("%s: no test vector found", name)
+		}
+	}
+}
+
+type specVector struct {
+	Input  map[string]interface{} `yaml:"input"`
+	Output interface{}            `yaml:"output"`
+}
+
+func readSpecVector(file string) (data specVector, err error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(file, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return data, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return
+	}
+	err = yaml.Unmarshal(b, &data)
+	return
+}
+
+// decodeHex decodes a 0x-prefixed hex string of exactly len(dst) bytes.
+func decodeHex(in interface{}, dst []byte) bool {
+	s, ok := in.(string)
+	if !ok || !strings.HasPrefix(s, "0x") || len(s) != 2+2*len(dst) {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s[2:]))
+	return err == nil
+}
+
+func encodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+// verifyOutput maps the result of a verification to the expected output: nil
+// if the inputs are invalid, false if the proof is rejected.
+func verifyOutput(err error) interface{} {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, kzg.ErrVerifyOpeningProof):
+		return false
+	default:
+		return nil
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
 	"github.com/consensys/gnark-crypto/fiat-shamir"
 
 	"github.com/consensys/gnark-crypto/internal/parallel"
@@ -37,6 +38,7 @@ var (
 	ErrVerifyOpeningProof            = errors.New("can't verify opening proof")
 	ErrVerifyBatchOpeningSinglePoint = errors.New("can't verify batch opening proof at single point")
 	ErrMinSRSSize                    = errors.New("minimum srs size is 2")
+	ErrInvalidSRS                    = errors.New("srs is not made of successive powers of τ")
 )
 
 // Digest commitment of a polynomial.
@@ -143,6 +145,55 @@ func NewSRS(size uint64, bAlpha *big.Int) (*SRS, error) {
 	return &srs, nil
 }
 
+// CheckConsistency verifies that the SRS is made of successive powers of a same τ, that is
+// Pk.G1 = [G₁, [τ]G₁, [τ²]G₁, ...] and Vk.G2 = [G₂, [τ]G₂] with G₁ = Vk.G1.
+//
+// It is meant for SRS obtained from an untrusted source. The powers are checked
+// with a random linear combination and a single pairing check:
+// e(∑ᵢρⁱ[τⁱ⁺¹]G₁, G₂) = e(∑ᵢρⁱ[τⁱ]G₁, [τ]G₂)
+func (srs *SRS) CheckConsistency() error {
+	n := len(srs.Pk.G1) - 1
+	if n < 1 {
+		return ErrMinSRSSize
+	}
+	if !srs.Pk.G1[0].Equal(&srs.Vk.G1) || srs.Vk.G1.IsInfinity() || srs.Vk.G2[0].IsInfinity() ||
+		srs.Pk.G1[1].IsInfinity() || srs.Vk.G2[1].IsInfinity() {
+		return ErrInvalidSRS
+	}
+
+	var rho fr.Element
+	if _, err := rho.SetRandom(); err != nil {
+		return err
+	}
+	rhos := make([]fr.Element, n)
+	rhos[0].SetOne()
+	for i := 1; i < n; i++ {
+		rhos[i].Mul(&rhos[i-1], &rho)
+	}
+
+	var a, b bls12381.G1Affine
+	config := ecc.MultiExpConfig{}
+	if _, err := a.MultiExp(srs.Pk.G1[:n], rhos, config); err != nil {
+		return err
+	}
+	if _, err := b.MultiExp(srs.Pk.G1[1:], rhos, config); err != nil {
+		return err
+	}
+	a.Neg(&a)
+
+	check, err := bls12381.PairingCheck(
+		[]bls12381.G1Affine{b, a},
+		[]bls12381.G2Affine{srs.Vk.G2[0], srs.Vk.G2[1]},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrInvalidSRS
+	}
+	return nil
+}
+
 // OpeningProof KZG proof for opening at a single point.
 //
 // implements io.ReaderFrom and io.WriterTo
@@ -214,6 +265,90 @@ func Open(p []fr.Element, point fr.Element, pk ProvingKey) (OpeningProof, error)
 	return res, nil
 }
 
+// CommitLagrange commits to a polynomial given by its evaluations on a domain,
+// using the proving key in Lagrange form returned by ProvingKey.ToLagrange.
+// The evaluations are in natural order and cover the whole domain: len(p) == len(pkLagrange.G1).
+func CommitLagrange(p []fr.Element, pkLagrange ProvingKey, nbTasks ...int) (Digest, error) {
+	if len(p) != len(pkLagrange.G1) {
+		return Digest{}, ErrInvalidPolynomialSize
+	}
+	return Commit(p, pkLagrange, nbTasks...)
+}
+
+// OpenLagrange computes an opening proof at point of a polynomial given by its
+// evaluations on domain, using the proving key in Lagrange form returned by
+// ProvingKey.ToLagrange. The proof is the same as the one of Open on the
+// canonical form of p and is verified with Verify.
+//
+// The quotient (p(X)-p(z))/(X-z) is computed in Lagrange form, without FFT.
+// If z is outside the domain, p(z) = (zⁿ-1)/n ⋅ ∑ᵢpᵢωⁱ/(z-ωⁱ). Otherwise z = ωᵐ,
+// p(z) = pₘ and the m-th evaluation of the quotient is p'(ωᵐ) = -ω⁻ᵐ∑_{i≠m}qᵢωⁱ.
+func OpenLagrange(p []fr.Element, point fr.Element, domain *fft.Domain, pkLagrange ProvingKey) (OpeningProof, error) {
+	n := len(p)
+	if n < 2 || uint64(n) != domain.Cardinality || n != len(pkLagrange.G1) {
+		return OpeningProof{}, ErrInvalidPolynomialSize
+	}
+
+	// ωⁱ and 1/(ωⁱ-z), the latter being 0 if z = ωᵐ
+	roots := make([]fr.Element, n)
+	denominators := make([]fr.Element, n)
+	roots[0].SetOne()
+	for i := 1; i < n; i++ {
+		roots[i].Mul(&roots[i-1], &domain.Generator)
+	}
+	m := -1
+	for i := 0; i < n; i++ {
+		denominators[i].Sub(&roots[i], &point)
+		if denominators[i].IsZero() {
+			m = i
+		}
+	}
+	denominators = fr.BatchInvert(denominators)
+
+	var res OpeningProof
+	var tmp fr.Element
+	if m != -1 {
+		res.ClaimedValue.Set(&p[m])
+	} else {
+		// -(zⁿ-1)/n ⋅ ∑ᵢpᵢωⁱ/(ωⁱ-z)
+		for i := 0; i < n; i++ {
+			tmp.Mul(&p[i], &roots[i]).Mul(&tmp, &denominators[i])
+			res.ClaimedValue.Add(&res.ClaimedValue, &tmp)
+		}
+		one := fr.One()
+		tmp.Exp(point, big.NewInt(int64(n)))
+		tmp.Sub(&one, &tmp).Mul(&tmp, &domain.CardinalityInv)
+		res.ClaimedValue.Mul(&res.ClaimedValue, &tmp)
+	}
+
+	// qᵢ = (pᵢ-p(z))/(ωⁱ-z)
+	q := make([]fr.Element, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			q[i].Sub(&p[i], &res.ClaimedValue).Mul(&q[i], &denominators[i])
+		}
+	})
+	if m != -1 {
+		q[m].SetZero()
+		for i := 0; i < n; i++ {
+			if i == m {
+				continue
+			}
+			tmp.Mul(&q[i], &roots[i])
+			q[m].Sub(&q[m], &tmp)
+		}
+		q[m].Mul(&q[m], &roots[(n-m)%n])
+	}
+
+	var err error
+	res.H, err = Commit(q, pkLagrange)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+
+	return res, nil
+}
+
 // Verify verifies a KZG opening proof at a single point
 func Verify(commitment *Digest, proof *OpeningProof, point fr.Element, vk VerifyingKey) error {
 
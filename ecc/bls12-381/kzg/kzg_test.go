@@ -94,16 +94,20 @@ func TestCommitLagrange(t *testing.T) {
 	}
 
 	// commitment using Lagrange SRS
+	d := fft.NewDomain(uint64(size))
+	pkLagrange, err := testSrs.Pk.ToLagrange(d)
+	assert.NoError(err)
 	lagrange, err := ToLagrangeG1(testSrs.Pk.G1[:size])
 	assert.NoError(err)
-	var pkLagrange ProvingKey
-	pkLagrange.G1 = lagrange
+	assert.Equal(lagrange, pkLagrange.G1)
 
-	digestLagrange, err := Commit(pol, pkLagrange)
+	digestLagrange, err := CommitLagrange(pol, pkLagrange)
 	assert.NoError(err)
 
+	_, err = CommitLagrange(pol[:size-1], pkLagrange)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+
 	// commitment using canonical SRS
-	d := fft.NewDomain(uint64(size))
 	d.FFTInverse(pol, fft.DIF)
 	fft.BitReverse(pol)
 	digestCanonical, err := Commit(pol, testSrs.Pk)
@@ -113,6 +117,53 @@ func TestCommitLagrange(t *testing.T) {
 	assert.True(digestCanonical.Equal(&digestLagrange), "error CommitLagrange")
 }
 
+func TestOpenLagrange(t *testing.T) {
+
+	assert := require.New(t)
+
+	const size = 64
+	d := fft.NewDomain(size)
+	pkLagrange, err := testSrs.Pk.ToLagrange(d)
+	assert.NoError(err)
+
+	// polynomial in Lagrange and canonical form
+	pol := make([]fr.Element, size)
+	for i := 0; i < size; i++ {
+		pol[i].SetRandom()
+	}
+	canonical := make([]fr.Element, size)
+	copy(canonical, pol)
+	d.FFTInverse(canonical, fft.DIF)
+	fft.BitReverse(canonical)
+
+	digest, err := CommitLagrange(pol, pkLagrange)
+	assert.NoError(err)
+
+	var outside, inside fr.Element
+	outside.SetRandom()
+	inside.Exp(d.Generator, big.NewInt(5))
+
+	for _, point := range []fr.Element{outside, inside} {
+		proof, err := OpenLagrange(pol, point, d, pkLagrange)
+		assert.NoError(err)
+
+		expected, err := Open(canonical, point, testSrs.Pk)
+		assert.NoError(err)
+		assert.True(expected.ClaimedValue.Equal(&proof.ClaimedValue), "wrong claimed value")
+		assert.True(expected.H.Equal(&proof.H), "proof differs from the one in canonical form")
+
+		assert.NoError(Verify(&digest, &proof, point, testSrs.Vk))
+	}
+	expected := eval(canonical, inside)
+	assert.True(pol[5].Equal(&expected), "evaluation at ω⁵ should be read from the evaluations")
+
+	_, err = OpenLagrange(pol[:size/2], outside, d, pkLagrange)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+
+	_, err = testSrs.Pk.ToLagrange(fft.NewDomain(uint64(2 * len(testSrs.Pk.G1))))
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
 func TestDividePolyByXminusA(t *testing.T) {
 
 	const pSize = 230
@@ -160,11 +211,38 @@ func TestSerializationSRS(t *testing.T) {
 	assert.NoError(t, err)
 	t.Run("proving key round-trip", testutils.SerializationRoundTrip(&srs.Pk))
 	t.Run("proving key raw round-trip", testutils.SerializationRoundTripRaw(&srs.Pk))
+	pkLagrange, err := srs.Pk.ToLagrange(fft.NewDomain(64))
+	assert.NoError(t, err)
+	t.Run("lagrange proving key round-trip", testutils.SerializationRoundTrip(&pkLagrange))
+	t.Run("lagrange proving key raw round-trip", testutils.SerializationRoundTripRaw(&pkLagrange))
 	t.Run("verifying key round-trip", testutils.SerializationRoundTrip(&srs.Vk))
 	t.Run("whole SRS round-trip", testutils.SerializationRoundTrip(srs))
 	t.Run("unsafe whole SRS round-trip", testutils.UnsafeBinaryMarshalerRoundTrip(srs))
 }
 
+func TestCheckConsistency(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := NewSRS(64, new(big.Int).SetInt64(42))
+	assert.NoError(err)
+	assert.NoError(srs.CheckConsistency())
+
+	quickSrs, err := NewSRS(64, new(big.Int).SetInt64(-1))
+	assert.NoError(err)
+	assert.NoError(quickSrs.CheckConsistency())
+
+	// swapped powers
+	srs.Pk.G1[10], srs.Pk.G1[11] = srs.Pk.G1[11], srs.Pk.G1[10]
+	assert.ErrorIs(srs.CheckConsistency(), ErrInvalidSRS)
+	srs.Pk.G1[10], srs.Pk.G1[11] = srs.Pk.G1[11], srs.Pk.G1[10]
+
+	// [τ]G₂ from another SRS
+	otherSrs, err := NewSRS(64, new(big.Int).SetInt64(43))
+	assert.NoError(err)
+	srs.Vk.G2[1] = otherSrs.Vk.G2[1]
+	assert.ErrorIs(srs.CheckConsistency(), ErrInvalidSRS)
+}
+
 func TestCommit(t *testing.T) {
 
 	// create a polynomial
@@ -0,0 +1,76 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package mpc
+
+import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// WriteTo writes binary encoding of the Contribution (compressed points)
+func (c *Contribution) WriteTo(w io.Writer) (int64, error) {
+	return c.writeTo(w)
+}
+
+// WriteRawTo writes binary encoding of the Contribution to w without point compression
+func (c *Contribution) WriteRawTo(w io.Writer) (int64, error) {
+	return c.writeTo(w, bls12381.RawEncoding())
+}
+
+func (c *Contribution) writeTo(w io.Writer, options ...func(*bls12381.Encoder)) (int64, error) {
+	enc := bls12381.NewEncoder(w, options...)
+
+	toEncode := []interface{}{
+		c.G1,
+		&c.G2[0],
+		&c.G2[1],
+		&c.Proof.S,
+		&c.Proof.SX,
+		&c.Proof.RX,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes Contribution data from reader.
+func (c *Contribution) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12381.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&c.G1,
+		&c.G2[0],
+		&c.G2[1],
+		&c.Proof.S,
+		&c.Proof.SX,
+		&c.Proof.RX,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
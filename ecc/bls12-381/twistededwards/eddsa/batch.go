@@ -0,0 +1,139 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eddsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+)
+
+var errInvalidBatch = errors.New("number of public keys, signatures and messages must match")
+
+// BatchOption configures BatchVerify
+type BatchOption func(*batchConfig)
+
+type batchConfig struct {
+	cofactorless bool
+}
+
+// WithCofactorlessEquation makes BatchVerify check the batch equation without
+// multiplying it by the cofactor. It is slightly faster, but the signatures
+// whose points have a small order component may then be rejected by the batch
+// (with a probability depending on the random coefficients) while they are
+// accepted by Verify.
+func WithCofactorlessEquation() BatchOption {
+	return func(c *batchConfig) {
+		c.cofactorless = true
+	}
+}
+
+// BatchVerify verifies the signatures of messages under publicKeys at once.
+//
+// With zᵢ random 128-bit scalars and kᵢ = H(Rᵢ, Aᵢ, Mᵢ), it checks that
+//
+//	[cofactor]([-∑zᵢSᵢ]Base + ∑[zᵢ]Rᵢ + ∑[zᵢkᵢ]Aᵢ) = 0
+//
+// with a single multi-scalar multiplication. As Verify also checks the
+// cofactored equation, a batch is valid if and only if (with overwhelming
+// probability) Verify accepts each of its signatures. See
+// WithCofactorlessEquation to skip the multiplication by the cofactor.
+//
+// It returns an error if the slices lengths differ or if a signature is not a
+// valid encoding.
+func BatchVerify(publicKeys []PublicKey, signatures, messages [][]byte, hFunc hash.Hash, opts ...BatchOption) (bool, error) {
+
+	// hFunc cannot be nil.
+	// We need a hash function for the Fiat-Shamir.
+	if hFunc == nil {
+		return false, errHashNeeded
+	}
+	if len(publicKeys) != len(signatures) || len(publicKeys) != len(messages) {
+		return false, errInvalidBatch
+	}
+	var cfg batchConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	n := len(publicKeys)
+	if n == 0 {
+		return true, nil
+	}
+
+	curveParams := twistededwards.GetEdwardsCurve()
+
+	points := make([]twistededwards.PointAffine, 2*n+1)
+	scalars := make([]big.Int, 2*n+1)
+	var sumZS, s, hramInt big.Int
+	var buf [16]byte
+	for i := 0; i < n; i++ {
+		if !publicKeys[i].A.IsOnCurve() {
+			return false, errNotOnCurve
+		}
+		var sig Signature
+		if _, err := sig.SetBytes(signatures[i]); err != nil {
+			return false, err
+		}
+
+		// compute H(R, A, M), all parameters in data are in Montgomery form
+		hFunc.Reset()
+		sigRX := sig.R.X.Bytes()
+		sigRY := sig.R.Y.Bytes()
+		sigAX := publicKeys[i].A.X.Bytes()
+		sigAY := publicKeys[i].A.Y.Bytes()
+		toWrite := [][]byte{sigRX[:], sigRY[:], sigAX[:], sigAY[:], messages[i]}
+		for _, bytes := range toWrite {
+			if _, err := hFunc.Write(bytes); err != nil {
+				return false, err
+			}
+		}
+		hramInt.SetBytes(hFunc.Sum(nil))
+
+		if _, err := rand.Read(buf[:]); err != nil {
+			return false, err
+		}
+		z := &scalars[2*i]
+		z.SetBytes(buf[:])
+
+		points[2*i] = sig.R
+		points[2*i+1] = publicKeys[i].A
+		scalars[2*i+1].Mul(z, &hramInt).
+			Mod(&scalars[2*i+1], &curveParams.Order)
+		s.SetBytes(sig.S[:])
+		s.Mul(&s, z)
+		sumZS.Add(&sumZS, &s)
+	}
+	points[2*n] = curveParams.Base
+	scalars[2*n].Mod(&sumZS, &curveParams.Order).
+		Sub(&curveParams.Order, &scalars[2*n]).
+		Mod(&scalars[2*n], &curveParams.Order)
+
+	var res twistededwards.PointExtended
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false, err
+	}
+	if !cfg.cofactorless {
+		var bCofactor big.Int
+		curveParams.Cofactor.BigInt(&bCofactor)
+		res.ScalarMultiplication(&res, &bCofactor)
+	}
+	return res.IsZero(), nil
+}
@@ -21,8 +21,10 @@ import (
 	"errors"
 	"fmt"
 	"hash"
+	"math"
 	"math/big"
 	"math/bits"
+	"sort"
 
 	"github.com/consensys/gnark-crypto/accumulator/merkletree"
 	"github.com/consensys/gnark-crypto/ecc"
@@ -38,6 +40,7 @@ var (
 	ErrMerkleRoot           = errors.New("merkle roots of the opening and the proof of proximity don't coincide")
 	ErrMerklePath           = errors.New("merkle path proof is wrong")
 	ErrRangePosition        = errors.New("the asked opening position is out of range")
+	ErrProofOfWork          = errors.New("the proof of work is wrong")
 )
 
 const rho = 8
@@ -90,7 +93,7 @@ type IOPP uint
 
 const (
 	// Multiplicative version of FRI, using the map x->x², on a
-	// power of 2 subgroup of Fr^{*}.
+	// power of 2 subgroup of Fr^{*}, or x->xᵃʳⁱᵗʸ with WithFoldingArity.
 	RADIX_2_FRI IOPP = iota
 )
 
@@ -117,10 +120,31 @@ type Round struct {
 	Evaluation fr.Element
 }
 
+// Step contains the data corresponding to a single folding step of fri, when
+// the Iopp is created with options: the commitment to the evaluations of the
+// folded polynomial, and the openings of the fibers of the queries of the
+// verifier.
+//
+// The evaluations are committed in the order of the fibers of x -> xᵃʳⁱᵗʸ (see
+// sortFibers), so that the values of a fiber are contiguous leaves, opened at
+// once for all the queries.
+type Step struct {
+
+	// MerkleRoot is the root of the Merkle tree of the evaluations.
+	MerkleRoot []byte
+
+	// Openings of the fibers of the queries. The leaves are the marshalled
+	// evaluations.
+	Openings merkletree.MultiProof
+}
+
 // ProofOfProximity proof of proximity, attesting that
 // a function is d-close to a low degree polynomial.
 //
-// It is composed of a series of Interactions, emulated with Fiat Shamir,
+// It is composed of a series of Interactions, emulated with Fiat Shamir.
+// With the default parameters, they are stored in Rounds. When the Iopp is
+// created with options (see Option), they are stored in Steps,
+// FinalPolynomial and Nonce instead.
 type ProofOfProximity struct {
 
 	// ID unique ID attached to the proof of proximity. It's needed for
@@ -131,6 +155,17 @@ type ProofOfProximity struct {
 	// round contains the data corresponding to a single round
 	// of fri. There are nbRounds rounds of Interactions.
 	Rounds []Round
+
+	// Steps contains the data corresponding to the folding steps of fri. The
+	// first one commits to the evaluations of the polynomial itself.
+	Steps []Step
+
+	// FinalPolynomial holds the coefficients of the fully folded polynomial,
+	// whose degree is below the cutoff (see WithFinalDegree).
+	FinalPolynomial []fr.Element
+
+	// Nonce is the solution of the proof of work (see WithGrindingBits).
+	Nonce uint64
 }
 
 // Iopp interface that an iopp should implement
@@ -160,11 +195,32 @@ func init() {
 	twoInv.SetUint64(2).Inverse(&twoInv)
 }
 
-// New creates a new IOPP capable to handle degree(size) polynomials.
-func (iopp IOPP) New(size uint64, h hash.Hash) Iopp {
+// New creates a new IOPP capable to handle degree(size) polynomials. It panics
+// if the options are invalid.
+func (iopp IOPP) New(size uint64, h hash.Hash, opts ...Option) Iopp {
+	cfg, err := friOptions(opts...)
+	if err != nil {
+		panic(err)
+	}
+	switch iopp {
+	case RADIX_2_FRI:
+		return newRadixTwoFri(size, h, cfg)
+	default:
+		panic("iopp name is not recognized")
+	}
+}
+
+// Security returns the conjectured and the proven number of bits of security
+// of the proofs of proximity of the Iopp returned by New with the same
+// arguments. It panics if the options are invalid.
+func (iopp IOPP) Security(size uint64, h hash.Hash, opts ...Option) (conjectured, proven float64) {
+	cfg, err := friOptions(opts...)
+	if err != nil {
+		panic(err)
+	}
 	switch iopp {
 	case RADIX_2_FRI:
-		return newRadixTwoFri(size, h)
+		return newRadixTwoFri(size, h, cfg).security()
 	default:
 		panic("iopp name is not recognized")
 	}
@@ -181,22 +237,51 @@ type radixTwoFri struct {
 	// nbSteps number of Interactions between the prover and the verifier
 	nbSteps int
 
+	// withOptions is true if the parameters are not the default ones, the
+	// proofs are then made of Steps instead of Rounds.
+	withOptions bool
+
+	// arities[i] is the folding arity of the i-th step. All the steps fold
+	// with the configured arity, except the last one which may fold less to
+	// reach the final polynomial.
+	arities []int
+
+	// rho blowup factor, finalSize number of coefficients of the final
+	// polynomial.
+	rho, finalSize int
+
+	nbQueries, grindingBits int
+
 	// domain used to build the Reed Solomon code from the given polynomial.
 	// The size of the domain is ρ*size_polynomial.
 	domain *fft.Domain
 }
 
-func newRadixTwoFri(size uint64, h hash.Hash) radixTwoFri {
+func newRadixTwoFri(size uint64, h hash.Hash, cfg friConfig) radixTwoFri {
 
 	var res radixTwoFri
+	res.withOptions = cfg != defaultConfig()
 
-	// computing the number of steps
+	// computing the number of steps, folding at least once
 	n := ecc.NextPowerOfTwo(size)
-	nbSteps := bits.TrailingZeros(uint(n))
-	res.nbSteps = nbSteps
+	if n < 2 {
+		n = 2
+	}
+	finalSize := ecc.NextPowerOfTwo(uint64(cfg.finalDegree) + 1)
+	if finalSize >= n {
+		finalSize = n / 2
+	}
+	for m := n; m > finalSize; {
+		arity := min(uint64(cfg.foldingArity), m/finalSize)
+		res.arities = append(res.arities, int(arity))
+		m /= arity
+	}
+	res.nbSteps = len(res.arities)
+	res.finalSize = int(finalSize)
 
 	// extending the domain
-	n = n * rho
+	res.rho = cfg.blowup
+	n = n * uint64(res.rho)
 
 	// building the domains
 	res.domain = fft.NewDomain(n)
@@ -204,22 +289,48 @@ func newRadixTwoFri(size uint64, h hash.Hash) radixTwoFri {
 	// hash function
 	res.h = h
 
+	res.nbQueries = cfg.nbQueries
+	res.grindingBits = cfg.grindingBits
+
 	return res
 }
 
-// convertCanonicalSorted convert the index i, an entry in a
-// sorted polynomial, to the corresponding entry in canonical
-// representation. n is the size of the polynomial.
-func convertCanonicalSorted(i, n int) int {
-
-	if i < n/2 {
-		return 2 * i
-	} else {
-		l := n - (i + 1)
-		l = 2 * l
-		return n - l - 1
+// security returns the conjectured and the proven number of bits of security
+// of the proofs of proximity, both bounded by the collision resistance of the
+// hash function.
+//
+// The conjectured security follows the ethSTARK conjecture: each query
+// adds log₂(ρ) bits. The proven security is in the unique decoding regime
+// (δ = (1-ρ⁻¹)/2): each query adds -log₂(1-δ) bits, and the soundness error
+// of the folding steps is bounded by ∑(arityᵢ-1)⋅|D|/|Fr| [BCIKS20]. The
+// grinding bits are added to the security of the queries.
+func (s radixTwoFri) security() (conjectured, proven float64) {
+
+	var modulus big.Float
+	modulus.SetInt(fr.Modulus())
+	fModulus, _ := modulus.Float64()
+
+	nbFolded := 0
+	for _, arity := range s.arities {
+		nbFolded += arity - 1
 	}
+	folding := math.Log2(fModulus) - math.Log2(float64(nbFolded)*float64(s.domain.Cardinality))
+	collision := float64(4 * s.h.Size())
+	bound := min(folding, collision)
+
+	rho := float64(s.rho)
+	conjectured = float64(s.nbQueries)*math.Log2(rho) + float64(s.grindingBits)
+	proven = -float64(s.nbQueries)*math.Log2((1+1/rho)/2) + float64(s.grindingBits)
+
+	return min(conjectured, bound), min(proven, bound)
+}
 
+// convertCanonicalSorted convert the index i, an entry in a
+// canonical polynomial of size n, to the corresponding entry
+// in the polynomial sorted by fibers of x -> xᵃʳⁱᵗʸ.
+func convertCanonicalSorted(i, n, arity int) int {
+	m := n / arity
+	return (i%m)*arity + i/m
 }
 
 // deriveQueriesPositions derives the indices of the oracle
@@ -229,29 +340,34 @@ func convertCanonicalSorted(i, n int) int {
 // * The result is a slice of []int, where each entry is a tuple (iₖ), such that
 // the verifier needs to evaluate ∑ₖ oracle(iₖ)xᵏ to build
 // the folded function.
+//
+// The i-th entry is sorted by fibers of x -> xᵃʳⁱᵗʸ, where arity is the one of
+// the i-th step: its fiber is made of the arity contiguous entries containing
+// it, and is folded into the entry of the next polynomial of canonical index
+// res[i]/arity.
 func (s radixTwoFri) deriveQueriesPositions(pos int, size int) []int {
 
-	_s := size / 2
 	res := make([]int, s.nbSteps)
 	res[0] = pos
 	for i := 1; i < s.nbSteps; i++ {
-		t := (res[i-1] - (res[i-1] % 2)) / 2
-		res[i] = convertCanonicalSorted(t, _s)
-		_s = _s / 2
+		t := res[i-1] / s.arities[i-1]
+		size /= s.arities[i-1]
+		res[i] = convertCanonicalSorted(t, size, s.arities[i])
 	}
 
 	return res
 }
 
-// sort orders the evaluation of a polynomial on a domain
-// such that contiguous entries are in the same fiber:
-// {q(g⁰), q(g^{n/2}), q(g¹), q(g^{1+n/2}),...,q(g^{n/2-1}), q(gⁿ⁻¹)}
-func sort(evaluations []fr.Element) []fr.Element {
+// sortFibers orders the evaluation of a polynomial on a domain of size n
+// such that contiguous entries are in the same fiber of x -> xᵃʳⁱᵗʸ:
+// {q(g⁰), q(g^{n/arity}), .., q(g^{(arity-1)n/arity}), q(g¹), q(g^{1+n/arity}),...}
+func sortFibers(evaluations []fr.Element, arity int) []fr.Element {
 	q := make([]fr.Element, len(evaluations))
-	n := len(evaluations) / 2
-	for i := 0; i < n; i++ {
-		q[2*i].Set(&evaluations[i])
-		q[2*i+1].Set(&evaluations[i+n])
+	m := len(evaluations) / arity
+	for i := 0; i < m; i++ {
+		for j := 0; j < arity; j++ {
+			q[i*arity+j].Set(&evaluations[i+j*m])
+		}
 	}
 	return q
 }
@@ -272,10 +388,10 @@ func (s radixTwoFri) Open(p []fr.Element, position uint64) (OpeningProof, error)
 
 	// sort q to have fibers in contiguous entries. The goal is to have one
 	// Merkle path for both openings of entries which are in the same fiber.
-	q = sort(q)
+	q = sortFibers(q, s.arities[0])
 
 	// build the Merkle proof, we the position is converted to fit the sorted polynomial
-	pos := convertCanonicalSorted(int(position), len(q))
+	pos := convertCanonicalSorted(int(position), len(q), s.arities[0])
 
 	tree := merkletree.New(s.h)
 	err := tree.SetIndex(uint64(pos))
@@ -303,23 +419,37 @@ func (s radixTwoFri) Open(p []fr.Element, position uint64) (OpeningProof, error)
 // those should be equal, if not an error is raised.
 func (s radixTwoFri) VerifyOpening(position uint64, openingProof OpeningProof, pp ProofOfProximity) error {
 
-	// To query the Merkle path, we look at the first series of Interactions, and check whether it's the point
-	// at 'position' or its neighbor that contains the full Merkle path.
-	var fullMerkleProof int
-	if len(pp.Rounds[0].Interactions[0][0].ProofSet) > len(pp.Rounds[0].Interactions[0][1].ProofSet) {
-		fullMerkleProof = 0
+	// the root hash of the first interaction
+	var merkleRoot []byte
+	if s.withOptions {
+		if len(pp.Steps) == 0 {
+			return ErrMerkleRoot
+		}
+		merkleRoot = pp.Steps[0].MerkleRoot
 	} else {
-		fullMerkleProof = 1
+		if len(pp.Rounds) == 0 || len(pp.Rounds[0].Interactions) == 0 {
+			return ErrMerkleRoot
+		}
+
+		// To query the Merkle path, we look at the first series of Interactions, and check whether it's the point
+		// at 'position' or its neighbor that contains the full Merkle path.
+		var fullMerkleProof int
+		if len(pp.Rounds[0].Interactions[0][0].ProofSet) > len(pp.Rounds[0].Interactions[0][1].ProofSet) {
+			fullMerkleProof = 0
+		} else {
+			fullMerkleProof = 1
+		}
+		merkleRoot = pp.Rounds[0].Interactions[0][fullMerkleProof].MerkleRoot
 	}
 
 	// check that the merkle roots coincide
-	if !bytes.Equal(openingProof.merkleRoot, pp.Rounds[0].Interactions[0][fullMerkleProof].MerkleRoot) {
+	if !bytes.Equal(openingProof.merkleRoot, merkleRoot) {
 		return ErrMerkleRoot
 	}
 
 	// convert position to the sorted version
 	sizePoly := s.domain.Cardinality
-	pos := convertCanonicalSorted(int(position), int(sizePoly))
+	pos := convertCanonicalSorted(int(position), int(sizePoly), s.arities[0])
 
 	// check the Merkle proof
 	res := merkletree.VerifyProof(s.h, openingProof.merkleRoot, openingProof.ProofSet, uint64(pos), openingProof.numLeaves)
@@ -412,7 +542,7 @@ func (s radixTwoFri) buildProofOfProximitySingleRound(salt fr.Element, p []fr.El
 
 	for i := 0; i < s.nbSteps; i++ {
 
-		evalsAtRound[i] = sort(_p)
+		evalsAtRound[i] = sortFibers(_p, 2)
 
 		// compute the root hash, needed to derive xi
 		t := merkletree.New(s.h)
@@ -505,10 +635,6 @@ func (s radixTwoFri) buildProofOfProximitySingleRound(salt fr.Element, p []fr.El
 // the verifier point of view, is in fact δ-close to a polynomial.
 func (s radixTwoFri) BuildProofOfProximity(p []fr.Element) (ProofOfProximity, error) {
 
-	// the proof will contain nbSteps Interactions
-	var proof ProofOfProximity
-	proof.Rounds = make([]Round, nbRounds)
-
 	// evaluate p
 	// evaluate p and sort the result
 	_p := make([]fr.Element, s.domain.Cardinality)
@@ -516,6 +642,14 @@ func (s radixTwoFri) BuildProofOfProximity(p []fr.Element) (ProofOfProximity, er
 	s.domain.FFT(_p, fft.DIF)
 	fft.BitReverse(_p)
 
+	if s.withOptions {
+		return s.buildProofOfProximitySteps(_p)
+	}
+
+	// the proof will contain nbSteps Interactions
+	var proof ProofOfProximity
+	proof.Rounds = make([]Round, nbRounds)
+
 	var err error
 	var salt, one fr.Element
 	one.SetOne()
@@ -534,6 +668,10 @@ func (s radixTwoFri) BuildProofOfProximity(p []fr.Element) (ProofOfProximity, er
 // verification fails.
 func (s radixTwoFri) verifyProofOfProximitySingleRound(salt fr.Element, proof Round) error {
 
+	if len(proof.Interactions) != s.nbSteps {
+		return ErrProximityTestFolding
+	}
+
 	// Fiat Shamir transcript to derive the challenges
 	xis := make([]string, s.nbSteps+1)
 	for i := 0; i < s.nbSteps; i++ {
@@ -564,12 +702,6 @@ func (s radixTwoFri) verifyProofOfProximitySingleRound(salt fr.Element, proof Ro
 	}
 
 	// derive the verifier queries
-	// for i := 0; i < len(proof.evaluation); i++ {
-	// 	err := fs.Bind(xis[s.nbSteps], proof.evaluation[i].Marshal())
-	// 	if err != nil {
-	// 		return err
-	// 	}
-	// }
 	err = fs.Bind(xis[s.nbSteps], proof.Evaluation.Marshal())
 	if err != nil {
 		return err
@@ -594,6 +726,9 @@ func (s radixTwoFri) verifyProofOfProximitySingleRound(salt fr.Element, proof Ro
 		// correctness of Merkle proof
 		// c is the entry containing the full Merkle proof.
 		c := si[i] % 2
+		if len(proof.Interactions[i][c].ProofSet) < 2 || len(proof.Interactions[i][1-c].ProofSet) != 2 {
+			return ErrMerklePath
+		}
 		res := merkletree.VerifyProof(
 			s.h,
 			proof.Interactions[i][c].MerkleRoot,
@@ -684,6 +819,13 @@ func (s radixTwoFri) verifyProofOfProximitySingleRound(salt fr.Element, proof Ro
 // by one.
 func (s radixTwoFri) VerifyProofOfProximity(proof ProofOfProximity) error {
 
+	if s.withOptions {
+		return s.verifyProofOfProximitySteps(proof)
+	}
+
+	if len(proof.Rounds) != nbRounds {
+		return ErrProximityTestFolding
+	}
 	var salt, one fr.Element
 	one.SetOne()
 	for i := 0; i < nbRounds; i++ {
@@ -696,3 +838,374 @@ func (s radixTwoFri) VerifyProofOfProximity(proof ProofOfProximity) error {
 	return nil
 
 }
+
+// foldFiber folds the values of a polynomial p on the fiber of x -> xᵃʳⁱᵗʸ
+// {g^{j+t⋅n/arity}, t < arity}, where g is a generator of the subgroup of
+// Fᵣ^{*} of size n, and returns the value of the folded polynomial at
+// g^{arity⋅j}. As the prover does, the polynomial is folded log₂(arity) times
+// by 2, with the challenges x, x², x⁴, ...
+func foldFiber(values []fr.Element, gInv fr.Element, j, n uint64, x fr.Element) fr.Element {
+
+	v := make([]fr.Element, len(values))
+	copy(v, values)
+
+	var zInv, omegaInv, l, r fr.Element
+	for arity := uint64(len(v)); arity > 1; arity /= 2 {
+
+		// z_t = g^{j+t⋅n/arity} and -z_t = z_{t+arity/2}
+		zInv.Exp(gInv, new(big.Int).SetUint64(j))
+		omegaInv.Exp(gInv, new(big.Int).SetUint64(n/arity))
+		for t := uint64(0); t < arity/2; t++ {
+			// p'(z²) = (p(z) + p(-z))/2 + x(p(z) - p(-z))/(2z)
+			l.Add(&v[t], &v[t+arity/2])
+			r.Sub(&v[t], &v[t+arity/2]).Mul(&r, &zInv).Mul(&r, &x)
+			v[t].Add(&l, &r).Mul(&v[t], &twoInv)
+			zInv.Mul(&zInv, &omegaInv)
+		}
+
+		// the z_t² are the fiber of x -> x^{arity/2} in the subgroup of size n/2
+		v = v[:arity/2]
+		gInv.Square(&gInv)
+		x.Square(&x)
+		n /= 2
+	}
+
+	return v[0]
+}
+
+// transcript returns the Fiat Shamir transcript of a proof of proximity made
+// of Steps. The xᵢ are used to fold the polynomials, pow is the seed of the
+// proof of work, and s0 is the seed of the queries.
+func (s radixTwoFri) transcript() (*fiatshamir.Transcript, []string) {
+	xis := make([]string, s.nbSteps+2)
+	for i := 0; i < s.nbSteps; i++ {
+		xis[i] = fmt.Sprintf("x%d", i)
+	}
+	xis[s.nbSteps] = "pow"
+	xis[s.nbSteps+1] = "s0"
+	return fiatshamir.NewTranscript(s.h, xis...), xis
+}
+
+// proofOfWorkSeed binds the final polynomial to the transcript, and derives
+// the seed of the proof of work.
+func (s radixTwoFri) proofOfWorkSeed(fs *fiatshamir.Transcript, finalPolynomial []fr.Element) ([]byte, error) {
+	for i := range finalPolynomial {
+		if err := fs.Bind("pow", finalPolynomial[i].Marshal()); err != nil {
+			return nil, err
+		}
+	}
+	return fs.ComputeChallenge("pow")
+}
+
+// checkProofOfWork returns true if H(seed ∥ nonce) starts with grindingBits
+// zero bits, or an error if the hash rejects seed.
+func (s radixTwoFri) checkProofOfWork(seed []byte, nonce uint64) (bool, error) {
+	if s.grindingBits == 0 {
+		return true, nil
+	}
+	var n fr.Element
+	n.SetUint64(nonce)
+	digest, err := s.hashWithElement(seed, n)
+	if err != nil {
+		return false, err
+	}
+
+	nbZeros := 0
+	for _, b := range digest {
+		nbZeros += bits.LeadingZeros8(b)
+		if b != 0 {
+			break
+		}
+	}
+	return nbZeros >= s.grindingBits, nil
+}
+
+// hashWithElement returns H(seed ∥ e). Hashes over a field, such as MiMC,
+// return an error if seed is not a canonical encoding of field elements.
+func (s radixTwoFri) hashWithElement(seed []byte, e fr.Element) ([]byte, error) {
+	s.h.Reset()
+	if _, err := s.h.Write(seed); err != nil {
+		return nil, err
+	}
+	if _, err := s.h.Write(e.Marshal()); err != nil {
+		return nil, err
+	}
+	return s.h.Sum(nil), nil
+}
+
+// deriveQueries binds the nonce of the proof of work to the transcript, and
+// derives the positions of the queries at each step (see
+// deriveQueriesPositions).
+func (s radixTwoFri) deriveQueries(fs *fiatshamir.Transcript, nonce uint64) ([][]int, error) {
+	var n fr.Element
+	n.SetUint64(nonce)
+	if err := fs.Bind("s0", n.Marshal()); err != nil {
+		return nil, err
+	}
+	binSeed, err := fs.ComputeChallenge("s0")
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([][]int, s.nbQueries)
+	var bPos, bCardinality big.Int
+	bCardinality.SetUint64(s.domain.Cardinality)
+	for i := range res {
+		n.SetUint64(uint64(i))
+		digest, err := s.hashWithElement(binSeed, n)
+		if err != nil {
+			return nil, err
+		}
+		bPos.SetBytes(digest)
+		bPos.Mod(&bPos, &bCardinality)
+		res[i] = s.deriveQueriesPositions(int(bPos.Uint64()), int(s.domain.Cardinality))
+	}
+	return res, nil
+}
+
+// fiberLeaves returns the indices of the leaves of the fibers of the queries
+// at the i-th step, in increasing order and without duplicates.
+func (s radixTwoFri) fiberLeaves(queries [][]int, i int) []uint64 {
+	arity := s.arities[i]
+	res := make([]uint64, 0, len(queries)*arity)
+	for _, si := range queries {
+		start := si[i] - si[i]%arity
+		for t := 0; t < arity; t++ {
+			res = append(res, uint64(start+t))
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	k := 0
+	for i := range res {
+		if i == 0 || res[i] != res[k-1] {
+			res[k] = res[i]
+			k++
+		}
+	}
+	return res[:k]
+}
+
+// buildProofOfProximitySteps generates a proof of proximity made of Steps,
+// with the parameters set by the options.
+// * p is in evaluation form
+func (s radixTwoFri) buildProofOfProximitySteps(p []fr.Element) (ProofOfProximity, error) {
+
+	var proof ProofOfProximity
+	proof.Steps = make([]Step, s.nbSteps)
+	_p := p
+
+	// Fiat Shamir transcript to derive the challenges. The xᵢ are used to fold the
+	// polynomials.
+	// During the i-th step, the prover has a polynomial P of degree n. The verifier sends
+	// xᵢ∈ Fᵣ to the prover. The prover expresses P in Fᵣ[X,Y]/<Y-Xᵃ> (a is the arity) as
+	// ∑ⱼXʲPⱼ(Y) where the Pⱼ are of degree n/a, and he then folds the polynomial
+	// into ∑ⱼxᵢʲPⱼ(Y). This amounts to log₂(a) foldings by 2 with xᵢ, xᵢ², xᵢ⁴, ...
+	fs, xis := s.transcript()
+
+	// gInv inverse of the generator of the cyclic group of size the size of the polynomial.
+	// The size of the cyclic group is ρ*s.domainSize, and not s.domainSize.
+	var gInv fr.Element
+	gInv.Set(&s.domain.GeneratorInv)
+
+	// step 1 : commit to the polynomial and fold it using the xi
+	trees := make([]*merkletree.Tree, s.nbSteps)
+	for i := 0; i < s.nbSteps; i++ {
+
+		// commit to the evaluations, sorted by fibers
+		evals := sortFibers(_p, s.arities[i])
+		trees[i] = merkletree.NewRetained(s.h)
+		for k := 0; k < len(evals); k++ {
+			trees[i].Push(evals[k].Marshal())
+		}
+		proof.Steps[i].MerkleRoot = trees[i].Root()
+		err := fs.Bind(xis[i], proof.Steps[i].MerkleRoot)
+		if err != nil {
+			return proof, err
+		}
+
+		// derive the challenge
+		bxi, err := fs.ComputeChallenge(xis[i])
+		if err != nil {
+			return proof, err
+		}
+		var xi fr.Element
+		xi.SetBytes(bxi)
+
+		// fold _p
+		for arity := s.arities[i]; arity > 1; arity /= 2 {
+			_p = foldPolynomialLagrangeBasis(sortFibers(_p, 2), gInv, xi)
+
+			// g <- g², x <- x²
+			gInv.Square(&gInv)
+			xi.Square(&xi)
+		}
+
+	}
+
+	// last step, provide the final polynomial. It is evaluated on a domain of size
+	// ρ*finalSize, and is supposed to be of degree less than finalSize.
+	finalDomain := fft.NewDomain(uint64(len(_p)))
+	finalDomain.FFTInverse(_p, fft.DIF)
+	fft.BitReverse(_p)
+	proof.FinalPolynomial = _p[:s.finalSize]
+
+	// step 2: solve the proof of work and derive the verifier queries
+	seed, err := s.proofOfWorkSeed(fs, proof.FinalPolynomial)
+	if err != nil {
+		return proof, err
+	}
+	for {
+		ok, err := s.checkProofOfWork(seed, proof.Nonce)
+		if err != nil {
+			return proof, err
+		}
+		if ok {
+			break
+		}
+		proof.Nonce++
+	}
+	queries, err := s.deriveQueries(fs, proof.Nonce)
+	if err != nil {
+		return proof, err
+	}
+
+	// step 3: provide the Merkle proofs of the fibers of the queries
+	for i := 0; i < s.nbSteps; i++ {
+		_, proof.Steps[i].Openings, err = trees[i].MultiProve(s.fiberLeaves(queries, i))
+		if err != nil {
+			return proof, err
+		}
+	}
+
+	return proof, nil
+}
+
+// openedValues checks the openings of the i-th step against its Merkle root,
+// and returns the opened values, indexed by leaf. n is the number of leaves.
+func (s radixTwoFri) openedValues(step Step, queries [][]int, i int, n uint64) (map[uint64]fr.Element, error) {
+
+	// the opened leaves must be the fibers of the queries
+	leaves := s.fiberLeaves(queries, i)
+	openings := step.Openings
+	if openings.NumLeaves != n || len(openings.Indices) != len(leaves) {
+		return nil, ErrMerklePath
+	}
+	for k := range leaves {
+		if openings.Indices[k] != leaves[k] {
+			return nil, ErrMerklePath
+		}
+	}
+	if !merkletree.VerifyMultiProof(s.h, step.MerkleRoot, openings) {
+		return nil, ErrMerklePath
+	}
+
+	res := make(map[uint64]fr.Element, len(leaves))
+	for k, leaf := range leaves {
+		var v fr.Element
+		v.SetBytes(openings.Leaves[k])
+		res[leaf] = v
+	}
+	return res, nil
+}
+
+// verifyProofOfProximitySteps verifies a proof of proximity made of Steps, by
+// checking the foldings of the fibers of each query, step by step.
+func (s radixTwoFri) verifyProofOfProximitySteps(proof ProofOfProximity) error {
+
+	if len(proof.Steps) != s.nbSteps {
+		return ErrProximityTestFolding
+	}
+	if len(proof.FinalPolynomial) != s.finalSize {
+		return ErrLowDegree
+	}
+
+	// Fiat Shamir transcript to derive the challenges
+	fs, xis := s.transcript()
+	xi := make([]fr.Element, s.nbSteps)
+	for i := 0; i < s.nbSteps; i++ {
+		err := fs.Bind(xis[i], proof.Steps[i].MerkleRoot)
+		if err != nil {
+			return err
+		}
+		bxi, err := fs.ComputeChallenge(xis[i])
+		if err != nil {
+			return err
+		}
+		xi[i].SetBytes(bxi)
+	}
+
+	// check the proof of work and derive the verifier queries
+	seed, err := s.proofOfWorkSeed(fs, proof.FinalPolynomial)
+	if err != nil {
+		return err
+	}
+	if ok, err := s.checkProofOfWork(seed, proof.Nonce); err != nil {
+		return err
+	} else if !ok {
+		return ErrProofOfWork
+	}
+	queries, err := s.deriveQueries(fs, proof.Nonce)
+	if err != nil {
+		return err
+	}
+
+	// for each step check the Merkle proofs and the correctness of the folding.
+	// folded[k] is the value of the current polynomial at the k-th query,
+	// obtained by folding its fiber in the previous step.
+	folded := make([]fr.Element, len(queries))
+	n := s.domain.Cardinality
+	var accGInv fr.Element
+	accGInv.Set(&s.domain.GeneratorInv)
+	for i := 0; i < s.nbSteps; i++ {
+
+		values, err := s.openedValues(proof.Steps[i], queries, i, n)
+		if err != nil {
+			return err
+		}
+
+		arity := uint64(s.arities[i])
+		fiber := make([]fr.Element, arity)
+		for k, si := range queries {
+			pos := uint64(si[i])
+
+			// correctness of the previous folding
+			if i > 0 {
+				v := values[pos]
+				if !v.Equal(&folded[k]) {
+					return ErrProximityTestFolding
+				}
+			}
+
+			start := pos - pos%arity
+			for t := uint64(0); t < arity; t++ {
+				fiber[t] = values[start+t]
+			}
+			folded[k] = foldFiber(fiber, accGInv, pos/arity, n, xi[i])
+		}
+
+		// next inverse generator
+		for k := arity; k > 1; k /= 2 {
+			accGInv.Square(&accGInv)
+		}
+		n /= arity
+	}
+
+	// Last step: the folded values should be the evaluations of the final
+	// polynomial.
+	var g, x, y fr.Element
+	g.Inverse(&accGInv)
+	last := s.nbSteps - 1
+	for k, si := range queries {
+		x.Exp(g, big.NewInt(int64(si[last]/s.arities[last])))
+		y.SetZero()
+		for j := len(proof.FinalPolynomial) - 1; j >= 0; j-- {
+			y.Mul(&y, &x).Add(&y, &proof.FinalPolynomial[j])
+		}
+		if !y.Equal(&folded[k]) {
+			return ErrLowDegree
+		}
+	}
+
+	return nil
+
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package merkle
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/utils"
+)
+
+var (
+	ErrArity    = errors.New("arity must be 2, 4, 8 or 16")
+	ErrNoLeaves = errors.New("a tree must have at least one leaf")
+	ErrIndex    = errors.New("leaf index out of range")
+	ErrProof    = errors.New("merkle proof verification failed")
+)
+
+// number of nodes computed by a job of the worker pool
+const minBlockSize = 64
+
+// Tree is a Merkle tree of field elements. It keeps all its nodes, so that
+// any set of leaves can be opened.
+type Tree struct {
+	arity    int
+	hasher   Hasher
+	nbLeaves int
+
+	// levels[0] holds the leaves padded with zeroes to a power of the arity,
+	// levels[l] the nodes of height l and levels[Depth()] the root.
+	levels []fr.Vector
+}
+
+// New builds the Merkle tree of the given leaves. The nodes of each level are
+// computed in parallel.
+func New(leaves fr.Vector, opts ...Option) (*Tree, error) {
+	cfg := options(opts...)
+	if !validArity(cfg.arity) {
+		return nil, ErrArity
+	}
+	if len(leaves) == 0 {
+		return nil, ErrNoLeaves
+	}
+
+	depth, size := 1, cfg.arity
+	for size < len(leaves) {
+		depth++
+		size *= cfg.arity
+	}
+
+	t := &Tree{
+		arity:    cfg.arity,
+		hasher:   cfg.hasher,
+		nbLeaves: len(leaves),
+		levels:   make([]fr.Vector, depth+1),
+	}
+	t.levels[0] = make(fr.Vector, size)
+	copy(t.levels[0], leaves)
+
+	workers := cfg.workers
+	if workers == nil {
+		workers = utils.NewWorkerPool()
+		defer workers.Stop()
+	}
+	for level := 1; level <= depth; level++ {
+		children := t.levels[level-1]
+		nodes := make(fr.Vector, len(children)/t.arity)
+		workers.Submit(len(nodes), func(start, end int) {
+			for i := start; i < end; i++ {
+				nodes[i] = t.hasher(children[i*t.arity : (i+1)*t.arity])
+			}
+		}, minBlockSize).Wait()
+		t.levels[level] = nodes
+	}
+
+	return t, nil
+}
+
+// Root returns the root of the tree.
+func (t *Tree) Root() fr.Element {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Arity returns the number of children of the nodes of the tree.
+func (t *Tree) Arity() int {
+	return t.arity
+}
+
+// Depth returns the length of the paths from the leaves to the root.
+func (t *Tree) Depth() int {
+	return len(t.levels) - 1
+}
+
+// NbLeaves returns the number of leaves of the tree, without the padding.
+func (t *Tree) NbLeaves() int {
+	return t.nbLeaves
+}
+
+// validArity returns true if the arity is supported.
+func validArity(arity int) bool {
+	return arity == 2 || arity == 4 || arity == 8 || arity == 16
+}
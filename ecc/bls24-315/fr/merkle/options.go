@@ -0,0 +1,67 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package merkle
+
+import (
+	"github.com/consensys/gnark-crypto/utils"
+)
+
+// Option configures the construction of a Tree: see WithArity, WithHasher and
+// WithWorkers.
+type Option func(*treeConfig)
+
+type treeConfig struct {
+	arity   int
+	hasher  Hasher
+	workers *utils.WorkerPool
+}
+
+// options returns the configuration of a Tree: the defaults, overridden by opts.
+func options(opts ...Option) treeConfig {
+	// apply options
+	cfg := treeConfig{
+		arity:  2,
+		hasher: Poseidon,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithArity sets the number of children of the nodes of the tree: 2, 4, 8 or
+// 16. Default is 2.
+func WithArity(arity int) Option {
+	return func(cfg *treeConfig) {
+		cfg.arity = arity
+	}
+}
+
+// WithHasher sets the hash function computing the nodes. Default is Poseidon.
+func WithHasher(h Hasher) Option {
+	return func(cfg *treeConfig) {
+		cfg.hasher = h
+	}
+}
+
+// WithWorkers sets the worker pool computing the nodes of each level in
+// parallel. By default, a pool is created for the construction of the tree.
+func WithWorkers(workers *utils.WorkerPool) Option {
+	return func(cfg *treeConfig) {
+		cfg.workers = workers
+	}
+}
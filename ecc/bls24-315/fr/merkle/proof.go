@@ -0,0 +1,222 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package merkle
+
+import (
+	"math/bits"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+)
+
+// Proof is an opening of a leaf of a Tree.
+type Proof struct {
+	// Index of the leaf.
+	Index uint64
+	// Leaf is the opened leaf.
+	Leaf fr.Element
+	// Siblings[l] are the arity-1 siblings, in order, of the node of height l
+	// on the path from the leaf to the root. The node itself is at position
+	// (Index / arityˡ) mod arity among its siblings.
+	Siblings []fr.Vector
+}
+
+// BatchProof is an opening of several leaves of a Tree. The nodes shared by
+// the paths of the leaves appear once, and the nodes which can be computed
+// from the leaves don't appear at all.
+type BatchProof struct {
+	// Arity and Depth of the tree.
+	Arity, Depth int
+	// Indices of the leaves, in increasing order.
+	Indices []uint64
+	// Leaves are the opened leaves.
+	Leaves fr.Vector
+	// Siblings are the nodes needed to compute the root from the leaves,
+	// level by level from the leaves up, and in increasing order of index
+	// within a level.
+	Siblings fr.Vector
+}
+
+// Open returns the opening of the leaf at index.
+func (t *Tree) Open(index uint64) (Proof, error) {
+	if index >= uint64(t.nbLeaves) {
+		return Proof{}, ErrIndex
+	}
+	proof := Proof{
+		Index:    index,
+		Leaf:     t.levels[0][index],
+		Siblings: make([]fr.Vector, t.Depth()),
+	}
+	arity := uint64(t.arity)
+	for level := range proof.Siblings {
+		first := index - index%arity
+		siblings := make(fr.Vector, 0, t.arity-1)
+		siblings = append(siblings, t.levels[level][first:index]...)
+		siblings = append(siblings, t.levels[level][index+1:first+arity]...)
+		proof.Siblings[level] = siblings
+		index /= arity
+	}
+	return proof, nil
+}
+
+// BatchOpen returns the opening of the leaves at the given indices, which may
+// be in any order. Duplicates are ignored.
+func (t *Tree) BatchOpen(indices []uint64) (BatchProof, error) {
+	proof := BatchProof{
+		Arity:   t.arity,
+		Depth:   t.Depth(),
+		Indices: sortedUnique(indices),
+	}
+	if len(proof.Indices) == 0 || proof.Indices[len(proof.Indices)-1] >= uint64(t.nbLeaves) {
+		return BatchProof{}, ErrIndex
+	}
+	proof.Leaves = make(fr.Vector, len(proof.Indices))
+	for i, index := range proof.Indices {
+		proof.Leaves[i] = t.levels[0][index]
+	}
+
+	// known are the indices of the nodes of the current level which can be
+	// computed from the leaves
+	known := proof.Indices
+	arity := uint64(t.arity)
+	for level := 0; level < t.Depth(); level++ {
+		parents := make([]uint64, 0, len(known))
+		for i := 0; i < len(known); {
+			parent := known[i] / arity
+			for child := parent * arity; child < (parent+1)*arity; child++ {
+				if i < len(known) && known[i] == child {
+					i++
+				} else {
+					proof.Siblings = append(proof.Siblings, t.levels[level][child])
+				}
+			}
+			parents = append(parents, parent)
+		}
+		known = parents
+	}
+	return proof, nil
+}
+
+// Verify returns nil if the proof is a valid opening of a tree of the given
+// root, arity and depth, whose nodes are computed with h. The shape of the tree
+// must be known to the verifier: a shorter proof would open a node as a leaf.
+func (proof *Proof) Verify(root fr.Element, arity, depth int, h Hasher) error {
+	if !validShape(arity, depth) || len(proof.Siblings) != depth || !validIndex(proof.Index, arity, depth) {
+		return ErrProof
+	}
+
+	node := proof.Leaf
+	index := proof.Index
+	children := make(fr.Vector, arity)
+	for _, siblings := range proof.Siblings {
+		if len(siblings) != arity-1 {
+			return ErrProof
+		}
+		position := int(index % uint64(arity))
+		copy(children[:position], siblings[:position])
+		children[position] = node
+		copy(children[position+1:], siblings[position:])
+		node = h(children)
+		index /= uint64(arity)
+	}
+
+	if !node.Equal(&root) {
+		return ErrProof
+	}
+	return nil
+}
+
+// Verify returns nil if the proof is a valid opening of a tree of the given
+// root, arity and depth, whose nodes are computed with h. The shape of the
+// proof must match the expected one.
+func (proof *BatchProof) Verify(root fr.Element, arity, depth int, h Hasher) error {
+	if !validShape(arity, depth) || proof.Arity != arity || proof.Depth != depth {
+		return ErrProof
+	}
+	n := len(proof.Indices)
+	if n == 0 || len(proof.Leaves) != n || !validIndex(proof.Indices[n-1], arity, depth) {
+		return ErrProof
+	}
+	for i := 1; i < n; i++ {
+		if proof.Indices[i] <= proof.Indices[i-1] {
+			return ErrProof
+		}
+	}
+
+	known := proof.Indices
+	nodes := proof.Leaves
+	siblings := proof.Siblings
+	a := uint64(arity)
+	children := make(fr.Vector, arity)
+	for level := 0; level < depth; level++ {
+		parents := make([]uint64, 0, len(known))
+		parentNodes := make(fr.Vector, 0, len(known))
+		for i := 0; i < len(known); {
+			parent := known[i] / a
+			for j, child := 0, parent*a; child < (parent+1)*a; j, child = j+1, child+1 {
+				if i < len(known) && known[i] == child {
+					children[j] = nodes[i]
+					i++
+				} else {
+					if len(siblings) == 0 {
+						return ErrProof
+					}
+					children[j] = siblings[0]
+					siblings = siblings[1:]
+				}
+			}
+			parents = append(parents, parent)
+			parentNodes = append(parentNodes, h(children))
+		}
+		known, nodes = parents, parentNodes
+	}
+
+	if len(siblings) != 0 || !nodes[0].Equal(&root) {
+		return ErrProof
+	}
+	return nil
+}
+
+// validShape returns true if the arity is supported and if the number of
+// leaves of a tree of the given depth, arity^depth, fits in a uint64.
+func validShape(arity, depth int) bool {
+	return validArity(arity) && depth >= 1 && depth <= 64/bits.TrailingZeros(uint(arity))
+}
+
+// validIndex returns true if index is smaller than arity^depth, for a valid
+// shape.
+func validIndex(index uint64, arity, depth int) bool {
+	logArity := bits.TrailingZeros(uint(arity))
+	if depth*logArity == 64 {
+		return true
+	}
+	return index < 1<<(depth*logArity)
+}
+
+// sortedUnique returns the indices in increasing order, without duplicates.
+func sortedUnique(indices []uint64) []uint64 {
+	res := append([]uint64(nil), indices...)
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	n := 0
+	for i := range res {
+		if i == 0 || res[i] != res[n-1] {
+			res[n] = res[i]
+			n++
+		}
+	}
+	return res[:n]
+}
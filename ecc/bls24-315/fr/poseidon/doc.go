@@ -0,0 +1,40 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package poseidon provides the Poseidon permutation and a sponge hash function built on it.
+//
+// The permutation is available for all widths from MinWidth to MaxWidth, with the
+// S-box x ↦ x^7. The round numbers target 128 bits of security
+// , following
+// the reference script of the Poseidon authors.
+//
+// # Hash input format
+//
+// The sponge hasher returned by NewPoseidon is defined over a field. The input
+// to the hash function is a byte slice, interpreted as a sequence of field elements.
+// Due to this interpretation, the input byte slice length must be multiple of the
+// field modulus size. And every sequence of byte slice for a single field element
+// must be strictly less than the field modulus.
+//
+// # Sponge
+//
+// The first element of the state is the capacity, the other ones are the rate.
+// The input is padded with a single 1 followed by zeroes up to a multiple of
+// the rate, then absorbed by adding each block to the rate part of the state
+// and applying the permutation. The digest is the second element of the state.
+//
+// See https://eprint.iacr.org/2019/458 for the specification.
+package poseidon
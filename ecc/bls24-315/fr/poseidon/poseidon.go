@@ -0,0 +1,202 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+)
+
+const (
+	// MinWidth is the smallest supported width of the permutation.
+	MinWidth = 2
+	// MaxWidth is the largest supported width of the permutation.
+	MaxWidth = 17
+	// SBoxDegree is the exponent α of the S-box x ↦ x^α.
+	SBoxDegree = 7
+)
+
+// number of full and partial rounds, indexed by width - MinWidth
+var (
+	nbFullRounds    = [...]int{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8}
+	nbPartialRounds = [...]int{46, 46, 46, 46, 46, 46, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47}
+)
+
+var (
+	ErrInvalidWidth      = fmt.Errorf("width must be between %d and %d", MinWidth, MaxWidth)
+	ErrInvalidSizebuffer = errors.New("the size of the input should match the size of the hash buffer")
+)
+
+// Parameters describes a Poseidon permutation instance.
+type Parameters struct {
+	// Width is the number of field elements of the state.
+	Width int
+
+	// NbFullRounds is the number of full rounds, half of them are
+	// performed before the partial rounds.
+	NbFullRounds int
+
+	// NbPartialRounds is the number of partial rounds.
+	NbPartialRounds int
+
+	// RoundKeys are the round constants, RoundKeys[i] is added to the state
+	// at the beginning of round i.
+	RoundKeys [][]fr.Element
+
+	// MDS is the matrix of the linear layer.
+	MDS [][]fr.Element
+}
+
+var (
+	parameters     [MaxWidth - MinWidth + 1]*Parameters
+	parametersOnce [MaxWidth - MinWidth + 1]sync.Once
+)
+
+// GetParameters returns the parameters of the permutation of the given width.
+//
+// The round constants and the MDS matrix are derived with the Grain LFSR as
+// in the reference implementation of the Poseidon authors. The MDS matrix is
+// the Cauchy matrix Mᵢⱼ = 1/(xᵢ+yⱼ) built from the first 2⋅width elements
+// output by the LFSR after the round constants.
+func GetParameters(width int) (*Parameters, error) {
+	if width < MinWidth || width > MaxWidth {
+		return nil, ErrInvalidWidth
+	}
+	i := width - MinWidth
+	parametersOnce[i].Do(func() {
+		parameters[i] = newParameters(width, nbFullRounds[i], nbPartialRounds[i])
+	})
+	return parameters[i], nil
+}
+
+func newParameters(width, rf, rp int) *Parameters {
+	p := &Parameters{
+		Width:           width,
+		NbFullRounds:    rf,
+		NbPartialRounds: rp,
+	}
+	g := newGrain(width, rf, rp)
+
+	p.RoundKeys = make([][]fr.Element, rf+rp)
+	for i := range p.RoundKeys {
+		p.RoundKeys[i] = make([]fr.Element, width)
+		for j := range p.RoundKeys[i] {
+			p.RoundKeys[i][j] = g.element()
+		}
+	}
+
+	xy := make([]fr.Element, 2*width)
+	for i := range xy {
+		xy[i] = g.elementReduced()
+	}
+	p.MDS = make([][]fr.Element, width)
+	for i := range p.MDS {
+		p.MDS[i] = make([]fr.Element, width)
+		for j := range p.MDS[i] {
+			p.MDS[i][j].Add(&xy[i], &xy[width+j])
+		}
+		p.MDS[i] = fr.BatchInvert(p.MDS[i])
+	}
+
+	return p
+}
+
+// Permutation is the Poseidon permutation of a given width.
+type Permutation struct {
+	params *Parameters
+}
+
+// NewPermutation returns the Poseidon permutation of the given width.
+func NewPermutation(width int) (*Permutation, error) {
+	params, err := GetParameters(width)
+	if err != nil {
+		return nil, err
+	}
+	return &Permutation{params: params}, nil
+}
+
+// Width returns the number of field elements of the state.
+func (p *Permutation) Width() int {
+	return p.params.Width
+}
+
+// Permutation applies the permutation in place on the state input.
+//
+// Each round adds the round keys, applies the S-box (to all the elements in
+// a full round, to the first one in a partial round) and multiplies the
+// state by the MDS matrix.
+func (p *Permutation) Permutation(input []fr.Element) error {
+	if len(input) != p.params.Width {
+		return ErrInvalidSizebuffer
+	}
+	rf := p.params.NbFullRounds / 2
+	tmp := make([]fr.Element, p.params.Width)
+	for i := range p.params.RoundKeys {
+		for j := range input {
+			input[j].Add(&input[j], &p.params.RoundKeys[i][j])
+		}
+		if i < rf || i >= rf+p.params.NbPartialRounds {
+			for j := range input {
+				sBox(&input[j])
+			}
+		} else {
+			sBox(&input[0])
+		}
+		p.mulMDS(input, tmp)
+	}
+	return nil
+}
+
+// mulMDS sets input to MDS ⋅ input, using tmp as scratch space.
+func (p *Permutation) mulMDS(input, tmp []fr.Element) {
+	var t fr.Element
+	for i := range tmp {
+		tmp[i].SetZero()
+		for j := range input {
+			t.Mul(&p.params.MDS[i][j], &input[j])
+			tmp[i].Add(&tmp[i], &t)
+		}
+	}
+	copy(input, tmp)
+}
+
+// Hash returns the Poseidon hash of 1 to MaxWidth-1 field elements as in circomlib:
+// the permutation of width len(inputs)+1 is applied to (0, inputs...) and
+// the first element of the state is returned.
+func Hash(inputs ...fr.Element) (fr.Element, error) {
+	p, err := NewPermutation(len(inputs) + 1)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	state := make([]fr.Element, len(inputs)+1)
+	copy(state[1:], inputs)
+	if err := p.Permutation(state); err != nil {
+		return fr.Element{}, err
+	}
+	return state[0], nil
+}
+
+// sBox sets x to x^7
+func sBox(x *fr.Element) {
+	var x2, x4 fr.Element
+	x2.Square(x)
+	x4.Square(&x2)
+	x.Mul(x, &x2).Mul(x, &x4)
+}
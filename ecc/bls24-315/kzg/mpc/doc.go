@@ -0,0 +1,31 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package mpc implements a sequential powers-of-tau ceremony (MPC setup)
+// producing a KZG SRS.
+//
+// Each participant takes the latest Contribution, samples a secret x and
+// multiplies the accumulated powers of τ by the powers of x. Along with the
+// updated powers, the participant publishes a proof of knowledge of x. As long
+// as one participant discards its secret, nobody knows the final τ.
+//
+// The proof of knowledge follows the Zcash powers-of-tau ceremony
+// (https://eprint.iacr.org/2017/1050): R = HashToG2(transcript) and the
+// participant publishes [x]R, with a pair ([r]G₁, [rx]G₁) binding x to the transcript.
+//
+// The whole chain of contributions is verified with a single PairingCheck, using
+// random linear combinations of the individual checks.
+package mpc
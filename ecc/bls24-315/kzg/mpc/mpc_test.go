@@ -0,0 +1,165 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package mpc
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/kzg"
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/utils/testutils"
+)
+
+// ceremony runs a ceremony with nbContributions participants
+func ceremony(t testing.TB, size uint64, nbContributions int) []*Contribution {
+	contributions := make([]*Contribution, nbContributions+1)
+	var err error
+	contributions[0], err = NewContribution(size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(contributions); i++ {
+		contributions[i], err = contributions[i-1].Contribute()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return contributions
+}
+
+func TestCeremony(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 33
+	contributions := ceremony(t, size, 3)
+
+	// verify the chain
+	assert.NoError(Verify(contributions...))
+
+	// the intermediate contributions can be truncated
+	truncated := make([]*Contribution, len(contributions))
+	copy(truncated, contributions)
+	for i := 0; i < len(truncated)-1; i++ {
+		c := *truncated[i]
+		c.G1 = c.G1[:2]
+		truncated[i] = &c
+	}
+	assert.NoError(Verify(truncated...))
+
+	// the SRS can be used with the kzg package
+	srs := contributions[len(contributions)-1].SRS()
+	p := make([]fr.Element, size)
+	for i := range p {
+		p[i].SetRandom()
+	}
+	digest, err := kzg.Commit(p, srs.Pk)
+	assert.NoError(err)
+	var point fr.Element
+	point.SetRandom()
+	proof, err := kzg.Open(p, point, srs.Pk)
+	assert.NoError(err)
+	assert.NoError(kzg.Verify(&digest, &proof, point, srs.Vk))
+	batchProof, err := kzg.BatchOpenSinglePoint([][]fr.Element{p, p}, []kzg.Digest{digest, digest}, point, sha256.New(), srs.Pk)
+	assert.NoError(err)
+	assert.NoError(kzg.BatchVerifySinglePoint([]kzg.Digest{digest, digest}, &batchProof, point, sha256.New(), srs.Vk))
+}
+
+func TestCeremonyInvalid(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 8
+	contributions := ceremony(t, size, 3)
+	assert.NoError(Verify(contributions...))
+
+	// a contribution is missing
+	assert.ErrorIs(Verify(contributions[0], contributions[2], contributions[3]), ErrInvalidContribution)
+	assert.ErrorIs(Verify(contributions[0], contributions[1], contributions[3]), ErrInvalidContribution)
+
+	// the chain doesn't start with the initial state
+	assert.ErrorIs(Verify(contributions[1:]...), ErrInvalidInitial)
+	assert.ErrorIs(Verify(contributions[0]), ErrInvalidContribution)
+
+	// the intermediate contributions must have at least 2 powers
+	truncated := *contributions[1]
+	truncated.G1 = truncated.G1[:1]
+	assert.ErrorIs(Verify(contributions[0], &truncated, contributions[2]), ErrMinSize)
+
+	// the last contribution is not made of powers of τ
+	last := *contributions[3]
+	last.G1 = make([]bls24315.G1Affine, size)
+	copy(last.G1, contributions[3].G1)
+	last.G1[5], last.G1[6] = last.G1[6], last.G1[5]
+	assert.ErrorIs(Verify(contributions[0], contributions[1], contributions[2], &last), ErrInvalidContribution)
+
+	// [τ]G₂ is inconsistent
+	last = *contributions[3]
+	last.G2[1] = contributions[2].G2[1]
+	assert.ErrorIs(Verify(contributions[0], contributions[1], contributions[2], &last), ErrInvalidContribution)
+
+	// proof of knowledge replayed from another contribution
+	last = *contributions[3]
+	last.Proof = contributions[2].Proof
+	assert.ErrorIs(Verify(contributions[0], contributions[1], contributions[2], &last), ErrInvalidContribution)
+
+	// proof of knowledge of another secret
+	var x fr.Element
+	x.SetRandom()
+	c, err := contributions[2].contribute(&x)
+	assert.NoError(err)
+	x.Double(&x)
+	c2, err := contributions[2].contribute(&x)
+	assert.NoError(err)
+	c.Proof = c2.Proof
+	assert.ErrorIs(Verify(contributions[0], contributions[1], contributions[2], c), ErrInvalidContribution)
+
+	// a zero secret erases the previous contributions
+	x.SetZero()
+	c, err = contributions[2].contribute(&x)
+	assert.NoError(err)
+	assert.ErrorIs(Verify(contributions[0], contributions[1], contributions[2], c), ErrDegenerateContribution)
+}
+
+func TestSerializationContribution(t *testing.T) {
+	contributions := ceremony(t, 16, 1)
+	t.Run("contribution round-trip", testutils.SerializationRoundTrip(contributions[1]))
+	t.Run("contribution raw round-trip", testutils.SerializationRoundTripRaw(contributions[1]))
+}
+
+func BenchmarkContribute(b *testing.B) {
+	const size = 1 << 10
+	c, err := NewContribution(size)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Contribute()
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	const size = 1 << 10
+	contributions := ceremony(b, size, 4)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Verify(contributions...)
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/utils/testutils"
+)
+
+// multiPointsTestData returns polynomials of various sizes, their digests, and
+// overlapping sets of opening points.
+func multiPointsTestData(t testing.TB) ([][]fr.Element, []Digest, [][]fr.Element) {
+	sizes := []int{40, 1, 17, 3, 64}
+	f := make([][]fr.Element, len(sizes))
+	digests := make([]Digest, len(sizes))
+	for i := range f {
+		f[i] = randomPolynomial(sizes[i])
+		var err error
+		digests[i], err = Commit(f[i], testSrs.Pk)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var shared fr.Element
+	shared.SetRandom()
+	points := make([][]fr.Element, len(sizes))
+	for i := range points {
+		// the sets share a point, and have 1 to 5 points (possibly more than the size of the polynomial)
+		points[i] = make([]fr.Element, i+1)
+		points[i][0] = shared
+		for j := 1; j < len(points[i]); j++ {
+			points[i][j].SetRandom()
+		}
+	}
+	return f, digests, points
+}
+
+func TestBatchOpenMultiPoints(t *testing.T) {
+	assert := require.New(t)
+
+	f, digests, points := multiPointsTestData(t)
+
+	// pick a hash function
+	hf := sha256.New()
+
+	proof, err := BatchOpenMultiPoints(f, digests, points, hf, testSrs.Pk)
+	assert.NoError(err)
+
+	// verify the claimed values
+	for i := range f {
+		for j := range points[i] {
+			expectedClaim := eval(f[i], points[i][j])
+			assert.True(expectedClaim.Equal(&proof.ClaimedValues[i][j]), "inconsistent claimed values")
+		}
+	}
+
+	// verify correct proof
+	assert.NoError(VerifyBatchMultiPoints(digests, &proof, points, hf, testSrs.Vk))
+
+	// verify correct proof with extended transcript
+	var salt fr.Element
+	salt.SetRandom()
+	proofExtendedTranscript, err := BatchOpenMultiPoints(f, digests, points, hf, testSrs.Pk, salt.Marshal())
+	assert.NoError(err)
+	assert.NoError(VerifyBatchMultiPoints(digests, &proofExtendedTranscript, points, hf, testSrs.Vk, salt.Marshal()))
+	assert.Error(VerifyBatchMultiPoints(digests, &proofExtendedTranscript, points, hf, testSrs.Vk), "transcript mismatch")
+
+	// single polynomial at a single point
+	proofSingle, err := BatchOpenMultiPoints(f[:1], digests[:1], points[:1], hf, testSrs.Pk)
+	assert.NoError(err)
+	assert.NoError(VerifyBatchMultiPoints(digests[:1], &proofSingle, points[:1], hf, testSrs.Vk))
+
+	// constant polynomial: W and W' are zero
+	proofConstant, err := BatchOpenMultiPoints(f[1:2], digests[1:2], points[:1], hf, testSrs.Pk)
+	assert.NoError(err)
+	assert.True(proofConstant.WPrime.IsInfinity())
+	assert.NoError(VerifyBatchMultiPoints(digests[1:2], &proofConstant, points[:1], hf, testSrs.Vk))
+
+	{
+		// verify wrong proof
+		tampered := proof
+		tampered.ClaimedValues = make([][]fr.Element, len(proof.ClaimedValues))
+		for i := range tampered.ClaimedValues {
+			tampered.ClaimedValues[i] = append([]fr.Element{}, proof.ClaimedValues[i]...)
+		}
+		tampered.ClaimedValues[2][1].Double(&tampered.ClaimedValues[2][1])
+		assert.Error(VerifyBatchMultiPoints(digests, &tampered, points, hf, testSrs.Vk))
+	}
+	{
+		// verify proof against wrong points
+		wrongPoints := make([][]fr.Element, len(points))
+		copy(wrongPoints, points)
+		wrongPoints[3] = append([]fr.Element{}, points[3]...)
+		wrongPoints[3][2].SetRandom()
+		assert.Error(VerifyBatchMultiPoints(digests, &proof, wrongPoints, hf, testSrs.Vk))
+	}
+	{
+		// verify proof against wrong digests
+		wrongDigests := append([]Digest{}, digests...)
+		wrongDigests[0], wrongDigests[4] = digests[4], digests[0]
+		assert.Error(VerifyBatchMultiPoints(wrongDigests, &proof, points, hf, testSrs.Vk))
+	}
+	{
+		// verify wrong proof with quotients set to zero
+		// see https://cryptosubtlety.medium.com/00-8d4adcf4d255
+		tampered := proof
+		tampered.W.X.SetZero()
+		tampered.W.Y.SetZero()
+		assert.Error(VerifyBatchMultiPoints(digests, &tampered, points, hf, testSrs.Vk))
+		tampered.WPrime.X.SetZero()
+		tampered.WPrime.Y.SetZero()
+		assert.Error(VerifyBatchMultiPoints(digests, &tampered, points, hf, testSrs.Vk))
+	}
+}
+
+func TestBatchOpenMultiPointsInvalidInputs(t *testing.T) {
+	assert := require.New(t)
+
+	f, digests, points := multiPointsTestData(t)
+	hf := sha256.New()
+
+	_, err := BatchOpenMultiPoints(f, digests[1:], points, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidNbDigests)
+
+	_, err = BatchOpenMultiPoints(nil, nil, nil, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrZeroNbDigests)
+
+	_, err = BatchOpenMultiPoints(f, digests, points[1:], hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidNbPoints)
+
+	duplicates := make([][]fr.Element, len(points))
+	copy(duplicates, points)
+	duplicates[2] = []fr.Element{points[2][0], points[2][1], points[2][0]}
+	_, err = BatchOpenMultiPoints(f, digests, duplicates, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPointSet)
+
+	empty := make([][]fr.Element, len(points))
+	copy(empty, points)
+	empty[0] = nil
+	_, err = BatchOpenMultiPoints(f, digests, empty, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPointSet)
+
+	proof, err := BatchOpenMultiPoints(f, digests, points, hf, testSrs.Pk)
+	assert.NoError(err)
+	assert.ErrorIs(VerifyBatchMultiPoints(digests, &proof, points[1:], hf, testSrs.Vk), ErrInvalidNbPoints)
+	assert.ErrorIs(VerifyBatchMultiPoints(digests[1:], &proof, points, hf, testSrs.Vk), ErrInvalidNbDigests)
+	assert.ErrorIs(VerifyBatchMultiPoints(digests, &proof, duplicates, hf, testSrs.Vk), ErrInvalidPointSet)
+}
+
+func TestInterpolateAt(t *testing.T) {
+	assert := require.New(t)
+
+	p := randomPolynomial(5)
+	points := make([]fr.Element, 5)
+	values := make([]fr.Element, 5)
+	for i := range points {
+		points[i].SetRandom()
+		values[i] = eval(p, points[i])
+	}
+
+	var z fr.Element
+	z.SetRandom()
+	expected := eval(p, z)
+	got := interpolateAt(points, values, z)
+	assert.True(expected.Equal(&got))
+}
+
+func TestSerializationMultiPointsOpeningProof(t *testing.T) {
+	f, digests, points := multiPointsTestData(t)
+	proof, err := BatchOpenMultiPoints(f, digests, points, sha256.New(), testSrs.Pk)
+	require.NoError(t, err)
+	t.Run("opening proof round-trip", testutils.SerializationRoundTrip(&proof))
+}
+
+func BenchmarkKZGBatchOpenMultiPoints10(b *testing.B) {
+	srs, polynomials, digests, points := benchmarkMultiPointsData(b)
+	hf := sha256.New()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchOpenMultiPoints(polynomials, digests, points, hf, srs.Pk)
+	}
+}
+
+func BenchmarkKZGVerifyBatchMultiPoints10(b *testing.B) {
+	srs, polynomials, digests, points := benchmarkMultiPointsData(b)
+	hf := sha256.New()
+
+	proof, err := BatchOpenMultiPoints(polynomials, digests, points, hf, srs.Pk)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		VerifyBatchMultiPoints(digests, &proof, points, hf, srs.Vk)
+	}
+}
+
+// benchmarkMultiPointsData returns 10 random polynomials, each opened at 2 points among 3
+func benchmarkMultiPointsData(b *testing.B) (*SRS, [][]fr.Element, []Digest, [][]fr.Element) {
+	srs, err := NewSRS(ecc.NextPowerOfTwo(benchSize), bAlpha)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var s [3]fr.Element
+	for i := range s {
+		s[i].SetRandom()
+	}
+
+	polynomials := make([][]fr.Element, 10)
+	digests := make([]Digest, 10)
+	points := make([][]fr.Element, 10)
+	for i := range polynomials {
+		polynomials[i] = randomPolynomial(benchSize / 2)
+		digests[i], _ = Commit(polynomials[i], srs.Pk)
+		points[i] = []fr.Element{s[i%3], s[(i+1)%3]}
+	}
+	return srs, polynomials, digests, points
+}
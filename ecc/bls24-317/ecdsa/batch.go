@@ -0,0 +1,47 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"errors"
+	"hash"
+)
+
+var errInvalidBatch = errors.New("number of public keys, signatures and messages must match")
+
+// BatchVerify verifies the signatures of messages under publicKeys. It returns
+// the index of the first invalid signature, or -1 when they are all valid.
+//
+// On bls24-317 the base field is much larger than the scalar field, so that
+// the nonce commitment R can't be lifted from r with a recovery bit and the
+// verification equations can't be combined in a single multi-exponentiation.
+// The signatures are hence verified one by one.
+func BatchVerify(publicKeys []PublicKey, signatures, messages [][]byte, hFunc hash.Hash) (bool, int, error) {
+	if len(publicKeys) != len(signatures) || len(publicKeys) != len(messages) {
+		return false, -1, errInvalidBatch
+	}
+	for i := range publicKeys {
+		valid, err := publicKeys[i].Verify(signatures[i], messages[i], hFunc)
+		if err != nil {
+			return false, i, err
+		}
+		if !valid {
+			return false, i, nil
+		}
+	}
+	return true, -1, nil
+}
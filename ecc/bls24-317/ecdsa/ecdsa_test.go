@@ -65,6 +65,41 @@ func TestECDSA(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
+func TestBatchVerify(t *testing.T) {
+	t.Parallel()
+	const n = 16
+	publicKeys := make([]PublicKey, n)
+	signatures := make([][]byte, n)
+	messages := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, err := GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKeys[i] = privKey.PublicKey
+		messages[i] = []byte{byte(i)}
+		signatures[i], err = privKey.Sign(messages[i], sha256.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	valid, idx, err := BatchVerify(publicKeys, signatures, messages, sha256.New())
+	if err != nil || !valid || idx != -1 {
+		t.Fatal("valid batch should verify")
+	}
+
+	messages[n/2] = []byte("tampered")
+	valid, idx, err = BatchVerify(publicKeys, signatures, messages, sha256.New())
+	if err != nil || valid || idx != n/2 {
+		t.Fatalf("batch with an invalid signature should fail at index %d, got %d", n/2, idx)
+	}
+
+	if _, _, err = BatchVerify(publicKeys, signatures[1:], messages, sha256.New()); err != errInvalidBatch {
+		t.Fatal("batch with mismatched lengths should fail")
+	}
+}
+
 func TestNonMalleability(t *testing.T) {
 
 	// buffer too big
@@ -164,3 +199,21 @@ func BenchmarkVerifyECDSA(b *testing.B) {
 		privKey.PublicKey.Verify(sig, msg, nil)
 	}
 }
+
+func BenchmarkBatchVerify64(b *testing.B) {
+	const n = 64
+	publicKeys := make([]PublicKey, n)
+	signatures := make([][]byte, n)
+	messages := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, _ := GenerateKey(rand.Reader)
+		publicKeys[i] = privKey.PublicKey
+		messages[i] = []byte{byte(i)}
+		signatures[i], _ = privKey.Sign(messages[i], nil)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchVerify(publicKeys, signatures, messages, nil)
+	}
+}
@@ -60,5 +60,27 @@ func TestSerialization(t *testing.T) {
 		},
 	))
 
+	properties.Property("[BLS24-317] ECDSA serialization: PublicKey.SetBytes should return the number of bytes read", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+
+			// the public key followed by a signature, as in a serialized transaction
+			buf := privKey.PublicKey.Bytes()
+			sig, _ := privKey.Sign([]byte("testing ECDSA"), nil)
+			buf = append(buf, sig...)
+
+			var pk PublicKey
+			n, err := pk.SetBytes(buf)
+			if err != nil || n != sizePublicKey || n != len(privKey.PublicKey.Bytes()) {
+				return false
+			}
+			var s Signature
+			if _, err := s.SetBytes(buf[n:]); err != nil {
+				return false
+			}
+			return pk.Equal(&privKey.PublicKey) && subtle.ConstantTimeCompare(s.Bytes(), sig) == 1
+		},
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
@@ -0,0 +1,283 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package mpc
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/kzg"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrMinSize                = errors.New("minimum size of the powers of tau is 2")
+	ErrInvalidInitial         = errors.New("the first contribution must be the initial one")
+	ErrDegenerateContribution = errors.New("contribution has a point at infinity")
+	ErrInvalidContribution    = errors.New("contribution chain verification failed")
+)
+
+// DST domain separation tag used to hash the transcript to G₂
+const DST = "KZG_POWERS_OF_TAU_BLS24-317_POK_"
+
+// Contribution state of the ceremony after a participant's contribution.
+//
+// implements io.ReaderFrom and io.WriterTo
+type Contribution struct {
+	// G1 [G₁ [τ]G₁ , [τ²]G₁, ... ] where τ is the accumulated secret
+	G1 []bls24317.G1Affine
+
+	// G2 [G₂, [τ]G₂ ]
+	G2 [2]bls24317.G2Affine
+
+	// Proof proof of knowledge of the secret x of the participant, such that
+	// the τ of this contribution is x times the τ of the previous one.
+	Proof ProofOfKnowledge
+}
+
+// ProofOfKnowledge proof of knowledge of a contribution secret x
+type ProofOfKnowledge struct {
+	S  bls24317.G1Affine // [r]G₁ for a random r
+	SX bls24317.G1Affine // [rx]G₁
+	RX bls24317.G2Affine // [x]R where R = HashToG2(previous contribution, S, SX)
+}
+
+// NewContribution returns the initial state of a ceremony for an SRS of the given size,
+// corresponding to τ = 1.
+func NewContribution(size uint64) (*Contribution, error) {
+	if size < 2 {
+		return nil, ErrMinSize
+	}
+	_, _, g1, g2 := bls24317.Generators()
+
+	var res Contribution
+	res.G1 = make([]bls24317.G1Affine, size)
+	parallel.Execute(len(res.G1), func(start, end int) {
+		for i := start; i < end; i++ {
+			res.G1[i] = g1
+		}
+	})
+	res.G2[0] = g2
+	res.G2[1] = g2
+
+	return &res, nil
+}
+
+// Contribute samples a random secret x and returns the next state of the ceremony,
+// where the powers of τ are replaced by the powers of xτ.
+//
+// The secret x is not returned and must not be persisted in any way.
+func (c *Contribution) Contribute() (*Contribution, error) {
+	var x fr.Element
+	for x.IsZero() {
+		if _, err := x.SetRandom(); err != nil {
+			return nil, err
+		}
+	}
+	res, err := c.contribute(&x)
+	x.SetZero()
+	return res, err
+}
+
+// contribute returns the next state of the ceremony for the secret x
+func (c *Contribution) contribute(x *fr.Element) (*Contribution, error) {
+
+	var res Contribution
+	res.G1 = make([]bls24317.G1Affine, len(c.G1))
+
+	// [xⁱτⁱ]G₁ = [xⁱ]([τⁱ]G₁)
+	parallel.Execute(len(c.G1), func(start, end int) {
+		var xi fr.Element
+		xi.Exp(*x, big.NewInt(int64(start)))
+		var bxi big.Int
+		tmp := make([]bls24317.G1Jac, end-start)
+		for i := start; i < end; i++ {
+			xi.BigInt(&bxi)
+			tmp[i-start].FromAffine(&c.G1[i])
+			tmp[i-start].ScalarMultiplication(&tmp[i-start], &bxi)
+			xi.Mul(&xi, x)
+		}
+		copy(res.G1[start:end], bls24317.BatchJacobianToAffineG1(tmp))
+	})
+
+	var bx big.Int
+	x.BigInt(&bx)
+	res.G2[0] = c.G2[0]
+	res.G2[1].ScalarMultiplication(&c.G2[1], &bx)
+
+	// proof of knowledge of x
+	var r, rx fr.Element
+	for r.IsZero() {
+		if _, err := r.SetRandom(); err != nil {
+			return nil, err
+		}
+	}
+	rx.Mul(&r, x)
+	var br, brx big.Int
+	res.Proof.S.ScalarMultiplicationBase(r.BigInt(&br))
+	res.Proof.SX.ScalarMultiplicationBase(rx.BigInt(&brx))
+	r.SetZero()
+	rx.SetZero()
+
+	R, err := c.hashToG2(&res.Proof)
+	if err != nil {
+		return nil, err
+	}
+	res.Proof.RX.ScalarMultiplication(&R, &bx)
+
+	return &res, nil
+}
+
+// SRS returns the KZG SRS corresponding to the powers of τ of the contribution.
+//
+// It does not verify the contribution, see Verify.
+func (c *Contribution) SRS() *kzg.SRS {
+	var srs kzg.SRS
+	srs.Pk.G1 = make([]bls24317.G1Affine, len(c.G1))
+	copy(srs.Pk.G1, c.G1)
+	srs.Vk.G1 = c.G1[0]
+	srs.Vk.G2 = c.G2
+	srs.Vk.Lines[0] = bls24317.PrecomputeLines(srs.Vk.G2[0])
+	srs.Vk.Lines[1] = bls24317.PrecomputeLines(srs.Vk.G2[1])
+	return &srs
+}
+
+// Verify verifies a chain of contributions, the first one being the initial state
+// of the ceremony (see NewContribution) and each one updating the previous one.
+//
+// It checks that each contribution comes with a valid proof of knowledge, that the
+// secrets were applied to the accumulated powers, and that the last contribution is
+// made of successive powers of a τ in G₁ and G₂.
+// Only the last contribution needs to be provided entirely: for the previous ones,
+// the first two powers in G₁ and G₂ and the proofs are enough.
+//
+// All the checks are batched in a single PairingCheck.
+func Verify(contributions ...*Contribution) error {
+	if len(contributions) < 2 {
+		return ErrInvalidContribution
+	}
+
+	// the chain must start with the initial state
+	_, _, g1, g2 := bls24317.Generators()
+	initial := contributions[0]
+	if len(initial.G1) < 2 || !initial.G1[0].Equal(&g1) || !initial.G1[1].Equal(&g1) ||
+		!initial.G2[0].Equal(&g2) || !initial.G2[1].Equal(&g2) {
+		return ErrInvalidInitial
+	}
+	last := contributions[len(contributions)-1]
+	if !last.G1[0].Equal(&g1) || !last.G2[0].Equal(&g2) {
+		return ErrInvalidContribution
+	}
+
+	nbContributions := len(contributions) - 1
+
+	// random coefficients for the linear combination of the checks
+	lambda := make([]fr.Element, 2*nbContributions+1)
+	for i := range lambda {
+		if _, err := lambda[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+
+	// 2 pairings per contribution, and 2 for the consistency of the last powers
+	P := make([]bls24317.G1Affine, 0, 2*nbContributions+2)
+	Q := make([]bls24317.G2Affine, 0, 2*nbContributions+2)
+	var t1, t2 bls24317.G1Jac
+	var b1, b2, nb1, nb2 big.Int
+	var neg fr.Element
+	for i := 1; i < len(contributions); i++ {
+		prev, next := contributions[i-1], contributions[i]
+		if len(next.G1) < 2 {
+			return ErrMinSize
+		}
+		if next.G1[1].IsInfinity() || next.G2[1].IsInfinity() ||
+			next.Proof.S.IsInfinity() || next.Proof.SX.IsInfinity() || next.Proof.RX.IsInfinity() {
+			return ErrDegenerateContribution
+		}
+		if !next.G2[0].Equal(&g2) {
+			return ErrInvalidContribution
+		}
+		R, err := prev.hashToG2(&next.Proof)
+		if err != nil {
+			return err
+		}
+
+		// e(S, [x]R) = e([rx]G₁, R) : knowledge of x
+		// e([xτ]G₁, R) = e([τ]G₁, [x]R) : τ is updated with x
+		// combined with λ, μ:
+		// e(λS - μ[τ]G₁, [x]R).e(μ[xτ]G₁ - λ[rx]G₁, R) == 1
+		lambda[2*i-2].BigInt(&b1)
+		lambda[2*i-1].BigInt(&b2)
+		neg.Neg(&lambda[2*i-2]).BigInt(&nb1)
+		neg.Neg(&lambda[2*i-1]).BigInt(&nb2)
+		var a1, a2 bls24317.G1Affine
+		t1.JointScalarMultiplication(&next.Proof.S, &prev.G1[1], &b1, &nb2)
+		t2.JointScalarMultiplication(&next.G1[1], &next.Proof.SX, &b2, &nb1)
+		a1.FromJacobian(&t1)
+		a2.FromJacobian(&t2)
+		P = append(P, a1, a2)
+		Q = append(Q, next.Proof.RX, R)
+	}
+
+	// e(∑ᵢρⁱ[τⁱ⁺¹]G₁, G₂) = e(∑ᵢρⁱ[τⁱ]G₁, [τ]G₂) : the last contribution is made of powers of τ
+	// the ρⁱ are derived from the last λ to save some randomness
+	n := len(last.G1) - 1
+	rho := make([]fr.Element, n)
+	rho[0].SetOne()
+	for i := 1; i < n; i++ {
+		rho[i].Mul(&rho[i-1], &lambda[len(lambda)-1])
+	}
+	var a, b bls24317.G1Affine
+	config := ecc.MultiExpConfig{}
+	if _, err := a.MultiExp(last.G1[:n], rho, config); err != nil {
+		return err
+	}
+	if _, err := b.MultiExp(last.G1[1:], rho, config); err != nil {
+		return err
+	}
+	a.Neg(&a)
+	P = append(P, b, a)
+	Q = append(Q, last.G2[0], last.G2[1])
+
+	ok, err := bls24317.PairingCheck(P, Q)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidContribution
+	}
+	return nil
+}
+
+// hashToG2 returns R = HashToG2(c, S, SX), where c is the contribution being updated
+// with the proof of knowledge pok.
+func (c *Contribution) hashToG2(pok *ProofOfKnowledge) (bls24317.G2Affine, error) {
+	h := sha256.New()
+	h.Write(c.G1[1].Marshal())
+	h.Write(c.G2[1].Marshal())
+	h.Write(c.Proof.S.Marshal())
+	h.Write(c.Proof.SX.Marshal())
+	h.Write(c.Proof.RX.Marshal())
+	h.Write(pok.S.Marshal())
+	h.Write(pok.SX.Marshal())
+	return bls24317.HashToG2(h.Sum(nil), []byte(DST))
+}
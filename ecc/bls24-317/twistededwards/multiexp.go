@@ -0,0 +1,108 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// MultiExp implements section 4 of https://eprint.iacr.org/2012/549.pdf
+//
+// Each c-bit window of the scalars is processed independently with the bucket
+// method, and the window sums are then combined with c doublings each. The
+// scalars must be non-negative; they are typically reduced modulo the order of
+// the curve.
+func (p *PointExtended) MultiExp(points []PointAffine, scalars []big.Int, config ecc.MultiExpConfig) (*PointExtended, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("len(points) != len(scalars)")
+	}
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU()
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+	nbBits := 0
+	for i := range scalars {
+		if scalars[i].Sign() < 0 {
+			return nil, errors.New("negative scalar")
+		}
+		if l := scalars[i].BitLen(); l > nbBits {
+			nbBits = l
+		}
+	}
+	if nbBits == 0 {
+		return p.setInfinity(), nil
+	}
+
+	c := bits.Len(uint(len(points)))/2 + 1
+	nbWindows := (nbBits + c - 1) / c
+
+	bases := make([]PointExtended, len(points))
+	for i := range points {
+		bases[i].FromAffine(&points[i])
+	}
+
+	windows := make([]PointExtended, nbWindows)
+	work := func(start, end int) {
+		buckets := make([]PointExtended, (1<<c)-1)
+		for w := start; w < end; w++ {
+			for i := range buckets {
+				buckets[i].setInfinity()
+			}
+			for i := range scalars {
+				if d := window(&scalars[i], w*c, c); d != 0 {
+					buckets[d-1].Add(&buckets[d-1], &bases[i])
+				}
+			}
+			// ∑ⱼ (j+1)⋅bucketⱼ with a running sum
+			var runningSum, sum PointExtended
+			runningSum.setInfinity()
+			sum.setInfinity()
+			for i := len(buckets) - 1; i >= 0; i-- {
+				runningSum.Add(&runningSum, &buckets[i])
+				sum.Add(&sum, &runningSum)
+			}
+			windows[w] = sum
+		}
+	}
+	parallel.Execute(nbWindows, work, config.NbTasks)
+
+	var res PointExtended
+	res.Set(&windows[nbWindows-1])
+	for w := nbWindows - 2; w >= 0; w-- {
+		for i := 0; i < c; i++ {
+			res.Double(&res)
+		}
+		res.Add(&res, &windows[w])
+	}
+	return p.Set(&res), nil
+}
+
+// window returns the c bits of s starting at bit index offset.
+func window(s *big.Int, offset, c int) uint {
+	var d uint
+	for i := c - 1; i >= 0; i-- {
+		d = d<<1 | s.Bit(offset+i)
+	}
+	return d
+}
@@ -0,0 +1,161 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+var errInvalidBatch = errors.New("number of public keys, signatures and messages must match")
+
+const sizeRecoverableSignature = sizeSignature + 1
+
+// SignRecoverable performs the ECDSA signature as Sign does, and appends the
+// public key recovery information v to it. The resulting r||s||v signatures
+// can be verified at once with BatchVerify.
+func (privKey *PrivateKey) SignRecoverable(message []byte, hFunc hash.Hash) ([]byte, error) {
+	v, r, s, err := privKey.SignForRecover(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+	var res [sizeRecoverableSignature]byte
+	r.FillBytes(res[:sizeFr])
+	s.FillBytes(res[sizeFr:sizeSignature])
+	res[sizeSignature] = byte(v)
+	return res[:], nil
+}
+
+// BatchVerify verifies the r||s||v signatures (as returned by SignRecoverable)
+// of messages under publicKeys at once.
+//
+// For each signature, the recovery information v is used to lift the nonce
+// commitment Rᵢ from rᵢ, so that the verification equation becomes an equality
+// of points
+//
+//	Rᵢ = [u1ᵢ]Base + [u2ᵢ]Pᵢ, with u1ᵢ = mᵢ⋅sᵢ⁻¹ and u2ᵢ = rᵢ⋅sᵢ⁻¹.
+//
+// With a₁ = 1 and aᵢ random 128-bit scalars, the batch checks that
+//
+//	∑[aᵢ]Rᵢ - ∑[aᵢ⋅u2ᵢ]Pᵢ - [∑aᵢ⋅u1ᵢ]Base = 0
+//
+// with a single multi-exponentiation. If it does not hold, the signatures are
+// verified one by one to locate the first invalid one, whose index is
+// returned. The index is -1 when the batch is valid.
+//
+// Note that a signature is rejected if its recovery information is wrong, even
+// when (r, s) is a valid ECDSA signature.
+func BatchVerify(publicKeys []PublicKey, signatures, messages [][]byte, hFunc hash.Hash) (bool, int, error) {
+	if len(publicKeys) != len(signatures) || len(publicKeys) != len(messages) {
+		return false, -1, errInvalidBatch
+	}
+	n := len(publicKeys)
+	if n == 0 {
+		return true, -1, nil
+	}
+
+	points := make([]bn254.G1Affine, 2*n+1)
+	scalars := make([]fr.Element, 2*n+1)
+	u1 := make([]fr.Element, n)
+	u2 := make([]fr.Element, n)
+	var sumAU, a, r, s, m, t fr.Element
+	var buf [16]byte
+	for i := 0; i < n; i++ {
+		if len(signatures[i]) != sizeRecoverableSignature {
+			return false, i, errWrongSize
+		}
+		var sig Signature
+		if _, err := sig.SetBytes(signatures[i][:sizeSignature]); err != nil {
+			return false, i, err
+		}
+		recID := uint(signatures[i][sizeSignature])
+		bigR := new(big.Int).SetBytes(sig.R[:sizeFr])
+		if recID > 3 || (recID&2 != 0 && new(big.Int).Add(bigR, order).Cmp(fp.Modulus()) >= 0) {
+			// the recovery information does not match any x-coordinate
+			return false, i, nil
+		}
+		R, err := recoverP(recID, bigR)
+		if err != nil {
+			// Rᵢ can't be lifted, the signature is invalid
+			return false, i, nil
+		}
+
+		var hashed []byte
+		if hFunc != nil {
+			hFunc.Reset()
+			if _, err := hFunc.Write(messages[i]); err != nil {
+				return false, i, err
+			}
+			hashed = hFunc.Sum(nil)
+		} else {
+			hashed = messages[i]
+		}
+		m.SetBigInt(HashToInt(hashed))
+		r.SetBytes(sig.R[:sizeFr])
+		s.SetBytes(sig.S[:sizeFr])
+		s.Inverse(&s)
+		u1[i].Mul(&m, &s)
+		u2[i].Mul(&r, &s)
+
+		if i == 0 {
+			a.SetOne()
+		} else {
+			if _, err := rand.Read(buf[:]); err != nil {
+				return false, -1, err
+			}
+			a.SetBytes(buf[:])
+		}
+
+		points[2*i] = *R
+		scalars[2*i] = a
+		points[2*i+1] = publicKeys[i].A
+		scalars[2*i+1].Mul(&a, &u2[i]).Neg(&scalars[2*i+1])
+		t.Mul(&a, &u1[i])
+		sumAU.Add(&sumAU, &t)
+	}
+	_, _, points[2*n], _ = bn254.Generators()
+	scalars[2*n].Neg(&sumAU)
+
+	var res bn254.G1Jac
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false, -1, err
+	}
+	if res.Z.IsZero() {
+		return true, -1, nil
+	}
+
+	// locate the first invalid signature
+	var U bn254.G1Jac
+	var UAff bn254.G1Affine
+	var bigU1, bigU2 big.Int
+	for i := 0; i < n; i++ {
+		U.JointScalarMultiplicationBase(&publicKeys[i].A, u1[i].BigInt(&bigU1), u2[i].BigInt(&bigU2))
+		UAff.FromJacobian(&U)
+		if !UAff.Equal(&points[2*i]) {
+			return false, i, nil
+		}
+	}
+	// unreachable: if all the equations hold, so does their linear combination
+	return true, -1, nil
+}
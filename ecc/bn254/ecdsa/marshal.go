@@ -58,7 +58,7 @@ func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
 	if _, err := pk.A.SetBytes(buf[:sizePublicKey]); err != nil {
 		return 0, err
 	}
-	n += sizeFp
+	n += sizePublicKey
 	return n, nil
 }
 
@@ -190,6 +190,45 @@ func TestFRI(t *testing.T) {
 		gen.Int32Range(0, int32(rho*size)),
 	))
 
+	properties.Property("Derive queries position with arity 4: points should belong the correct fiber", prop.ForAll(
+
+		func(m int32) bool {
+
+			_s := RADIX_2_FRI.New(uint64(size), sha256.New(), WithFoldingArity(4))
+			s := _s.(radixTwoFri)
+
+			var g fr.Element
+
+			_m := int(m) % size
+			pos := s.deriveQueriesPositions(_m, int(s.domain.Cardinality))
+			g.Set(&s.domain.Generator)
+			n := int(s.domain.Cardinality)
+
+			for i := 0; i < len(pos)-1; i++ {
+
+				// the canonical positions of the query and of the next one,
+				// which must be the image of the fiber of the query
+				arity := s.arities[i]
+				u := (pos[i]%arity)*(n/arity) + pos[i]/arity
+				nextArity := s.arities[i+1]
+				v := (pos[i+1]%nextArity)*(n/arity/nextArity) + pos[i+1]/nextArity
+
+				var g1, g2 fr.Element
+				bArity := big.NewInt(int64(arity))
+				g1.Exp(g, big.NewInt(int64(u))).Exp(g1, bArity)
+				g.Exp(g, bArity)
+				g2.Exp(g, big.NewInt(int64(v)))
+
+				if !g1.Equal(&g2) {
+					return false
+				}
+				n /= arity
+			}
+			return true
+		},
+		gen.Int32Range(0, int32(rho*size)),
+	))
+
 	properties.Property("verifying a correctly formed proof should succeed", prop.ForAll(
 
 		func(s int32) bool {
@@ -212,6 +251,116 @@ func TestFRI(t *testing.T) {
 
 }
 
+func TestFRIOptions(t *testing.T) {
+
+	const size = 1000
+	p := randomPolynomial(size, 42)
+
+	for _, opts := range [][]Option{
+		{WithFoldingArity(4)},
+		{WithFoldingArity(8), WithBlowup(2)},
+		{WithFoldingArity(16), WithNbQueries(20)},
+		{WithFoldingArity(8), WithFinalDegree(3), WithNbQueries(5)},
+		{WithBlowup(16), WithFinalDegree(100), WithNbQueries(3)},
+		{WithFinalDegree(size), WithNbQueries(3)},
+		{WithGrindingBits(8), WithNbQueries(2)},
+	} {
+		iop := RADIX_2_FRI.New(size, sha256.New(), opts...)
+		proof, err := iop.BuildProofOfProximity(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(proof.Rounds) != 0 {
+			t.Fatal("a proof with options should be made of steps")
+		}
+		if err := iop.VerifyProofOfProximity(proof); err != nil {
+			t.Fatal(err)
+		}
+
+		// the openings must match the first commitment
+		openingProof, err := iop.Open(p, 17)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := iop.VerifyOpening(17, openingProof, proof); err != nil {
+			t.Fatal(err)
+		}
+
+		// tampered proofs
+		tampered := proof
+		tampered.FinalPolynomial = append([]fr.Element{}, proof.FinalPolynomial...)
+		tampered.FinalPolynomial[0].SetOne()
+		if iop.VerifyProofOfProximity(tampered) == nil {
+			t.Fatal("tampered final polynomial should be rejected")
+		}
+		tampered = proof
+		tampered.Steps = append([]Step{}, proof.Steps...)
+		tampered.Steps[0].Openings.Leaves = append([][]byte{}, proof.Steps[0].Openings.Leaves...)
+		one := fr.One()
+		tampered.Steps[0].Openings.Leaves[0] = one.Marshal()
+		if iop.VerifyProofOfProximity(tampered) == nil {
+			t.Fatal("tampered opening should be rejected")
+		}
+		tampered = proof
+		tampered.Nonce++
+		if iop.VerifyProofOfProximity(tampered) == nil {
+			t.Fatal("tampered nonce should be rejected")
+		}
+	}
+
+	// a polynomial of too high degree is rejected with high probability
+	iop := RADIX_2_FRI.New(size/4, sha256.New(), WithFoldingArity(4), WithNbQueries(20))
+	proof, err := iop.BuildProofOfProximity(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if iop.VerifyProofOfProximity(proof) == nil {
+		t.Fatal("proof for a polynomial of too high degree should be rejected")
+	}
+
+	// the default parameters build proofs made of rounds, which are not
+	// accepted by an Iopp with other parameters
+	iop = RADIX_2_FRI.New(size, sha256.New(), WithFoldingArity(2), WithBlowup(rho))
+	proof, err = iop.BuildProofOfProximity(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proof.Rounds) != nbRounds || len(proof.Steps) != 0 {
+		t.Fatal("a proof with the default options should be made of rounds")
+	}
+	if RADIX_2_FRI.New(size, sha256.New(), WithNbQueries(2)).VerifyProofOfProximity(proof) == nil {
+		t.Fatal("proof made of rounds should be rejected with options")
+	}
+
+	// invalid options
+	for _, opt := range []Option{WithBlowup(3), WithFoldingArity(3), WithNbQueries(0), WithFinalDegree(-1), WithGrindingBits(64)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("invalid option should panic")
+				}
+			}()
+			RADIX_2_FRI.New(size, sha256.New(), opt)
+		}()
+	}
+}
+
+func TestFRISecurity(t *testing.T) {
+
+	conjectured, proven := RADIX_2_FRI.Security(1<<10, sha256.New(), WithNbQueries(40))
+	if conjectured != 120 {
+		t.Fatalf("conjectured security: expected 120 bits, got %f", conjectured)
+	}
+	if proven >= conjectured || proven < 30 {
+		t.Fatalf("proven security: unexpected %f bits", proven)
+	}
+
+	conjectured, _ = RADIX_2_FRI.Security(1<<10, sha256.New(), WithNbQueries(40), WithGrindingBits(16))
+	if conjectured != 128 {
+		t.Fatalf("conjectured security is bounded by the hash: expected 128 bits, got %f", conjectured)
+	}
+}
+
 // Benchmarks
 
 func BenchmarkProximityVerification(b *testing.B) {
@@ -0,0 +1,56 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package merkle
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon"
+)
+
+// Hasher computes a node from its children. It must be safe for concurrent
+// use.
+type Hasher func(children []fr.Element) fr.Element
+
+// Poseidon is the Hasher computing the Poseidon hash of the children, as
+// poseidon.Hash and circomlib do: the permutation of width arity+1 is applied
+// to (0, children...) and the first element of the state is returned.
+func Poseidon(children []fr.Element) fr.Element {
+	res, err := poseidon.Hash(children...)
+	if err != nil {
+		// the arity is checked when building or verifying
+		panic(err)
+	}
+	return res
+}
+
+// MiMC is the Hasher computing the MiMC hash of the children, written in
+// order to the hash (as the MiMC gadget of gnark does).
+func MiMC(children []fr.Element) fr.Element {
+	h := mimc.NewMiMC()
+	for i := range children {
+		b := children[i].Bytes()
+		// Write only fails on non-canonical encodings, and fr.Element.Bytes is
+		// always canonical
+		if _, err := h.Write(b[:]); err != nil {
+			panic(err)
+		}
+	}
+	var res fr.Element
+	res.SetBytes(h.Sum(nil))
+	return res
+}
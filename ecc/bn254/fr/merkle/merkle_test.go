@@ -0,0 +1,226 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/utils"
+)
+
+func randomLeaves(n int) fr.Vector {
+	leaves := make(fr.Vector, n)
+	for i := range leaves {
+		leaves[i].SetRandom()
+	}
+	return leaves
+}
+
+// naiveRoot computes the root of the tree recursively
+func naiveRoot(leaves fr.Vector, arity int, h Hasher) fr.Element {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	size := len(leaves) / arity
+	children := make(fr.Vector, arity)
+	for i := range children {
+		children[i] = naiveRoot(leaves[i*size:(i+1)*size], arity, h)
+	}
+	return h(children)
+}
+
+func TestTree(t *testing.T) {
+	workers := utils.NewWorkerPool()
+	defer workers.Stop()
+
+	for _, arity := range []int{2, 4, 8, 16} {
+		for _, n := range []int{1, arity - 1, arity, arity + 1, 1000} {
+			leaves := randomLeaves(n)
+			tree, err := New(leaves, WithArity(arity), WithWorkers(workers))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			padded := make(fr.Vector, 1)
+			for len(padded) < n || len(padded) == 1 {
+				padded = make(fr.Vector, len(padded)*arity)
+			}
+			copy(padded, leaves)
+			expected := naiveRoot(padded, arity, Poseidon)
+			root := tree.Root()
+			if !root.Equal(&expected) {
+				t.Fatalf("arity %d, %d leaves: wrong root", arity, n)
+			}
+
+			for i := uint64(0); i < uint64(n); i += 1 + uint64(n)/16 {
+				proof, err := tree.Open(i)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(proof.Siblings) != tree.Depth() {
+					t.Fatal("wrong proof length")
+				}
+				if err := proof.Verify(root, arity, tree.Depth(), Poseidon); err != nil {
+					t.Fatalf("arity %d, %d leaves, index %d: %s", arity, n, i, err)
+				}
+				proof.Leaf.SetOne()
+				if err := proof.Verify(root, arity, tree.Depth(), Poseidon); err == nil {
+					t.Fatal("wrong leaf accepted")
+				}
+			}
+			if _, err := tree.Open(uint64(n)); err == nil {
+				t.Fatal("out of range index should be rejected")
+			}
+		}
+	}
+
+	if _, err := New(randomLeaves(2), WithArity(3)); err != ErrArity {
+		t.Fatal("arity 3 should be rejected")
+	}
+	if _, err := New(nil); err != ErrNoLeaves {
+		t.Fatal("empty tree should be rejected")
+	}
+}
+
+func TestBatchOpen(t *testing.T) {
+	for _, arity := range []int{2, 4, 8, 16} {
+		const n = 300
+		tree, err := New(randomLeaves(n), WithArity(arity))
+		if err != nil {
+			t.Fatal(err)
+		}
+		root := tree.Root()
+		for _, indices := range [][]uint64{
+			{0},
+			{n - 1},
+			{n - 1, 0, 1, 2, 3, 0},
+			{5, 17, 100, 101, 250},
+		} {
+			proof, err := tree.BatchOpen(indices)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := proof.Verify(root, arity, tree.Depth(), Poseidon); err != nil {
+				t.Fatalf("arity %d, %v: %s", arity, indices, err)
+			}
+
+			// the siblings are shared, there can't be more than in separate proofs
+			nbSiblings := len(proof.Indices) * tree.Depth() * (arity - 1)
+			if len(proof.Siblings) > nbSiblings {
+				t.Fatalf("arity %d, %v: %d siblings, more than %d", arity, indices, len(proof.Siblings), nbSiblings)
+			}
+
+			tampered := proof
+			tampered.Leaves = append(fr.Vector{fr.One()}, proof.Leaves[1:]...)
+			if err := tampered.Verify(root, arity, tree.Depth(), Poseidon); err == nil {
+				t.Fatal("wrong leaf accepted")
+			}
+			tampered = proof
+			tampered.Siblings = proof.Siblings[1:]
+			if err := tampered.Verify(root, arity, tree.Depth(), Poseidon); err == nil {
+				t.Fatal("missing sibling accepted")
+			}
+		}
+		if _, err := tree.BatchOpen([]uint64{0, n}); err == nil {
+			t.Fatal("out of range index should be rejected")
+		}
+	}
+}
+
+func TestShape(t *testing.T) {
+	tree, err := New(randomLeaves(64), WithArity(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := tree.Root()
+
+	// a node of height 1 opened as a leaf by a shorter proof
+	proof, err := tree.Open(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	short := Proof{Index: 1, Leaf: tree.levels[1][1], Siblings: proof.Siblings[1:]}
+	if err := short.Verify(root, 4, tree.Depth()-1, Poseidon); err != nil {
+		t.Fatal("the forged proof should be consistent with a smaller tree")
+	}
+	if err := short.Verify(root, 4, tree.Depth(), Poseidon); err == nil {
+		t.Fatal("node opened as a leaf")
+	}
+	if err := proof.Verify(root, 2, tree.Depth(), Poseidon); err == nil {
+		t.Fatal("wrong arity accepted")
+	}
+
+	batch, err := tree.BatchOpen([]uint64{4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, shape := range []struct{ arity, depth int }{
+		{4, tree.Depth() - 1},
+		{2, tree.Depth()},
+		{4, 1 << 40},
+	} {
+		if err := batch.Verify(root, shape.arity, shape.depth, Poseidon); err == nil {
+			t.Fatalf("arity %d, depth %d: wrong shape accepted", shape.arity, shape.depth)
+		}
+	}
+	tampered := batch
+	tampered.Depth = 1 << 40
+	if err := tampered.Verify(root, 4, tree.Depth(), Poseidon); err == nil {
+		t.Fatal("proof of another depth accepted")
+	}
+	for _, arity := range []int{2, 4, 8, 16} {
+		if validShape(arity, 64/bits.TrailingZeros(uint(arity))+1) {
+			t.Fatalf("arity %d: too deep tree accepted", arity)
+		}
+	}
+}
+
+func TestMiMC(t *testing.T) {
+	leaves := randomLeaves(100)
+	tree, err := New(leaves, WithArity(4), WithHasher(MiMC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := tree.Open(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proof.Verify(tree.Root(), 4, tree.Depth(), MiMC); err != nil {
+		t.Fatal(err)
+	}
+	if err := proof.Verify(tree.Root(), 4, tree.Depth(), Poseidon); err == nil {
+		t.Fatal("proof should not verify with another hash")
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	leaves := randomLeaves(1 << 12)
+	workers := utils.NewWorkerPool()
+	defer workers.Stop()
+	for _, arity := range []int{2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("arity=%d", arity), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := New(leaves, WithArity(arity), WithWorkers(workers)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
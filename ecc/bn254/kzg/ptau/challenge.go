@@ -0,0 +1,104 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ptau
+
+import (
+	"fmt"
+	"io"
+
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+)
+
+// challengeHashSize is the size of the BLAKE2b hash of the previous response
+// which starts a challenge file.
+const challengeHashSize = 64
+
+// flags of the most significant byte of an uncompressed point
+const (
+	flagCompressed byte = 1 << 7
+	flagInfinity   byte = 1 << 6
+)
+
+// ReadChallenge reads a challenge file of the perpetual powers-of-tau ceremony.
+//
+// The file does not record its size, so the power p of the ceremony must be
+// provided (28 for the main ceremony). Points are uncompressed, with big-endian
+// coordinates; G₂ coordinates are ordered as x.A1 ‖ x.A0 ‖ y.A1 ‖ y.A0.
+func ReadChallenge(r io.Reader, power int, opts ...Option) (*kzg.SRS, error) {
+	if power < 1 || power > 30 {
+		return nil, ErrInvalidSize
+	}
+	cfg, err := newConfig(power, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = io.CopyN(io.Discard, r, challengeHashSize); err != nil {
+		return nil, err
+	}
+
+	g1, err := readG1(r, cfg.size, decodeChallengeG1)
+	if err != nil {
+		return nil, fmt.Errorf("tauG1: %w", err)
+	}
+	remaining := int64(2<<power-1-cfg.size) * 2 * fp.Bytes
+	if _, err = io.CopyN(io.Discard, r, remaining); err != nil {
+		return nil, err
+	}
+	g2, err := readG2(r, 2, decodeChallengeG2)
+	if err != nil {
+		return nil, fmt.Errorf("tauG2: %w", err)
+	}
+
+	return newSRS(g1, g2, cfg)
+}
+
+// decodeChallengeElements decodes len(res) big-endian coordinates of an
+// uncompressed point. The flags are carried by the first coordinate.
+func decodeChallengeElements(b []byte, res ...*fp.Element) error {
+	if b[0]&flagCompressed != 0 {
+		return ErrInvalidPoint
+	}
+	if b[0]&flagInfinity != 0 {
+		if b[0] != flagInfinity {
+			return ErrInvalidPoint
+		}
+		for _, v := range b[1:] {
+			if v != 0 {
+				return ErrInvalidPoint
+			}
+		}
+		for _, e := range res {
+			e.SetZero()
+		}
+		return nil
+	}
+	for i, e := range res {
+		if err := e.SetBytesCanonical(b[i*fp.Bytes : (i+1)*fp.Bytes]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeChallengeG1(p *bn254.G1Affine, b []byte) error {
+	return decodeChallengeElements(b, &p.X, &p.Y)
+}
+
+func decodeChallengeG2(p *bn254.G2Affine, b []byte) error {
+	return decodeChallengeElements(b, &p.X.A1, &p.X.A0, &p.Y.A1, &p.Y.A0)
+}
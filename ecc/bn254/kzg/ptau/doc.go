@@ -0,0 +1,34 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ptau loads the output of a powers-of-tau ceremony on bn254 into a kzg.SRS.
+//
+// Two formats are supported:
+//   - the snarkjs .ptau format ([ReadPtau]), in which the Hermez and
+//     perpetual powers-of-tau transcripts are commonly distributed,
+//   - the challenge file of the perpetual powers-of-tau ceremony
+//     ([ReadChallenge]).
+//
+// Both formats contain 2ᵖ⁺¹-1 powers of τ in G₁ and 2ᵖ powers of τ in G₂,
+// followed by data that only matters to the Groth16 phase 2 (powers of α and
+// β), which is skipped. Only the first two G₂ powers are part of the kzg
+// verifying key.
+//
+// Every point is checked to be on the curve and in the prime order subgroup.
+// The relations between the points can be checked with [WithConsistencyCheck].
+//
+// Documentation:
+//   - perpetual powers of tau: https://github.com/privacy-scaling-explorations/perpetualpowersoftau
+//   - snarkjs: https://github.com/iden3/snarkjs
+package ptau
@@ -0,0 +1,289 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ptau
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidMagic   = errors.New("invalid ptau magic number")
+	ErrInvalidHeader  = errors.New("invalid ptau header")
+	ErrMissingSection = errors.New("missing ptau section")
+	ErrInvalidSize    = errors.New("invalid SRS size")
+	ErrInvalidPoint   = errors.New("invalid point encoding")
+)
+
+// ptau section types, see snarkjs/src/powersoftau_new.js
+const (
+	sectionHeader = 1
+	sectionTauG1  = 2
+	sectionTauG2  = 3
+)
+
+type config struct {
+	size             int
+	consistencyCheck bool
+}
+
+// Option allows to configure how a transcript is read.
+type Option func(*config)
+
+// WithSize keeps only the first size powers of τ in G₁. By default all the
+// 2ᵖ⁺¹-1 powers of the transcript are kept.
+func WithSize(size int) Option {
+	return func(c *config) {
+		c.size = size
+	}
+}
+
+// WithConsistencyCheck checks that the resulting SRS is made of successive
+// powers of a same τ, see [kzg.SRS.CheckConsistency].
+func WithConsistencyCheck() Option {
+	return func(c *config) {
+		c.consistencyCheck = true
+	}
+}
+
+// ReadPtau reads a transcript in the snarkjs .ptau format.
+//
+// The coordinates in this format are stored in little-endian Montgomery form.
+// Sections are read sequentially; the header section must come first, as
+// written by snarkjs.
+func ReadPtau(r io.Reader, opts ...Option) (*kzg.SRS, error) {
+	var buf [12]byte
+	if _, err := io.ReadFull(r, buf[:12]); err != nil {
+		return nil, err
+	}
+	if string(buf[:4]) != "ptau" {
+		return nil, ErrInvalidMagic
+	}
+	nbSections := binary.LittleEndian.Uint32(buf[8:12])
+
+	var (
+		power   = -1
+		g1      []bn254.G1Affine
+		g2      []bn254.G2Affine
+		cfg     config
+		decoded int
+	)
+	for i := uint32(0); i < nbSections; i++ {
+		if _, err := io.ReadFull(r, buf[:12]); err != nil {
+			return nil, err
+		}
+		sectionType := binary.LittleEndian.Uint32(buf[:4])
+		sectionSize := int64(binary.LittleEndian.Uint64(buf[4:12]))
+		section := io.LimitReader(r, sectionSize)
+
+		var err error
+		switch sectionType {
+		case sectionHeader:
+			if power, err = readPtauHeader(section); err != nil {
+				return nil, err
+			}
+			if cfg, err = newConfig(power, opts); err != nil {
+				return nil, err
+			}
+		case sectionTauG1:
+			if power < 0 {
+				return nil, ErrInvalidHeader
+			}
+			if sectionSize != int64(2<<power-1)*2*fp.Bytes {
+				return nil, ErrInvalidHeader
+			}
+			if g1, err = readG1(section, cfg.size, decodePtauG1); err != nil {
+				return nil, fmt.Errorf("tauG1: %w", err)
+			}
+			decoded++
+		case sectionTauG2:
+			if power < 0 {
+				return nil, ErrInvalidHeader
+			}
+			if sectionSize != int64(1<<power)*4*fp.Bytes {
+				return nil, ErrInvalidHeader
+			}
+			if g2, err = readG2(section, 2, decodePtauG2); err != nil {
+				return nil, fmt.Errorf("tauG2: %w", err)
+			}
+			decoded++
+		}
+
+		// skip the remainder of the section
+		if _, err = io.Copy(io.Discard, section); err != nil {
+			return nil, err
+		}
+	}
+	if decoded != 2 {
+		return nil, ErrMissingSection
+	}
+
+	return newSRS(g1, g2, cfg)
+}
+
+// readPtauHeader reads the header section and returns the power of the transcript.
+func readPtauHeader(r io.Reader) (int, error) {
+	var buf [4 + fp.Bytes + 8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	if binary.LittleEndian.Uint32(buf[:4]) != fp.Bytes {
+		return 0, ErrInvalidHeader
+	}
+	var q [fp.Bytes]byte
+	for i := range q {
+		q[i] = buf[4+fp.Bytes-1-i]
+	}
+	if new(big.Int).SetBytes(q[:]).Cmp(fp.Modulus()) != 0 {
+		return 0, fmt.Errorf("%w: not a bn254 transcript", ErrInvalidHeader)
+	}
+	power := binary.LittleEndian.Uint32(buf[4+fp.Bytes:])
+	if power == 0 || power > 30 {
+		return 0, ErrInvalidHeader
+	}
+	return int(power), nil
+}
+
+// rInv is the inverse of the Montgomery constant 2²⁵⁶ mod q.
+var rInv = func() fp.Element {
+	var r fp.Element
+	r.SetBigInt(new(big.Int).Lsh(big.NewInt(1), 256))
+	return *r.Inverse(&r)
+}()
+
+// decodePtauElement decodes a coordinate stored in little-endian Montgomery form.
+func decodePtauElement(b []byte) (fp.Element, error) {
+	e, err := fp.LittleEndian.Element((*[fp.Bytes]byte)(b))
+	if err != nil {
+		return e, err
+	}
+	return *e.Mul(&e, &rInv), nil
+}
+
+func decodePtauG1(p *bn254.G1Affine, b []byte) (err error) {
+	if p.X, err = decodePtauElement(b[:fp.Bytes]); err != nil {
+		return
+	}
+	p.Y, err = decodePtauElement(b[fp.Bytes:])
+	return
+}
+
+func decodePtauG2(p *bn254.G2Affine, b []byte) (err error) {
+	if p.X.A0, err = decodePtauElement(b[:fp.Bytes]); err != nil {
+		return
+	}
+	if p.X.A1, err = decodePtauElement(b[fp.Bytes : 2*fp.Bytes]); err != nil {
+		return
+	}
+	if p.Y.A0, err = decodePtauElement(b[2*fp.Bytes : 3*fp.Bytes]); err != nil {
+		return
+	}
+	p.Y.A1, err = decodePtauElement(b[3*fp.Bytes:])
+	return
+}
+
+func newConfig(power int, opts []Option) (config, error) {
+	cfg := config{size: 2<<power - 1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.size < 2 || cfg.size > 2<<power-1 {
+		return cfg, ErrInvalidSize
+	}
+	return cfg, nil
+}
+
+// readG1 reads n uncompressed G1 points, decodes them in parallel and checks
+// that they are on the curve.
+func readG1(r io.Reader, n int, decode func(*bn254.G1Affine, []byte) error) ([]bn254.G1Affine, error) {
+	const size = 2 * fp.Bytes
+	buf := make([]byte, n*size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	res := make([]bn254.G1Affine, n)
+	err := decodeAll(n, func(i int) error {
+		if err := decode(&res[i], buf[i*size:(i+1)*size]); err != nil {
+			return err
+		}
+		if !res[i].IsOnCurve() {
+			return errors.New("point is not on the curve")
+		}
+		return nil
+	})
+	return res, err
+}
+
+// readG2 reads n uncompressed G2 points, decodes them in parallel and checks
+// that they are on the curve and in the prime order subgroup.
+func readG2(r io.Reader, n int, decode func(*bn254.G2Affine, []byte) error) ([]bn254.G2Affine, error) {
+	const size = 4 * fp.Bytes
+	buf := make([]byte, n*size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	res := make([]bn254.G2Affine, n)
+	err := decodeAll(n, func(i int) error {
+		if err := decode(&res[i], buf[i*size:(i+1)*size]); err != nil {
+			return err
+		}
+		if !res[i].IsOnCurve() || !res[i].IsInSubGroup() {
+			return errors.New("point is not in the prime order subgroup")
+		}
+		return nil
+	})
+	return res, err
+}
+
+// decodeAll runs decode on [0, n) in parallel and returns the first error, if any.
+func decodeAll(n int, decode func(i int) error) error {
+	errs := make([]error, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			errs[i] = decode(i)
+		}
+	})
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("point %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func newSRS(g1 []bn254.G1Affine, g2 []bn254.G2Affine, cfg config) (*kzg.SRS, error) {
+	var srs kzg.SRS
+	srs.Pk.G1 = g1
+	srs.Vk.G1 = g1[0]
+	srs.Vk.G2[0] = g2[0]
+	srs.Vk.G2[1] = g2[1]
+	srs.Vk.Lines[0] = bn254.PrecomputeLines(srs.Vk.G2[0])
+	srs.Vk.Lines[1] = bn254.PrecomputeLines(srs.Vk.G2[1])
+
+	if cfg.consistencyCheck {
+		if err := srs.CheckConsistency(); err != nil {
+			return nil, err
+		}
+	}
+	return &srs, nil
+}
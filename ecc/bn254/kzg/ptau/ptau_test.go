@@ -0,0 +1,203 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ptau
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+)
+
+// The fixtures are transcripts of power 3 computed with the toy secret
+// τ = 987654321 (and α = 2, β = 3 for the skipped sections).
+const (
+	testPtau      = "testdata/powersOfTau_03.ptau"
+	testChallenge = "testdata/challenge_03"
+	testPower     = 3
+	testTau       = 987654321
+
+	// offsets of the first tauG1 point in the fixtures
+	ptauTauG1Offset      = 12 + 12 + 44 + 12
+	challengeTauG1Offset = challengeHashSize
+)
+
+func readFile(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func checkSRS(t *testing.T, srs *kzg.SRS, size int) {
+	t.Helper()
+	expected, err := kzg.NewSRS(uint64(size), big.NewInt(testTau))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(srs.Pk.G1) != size {
+		t.Fatalf("expected %d G1 points, got %d", size, len(srs.Pk.G1))
+	}
+	for i := range expected.Pk.G1 {
+		if !srs.Pk.G1[i].Equal(&expected.Pk.G1[i]) {
+			t.Fatalf("G1 point %d differs", i)
+		}
+	}
+	if !srs.Vk.G1.Equal(&expected.Vk.G1) ||
+		!srs.Vk.G2[0].Equal(&expected.Vk.G2[0]) ||
+		!srs.Vk.G2[1].Equal(&expected.Vk.G2[1]) {
+		t.Fatal("verifying key differs")
+	}
+	if srs.Vk.Lines != expected.Vk.Lines {
+		t.Fatal("precomputed lines differ")
+	}
+}
+
+func TestReadPtau(t *testing.T) {
+	b := readFile(t, testPtau)
+
+	srs, err := ReadPtau(bytes.NewReader(b), WithConsistencyCheck())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSRS(t, srs, 2<<testPower-1)
+
+	srs, err = ReadPtau(bytes.NewReader(b), WithSize(1<<testPower))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSRS(t, srs, 1<<testPower)
+}
+
+func TestReadChallenge(t *testing.T) {
+	b := readFile(t, testChallenge)
+
+	srs, err := ReadChallenge(bytes.NewReader(b), testPower, WithConsistencyCheck())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSRS(t, srs, 2<<testPower-1)
+
+	srs, err = ReadChallenge(bytes.NewReader(b), testPower, WithSize(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSRS(t, srs, 5)
+}
+
+func TestCommitOpenVerify(t *testing.T) {
+	srs, err := ReadPtau(bytes.NewReader(readFile(t, testPtau)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := make([]fr.Element, len(srs.Pk.G1))
+	for i := range p {
+		p[i].SetRandom()
+	}
+	digest, err := kzg.Commit(p, srs.Pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var point fr.Element
+	point.SetRandom()
+	proof, err := kzg.Open(p, point, srs.Pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = kzg.Verify(&digest, &proof, point, srs.Vk); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadPtauInvalid(t *testing.T) {
+	ptau := readFile(t, testPtau)
+	corrupt := func(f func(b []byte)) []byte {
+		b := append([]byte{}, ptau...)
+		f(b)
+		return b
+	}
+
+	if _, err := ReadPtau(bytes.NewReader(corrupt(func(b []byte) { b[0] = 'q' }))); !errors.Is(err, ErrInvalidMagic) {
+		t.Fatal("expected ErrInvalidMagic, got", err)
+	}
+
+	// modulus of the header
+	if _, err := ReadPtau(bytes.NewReader(corrupt(func(b []byte) { b[28] ^= 1 }))); !errors.Is(err, ErrInvalidHeader) {
+		t.Fatal("expected ErrInvalidHeader, got", err)
+	}
+
+	if _, err := ReadPtau(bytes.NewReader(ptau), WithSize(2<<testPower)); !errors.Is(err, ErrInvalidSize) {
+		t.Fatal("expected ErrInvalidSize, got", err)
+	}
+
+	if _, err := ReadPtau(bytes.NewReader(ptau[:len(ptau)/2])); err == nil {
+		t.Fatal("expected an error on a truncated file")
+	}
+
+	// y-coordinate of [τ]G₁
+	if _, err := ReadPtau(bytes.NewReader(corrupt(func(b []byte) { b[ptauTauG1Offset+64+32] ^= 1 }))); err == nil {
+		t.Fatal("expected an error on a point not on the curve")
+	}
+
+	swapped := corrupt(func(b []byte) {
+		p2 := b[ptauTauG1Offset+2*64 : ptauTauG1Offset+3*64]
+		p3 := b[ptauTauG1Offset+3*64 : ptauTauG1Offset+4*64]
+		var tmp [64]byte
+		copy(tmp[:], p2)
+		copy(p2, p3)
+		copy(p3, tmp[:])
+	})
+	if _, err := ReadPtau(bytes.NewReader(swapped)); err != nil {
+		t.Fatal("points are valid, only the consistency check should fail:", err)
+	}
+	if _, err := ReadPtau(bytes.NewReader(swapped), WithConsistencyCheck()); !errors.Is(err, kzg.ErrInvalidSRS) {
+		t.Fatal("expected ErrInvalidSRS, got", err)
+	}
+}
+
+func TestReadChallengeInvalid(t *testing.T) {
+	challenge := readFile(t, testChallenge)
+	corrupt := func(f func(b []byte)) []byte {
+		b := append([]byte{}, challenge...)
+		f(b)
+		return b
+	}
+
+	if _, err := ReadChallenge(bytes.NewReader(challenge), 0); !errors.Is(err, ErrInvalidSize) {
+		t.Fatal("expected ErrInvalidSize, got", err)
+	}
+
+	// reading with a larger power runs out of data
+	if _, err := ReadChallenge(bytes.NewReader(challenge), testPower+1); err == nil {
+		t.Fatal("expected an error with a wrong power")
+	}
+
+	flagged := corrupt(func(b []byte) { b[challengeTauG1Offset+64] |= flagCompressed })
+	if _, err := ReadChallenge(bytes.NewReader(flagged), testPower); !errors.Is(err, ErrInvalidPoint) {
+		t.Fatal("expected ErrInvalidPoint, got", err)
+	}
+
+	// y-coordinate of [τ]G₁
+	if _, err := ReadChallenge(bytes.NewReader(corrupt(func(b []byte) { b[challengeTauG1Offset+64+63] ^= 1 })), testPower); err == nil {
+		t.Fatal("expected an error on a point not on the curve")
+	}
+}
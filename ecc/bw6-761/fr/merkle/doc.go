@@ -0,0 +1,28 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package merkle provides Merkle trees whose leaves and nodes are elements
+// of fr, built with a field-native hash function.
+//
+// A node of a tree of arity a (2, 4, 8 or 16) is the hash of its a children,
+// computed by a Hasher on field elements: the Poseidon hash of circomlib by
+// default, or MiMC. The leaves are not hashed, and are padded with zeroes up to
+// a power of the arity, so that all the paths have the same length.
+//
+// The proofs only hold field elements, in the order in which a verifier
+// circuit consumes them, so that they can be assigned to the witness of a
+// gnark circuit without any conversion.
+package merkle
@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package ecc provides bls12-381, bls12-377, bn254, bw6-761, bls24-315, bls24-317, bw6-633, secp256k1 and stark-curve elliptic curves implementation (+pairing).
+// Package ecc provides bls12-381, bls12-377, bn254, bw6-761, bls24-315, bls24-317, bw6-633, secp256k1, stark-curve, pallas, vesta, grumpkin and p256 elliptic curves implementation (+pairing).
 //
 // Also
 //
@@ -49,11 +49,15 @@ const (
 	BW6_633
 	STARK_CURVE
 	SECP256K1
+	PALLAS
+	VESTA
+	GRUMPKIN
+	P256
 )
 
 // Implemented return the list of curves fully implemented in gnark-crypto
 func Implemented() []ID {
-	return []ID{BN254, BLS12_377, BLS12_381, BW6_761, BLS24_315, BW6_633, BLS24_317, STARK_CURVE, SECP256K1}
+	return []ID{BN254, BLS12_377, BLS12_381, BW6_761, BLS24_315, BW6_633, BLS24_317, STARK_CURVE, SECP256K1, PALLAS, VESTA, GRUMPKIN, P256}
 }
 
 func IDFromString(s string) (ID, error) {
@@ -104,6 +108,14 @@ func (id ID) config() *config.Curve {
 		return &config.STARK_CURVE
 	case SECP256K1:
 		return &config.SECP256K1
+	case PALLAS:
+		return &config.PALLAS
+	case VESTA:
+		return &config.VESTA
+	case GRUMPKIN:
+		return &config.GRUMPKIN
+	case P256:
+		return &config.P256
 	default:
 		panic("unimplemented ecc ID")
 	}
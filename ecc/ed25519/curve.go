@@ -0,0 +1,63 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ed25519
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/ed25519/fp"
+)
+
+// CurveParams curve parameters: ax^2 + y^2 = 1 + d*x^2*y^2
+type CurveParams struct {
+	A, D     fp.Element
+	Cofactor fp.Element
+	Order    big.Int
+	Base     PointAffine
+}
+
+// GetEdwardsCurve returns the edwards25519 curve
+func GetEdwardsCurve() CurveParams {
+	initOnce.Do(initCurveParams)
+	// copy to keep Order private
+	var res CurveParams
+
+	res.A.Set(&curveParams.A)
+	res.D.Set(&curveParams.D)
+	res.Cofactor.Set(&curveParams.Cofactor)
+	res.Order.Set(&curveParams.Order)
+	res.Base.Set(&curveParams.Base)
+
+	return res
+}
+
+var (
+	initOnce    sync.Once
+	curveParams CurveParams
+	d2          fp.Element // 2d, used in the addition formulas
+)
+
+func initCurveParams() {
+	curveParams.A.SetString("-1")
+	curveParams.D.SetString("37095705934669439343138083508754565189542113879843219016388785533085940283555") // -121665/121666
+	curveParams.Cofactor.SetString("8")
+	curveParams.Order.SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+
+	curveParams.Base.X.SetString("15112221349535400772501151409588531511454012693041857206046113283949847762202")
+	curveParams.Base.Y.SetString("46316835694926478169428394003475163141307993866256225615783033603165251855960") // 4/5
+
+	d2.Double(&curveParams.D)
+}
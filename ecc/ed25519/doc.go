@@ -0,0 +1,31 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ed25519 efficient elliptic curve implementation for edwards25519, the
+// twisted Edwards form of Curve25519 used by Ed25519 signatures (RFC 8032).
+//
+// edwards25519: a twisted Edwards curve with
+//
+//	𝔽p: p=2²⁵⁵-19
+//	𝔽r: r=2²⁵²+27742317777372353535851937790883648493 (order of the prime subgroup)
+//	(E/𝔽p): -x²+y²=1+dx²y², with d=-121665/121666
+//	cofactor: 8
+//
+// Points are encoded as in https://tools.ietf.org/html/rfc8032#section-5.1.2: the
+// y coordinate in little endian, with the parity of x in the most significant bit.
+//
+// # Warning
+//
+// This code has not been audited and is provided as-is. In particular, there is no security guarantees such as constant time implementation or side-channel attack resistance.
+package ed25519
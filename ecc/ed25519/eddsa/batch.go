@@ -0,0 +1,79 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"crypto/rand"
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/ed25519"
+	"github.com/consensys/gnark-crypto/ecc/ed25519/fr"
+)
+
+var errInvalidBatch = errors.New("number of public keys, signatures and messages must match")
+
+// BatchVerify verifies the signatures of messages under publicKeys at once.
+//
+// With zᵢ random 128-bit scalars, it checks the cofactored equation
+//
+//	[8]([-∑zᵢSᵢ]B + ∑[zᵢ]Rᵢ + ∑[zᵢkᵢ]Aᵢ) = 0
+//
+// with a single multi-exponentiation. As Verify also uses the cofactored
+// equation, a batch is valid if and only if (with overwhelming probability)
+// each of its signatures is. It returns an error if the slices lengths differ
+// or if a signature is not a valid encoding.
+func BatchVerify(publicKeys []PublicKey, signatures, messages [][]byte) (bool, error) {
+	if len(publicKeys) != len(signatures) || len(publicKeys) != len(messages) {
+		return false, errInvalidBatch
+	}
+	n := len(publicKeys)
+	if n == 0 {
+		return true, nil
+	}
+
+	points := make([]ed25519.PointAffine, 2*n+1)
+	scalars := make([]fr.Element, 2*n+1)
+	var sumZS, z, s, k fr.Element
+	var buf [16]byte
+	for i := 0; i < n; i++ {
+		var sig Signature
+		if _, err := sig.SetBytes(signatures[i]); err != nil {
+			return false, err
+		}
+		s, _ = fr.LittleEndian.Element(&sig.S) // checked in SetBytes
+		k = challenge(signatures[i][:ed25519.SizePointCompressed], publicKeys[i].Bytes(), messages[i])
+
+		if _, err := rand.Read(buf[:]); err != nil {
+			return false, err
+		}
+		z.SetBytes(buf[:])
+
+		points[2*i] = sig.R
+		scalars[2*i] = z
+		points[2*i+1] = publicKeys[i].A
+		scalars[2*i+1].Mul(&z, &k)
+		s.Mul(&s, &z)
+		sumZS.Add(&sumZS, &s)
+	}
+	points[2*n] = ed25519.GetEdwardsCurve().Base
+	scalars[2*n].Neg(&sumZS)
+
+	var res ed25519.PointExtended
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false, err
+	}
+	return res.MulByCofactor(&res).IsZero(), nil
+}
@@ -0,0 +1,27 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eddsa provides Ed25519 signatures, following RFC 8032.
+//
+// See https://tools.ietf.org/html/rfc8032#section-5.1
+//
+// Contrary to the eddsa packages of the twisted Edwards companion curves, the
+// hash function is SHA-512, as the RFC mandates, and signatures are verified
+// with the cofactored equation [8][S]B = [8]R + [8][k]A, so that single and
+// batch verifications agree on every input.
+//
+// # Warning
+//
+// This code has not been audited and is provided as-is. In particular, there is no security guarantees such as constant time implementation or side-channel attack resistance.
+package eddsa
@@ -0,0 +1,201 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"crypto/sha512"
+	"crypto/subtle"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/ed25519"
+	"github.com/consensys/gnark-crypto/ecc/ed25519/fr"
+	"github.com/consensys/gnark-crypto/signature"
+)
+
+const (
+	sizeFr = fr.Bytes
+
+	// SeedSize size in bytes of a private key seed, as defined in RFC 8032
+	SeedSize = 32
+
+	// SizePublicKey size in bytes of an encoded public key
+	SizePublicKey = ed25519.SizePointCompressed
+
+	// SizeSignature size in bytes of an encoded signature R||S
+	SizeSignature = ed25519.SizePointCompressed + sizeFr
+
+	sizePrivateKey = SizePublicKey + 2*sizeFr
+)
+
+// PublicKey eddsa signature object
+// cf https://en.wikipedia.org/wiki/EdDSA for notation
+type PublicKey struct {
+	A ed25519.PointAffine
+}
+
+// PrivateKey private key of an eddsa instance
+type PrivateKey struct {
+	PublicKey PublicKey    // copy of the associated public key
+	scalar    [sizeFr]byte // secret scalar, in big Endian
+	randSrc   [32]byte     // prefix used to derive the nonces
+}
+
+// Signature represents an eddsa signature
+// cf https://en.wikipedia.org/wiki/EdDSA for notation
+type Signature struct {
+	R ed25519.PointAffine
+	S [sizeFr]byte // in little endian, as in RFC 8032
+}
+
+// GenerateKey generates a public and private key pair from a random seed.
+func GenerateKey(r io.Reader) (*PrivateKey, error) {
+	seed := make([]byte, SeedSize)
+	if _, err := io.ReadFull(r, seed); err != nil {
+		return nil, err
+	}
+	return NewKeyFromSeed(seed)
+}
+
+// NewKeyFromSeed returns the private key derived from a 32 bytes seed, as in
+// https://tools.ietf.org/html/rfc8032#section-5.1.5
+func NewKeyFromSeed(seed []byte) (*PrivateKey, error) {
+	if len(seed) != SeedSize {
+		return nil, errWrongSize
+	}
+	var priv PrivateKey
+
+	// hash(h) = private_key || random_source, on 32 bytes each
+	h := sha512.Sum512(seed)
+	copy(priv.randSrc[:], h[32:])
+
+	// prune the key
+	h[0] &= 0xF8
+	h[31] &= 0x7F
+	h[31] |= 0x40
+
+	// reverse first bytes because setBytes interpret stream as big endian
+	// but in eddsa specs s is the first 32 bytes in little endian
+	for i, j := 0, sizeFr-1; i < sizeFr; i, j = i+1, j-1 {
+		priv.scalar[i] = h[j]
+	}
+
+	var bScalar big.Int
+	bScalar.SetBytes(priv.scalar[:])
+	base := ed25519.GetEdwardsCurve().Base
+	priv.PublicKey.A.ScalarMultiplication(&base, &bScalar)
+
+	return &priv, nil
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(x signature.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	bpk := pub.Bytes()
+	bxx := xx.Bytes()
+	return subtle.ConstantTimeCompare(bpk, bxx) == 1
+}
+
+// Public returns the public key associated to the private key.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.A.Set(&privKey.PublicKey.A)
+	return &pub
+}
+
+// Sign signs a message following https://tools.ietf.org/html/rfc8032#section-5.1.6.
+//
+// hFunc is ignored and may be nil: Ed25519 hashes the message with SHA-512.
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	base := ed25519.GetEdwardsCurve().Base
+
+	// r = H(randSrc || M)
+	h := sha512.New()
+	h.Write(privKey.randSrc[:])
+	h.Write(message)
+	r := hashToScalar(h.Sum(nil))
+
+	var res Signature
+	var rBigInt big.Int
+	r.BigInt(&rBigInt)
+	res.R.ScalarMultiplication(&base, &rBigInt)
+
+	// S = r + H(R || A || M)⋅s
+	rBytes := res.R.Bytes()
+	aBytes := privKey.PublicKey.A.Bytes()
+	k := challenge(rBytes[:], aBytes[:], message)
+	var s fr.Element
+	s.SetBytes(privKey.scalar[:])
+	s.Mul(&s, &k).Add(&s, &r)
+	fr.LittleEndian.PutElement(&res.S, s)
+
+	return res.Bytes(), nil
+}
+
+// Verify verifies a signature following https://tools.ietf.org/html/rfc8032#section-5.1.7,
+// with the cofactored equation [8][S]B = [8]R + [8][k]A.
+//
+// hFunc is ignored and may be nil: Ed25519 hashes the message with SHA-512.
+// It returns an error if the signature is not a valid encoding.
+func (pub *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return false, err
+	}
+	s, _ := fr.LittleEndian.Element(&sig.S) // checked in SetBytes
+	k := challenge(sigBin[:ed25519.SizePointCompressed], pub.Bytes(), message)
+
+	// [S]B - [k]A - R
+	var bs, bk big.Int
+	s.BigInt(&bs)
+	k.BigInt(&bk)
+	var lhs, tmp ed25519.PointExtended
+	base := ed25519.GetEdwardsCurve().Base
+	lhs.FromAffine(&base)
+	lhs.ScalarMultiplication(&lhs, &bs)
+	tmp.FromAffine(&pub.A)
+	tmp.ScalarMultiplication(&tmp, &bk)
+	tmp.Neg(&tmp)
+	lhs.Add(&lhs, &tmp)
+	tmp.FromAffine(&sig.R)
+	tmp.Neg(&tmp)
+	lhs.Add(&lhs, &tmp)
+
+	return lhs.MulByCofactor(&lhs).IsZero(), nil
+}
+
+// challenge returns k = H(R || A || M) mod r
+func challenge(r, a, message []byte) fr.Element {
+	h := sha512.New()
+	h.Write(r)
+	h.Write(a)
+	h.Write(message)
+	return hashToScalar(h.Sum(nil))
+}
+
+// hashToScalar interprets a SHA-512 digest as a little endian integer, reduced
+// modulo r
+func hashToScalar(digest []byte) fr.Element {
+	for i, j := 0, len(digest)-1; i < j; i, j = i+1, j-1 {
+		digest[i], digest[j] = digest[j], digest[i]
+	}
+	var res fr.Element
+	res.SetBytes(digest)
+	return res
+}
@@ -0,0 +1,433 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"bytes"
+	stded25519 "crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/ed25519"
+	"github.com/consensys/gnark-crypto/ecc/ed25519/fr"
+)
+
+// test vectors from https://tools.ietf.org/html/rfc8032#section-7.1
+var rfc8032Vectors = []struct {
+	seed, publicKey, message, signature string
+}{
+	{
+		"9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+		"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		"",
+		"e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b",
+	},
+	{
+		"4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb",
+		"3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+		"72",
+		"92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00",
+	},
+	{
+		"c5aa8df43f9f837bedb7442f31dcb7b166d38535076f094b85ce3a2e0b4458f7",
+		"fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025",
+		"af82",
+		"6291d657deec24024827e69c3abe01a30ce548a284743a445e3680d7db5ac3ac18ff9b538d16f290ae67f760984dc6594a7c15e9716ed28dc027beceea1ec40a",
+	},
+	{
+		"833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42",
+		"ec172b93ad5e563bf4932c70e1245034c35467ef2efd4d64ebf819683467e2bf",
+		"ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+		"dc2a4459e7369633a52b1bf277839a00201009a3efbf3ecb69bea2186c26b58909351fc9ac90b3ecfdfbc7c66431e0303dca179c138ac17ad9bef1177331a704",
+	},
+}
+
+func TestRFC8032(t *testing.T) {
+	for i, v := range rfc8032Vectors {
+		seed, _ := hex.DecodeString(v.seed)
+		message, _ := hex.DecodeString(v.message)
+		expectedPub, _ := hex.DecodeString(v.publicKey)
+		expectedSig, _ := hex.DecodeString(v.signature)
+
+		priv, err := NewKeyFromSeed(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(priv.PublicKey.Bytes(), expectedPub) {
+			t.Fatalf("vector %d: wrong public key", i)
+		}
+		sig, err := priv.Sign(message, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sig, expectedSig) {
+			t.Fatalf("vector %d: wrong signature", i)
+		}
+
+		var pub PublicKey
+		if _, err := pub.SetBytes(expectedPub); err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := pub.Verify(expectedSig, message, nil); !ok || err != nil {
+			t.Fatalf("vector %d: valid signature rejected", i)
+		}
+		if ok, _ := pub.Verify(expectedSig, append(message, 0), nil); ok {
+			t.Fatalf("vector %d: signature accepted on a wrong message", i)
+		}
+	}
+}
+
+func TestStdlibInterop(t *testing.T) {
+	t.Parallel()
+	for i := 0; i < 10; i++ {
+		seed := make([]byte, SeedSize)
+		message := make([]byte, i*7)
+		rand.Read(seed)
+		rand.Read(message)
+
+		priv, err := NewKeyFromSeed(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stdPriv := stded25519.NewKeyFromSeed(seed)
+
+		sig, err := priv.Sign(message, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !stded25519.Verify(stdPriv.Public().(stded25519.PublicKey), message, sig) {
+			t.Fatal("signature rejected by crypto/ed25519")
+		}
+
+		stdSig := stded25519.Sign(stdPriv, message)
+		if ok, err := priv.Public().Verify(stdSig, message, nil); !ok || err != nil {
+			t.Fatal("crypto/ed25519 signature rejected")
+		}
+	}
+}
+
+func TestNonCanonicalSignature(t *testing.T) {
+	t.Parallel()
+	priv, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("non canonical")
+	sig, err := priv.Sign(message, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := priv.Public()
+
+	// S + r verifies the same equation but must be rejected
+	var s big.Int
+	s.SetBytes(reverse(sig[ed25519.SizePointCompressed:]))
+	order := ed25519.GetEdwardsCurve().Order
+	s.Add(&s, &order)
+	malleated := append([]byte{}, sig[:ed25519.SizePointCompressed]...)
+	malleated = append(malleated, reverse(s.FillBytes(make([]byte, sizeFr)))...)
+	if ok, err := pub.Verify(malleated, message, nil); ok || err != errSBiggerThanRMod {
+		t.Fatal("S ≥ r should be rejected")
+	}
+
+	// R with y ≥ p
+	nonCanonical := append([]byte{}, sig...)
+	nonCanonical[0] = 0xee
+	for i := 1; i < 31; i++ {
+		nonCanonical[i] = 0xff
+	}
+	nonCanonical[31] = 0x7f
+	if ok, err := pub.Verify(nonCanonical, message, nil); ok || err != ed25519.ErrInvalidEncoding {
+		t.Fatal("non-canonical R should be rejected")
+	}
+
+	if ok, err := pub.Verify(sig[1:], message, nil); ok || err != errWrongSize {
+		t.Fatal("short signature should be rejected")
+	}
+}
+
+func TestSerialization(t *testing.T) {
+	t.Parallel()
+	priv, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var priv2 PrivateKey
+	if _, err := priv2.SetBytes(priv.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(priv.Bytes(), priv2.Bytes()) {
+		t.Fatal("private key serialization round trip failed")
+	}
+
+	var pub PublicKey
+	if _, err := pub.SetBytes(priv.PublicKey.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if !pub.Equal(priv.Public()) {
+		t.Fatal("public key serialization round trip failed")
+	}
+
+	message := []byte("serialization")
+	sig, err := priv2.Sign(message, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := pub.Verify(sig, message, nil); !ok || err != nil {
+		t.Fatal("valid signature rejected")
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	t.Parallel()
+	const n = 16
+	publicKeys := make([]PublicKey, n)
+	signatures := make([][]byte, n)
+	messages := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		priv, err := GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKeys[i] = priv.PublicKey
+		messages[i] = []byte{byte(i)}
+		if signatures[i], err = priv.Sign(messages[i], nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if ok, err := BatchVerify(publicKeys, signatures, messages); !ok || err != nil {
+		t.Fatal("valid batch rejected")
+	}
+
+	messages[n/2] = []byte("forged")
+	if ok, err := BatchVerify(publicKeys, signatures, messages); ok || err != nil {
+		t.Fatal("invalid batch accepted")
+	}
+
+	if _, err := BatchVerify(publicKeys[1:], signatures, messages); err != errInvalidBatch {
+		t.Fatal("batch of mismatched lengths accepted")
+	}
+}
+
+// TestWycheproof checks the test vectors of
+// https://github.com/google/wycheproof (testvectors/eddsa_test.json), which
+// include malleated signatures (S + r) and invalid encodings of R and S.
+func TestWycheproof(t *testing.T) {
+	t.Parallel()
+	var vectors struct {
+		TestGroups []struct {
+			Key struct {
+				PK string `json:"pk"`
+				SK string `json:"sk"`
+			} `json:"key"`
+			Tests []struct {
+				TcID    int    `json:"tcId"`
+				Comment string `json:"comment"`
+				Msg     string `json:"msg"`
+				Sig     string `json:"sig"`
+				Result  string `json:"result"`
+			} `json:"tests"`
+		} `json:"testGroups"`
+	}
+	b, err := os.ReadFile("testdata/wycheproof_Ed25519.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &vectors); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, group := range vectors.TestGroups {
+		pkBin, _ := hex.DecodeString(group.Key.PK)
+		seed, _ := hex.DecodeString(group.Key.SK)
+		var pub PublicKey
+		if _, err := pub.SetBytes(pkBin); err != nil {
+			t.Fatal(err)
+		}
+		priv, err := NewKeyFromSeed(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(priv.PublicKey.Bytes(), pkBin) {
+			t.Fatal("public key mismatch")
+		}
+
+		for _, v := range group.Tests {
+			msg, _ := hex.DecodeString(v.Msg)
+			sig, _ := hex.DecodeString(v.Sig)
+			ok, err := pub.Verify(sig, msg, nil)
+			switch v.Result {
+			case "valid":
+				if !ok || err != nil {
+					t.Fatalf("tcId %d (%s): valid signature rejected", v.TcID, v.Comment)
+				}
+				// signatures are deterministic
+				if expected, _ := priv.Sign(msg, nil); !bytes.Equal(expected, sig) {
+					t.Fatalf("tcId %d (%s): signature mismatch", v.TcID, v.Comment)
+				}
+			case "invalid":
+				if ok {
+					t.Fatalf("tcId %d (%s): invalid signature accepted", v.TcID, v.Comment)
+				}
+			default:
+				t.Fatalf("tcId %d: unexpected result %s", v.TcID, v.Result)
+			}
+		}
+	}
+}
+
+// encodings of the 8 points of small order
+var lowOrderPoints = []string{
+	"0100000000000000000000000000000000000000000000000000000000000000",
+	"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	"0000000000000000000000000000000000000000000000000000000000000000",
+	"0000000000000000000000000000000000000000000000000000000000000080",
+	"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a",
+	"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac03fa",
+	"26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc05",
+	"26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85",
+}
+
+func TestNonCanonicalPublicKey(t *testing.T) {
+	t.Parallel()
+	for _, enc := range []string{
+		// y = p, y = p + 1 and y = 2²⁵⁵ - 1, i.e. y = 0, 1 and 18 mod p
+		"edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		"eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		// x = 0 with the sign bit set ("negative zero")
+		"0100000000000000000000000000000000000000000000000000000000000080",
+		"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		// y such that (y² - 1) / (dy² + 1) is not a square
+		"0200000000000000000000000000000000000000000000000000000000000000",
+	} {
+		b, _ := hex.DecodeString(enc)
+		var pub PublicKey
+		if _, err := pub.SetBytes(b); err != ed25519.ErrInvalidEncoding {
+			t.Fatalf("non-canonical encoding %s accepted", enc)
+		}
+	}
+}
+
+// TestLowOrderPoints checks that Verify and BatchVerify, which both use the
+// cofactored equation, agree on signatures whose points have a small order.
+func TestLowOrderPoints(t *testing.T) {
+	t.Parallel()
+	points := make([]ed25519.PointAffine, len(lowOrderPoints))
+	for i, enc := range lowOrderPoints {
+		b, _ := hex.DecodeString(enc)
+		if _, err := points[i].SetBytes(b); err != nil {
+			t.Fatalf("low order point %s rejected: %v", enc, err)
+		}
+		if got := points[i].Bytes(); !bytes.Equal(got[:], b) {
+			t.Fatalf("low order point %s: encoding mismatch", enc)
+		}
+		var p ed25519.PointExtended
+		p.FromAffine(&points[i])
+		if !p.MulByCofactor(&p).IsZero() {
+			t.Fatalf("%s is not of small order", enc)
+		}
+	}
+
+	priv, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("low order")
+	var publicKeys []PublicKey
+	var signatures, messages [][]byte
+	for i := range points {
+		// with a public key of small order, (R, 0) verifies any message for R
+		// of small order
+		sig := Signature{R: points[(i+1)%len(points)]}
+		pub := PublicKey{A: points[i]}
+		if ok, err := pub.Verify(sig.Bytes(), message, nil); !ok || err != nil {
+			t.Fatalf("low order public key %s: signature rejected", lowOrderPoints[i])
+		}
+		publicKeys = append(publicKeys, pub)
+		signatures = append(signatures, sig.Bytes())
+		messages = append(messages, message)
+
+		// a signature whose nonce commitment R = [r]B + T has a small order
+		// component T: [S]B = R + [k]A - T holds only up to the cofactor
+		var nonce, k, sc fr.Element
+		nonce.SetRandom()
+		var bNonce big.Int
+		nonce.BigInt(&bNonce)
+		base := ed25519.GetEdwardsCurve().Base
+		sig.R.ScalarMultiplication(&base, &bNonce)
+		sig.R.Add(&sig.R, &points[i])
+		rBytes, aBytes := sig.R.Bytes(), priv.PublicKey.A.Bytes()
+		k = challenge(rBytes[:], aBytes[:], message)
+		sc.SetBytes(priv.scalar[:])
+		sc.Mul(&sc, &k).Add(&sc, &nonce)
+		fr.LittleEndian.PutElement(&sig.S, sc)
+		if ok, err := priv.PublicKey.Verify(sig.Bytes(), message, nil); !ok || err != nil {
+			t.Fatalf("low order component %s: signature rejected", lowOrderPoints[i])
+		}
+		publicKeys = append(publicKeys, priv.PublicKey)
+		signatures = append(signatures, sig.Bytes())
+		messages = append(messages, message)
+	}
+	if ok, err := BatchVerify(publicKeys, signatures, messages); !ok || err != nil {
+		t.Fatal("BatchVerify should accept the signatures accepted by Verify")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkVerify(b *testing.B) {
+	priv, _ := GenerateKey(rand.Reader)
+	message := []byte("benchmark")
+	sig, _ := priv.Sign(message, nil)
+	pub := priv.Public()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pub.Verify(sig, message, nil)
+	}
+}
+
+func BenchmarkBatchVerify64(b *testing.B) {
+	const n = 64
+	publicKeys := make([]PublicKey, n)
+	signatures := make([][]byte, n)
+	messages := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		priv, _ := GenerateKey(rand.Reader)
+		publicKeys[i] = priv.PublicKey
+		messages[i] = []byte{byte(i)}
+		signatures[i], _ = priv.Sign(messages[i], nil)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchVerify(publicKeys, signatures, messages)
+	}
+}
+
+func reverse(b []byte) []byte {
+	res := make([]byte, len(b))
+	for i := range b {
+		res[len(b)-1-i] = b[i]
+	}
+	return res
+}
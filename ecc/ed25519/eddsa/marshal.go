@@ -0,0 +1,106 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eddsa
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/ed25519"
+	"github.com/consensys/gnark-crypto/ecc/ed25519/fr"
+)
+
+var errWrongSize = errors.New("wrong size buffer")
+var errSBiggerThanRMod = errors.New("s >= r_mod")
+
+// Bytes returns the binary representation of the public key
+// follows https://tools.ietf.org/html/rfc8032#section-5.1.2
+func (pk *PublicKey) Bytes() []byte {
+	pkBin := pk.A.Bytes()
+	return pkBin[:]
+}
+
+// SetBytes sets pk from its binary representation in buf, as in
+// https://tools.ietf.org/html/rfc8032#section-5.1.3
+// It returns the number of bytes read from the buffer.
+func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
+	return pk.A.SetBytes(buf)
+}
+
+// Bytes returns the binary representation of pk,
+// as byte array publicKey||scalar||randSrc
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+func (privKey *PrivateKey) Bytes() []byte {
+	var res [sizePrivateKey]byte
+	pubkBin := privKey.PublicKey.A.Bytes()
+	subtle.ConstantTimeCopy(1, res[:SizePublicKey], pubkBin[:])
+	subtle.ConstantTimeCopy(1, res[SizePublicKey:SizePublicKey+sizeFr], privKey.scalar[:])
+	subtle.ConstantTimeCopy(1, res[SizePublicKey+sizeFr:], privKey.randSrc[:])
+	return res[:]
+}
+
+// SetBytes sets pk from buf, where buf is interpreted
+// as  publicKey||scalar||randSrc
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+// It returns the number byte read.
+func (privKey *PrivateKey) SetBytes(buf []byte) (int, error) {
+	n := 0
+	if len(buf) < sizePrivateKey {
+		return n, io.ErrShortBuffer
+	}
+	if _, err := privKey.PublicKey.A.SetBytes(buf[:SizePublicKey]); err != nil {
+		return 0, err
+	}
+	n += SizePublicKey
+	subtle.ConstantTimeCopy(1, privKey.scalar[:], buf[n:n+sizeFr])
+	n += sizeFr
+	subtle.ConstantTimeCopy(1, privKey.randSrc[:], buf[n:n+sizeFr])
+	n += sizeFr
+	return n, nil
+}
+
+// Bytes returns the binary representation of sig
+// as a byte array R||S, as in https://tools.ietf.org/html/rfc8032#section-5.1.6
+func (sig *Signature) Bytes() []byte {
+	var res [SizeSignature]byte
+	sigRBin := sig.R.Bytes()
+	subtle.ConstantTimeCopy(1, res[:ed25519.SizePointCompressed], sigRBin[:])
+	subtle.ConstantTimeCopy(1, res[ed25519.SizePointCompressed:], sig.S[:])
+	return res[:]
+}
+
+// SetBytes sets sig from a buffer in binary, interpreted as R||S. It returns an
+// error if R is not a valid point encoding or if S is not reduced modulo the
+// order of the subgroup (to avoid malleability).
+//
+// It returns the number of bytes read from buf.
+func (sig *Signature) SetBytes(buf []byte) (int, error) {
+	if len(buf) != SizeSignature {
+		return 0, errWrongSize
+	}
+	n, err := sig.R.SetBytes(buf[:ed25519.SizePointCompressed])
+	if err != nil {
+		return 0, err
+	}
+	copy(sig.S[:], buf[n:])
+	if _, err := fr.LittleEndian.Element(&sig.S); err != nil {
+		return 0, errSBiggerThanRMod
+	}
+	n += sizeFr
+	return n, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ed25519
+
+import (
+	"errors"
+	"math/bits"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/ed25519/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// MultiExp implements section 4 of https://eprint.iacr.org/2012/549.pdf
+//
+// Each c-bit window of the scalars is processed independently with the bucket
+// method, and the window sums are then combined with c doublings each.
+func (p *PointExtended) MultiExp(points []PointAffine, scalars []fr.Element, config ecc.MultiExpConfig) (*PointExtended, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("len(points) != len(scalars)")
+	}
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU()
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+	if len(points) == 0 {
+		return p.setInfinity(), nil
+	}
+
+	c := bits.Len(uint(len(points)))/2 + 1
+	nbWindows := (fr.Bits + c - 1) / c
+
+	words := make([][fr.Limbs]uint64, len(scalars))
+	for i := range scalars {
+		words[i] = scalars[i].Bits()
+	}
+	bases := make([]PointExtended, len(points))
+	for i := range points {
+		bases[i].FromAffine(&points[i])
+	}
+
+	windows := make([]PointExtended, nbWindows)
+	work := func(start, end int) {
+		buckets := make([]PointExtended, (1<<c)-1)
+		for w := start; w < end; w++ {
+			for i := range buckets {
+				buckets[i].setInfinity()
+			}
+			for i := range words {
+				if d := window(&words[i], w*c, c); d != 0 {
+					buckets[d-1].Add(&buckets[d-1], &bases[i])
+				}
+			}
+			// ∑ⱼ (j+1)⋅bucketⱼ with a running sum
+			var runningSum, sum PointExtended
+			runningSum.setInfinity()
+			sum.setInfinity()
+			for i := len(buckets) - 1; i >= 0; i-- {
+				runningSum.Add(&runningSum, &buckets[i])
+				sum.Add(&sum, &runningSum)
+			}
+			windows[w] = sum
+		}
+	}
+	parallel.Execute(nbWindows, work, config.NbTasks)
+
+	var res PointExtended
+	res.Set(&windows[nbWindows-1])
+	for w := nbWindows - 2; w >= 0; w-- {
+		for i := 0; i < c; i++ {
+			res.Double(&res)
+		}
+		res.Add(&res, &windows[w])
+	}
+	return p.Set(&res), nil
+}
+
+// window returns the c bits of s starting at bit index offset.
+func window(s *[fr.Limbs]uint64, offset, c int) uint64 {
+	i, shift := offset/64, offset%64
+	d := s[i] >> shift
+	if shift+c > 64 && i+1 < len(s) {
+		d |= s[i+1] << (64 - shift)
+	}
+	return d & ((1 << c) - 1)
+}
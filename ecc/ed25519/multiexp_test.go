@@ -0,0 +1,82 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ed25519
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/ed25519/fr"
+)
+
+func TestMultiExp(t *testing.T) {
+	t.Parallel()
+	params := GetEdwardsCurve()
+
+	for _, n := range []int{1, 2, 7, 64, 200} {
+		points := make([]PointAffine, n)
+		scalars := make([]fr.Element, n)
+		for i := 0; i < n; i++ {
+			scalars[i].SetRandom()
+			points[i].ScalarMultiplication(&params.Base, big.NewInt(int64(i+1)))
+		}
+		// the last scalar is the largest possible one
+		scalars[n-1].SetOne().Neg(&scalars[n-1])
+
+		// ∑ᵢ [sᵢ]Pᵢ, naively
+		var expected, tmp PointExtended
+		expected.setInfinity()
+		for i := 0; i < n; i++ {
+			var s big.Int
+			scalars[i].BigInt(&s)
+			tmp.FromAffine(&points[i])
+			tmp.ScalarMultiplication(&tmp, &s)
+			expected.Add(&expected, &tmp)
+		}
+
+		for _, nbTasks := range []int{0, 1, 3} {
+			var res PointExtended
+			if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+				t.Fatal(err)
+			}
+			if !res.Equal(&expected) {
+				t.Fatalf("MultiExp mismatch for n=%d, nbTasks=%d", n, nbTasks)
+			}
+		}
+	}
+
+	var res PointExtended
+	if _, err := res.MultiExp(make([]PointAffine, 2), make([]fr.Element, 1), ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("MultiExp should fail on inputs of different lengths")
+	}
+}
+
+func BenchmarkMultiExp(b *testing.B) {
+	const n = 1 << 10
+	params := GetEdwardsCurve()
+	points := make([]PointAffine, n)
+	scalars := make([]fr.Element, n)
+	for i := range points {
+		scalars[i].SetRandom()
+		points[i].ScalarMultiplication(&params.Base, big.NewInt(int64(i+1)))
+	}
+
+	b.ResetTimer()
+	for j := 0; j < b.N; j++ {
+		var res PointExtended
+		res.MultiExp(points, scalars, ecc.MultiExpConfig{})
+	}
+}
@@ -0,0 +1,363 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ed25519
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/ed25519/fp"
+)
+
+// PointAffine point on edwards25519
+type PointAffine struct {
+	X, Y fp.Element
+}
+
+// PointExtended point in extended coordinates (X:Y:Z:T), with x=X/Z, y=Y/Z and
+// xy=T/Z
+type PointExtended struct {
+	X, Y, Z, T fp.Element
+}
+
+const (
+	// SizePointCompressed size in byte of a compressed point
+	SizePointCompressed = fp.Bytes
+
+	mParity = 0x80
+	mUnmask = 0x7f
+)
+
+// ErrInvalidEncoding is returned when decoding a point that is not the encoding
+// of a point on the curve, following https://tools.ietf.org/html/rfc8032#section-5.1.3
+var ErrInvalidEncoding = errors.New("invalid edwards25519 point encoding")
+
+// Bytes returns the compressed point as a byte array
+// Follows https://tools.ietf.org/html/rfc8032#section-5.1.2: y in little endian,
+// the most significant bit of the last byte holding the parity of x.
+func (p *PointAffine) Bytes() [SizePointCompressed]byte {
+	var res [SizePointCompressed]byte
+	fp.LittleEndian.PutElement(&res, p.Y)
+	if p.X.Bits()[0]&1 == 1 {
+		res[SizePointCompressed-1] |= mParity
+	}
+	return res
+}
+
+// Marshal converts p to a byte slice
+func (p *PointAffine) Marshal() []byte {
+	b := p.Bytes()
+	return b[:]
+}
+
+// SetBytes sets p from buf, following https://tools.ietf.org/html/rfc8032#section-5.1.3.
+// len(buf) >= SizePointCompressed
+// It returns the number of read bytes, io.ErrShortBuffer if the buffer is too
+// short, and ErrInvalidEncoding if y ≥ p or if no point with such coordinates
+// exists.
+func (p *PointAffine) SetBytes(buf []byte) (int, error) {
+	if len(buf) < SizePointCompressed {
+		return 0, io.ErrShortBuffer
+	}
+	var b [SizePointCompressed]byte
+	copy(b[:], buf[:SizePointCompressed])
+	xParity := uint64(b[SizePointCompressed-1]&mParity) >> 7
+	b[SizePointCompressed-1] &= mUnmask
+
+	y, err := fp.LittleEndian.Element(&b)
+	if err != nil {
+		return 0, ErrInvalidEncoding
+	}
+	x, ok := computeX(&y)
+	if !ok {
+		return 0, ErrInvalidEncoding
+	}
+	if x.IsZero() && xParity == 1 {
+		return 0, ErrInvalidEncoding
+	}
+	if x.Bits()[0]&1 != xParity {
+		x.Neg(&x)
+	}
+	p.X, p.Y = x, y
+
+	return SizePointCompressed, nil
+}
+
+// Unmarshal alias to SetBytes()
+func (p *PointAffine) Unmarshal(b []byte) error {
+	_, err := p.SetBytes(b)
+	return err
+}
+
+// computeX returns a square root of (y²-1)/(dy²+1), and false if there is none
+func computeX(y *fp.Element) (x fp.Element, ok bool) {
+	initOnce.Do(initCurveParams)
+
+	var one, num, den fp.Element
+	one.SetOne()
+	num.Square(y)
+	den.Mul(&num, &curveParams.D).Add(&den, &one)
+	num.Sub(&num, &one)
+	x.Div(&num, &den)
+	if x.Sqrt(&x) == nil {
+		return x, false
+	}
+	return x, true
+}
+
+// Set sets p to p1 and return it
+func (p *PointAffine) Set(p1 *PointAffine) *PointAffine {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	return p
+}
+
+// Equal returns true if p=p1 false otherwise
+func (p *PointAffine) Equal(p1 *PointAffine) bool {
+	return p.X.Equal(&p1.X) && p.Y.Equal(&p1.Y)
+}
+
+// IsZero returns true if p=0 false otherwise
+func (p *PointAffine) IsZero() bool {
+	var one fp.Element
+	one.SetOne()
+	return p.X.IsZero() && p.Y.Equal(&one)
+}
+
+// NewPointAffine creates a new instance of PointAffine
+func NewPointAffine(x, y fp.Element) PointAffine {
+	return PointAffine{x, y}
+}
+
+// IsOnCurve checks if a point is on edwards25519
+func (p *PointAffine) IsOnCurve() bool {
+	initOnce.Do(initCurveParams)
+
+	var lhs, rhs, xx, yy fp.Element
+
+	xx.Square(&p.X)
+	yy.Square(&p.Y)
+	lhs.Sub(&yy, &xx)
+
+	rhs.Mul(&xx, &yy).
+		Mul(&rhs, &curveParams.D)
+	xx.SetOne()
+	rhs.Add(&rhs, &xx)
+
+	return lhs.Equal(&rhs)
+}
+
+// IsInSubGroup checks that p is on the curve and in the subgroup of prime
+// order, i.e. that [r]p = 0
+func (p *PointAffine) IsInSubGroup() bool {
+	if !p.IsOnCurve() {
+		return false
+	}
+	var _p PointExtended
+	_p.FromAffine(p)
+	_p.ScalarMultiplication(&_p, &curveParams.Order)
+	return _p.IsZero()
+}
+
+// Neg sets p to -p1 and returns it
+func (p *PointAffine) Neg(p1 *PointAffine) *PointAffine {
+	p.X.Neg(&p1.X)
+	p.Y = p1.Y
+	return p
+}
+
+// Add adds two points in affine coordinates
+func (p *PointAffine) Add(p1, p2 *PointAffine) *PointAffine {
+	var _p1, _p2 PointExtended
+	_p1.FromAffine(p1)
+	_p2.FromAffine(p2)
+	_p1.Add(&_p1, &_p2)
+	return p.FromExtended(&_p1)
+}
+
+// Double doubles a point in affine coordinates
+func (p *PointAffine) Double(p1 *PointAffine) *PointAffine {
+	var _p PointExtended
+	_p.FromAffine(p1)
+	_p.Double(&_p)
+	return p.FromExtended(&_p)
+}
+
+// FromExtended sets p in affine from p in extended coordinates
+func (p *PointAffine) FromExtended(p1 *PointExtended) *PointAffine {
+	var I fp.Element
+	I.Inverse(&p1.Z)
+	p.X.Mul(&p1.X, &I)
+	p.Y.Mul(&p1.Y, &I)
+	return p
+}
+
+// ScalarMultiplication scalar multiplication of a point
+// p1 in affine coordinates with a scalar in big.Int
+func (p *PointAffine) ScalarMultiplication(p1 *PointAffine, scalar *big.Int) *PointAffine {
+	var _p PointExtended
+	_p.FromAffine(p1)
+	_p.ScalarMultiplication(&_p, scalar)
+	return p.FromExtended(&_p)
+}
+
+// ------- Extended coordinates
+
+// Set sets p to p1 and return it
+func (p *PointExtended) Set(p1 *PointExtended) *PointExtended {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.T.Set(&p1.T)
+	p.Z.Set(&p1.Z)
+	return p
+}
+
+// IsZero returns true if p=0 false otherwise
+func (p *PointExtended) IsZero() bool {
+	return p.X.IsZero() && p.Y.Equal(&p.Z) && p.T.IsZero()
+}
+
+// Equal returns true if p=p1 false otherwise
+func (p *PointExtended) Equal(p1 *PointExtended) bool {
+	var l, r fp.Element
+	l.Mul(&p.X, &p1.Z)
+	r.Mul(&p1.X, &p.Z)
+	if !l.Equal(&r) {
+		return false
+	}
+	l.Mul(&p.Y, &p1.Z)
+	r.Mul(&p1.Y, &p.Z)
+	return l.Equal(&r)
+}
+
+// Neg sets p to -p1 and returns it
+func (p *PointExtended) Neg(p1 *PointExtended) *PointExtended {
+	p.X.Neg(&p1.X)
+	p.Y = p1.Y
+	p.Z = p1.Z
+	p.T.Neg(&p1.T)
+	return p
+}
+
+// FromAffine sets p in extended from p in affine
+func (p *PointExtended) FromAffine(p1 *PointAffine) *PointExtended {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.Z.SetOne()
+	p.T.Mul(&p1.X, &p1.Y)
+	return p
+}
+
+// Add adds points in extended coordinates. The formulas are complete since d
+// is not a square in 𝔽p.
+// See https://hyperelliptic.org/EFD/g1p/auto-twisted-extended-1.html#addition-add-2008-hwcd-3
+func (p *PointExtended) Add(p1, p2 *PointExtended) *PointExtended {
+	initOnce.Do(initCurveParams)
+
+	var A, B, C, D, E, F, G, H, tmp fp.Element
+	A.Sub(&p1.Y, &p1.X)
+	tmp.Sub(&p2.Y, &p2.X)
+	A.Mul(&A, &tmp)
+	B.Add(&p1.Y, &p1.X)
+	tmp.Add(&p2.Y, &p2.X)
+	B.Mul(&B, &tmp)
+	C.Mul(&p1.T, &p2.T).Mul(&C, &d2)
+	D.Mul(&p1.Z, &p2.Z).Double(&D)
+	E.Sub(&B, &A)
+	F.Sub(&D, &C)
+	G.Add(&D, &C)
+	H.Add(&B, &A)
+
+	p.X.Mul(&E, &F)
+	p.Y.Mul(&G, &H)
+	p.T.Mul(&E, &H)
+	p.Z.Mul(&F, &G)
+
+	return p
+}
+
+// Double doubles a point in extended coordinates
+// https://hyperelliptic.org/EFD/g1p/auto-twisted-extended-1.html#doubling-dbl-2008-hwcd
+func (p *PointExtended) Double(p1 *PointExtended) *PointExtended {
+	var A, B, C, D, E, F, G, H fp.Element
+
+	A.Square(&p1.X)
+	B.Square(&p1.Y)
+	C.Square(&p1.Z).
+		Double(&C)
+	D.Neg(&A)
+	E.Add(&p1.X, &p1.Y).
+		Square(&E).
+		Sub(&E, &A).
+		Sub(&E, &B)
+	G.Add(&D, &B)
+	F.Sub(&G, &C)
+	H.Sub(&D, &B)
+
+	p.X.Mul(&E, &F)
+	p.Y.Mul(&G, &H)
+	p.T.Mul(&H, &E)
+	p.Z.Mul(&F, &G)
+
+	return p
+}
+
+// MulByCofactor sets p to [8]p1 and returns it
+func (p *PointExtended) MulByCofactor(p1 *PointExtended) *PointExtended {
+	return p.Double(p1).Double(p).Double(p)
+}
+
+// setInfinity sets p to O (0:1:1:0)
+func (p *PointExtended) setInfinity() *PointExtended {
+	p.X.SetZero()
+	p.Y.SetOne()
+	p.Z.SetOne()
+	p.T.SetZero()
+	return p
+}
+
+// ScalarMultiplication scalar multiplication of a point
+// p1 in extended coordinates with a scalar in big.Int
+// using the double-and-add method.
+func (p *PointExtended) ScalarMultiplication(p1 *PointExtended, scalar *big.Int) *PointExtended {
+	var _scalar big.Int
+	_scalar.Set(scalar)
+	var base PointExtended
+	base.Set(p1)
+	if _scalar.Sign() == -1 {
+		_scalar.Neg(&_scalar)
+		base.Neg(&base)
+	}
+	var res PointExtended
+	res.setInfinity()
+	const wordSize = bits.UintSize
+	sWords := _scalar.Bits()
+
+	for i := len(sWords) - 1; i >= 0; i-- {
+		ithWord := sWords[i]
+		for k := 0; k < wordSize; k++ {
+			res.Double(&res)
+			kthBit := (ithWord >> (wordSize - 1 - k)) & 1
+			if kthBit == 1 {
+				res.Add(&res, &base)
+			}
+		}
+	}
+
+	p.Set(&res)
+	return p
+}
@@ -0,0 +1,234 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ed25519
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/ed25519/fr"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+const (
+	nbFuzzShort = 10
+	nbFuzz      = 100
+)
+
+func TestPointOps(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+	genS := GenBigInt()
+
+	properties.Property("(extended) P+(-P) should be 0", prop.ForAll(
+		func(s big.Int) bool {
+			var p, q PointExtended
+			p.fromScalar(&s)
+			q.Neg(&p).Add(&q, &p)
+			return q.IsZero()
+		},
+		genS,
+	))
+
+	properties.Property("(extended) 2P should equal P+P", prop.ForAll(
+		func(s big.Int) bool {
+			var p, p2, pp PointExtended
+			p.fromScalar(&s)
+			p2.Double(&p)
+			pp.Add(&p, &p)
+			return p2.Equal(&pp)
+		},
+		genS,
+	))
+
+	properties.Property("(extended) [s1]B+[s2]B should equal [s1+s2]B", prop.ForAll(
+		func(s1, s2 big.Int) bool {
+			var p1, p2, p3 PointExtended
+			var s3 big.Int
+			s3.Add(&s1, &s2)
+			p1.fromScalar(&s1)
+			p2.fromScalar(&s2)
+			p3.fromScalar(&s3)
+			p1.Add(&p1, &p2)
+			return p1.Equal(&p3)
+		},
+		genS,
+		genS,
+	))
+
+	properties.Property("(affine) [s]B should be in the prime order subgroup", prop.ForAll(
+		func(s big.Int) bool {
+			var p PointAffine
+			p.ScalarMultiplication(&curveParams.Base, &s)
+			return p.IsOnCurve() && p.IsInSubGroup()
+		},
+		genS,
+	))
+
+	properties.Property("(affine) SetBytes(Bytes()) should be the identity", prop.ForAll(
+		func(s big.Int) bool {
+			var p, q PointAffine
+			p.ScalarMultiplication(&curveParams.Base, &s)
+			b := p.Bytes()
+			if _, err := q.SetBytes(b[:]); err != nil {
+				return false
+			}
+			return p.Equal(&q)
+		},
+		genS,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestBase(t *testing.T) {
+	t.Parallel()
+	params := GetEdwardsCurve()
+	if !params.Base.IsOnCurve() {
+		t.Fatal("base point not on curve")
+	}
+	if !params.Base.IsInSubGroup() {
+		t.Fatal("base point not in the prime order subgroup")
+	}
+
+	// the base point encoding is 0x58666...66
+	b := params.Base.Bytes()
+	if b[0] != 0x58 {
+		t.Fatal("wrong base point encoding")
+	}
+	for i := 1; i < len(b); i++ {
+		if b[i] != 0x66 {
+			t.Fatal("wrong base point encoding")
+		}
+	}
+}
+
+func TestScalarMultiplicationMatchesStdlib(t *testing.T) {
+	t.Parallel()
+	params := GetEdwardsCurve()
+	for i := 0; i < 10; i++ {
+		seed := make([]byte, ed25519.SeedSize)
+		if _, err := rand.Read(seed); err != nil {
+			t.Fatal(err)
+		}
+		expected := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+
+		// https://tools.ietf.org/html/rfc8032#section-5.1.5
+		h := sha512.Sum512(seed)
+		h[0] &= 248
+		h[31] &= 127
+		h[31] |= 64
+		var s big.Int
+		s.SetBytes(reverse(h[:32]))
+
+		var a PointAffine
+		a.ScalarMultiplication(&params.Base, &s)
+		b := a.Bytes()
+		if string(b[:]) != string(expected) {
+			t.Fatalf("public key mismatch: %x != %x", b, expected)
+		}
+	}
+}
+
+func TestSetBytesInvalid(t *testing.T) {
+	t.Parallel()
+	var p PointAffine
+
+	// y = p is not canonical
+	var b [SizePointCompressed]byte
+	b[0] = 0xed
+	for i := 1; i < 31; i++ {
+		b[i] = 0xff
+	}
+	b[31] = 0x7f
+	if _, err := p.SetBytes(b[:]); err != ErrInvalidEncoding {
+		t.Fatal("non-canonical y should be rejected")
+	}
+
+	// y = 1, x = 0 with the parity bit set
+	b = [SizePointCompressed]byte{1}
+	b[31] = mParity
+	if _, err := p.SetBytes(b[:]); err != ErrInvalidEncoding {
+		t.Fatal("x = 0 with the parity bit set should be rejected")
+	}
+
+	// y = 2 is not the ordinate of a point
+	b = [SizePointCompressed]byte{2}
+	if _, err := p.SetBytes(b[:]); err != ErrInvalidEncoding {
+		t.Fatal("y = 2 should be rejected")
+	}
+
+	if _, err := p.SetBytes(b[:SizePointCompressed-1]); err == nil {
+		t.Fatal("short buffer should be rejected")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkScalarMulExtended(b *testing.B) {
+	params := GetEdwardsCurve()
+	var a PointExtended
+	var s big.Int
+	s.SetString("52435875175126190479447705081859658376581184513", 10)
+	a.FromAffine(&params.Base)
+
+	b.ResetTimer()
+	for j := 0; j < b.N; j++ {
+		a.ScalarMultiplication(&a, &s)
+	}
+}
+
+// ------------------------------------------------------------
+// utils
+
+// GenBigInt generates a big.Int smaller than the order of the subgroup
+func GenBigInt() gopter.Gen {
+	return func(genParams *gopter.GenParameters) *gopter.GenResult {
+		var s fr.Element
+		if _, err := s.SetRandom(); err != nil {
+			panic(err)
+		}
+		var res big.Int
+		s.BigInt(&res)
+		return gopter.NewGenResult(res, gopter.NoShrinker)
+	}
+}
+
+// fromScalar sets p to [s]B
+func (p *PointExtended) fromScalar(s *big.Int) *PointExtended {
+	params := GetEdwardsCurve()
+	p.FromAffine(&params.Base)
+	return p.ScalarMultiplication(p, s)
+}
+
+func reverse(b []byte) []byte {
+	res := make([]byte, len(b))
+	for i := range b {
+		res[len(b)-1-i] = b[i]
+	}
+	return res
+}
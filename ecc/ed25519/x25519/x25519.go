@@ -0,0 +1,134 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package x25519 implements the X25519 Diffie-Hellman function on Curve25519,
+// the Montgomery form of edwards25519, following RFC 7748.
+//
+// See https://tools.ietf.org/html/rfc7748#section-5
+//
+// # Warning
+//
+// This code has not been audited and is provided as-is. In particular, there is no security guarantees such as constant time implementation or side-channel attack resistance.
+package x25519
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/ed25519/fp"
+)
+
+// Size size in bytes of scalars and u-coordinates
+const Size = 32
+
+// Basepoint is the canonical Curve25519 generator, u = 9
+var Basepoint = []byte{9, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+
+var (
+	errWrongSize  = errors.New("x25519: wrong size buffer")
+	errLowOrderPt = errors.New("x25519: low order point")
+)
+
+// X25519 returns the u-coordinate of [scalar]P, where P has u-coordinate point.
+// Both inputs and the output are little endian on Size bytes.
+//
+// The scalar is clamped and the most significant bit of point is ignored, as in
+// RFC 7748. Following https://tools.ietf.org/html/rfc7748#section-6.1, an error
+// is returned if the result is zero, i.e. if point has a small order.
+func X25519(scalar, point []byte) ([]byte, error) {
+	if len(scalar) != Size || len(point) != Size {
+		return nil, errWrongSize
+	}
+
+	var k [Size]byte
+	copy(k[:], scalar)
+	k[0] &= 248
+	k[31] &= 127
+	k[31] |= 64
+
+	u := decodeU(point)
+	res := ladder(&k, &u)
+
+	var out [Size]byte
+	fp.LittleEndian.PutElement(&out, res)
+	var zero [Size]byte
+	if subtle.ConstantTimeCompare(out[:], zero[:]) == 1 {
+		return nil, errLowOrderPt
+	}
+	return out[:], nil
+}
+
+// decodeU decodes a u-coordinate: the most significant bit is masked, and
+// non-canonical values are reduced modulo p
+func decodeU(b []byte) fp.Element {
+	var be [Size]byte
+	for i := 0; i < Size; i++ {
+		be[i] = b[Size-1-i]
+	}
+	be[0] &= 0x7f
+	var u fp.Element
+	u.SetBytes(be[:])
+	return u
+}
+
+// ladder computes the u-coordinate of [k]P with the Montgomery ladder
+// https://tools.ietf.org/html/rfc7748#section-5
+func ladder(k *[Size]byte, u *fp.Element) fp.Element {
+	var x2, z2, x3, z3 fp.Element
+	x2.SetOne()
+	x3.Set(u)
+	z3.SetOne()
+
+	// a24 = (A-2)/4, with A = 486662
+	a24 := fp.NewElement(121665)
+
+	var A, AA, B, BB, E, C, D, DA, CB, tmp fp.Element
+	swap := 0
+	for t := 254; t >= 0; t-- {
+		kt := int(k[t/8]>>(t%8)) & 1
+		swap ^= kt
+		cswap(swap, &x2, &x3)
+		cswap(swap, &z2, &z3)
+		swap = kt
+
+		A.Add(&x2, &z2)
+		AA.Square(&A)
+		B.Sub(&x2, &z2)
+		BB.Square(&B)
+		E.Sub(&AA, &BB)
+		C.Add(&x3, &z3)
+		D.Sub(&x3, &z3)
+		DA.Mul(&D, &A)
+		CB.Mul(&C, &B)
+		x3.Add(&DA, &CB).Square(&x3)
+		z3.Sub(&DA, &CB).Square(&z3).Mul(&z3, u)
+		x2.Mul(&AA, &BB)
+		tmp.Mul(&a24, &E).Add(&tmp, &AA)
+		z2.Mul(&E, &tmp)
+	}
+	cswap(swap, &x2, &x3)
+	cswap(swap, &z2, &z3)
+
+	// z2 = 0 (P of small order) yields 0
+	z2.Inverse(&z2)
+	return *x2.Mul(&x2, &z2)
+}
+
+// cswap swaps a and b if swap = 1
+func cswap(swap int, a, b *fp.Element) {
+	var t0, t1 fp.Element
+	t0.Select(swap, a, b)
+	t1.Select(swap, b, a)
+	*a, *b = t0, t1
+}
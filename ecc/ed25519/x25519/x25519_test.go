@@ -0,0 +1,173 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package x25519
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// test vectors from https://tools.ietf.org/html/rfc7748#section-5.2
+func TestRFC7748(t *testing.T) {
+	vectors := []struct {
+		scalar, u, expected string
+	}{
+		{
+			"a546e36bf0527c9d3b16154b82465edd62144c0ac1fc5a18506a2244ba449ac4",
+			"e6db6867583030db3594c1a424b15f7c726624ec26b3353b10a903a6d0ab1c4c",
+			"c3da55379de9c6908e94ea4df28d084f32eccf03491c71f754b4075577a28552",
+		},
+		{
+			"4b66e9d4d1b4673c5ad22691957d6af5c11b6421e0ea01d42ca4169e7918ba0d",
+			"e5210f12786811d3f4b7959d0538ae2c31dbe7106fc03c3efc4cd549c715a493",
+			"95cbde9476e8907d7aade45cb4b873f88b595a68799fa152e6f8f7647aac7957",
+		},
+	}
+	for i, v := range vectors {
+		scalar, _ := hex.DecodeString(v.scalar)
+		u, _ := hex.DecodeString(v.u)
+		res, err := X25519(scalar, u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(res) != v.expected {
+			t.Fatalf("vector %d: expected %s, got %x", i, v.expected, res)
+		}
+	}
+}
+
+func TestRFC7748Iterated(t *testing.T) {
+	expected := map[int]string{
+		1:    "422c8e7a6227d7bca1350b3e2bb7279f7897b87bb6854b783c60e80311ae3079",
+		1000: "684cf59ba83309552800ef566f2f4d3c1c3887c49360e3875f2eb94d99532c51",
+	}
+	n := 1000
+	if testing.Short() {
+		n = 1
+	}
+	k := append([]byte{}, Basepoint...)
+	u := append([]byte{}, Basepoint...)
+	for i := 1; i <= n; i++ {
+		res, err := X25519(k, u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, k = k, res
+		if e, ok := expected[i]; ok && hex.EncodeToString(k) != e {
+			t.Fatalf("iteration %d: expected %s, got %x", i, e, k)
+		}
+	}
+}
+
+func TestStdlibInterop(t *testing.T) {
+	t.Parallel()
+	for i := 0; i < 10; i++ {
+		alice, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bob := make([]byte, Size)
+		rand.Read(bob)
+		bobPub, err := X25519(bob, Basepoint)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bobPubStd, err := ecdh.X25519().NewPublicKey(bobPub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := alice.ECDH(bobPubStd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shared, err := X25519(bob, alice.PublicKey().Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(shared, expected) {
+			t.Fatal("shared secret mismatch with crypto/ecdh")
+		}
+	}
+}
+
+// TestWycheproof checks a subset of the test vectors of
+// https://github.com/google/wycheproof (testvectors/x25519_test.json): public
+// keys of low order, non-canonical (u ≥ p) or on the twist, and edge cases of
+// the arithmetic. A shared secret of zero means a low order public key.
+func TestWycheproof(t *testing.T) {
+	t.Parallel()
+	var vectors []struct {
+		TcID    int    `json:"tcId"`
+		Comment string `json:"comment"`
+		Public  string `json:"public"`
+		Private string `json:"private"`
+		Shared  string `json:"shared"`
+	}
+	b, err := os.ReadFile("testdata/wycheproof_X25519.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	zero := make([]byte, Size)
+	for _, v := range vectors {
+		public, _ := hex.DecodeString(v.Public)
+		private, _ := hex.DecodeString(v.Private)
+		expected, _ := hex.DecodeString(v.Shared)
+		shared, err := X25519(private, public)
+		if bytes.Equal(expected, zero) {
+			if err != errLowOrderPt {
+				t.Fatalf("tcId %d (%s): low order public key accepted", v.TcID, v.Comment)
+			}
+			continue
+		}
+		if err != nil || !bytes.Equal(shared, expected) {
+			t.Fatalf("tcId %d (%s): shared secret mismatch", v.TcID, v.Comment)
+		}
+	}
+}
+
+func TestLowOrderPoint(t *testing.T) {
+	t.Parallel()
+	scalar := make([]byte, Size)
+	rand.Read(scalar)
+
+	// u = 0 and u = 1 are points of small order
+	for _, u := range [][]byte{make([]byte, Size), append([]byte{1}, make([]byte, Size-1)...)} {
+		if _, err := X25519(scalar, u); err != errLowOrderPt {
+			t.Fatalf("low order point %x accepted", u)
+		}
+	}
+
+	if _, err := X25519(scalar[1:], Basepoint); err != errWrongSize {
+		t.Fatal("short scalar accepted")
+	}
+}
+
+func BenchmarkX25519(b *testing.B) {
+	scalar := make([]byte, Size)
+	rand.Read(scalar)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		X25519(scalar, Basepoint)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grumpkin efficient elliptic curve implementation for Grumpkin. BN254 and Grumpkin form a cycle of curves: the base field of Grumpkin is the scalar field of BN254 and its scalar field is the base field of BN254. It is used to recurse over BN254 SNARKs.
+//
+// The field elements are the ones of the bn254 packages: fp.Element is
+// bn254/fr.Element and fr.Element is bn254/fp.Element.
+//
+// grumpkin: A j=0 curve with
+//
+//	𝔽r: r=21888242871839275222246405745257275088696311157297823662689037894645226208583
+//	𝔽p: p=21888242871839275222246405745257275088548364400416034343698204186575808495617 (0x30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001)
+//	(E/𝔽p): Y²=X³-17
+//
+// Security: estimated 127-bit level using Pollard's \rho attack
+// (r is 254 bits)
+//
+// # Warning
+//
+// This code has not been audited and is provided as-is. In particular, there is no security guarantees such as constant time implementation or side-channel attack resistance.
+package grumpkin
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr "github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	fp "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// ID grumpkin ID
+const ID = ecc.GRUMPKIN
+
+// aCurveCoeff is the a coefficients of the curve Y²=X³+ax+b
+var aCurveCoeff fp.Element
+var bCurveCoeff fp.Element
+
+// generator of the r-torsion group
+var g1Gen G1Jac
+
+var g1GenAff G1Affine
+
+// point at infinity
+var g1Infinity G1Jac
+
+// Parameters useful for the GLV scalar multiplication. The third roots define the
+// endomorphisms ϕ₁ for <G1Affine>. lambda is such that <r, ϕ-λ> lies above
+// <r> in the ring Z[ϕ]. More concretely it's the associated eigenvalue
+// of ϕ₁ restricted to <G1Affine>
+// see https://www.cosic.esat.kuleuven.be/nessie/reports/phase2/GLV.pdf
+var thirdRootOneG1 fp.Element
+var lambdaGLV big.Int
+
+// glvBasis stores R-linearly independent vectors (a,b), (c,d)
+// in ker((u,v) → u+vλ[r]), and their determinant
+var glvBasis ecc.Lattice
+
+func init() {
+	aCurveCoeff.SetUint64(0)
+	bCurveCoeff.SetInt64(-17)
+
+	// (1, √-16)
+	g1Gen.X.SetOne()
+	g1Gen.Y.SetString("17631683881184975370165255887551781615748388533673675138860")
+	g1Gen.Z.SetOne()
+
+	g1GenAff.FromJacobian(&g1Gen)
+
+	// (X,Y,Z) = (1,1,0)
+	g1Infinity.X.SetOne()
+	g1Infinity.Y.SetOne()
+
+	thirdRootOneG1.SetString("4407920970296243842393367215006156084916469457145843978461") // 3^((p-1)/3)
+	lambdaGLV.SetString("2203960485148121921418603742825762020974279258880205651966", 10)  // 9^((r-1)/3)
+	_r := fr.Modulus()
+	ecc.PrecomputeLattice(_r, &lambdaGLV, &glvBasis)
+
+}
+
+// Generators return the generators of the r-torsion group, resp. in ker(pi-id), ker(Tr)
+func Generators() (g1Jac G1Jac, g1Aff G1Affine) {
+	g1Aff = g1GenAff
+	g1Jac = g1Gen
+	return
+}
+
+// CurveCoefficients returns the a, b coefficients of the curve equation.
+func CurveCoefficients() (a, b fp.Element) {
+	return aCurveCoeff, bCurveCoeff
+}
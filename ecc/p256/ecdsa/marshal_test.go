@@ -0,0 +1,197 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+const (
+	nbFuzzShort = 10
+	nbFuzz      = 100
+)
+
+func TestSerialization(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[P256] ECDSA serialization: SetBytes(Bytes()) should stay the same", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+
+			var end PrivateKey
+			buf := privKey.Bytes()
+			n, err := end.SetBytes(buf[:])
+			if err != nil {
+				return false
+			}
+			if n != sizePrivateKey {
+				return false
+			}
+
+			return end.PublicKey.Equal(&privKey.PublicKey) && subtle.ConstantTimeCompare(end.scalar[:], privKey.scalar[:]) == 1
+
+		},
+	))
+
+	properties.Property("[P256] ECDSA serialization: PublicKey.SetBytes should return the number of bytes read", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+
+			// the public key followed by a signature, as in a serialized transaction
+			buf := privKey.PublicKey.Bytes()
+			sig, _ := privKey.Sign([]byte("testing ECDSA"), nil)
+			buf = append(buf, sig...)
+
+			var pk PublicKey
+			n, err := pk.SetBytes(buf)
+			if err != nil || n != sizePublicKey || n != len(privKey.PublicKey.Bytes()) {
+				return false
+			}
+			var s Signature
+			if _, err := s.SetBytes(buf[n:]); err != nil {
+				return false
+			}
+			return pk.Equal(&privKey.PublicKey) && subtle.ConstantTimeCompare(s.Bytes(), sig) == 1
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestStandardEncodings(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[P256] SEC1, PKIX and PKCS#8 encodings should round trip", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+
+			var sec1, pkcs8 PrivateKey
+			if _, err := sec1.SetBytesSEC1(privKey.BytesSEC1()); err != nil {
+				return false
+			}
+			if _, err := pkcs8.SetBytesPKCS8(privKey.BytesPKCS8()); err != nil {
+				return false
+			}
+			var compressed, uncompressed, pkix PublicKey
+			if n, err := compressed.SetBytesSEC1(privKey.PublicKey.BytesSEC1(true)); err != nil || n != 1+sizeFp {
+				return false
+			}
+			if n, err := uncompressed.SetBytesSEC1(privKey.PublicKey.BytesSEC1(false)); err != nil || n != 1+2*sizeFp {
+				return false
+			}
+			if _, err := pkix.SetBytesPKIX(privKey.PublicKey.BytesPKIX()); err != nil {
+				return false
+			}
+
+			return bytes.Equal(sec1.Bytes(), privKey.Bytes()) &&
+				bytes.Equal(pkcs8.Bytes(), privKey.Bytes()) &&
+				compressed.Equal(&privKey.PublicKey) &&
+				uncompressed.Equal(&privKey.PublicKey) &&
+				pkix.Equal(&privKey.PublicKey)
+		},
+	))
+
+	properties.Property("[P256] DER signatures should round trip", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			sigBin, _ := privKey.Sign([]byte("testing DER"), nil)
+
+			var sig, end Signature
+			if _, err := sig.SetBytes(sigBin); err != nil {
+				return false
+			}
+			der := sig.BytesDER()
+			if n, err := end.SetBytesDER(der); err != nil || n != len(der) {
+				return false
+			}
+			return bytes.Equal(end.Bytes(), sigBin)
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestStandardEncodingsErrors(t *testing.T) {
+	t.Parallel()
+
+	derSignature := func(r, s *big.Int) []byte {
+		res, err := asn1.Marshal(ecdsaSignature{R: r, S: s})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	var sig Signature
+	if _, err := sig.SetBytesDER(derSignature(big.NewInt(0), big.NewInt(1))); err != errZero {
+		t.Fatal("DER signature with r = 0 should be rejected")
+	}
+	if _, err := sig.SetBytesDER(derSignature(big.NewInt(1), big.NewInt(-1))); err != errInvalidEncoding {
+		t.Fatal("DER signature with s < 0 should be rejected")
+	}
+	if _, err := sig.SetBytesDER(derSignature(order, big.NewInt(1))); err != errRBiggerThanRMod {
+		t.Fatal("DER signature with r >= r_mod should be rejected")
+	}
+	if _, err := sig.SetBytesDER(derSignature(big.NewInt(1), order)); err != errSBiggerThanRMod {
+		t.Fatal("DER signature with s >= r_mod should be rejected")
+	}
+
+	privKey, _ := GenerateKey(rand.Reader)
+	buf := privKey.PublicKey.BytesSEC1(false)
+	var publicKey PublicKey
+	buf[0] = 0x05
+	if _, err := publicKey.SetBytesSEC1(buf); err != errInvalidEncoding {
+		t.Fatal("SEC1 public key with a wrong prefix should be rejected")
+	}
+	buf[0] = sec1Uncompressed
+	buf[len(buf)-1] ^= 1
+	if _, err := publicKey.SetBytesSEC1(buf); err != errNotOnCurve {
+		t.Fatal("SEC1 public key not on the curve should be rejected")
+	}
+
+	zero := ecPrivateKey{Version: ecPrivKeyVersion, PrivateKey: make([]byte, sizeFr)}
+	zeroBin, err := asn1.Marshal(zero)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := privKey.SetBytesSEC1(zeroBin); err != errInvalidSecretKey {
+		t.Fatal("SEC1 private key with a zero scalar should be rejected")
+	}
+}
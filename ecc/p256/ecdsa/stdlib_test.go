@@ -0,0 +1,173 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecdsa
+
+import (
+	"bytes"
+	stdecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"math/big"
+	"testing"
+)
+
+func TestStdlibInterop(t *testing.T) {
+	t.Parallel()
+	curve := elliptic.P256()
+	msg := []byte("testing ECDSA interoperability with crypto/ecdsa")
+	digest := sha256.Sum256(msg)
+
+	// gnark-crypto signs, crypto/ecdsa verifies
+	for i := 0; i < 10; i++ {
+		privKey, err := GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pkBytes := privKey.PublicKey.Bytes()
+		x, y := elliptic.UnmarshalCompressed(curve, pkBytes)
+		if x == nil {
+			t.Fatal("crypto/elliptic rejected the compressed public key")
+		}
+		rawBytes := privKey.PublicKey.A.RawBytes()
+		if !bytes.Equal(rawBytes[:], elliptic.Marshal(curve, x, y)) {
+			t.Fatal("uncompressed encodings differ")
+		}
+
+		sig, err := privKey.Sign(msg, sha256.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := new(big.Int).SetBytes(sig[:sizeFr])
+		s := new(big.Int).SetBytes(sig[sizeFr:])
+		if !stdecdsa.Verify(&stdecdsa.PublicKey{Curve: curve, X: x, Y: y}, digest[:], r, s) {
+			t.Fatal("crypto/ecdsa rejected the signature")
+		}
+	}
+
+	// crypto/ecdsa signs, gnark-crypto verifies
+	for i := 0; i < 10; i++ {
+		stdKey, err := stdecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var publicKey PublicKey
+		if _, err := publicKey.SetBytes(elliptic.MarshalCompressed(curve, stdKey.X, stdKey.Y)); err != nil {
+			t.Fatal(err)
+		}
+		r, s, err := stdecdsa.Sign(rand.Reader, stdKey, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig := make([]byte, sizeSignature)
+		r.FillBytes(sig[:sizeFr])
+		s.FillBytes(sig[sizeFr:])
+		ok, err := publicKey.Verify(sig, msg, sha256.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("the signature of crypto/ecdsa was rejected")
+		}
+	}
+}
+
+func TestX509Interop(t *testing.T) {
+	t.Parallel()
+	msg := []byte("testing key encodings interoperability with crypto/x509")
+	digest := sha256.Sum256(msg)
+
+	for i := 0; i < 10; i++ {
+		// fixtures produced by crypto/x509
+		stdKey, err := stdecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sec1, err := x509.MarshalECPrivateKey(stdKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pkcs8, err := x509.MarshalPKCS8PrivateKey(stdKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pkix, err := x509.MarshalPKIXPublicKey(&stdKey.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der, err := stdecdsa.SignASN1(rand.Reader, stdKey, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var privKey, privKey2 PrivateKey
+		if _, err := privKey.SetBytesSEC1(sec1); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(privKey.BytesSEC1(), sec1) {
+			t.Fatal("SEC1 private key round trip failed")
+		}
+		if _, err := privKey2.SetBytesPKCS8(pkcs8); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(privKey2.BytesPKCS8(), pkcs8) {
+			t.Fatal("PKCS#8 private key round trip failed")
+		}
+		if !bytes.Equal(privKey.Bytes(), privKey2.Bytes()) {
+			t.Fatal("SEC1 and PKCS#8 private keys differ")
+		}
+
+		var publicKey PublicKey
+		if _, err := publicKey.SetBytesPKIX(pkix); err != nil {
+			t.Fatal(err)
+		}
+		if !publicKey.Equal(&privKey.PublicKey) {
+			t.Fatal("wrong public key")
+		}
+		if !bytes.Equal(publicKey.BytesPKIX(), pkix) {
+			t.Fatal("PKIX public key round trip failed")
+		}
+		if !bytes.Equal(publicKey.BytesSEC1(false), elliptic.Marshal(elliptic.P256(), stdKey.X, stdKey.Y)) {
+			t.Fatal("wrong SEC1 uncompressed public key")
+		}
+		if !bytes.Equal(publicKey.BytesSEC1(true), elliptic.MarshalCompressed(elliptic.P256(), stdKey.X, stdKey.Y)) {
+			t.Fatal("wrong SEC1 compressed public key")
+		}
+
+		var sig Signature
+		if _, err := sig.SetBytesDER(der); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sig.BytesDER(), der) {
+			t.Fatal("DER signature round trip failed")
+		}
+		if ok, err := publicKey.Verify(sig.Bytes(), msg, sha256.New()); err != nil || !ok {
+			t.Fatal("the DER signature of crypto/ecdsa was rejected")
+		}
+
+		// and the other way around
+		sigBin, err := privKey.Sign(msg, sha256.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sig.SetBytes(sigBin); err != nil {
+			t.Fatal(err)
+		}
+		if !stdecdsa.VerifyASN1(&stdKey.PublicKey, digest[:], sig.BytesDER()) {
+			t.Fatal("crypto/ecdsa rejected the DER signature")
+		}
+	}
+}
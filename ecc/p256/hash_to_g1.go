@@ -0,0 +1,221 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package p256
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/p256/fp"
+
+	"math/big"
+)
+
+//Note: This only works for simple extensions
+
+// g1SqrtRatio computes the square root of u/v and returns 0 iff u/v was indeed a quadratic residue
+// if not, we get sqrt(Z * u / v). Recall that Z is non-residue
+// If v = 0, u/v is meaningless and the output is unspecified, without raising an error.
+// The main idea is that since the computation of the square root involves taking large powers of u/v, the inversion of v can be avoided
+func g1SqrtRatio(z *fp.Element, u *fp.Element, v *fp.Element) uint64 {
+	// https://www.ietf.org/archive/id/draft-irtf-cfrg-hash-to-curve-16.html#name-optimized-sqrt_ratio-for-q- (3 mod 4)
+	var tv1 fp.Element
+	tv1.Square(v) // 1. tv1 = v²
+	var tv2 fp.Element
+	tv2.Mul(u, v)       // 2. tv2 = u * v
+	tv1.Mul(&tv1, &tv2) // 3. tv1 = tv1 * tv2
+
+	var y1 fp.Element
+	{
+		var c1 big.Int
+		// c1 = 28948022302589062190674361737351893382521535853822578548883407827216774463487
+		c1.SetBytes([]byte{63, 255, 255, 255, 192, 0, 0, 0, 64, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 63, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255}) // c1 = (q - 3) / 4     # Integer arithmetic
+
+		y1.Exp(tv1, &c1) // 4. y1 = tv1ᶜ¹
+	}
+
+	y1.Mul(&y1, &tv2) // 5. y1 = y1 * tv2
+
+	var y2 fp.Element
+	// c2 = sqrt(-Z)
+	tv3 := fp.Element{11672548406628292093, 8664937602706427760, 7571615917707681774, 10399324384776483588}
+	y2.Mul(&y1, &tv3)              // 6. y2 = y1 * c2
+	tv3.Square(&y1)                // 7. tv3 = y1²
+	tv3.Mul(&tv3, v)               // 8. tv3 = tv3 * v
+	isQNr := tv3.NotEqual(u)       // 9. isQR = tv3 == u
+	z.Select(int(isQNr), &y1, &y2) // 10. y = CMOV(y2, y1, isQR)
+	return isQNr
+}
+
+// g1MulByZ multiplies x by [-10] and stores the result in z
+func g1MulByZ(z *fp.Element, x *fp.Element) {
+
+	var res fp.Element
+	res.Neg(x)
+
+	res.Double(&res)
+	res.Double(&res)
+	res.Sub(&res, x)
+	res.Double(&res)
+
+	*z = res
+}
+
+// https://www.ietf.org/archive/id/draft-irtf-cfrg-hash-to-curve-16.html#name-simplified-swu-method
+// MapToCurve1 implements the SSWU map
+// No cofactor clearing or isogeny
+func MapToCurve1(u *fp.Element) G1Affine {
+
+	var tv1 fp.Element
+	tv1.Square(u) // 1.  tv1 = u²
+
+	//mul tv1 by Z
+	g1MulByZ(&tv1, &tv1) // 2.  tv1 = Z * tv1
+
+	var tv2 fp.Element
+	tv2.Square(&tv1)    // 3.  tv2 = tv1²
+	tv2.Add(&tv2, &tv1) // 4.  tv2 = tv2 + tv1
+
+	var tv3 fp.Element
+	var tv4 fp.Element
+	tv4.SetOne()
+	tv3.Add(&tv2, &tv4)         // 5.  tv3 = tv2 + 1
+	tv3.Mul(&tv3, &bCurveCoeff) // 6.  tv3 = B * tv3
+
+	tv2NZero := g1NotZero(&tv2)
+
+	// tv4 = Z
+	tv4 = fp.Element{18446744073709551605, 47244640255, 0, 18446744026464911371}
+
+	tv2.Neg(&tv2)
+	tv4.Select(int(tv2NZero), &tv4, &tv2) // 7.  tv4 = CMOV(Z, -tv2, tv2 != 0)
+	tv4.Mul(&tv4, &aCurveCoeff)           // 8.  tv4 = A * tv4
+
+	tv2.Square(&tv3) // 9.  tv2 = tv3²
+
+	var tv6 fp.Element
+	tv6.Square(&tv4) // 10. tv6 = tv4²
+
+	var tv5 fp.Element
+	tv5.Mul(&tv6, &aCurveCoeff) // 11. tv5 = A * tv6
+
+	tv2.Add(&tv2, &tv5) // 12. tv2 = tv2 + tv5
+	tv2.Mul(&tv2, &tv3) // 13. tv2 = tv2 * tv3
+	tv6.Mul(&tv6, &tv4) // 14. tv6 = tv6 * tv4
+
+	tv5.Mul(&tv6, &bCurveCoeff) // 15. tv5 = B * tv6
+	tv2.Add(&tv2, &tv5)         // 16. tv2 = tv2 + tv5
+
+	var x fp.Element
+	x.Mul(&tv1, &tv3) // 17.   x = tv1 * tv3
+
+	var y1 fp.Element
+	gx1NSquare := g1SqrtRatio(&y1, &tv2, &tv6) // 18. (is_gx1_square, y1) = sqrt_ratio(tv2, tv6)
+
+	var y fp.Element
+	y.Mul(&tv1, u) // 19.   y = tv1 * u
+
+	y.Mul(&y, &y1) // 20.   y = y * y1
+
+	x.Select(int(gx1NSquare), &tv3, &x) // 21.   x = CMOV(x, tv3, is_gx1_square)
+	y.Select(int(gx1NSquare), &y1, &y)  // 22.   y = CMOV(y, y1, is_gx1_square)
+
+	y1.Neg(&y)
+	y.Select(int(g1Sgn0(u)^g1Sgn0(&y)), &y, &y1)
+
+	// 23.  e1 = sgn0(u) == sgn0(y)
+	// 24.   y = CMOV(-y, y, e1)
+
+	x.Div(&x, &tv4) // 25.   x = x / tv4
+
+	return G1Affine{x, y}
+}
+
+func g1EvalPolynomial(z *fp.Element, monic bool, coefficients []fp.Element, x *fp.Element) {
+	dst := coefficients[len(coefficients)-1]
+
+	if monic {
+		dst.Add(&dst, x)
+	}
+
+	for i := len(coefficients) - 2; i >= 0; i-- {
+		dst.Mul(&dst, x)
+		dst.Add(&dst, &coefficients[i])
+	}
+
+	z.Set(&dst)
+}
+
+// g1Sgn0 is an algebraic substitute for the notion of sign in ordered fields
+// Namely, every non-zero quadratic residue in a finite field of characteristic =/= 2 has exactly two square roots, one of each sign
+// https://www.ietf.org/archive/id/draft-irtf-cfrg-hash-to-curve-16.html#name-the-sgn0-function
+// The sign of an element is not obviously related to that of its Montgomery form
+func g1Sgn0(z *fp.Element) uint64 {
+
+	nonMont := z.Bits()
+
+	// m == 1
+	return nonMont[0] % 2
+
+}
+
+// MapToG1 invokes the SSWU map, and guarantees that the result is in g1
+func MapToG1(u fp.Element) G1Affine {
+	res := MapToCurve1(&u)
+	return res
+}
+
+// EncodeToG1 hashes a message to a point on the G1 curve using the SSWU map.
+// It is faster than HashToG1, but the result is not uniformly distributed. Unsuitable as a random oracle.
+// dst stands for "domain separation tag", a string unique to the construction using the hash function
+// https://www.ietf.org/archive/id/draft-irtf-cfrg-hash-to-curve-16.html#roadmap
+func EncodeToG1(msg, dst []byte) (G1Affine, error) {
+
+	var res G1Affine
+	u, err := fp.Hash(msg, dst, 1)
+	if err != nil {
+		return res, err
+	}
+
+	res = MapToCurve1(&u[0])
+
+	return res, nil
+}
+
+// HashToG1 hashes a message to a point on the G1 curve using the SSWU map.
+// Slower than EncodeToG1, but usable as a random oracle.
+// dst stands for "domain separation tag", a string unique to the construction using the hash function
+// https://www.ietf.org/archive/id/draft-irtf-cfrg-hash-to-curve-16.html#roadmap
+func HashToG1(msg, dst []byte) (G1Affine, error) {
+	u, err := fp.Hash(msg, dst, 2*1)
+	if err != nil {
+		return G1Affine{}, err
+	}
+
+	Q0 := MapToCurve1(&u[0])
+	Q1 := MapToCurve1(&u[1])
+
+	var _Q0, _Q1 G1Jac
+	_Q0.FromAffine(&Q0)
+	_Q1.FromAffine(&Q1).AddAssign(&_Q0)
+
+	Q1.FromJacobian(&_Q1)
+	return Q1, nil
+}
+
+func g1NotZero(x *fp.Element) uint64 {
+
+	return x[0] | x[1] | x[2] | x[3]
+
+}
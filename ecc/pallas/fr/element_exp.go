@@ -0,0 +1,425 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fr
+
+// expBySqrtExp is equivalent to z.Exp(x, 2000000000000000000000000000000011234c7e04ca546ec6237590)
+//
+// uses github.com/mmcloughlin/addchain v0.4.0 to generate a shorter addition chain
+func (z *Element) expBySqrtExp(x Element) *Element {
+	// addition chain:
+	//
+	//	_10       = 2*1
+	//	_100      = 2*_10
+	//	_1000     = 2*_100
+	//	_1001     = 1 + _1000
+	//	_1100     = _100 + _1000
+	//	_10001    = _1000 + _1001
+	//	_10010    = 1 + _10001
+	//	_10011    = 1 + _10010
+	//	_10110    = _100 + _10010
+	//	_11000    = _10 + _10110
+	//	_100011   = _10001 + _10010
+	//	_101001   = _10001 + _11000
+	//	_111111   = _10110 + _101001
+	//	_1010001  = _10010 + _111111
+	//	_1011101  = _1100 + _1010001
+	//	_10000000 = _100011 + _1011101
+	//	i158      = ((_10000000 << 126 + _10001) << 8 + _100011) << 6
+	//	i181      = ((_10011 + i158) << 9 + _111111) << 11 + _10011
+	//	i206      = ((i181 << 8 + _101001) << 8 + _1010001) << 7
+	//	i224      = ((_1011101 + i206) << 6 + _100011) << 9 + _100011
+	//	i239      = (i224 << 8 + _11000 + _1011101) << 4 + _1001
+	//	return      i239 << 4
+	//
+	// Operations: 217 squares 26 multiplies
+
+	// Allocate Temporaries.
+	var (
+		t0 = new(Element)
+		t1 = new(Element)
+		t2 = new(Element)
+		t3 = new(Element)
+		t4 = new(Element)
+		t5 = new(Element)
+		t6 = new(Element)
+		t7 = new(Element)
+		t8 = new(Element)
+	)
+
+	// var t0,t1,t2,t3,t4,t5,t6,t7,t8 Element
+	// Step 1: t1 = x^0x2
+	t1.Square(&x)
+
+	// Step 2: t2 = x^0x4
+	t2.Square(t1)
+
+	// Step 3: t3 = x^0x8
+	t3.Square(t2)
+
+	// Step 4: z = x^0x9
+	z.Mul(&x, t3)
+
+	// Step 5: t0 = x^0xc
+	t0.Mul(t2, t3)
+
+	// Step 6: t7 = x^0x11
+	t7.Mul(t3, z)
+
+	// Step 7: t3 = x^0x12
+	t3.Mul(&x, t7)
+
+	// Step 8: t5 = x^0x13
+	t5.Mul(&x, t3)
+
+	// Step 9: t6 = x^0x16
+	t6.Mul(t2, t3)
+
+	// Step 10: t1 = x^0x18
+	t1.Mul(t1, t6)
+
+	// Step 11: t2 = x^0x23
+	t2.Mul(t7, t3)
+
+	// Step 12: t4 = x^0x29
+	t4.Mul(t7, t1)
+
+	// Step 13: t6 = x^0x3f
+	t6.Mul(t6, t4)
+
+	// Step 14: t3 = x^0x51
+	t3.Mul(t3, t6)
+
+	// Step 15: t0 = x^0x5d
+	t0.Mul(t0, t3)
+
+	// Step 16: t8 = x^0x80
+	t8.Mul(t2, t0)
+
+	// Step 142: t8 = x^0x2000000000000000000000000000000000
+	for s := 0; s < 126; s++ {
+		t8.Square(t8)
+	}
+
+	// Step 143: t7 = x^0x2000000000000000000000000000000011
+	t7.Mul(t7, t8)
+
+	// Step 151: t7 = x^0x200000000000000000000000000000001100
+	for s := 0; s < 8; s++ {
+		t7.Square(t7)
+	}
+
+	// Step 152: t7 = x^0x200000000000000000000000000000001123
+	t7.Mul(t2, t7)
+
+	// Step 158: t7 = x^0x80000000000000000000000000000000448c0
+	for s := 0; s < 6; s++ {
+		t7.Square(t7)
+	}
+
+	// Step 159: t7 = x^0x80000000000000000000000000000000448d3
+	t7.Mul(t5, t7)
+
+	// Step 168: t7 = x^0x100000000000000000000000000000000891a600
+	for s := 0; s < 9; s++ {
+		t7.Square(t7)
+	}
+
+	// Step 169: t6 = x^0x100000000000000000000000000000000891a63f
+	t6.Mul(t6, t7)
+
+	// Step 180: t6 = x^0x80000000000000000000000000000000448d31f800
+	for s := 0; s < 11; s++ {
+		t6.Square(t6)
+	}
+
+	// Step 181: t5 = x^0x80000000000000000000000000000000448d31f813
+	t5.Mul(t5, t6)
+
+	// Step 189: t5 = x^0x80000000000000000000000000000000448d31f81300
+	for s := 0; s < 8; s++ {
+		t5.Square(t5)
+	}
+
+	// Step 190: t4 = x^0x80000000000000000000000000000000448d31f81329
+	t4.Mul(t4, t5)
+
+	// Step 198: t4 = x^0x80000000000000000000000000000000448d31f8132900
+	for s := 0; s < 8; s++ {
+		t4.Square(t4)
+	}
+
+	// Step 199: t3 = x^0x80000000000000000000000000000000448d31f8132951
+	t3.Mul(t3, t4)
+
+	// Step 206: t3 = x^0x40000000000000000000000000000000224698fc0994a880
+	for s := 0; s < 7; s++ {
+		t3.Square(t3)
+	}
+
+	// Step 207: t3 = x^0x40000000000000000000000000000000224698fc0994a8dd
+	t3.Mul(t0, t3)
+
+	// Step 213: t3 = x^0x100000000000000000000000000000000891a63f02652a3740
+	for s := 0; s < 6; s++ {
+		t3.Square(t3)
+	}
+
+	// Step 214: t3 = x^0x100000000000000000000000000000000891a63f02652a3763
+	t3.Mul(t2, t3)
+
+	// Step 223: t3 = x^0x2000000000000000000000000000000011234c7e04ca546ec600
+	for s := 0; s < 9; s++ {
+		t3.Square(t3)
+	}
+
+	// Step 224: t2 = x^0x2000000000000000000000000000000011234c7e04ca546ec623
+	t2.Mul(t2, t3)
+
+	// Step 232: t2 = x^0x2000000000000000000000000000000011234c7e04ca546ec62300
+	for s := 0; s < 8; s++ {
+		t2.Square(t2)
+	}
+
+	// Step 233: t1 = x^0x2000000000000000000000000000000011234c7e04ca546ec62318
+	t1.Mul(t1, t2)
+
+	// Step 234: t0 = x^0x2000000000000000000000000000000011234c7e04ca546ec62375
+	t0.Mul(t0, t1)
+
+	// Step 238: t0 = x^0x2000000000000000000000000000000011234c7e04ca546ec623750
+	for s := 0; s < 4; s++ {
+		t0.Square(t0)
+	}
+
+	// Step 239: z = x^0x2000000000000000000000000000000011234c7e04ca546ec623759
+	z.Mul(z, t0)
+
+	// Step 243: z = x^0x2000000000000000000000000000000011234c7e04ca546ec6237590
+	for s := 0; s < 4; s++ {
+		z.Square(z)
+	}
+
+	return z
+}
+
+// expByLegendreExp is equivalent to z.Exp(x, 2000000000000000000000000000000011234c7e04ca546ec623759080000000)
+//
+// uses github.com/mmcloughlin/addchain v0.4.0 to generate a shorter addition chain
+func (z *Element) expByLegendreExp(x Element) *Element {
+	// addition chain:
+	//
+	//	_10       = 2*1
+	//	_100      = 2*_10
+	//	_1000     = 2*_100
+	//	_1001     = 1 + _1000
+	//	_1100     = _100 + _1000
+	//	_10001    = _1000 + _1001
+	//	_10010    = 1 + _10001
+	//	_10011    = 1 + _10010
+	//	_10110    = _100 + _10010
+	//	_11000    = _10 + _10110
+	//	_100011   = _10001 + _10010
+	//	_101001   = _10001 + _11000
+	//	_111111   = _10110 + _101001
+	//	_1010001  = _10010 + _111111
+	//	_1011101  = _1100 + _1010001
+	//	_10000000 = _100011 + _1011101
+	//	i158      = ((_10000000 << 126 + _10001) << 8 + _100011) << 6
+	//	i181      = ((_10011 + i158) << 9 + _111111) << 11 + _10011
+	//	i206      = ((i181 << 8 + _101001) << 8 + _1010001) << 7
+	//	i224      = ((_1011101 + i206) << 6 + _100011) << 9 + _100011
+	//	i239      = (i224 << 8 + _11000 + _1011101) << 4 + _1001
+	//	return      (i239 << 5 + 1) << 31
+	//
+	// Operations: 249 squares 27 multiplies
+
+	// Allocate Temporaries.
+	var (
+		t0 = new(Element)
+		t1 = new(Element)
+		t2 = new(Element)
+		t3 = new(Element)
+		t4 = new(Element)
+		t5 = new(Element)
+		t6 = new(Element)
+		t7 = new(Element)
+		t8 = new(Element)
+	)
+
+	// var t0,t1,t2,t3,t4,t5,t6,t7,t8 Element
+	// Step 1: t1 = x^0x2
+	t1.Square(&x)
+
+	// Step 2: t2 = x^0x4
+	t2.Square(t1)
+
+	// Step 3: t3 = x^0x8
+	t3.Square(t2)
+
+	// Step 4: z = x^0x9
+	z.Mul(&x, t3)
+
+	// Step 5: t0 = x^0xc
+	t0.Mul(t2, t3)
+
+	// Step 6: t7 = x^0x11
+	t7.Mul(t3, z)
+
+	// Step 7: t3 = x^0x12
+	t3.Mul(&x, t7)
+
+	// Step 8: t5 = x^0x13
+	t5.Mul(&x, t3)
+
+	// Step 9: t6 = x^0x16
+	t6.Mul(t2, t3)
+
+	// Step 10: t1 = x^0x18
+	t1.Mul(t1, t6)
+
+	// Step 11: t2 = x^0x23
+	t2.Mul(t7, t3)
+
+	// Step 12: t4 = x^0x29
+	t4.Mul(t7, t1)
+
+	// Step 13: t6 = x^0x3f
+	t6.Mul(t6, t4)
+
+	// Step 14: t3 = x^0x51
+	t3.Mul(t3, t6)
+
+	// Step 15: t0 = x^0x5d
+	t0.Mul(t0, t3)
+
+	// Step 16: t8 = x^0x80
+	t8.Mul(t2, t0)
+
+	// Step 142: t8 = x^0x2000000000000000000000000000000000
+	for s := 0; s < 126; s++ {
+		t8.Square(t8)
+	}
+
+	// Step 143: t7 = x^0x2000000000000000000000000000000011
+	t7.Mul(t7, t8)
+
+	// Step 151: t7 = x^0x200000000000000000000000000000001100
+	for s := 0; s < 8; s++ {
+		t7.Square(t7)
+	}
+
+	// Step 152: t7 = x^0x200000000000000000000000000000001123
+	t7.Mul(t2, t7)
+
+	// Step 158: t7 = x^0x80000000000000000000000000000000448c0
+	for s := 0; s < 6; s++ {
+		t7.Square(t7)
+	}
+
+	// Step 159: t7 = x^0x80000000000000000000000000000000448d3
+	t7.Mul(t5, t7)
+
+	// Step 168: t7 = x^0x100000000000000000000000000000000891a600
+	for s := 0; s < 9; s++ {
+		t7.Square(t7)
+	}
+
+	// Step 169: t6 = x^0x100000000000000000000000000000000891a63f
+	t6.Mul(t6, t7)
+
+	// Step 180: t6 = x^0x80000000000000000000000000000000448d31f800
+	for s := 0; s < 11; s++ {
+		t6.Square(t6)
+	}
+
+	// Step 181: t5 = x^0x80000000000000000000000000000000448d31f813
+	t5.Mul(t5, t6)
+
+	// Step 189: t5 = x^0x80000000000000000000000000000000448d31f81300
+	for s := 0; s < 8; s++ {
+		t5.Square(t5)
+	}
+
+	// Step 190: t4 = x^0x80000000000000000000000000000000448d31f81329
+	t4.Mul(t4, t5)
+
+	// Step 198: t4 = x^0x80000000000000000000000000000000448d31f8132900
+	for s := 0; s < 8; s++ {
+		t4.Square(t4)
+	}
+
+	// Step 199: t3 = x^0x80000000000000000000000000000000448d31f8132951
+	t3.Mul(t3, t4)
+
+	// Step 206: t3 = x^0x40000000000000000000000000000000224698fc0994a880
+	for s := 0; s < 7; s++ {
+		t3.Square(t3)
+	}
+
+	// Step 207: t3 = x^0x40000000000000000000000000000000224698fc0994a8dd
+	t3.Mul(t0, t3)
+
+	// Step 213: t3 = x^0x100000000000000000000000000000000891a63f02652a3740
+	for s := 0; s < 6; s++ {
+		t3.Square(t3)
+	}
+
+	// Step 214: t3 = x^0x100000000000000000000000000000000891a63f02652a3763
+	t3.Mul(t2, t3)
+
+	// Step 223: t3 = x^0x2000000000000000000000000000000011234c7e04ca546ec600
+	for s := 0; s < 9; s++ {
+		t3.Square(t3)
+	}
+
+	// Step 224: t2 = x^0x2000000000000000000000000000000011234c7e04ca546ec623
+	t2.Mul(t2, t3)
+
+	// Step 232: t2 = x^0x2000000000000000000000000000000011234c7e04ca546ec62300
+	for s := 0; s < 8; s++ {
+		t2.Square(t2)
+	}
+
+	// Step 233: t1 = x^0x2000000000000000000000000000000011234c7e04ca546ec62318
+	t1.Mul(t1, t2)
+
+	// Step 234: t0 = x^0x2000000000000000000000000000000011234c7e04ca546ec62375
+	t0.Mul(t0, t1)
+
+	// Step 238: t0 = x^0x2000000000000000000000000000000011234c7e04ca546ec623750
+	for s := 0; s < 4; s++ {
+		t0.Square(t0)
+	}
+
+	// Step 239: z = x^0x2000000000000000000000000000000011234c7e04ca546ec623759
+	z.Mul(z, t0)
+
+	// Step 244: z = x^0x40000000000000000000000000000000224698fc0994a8dd8c46eb20
+	for s := 0; s < 5; s++ {
+		z.Square(z)
+	}
+
+	// Step 245: z = x^0x40000000000000000000000000000000224698fc0994a8dd8c46eb21
+	z.Mul(&x, z)
+
+	// Step 276: z = x^0x2000000000000000000000000000000011234c7e04ca546ec623759080000000
+	for s := 0; s < 31; s++ {
+		z.Square(z)
+	}
+
+	return z
+}
@@ -0,0 +1,151 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecdsa
+
+import (
+	"bytes"
+	stdecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"testing"
+)
+
+// crypto/x509 does not support secp256k1, the fixtures are produced by OpenSSL:
+//
+//	openssl ecparam -name secp256k1 -genkey -noout -outform DER -out sec1.der
+//	openssl pkcs8 -topk8 -nocrypt -inform DER -in sec1.der -outform DER -out pkcs8.der
+//	openssl ec -inform DER -in sec1.der -pubout -outform DER -out pkix.der
+//	openssl ec -inform DER -in sec1.der -pubout -conv_form compressed -outform DER -out pkixc.der
+//	openssl dgst -sha256 -sign sec1.der -keyform DER -out sig.der msg
+const (
+	fixtureSEC1        = "307402010104202025a93b0d05584815aae60129e65cc54d2ddb425e3f627acb14c7bacd14ffd9a00706052b8104000aa144034200045636e8301a1dbdc5c060d951406260bf4db76e16e1191fb148874ed7915e481462efa3735306f9d45a382887e4cc5077d800b92f480538c48227914483a434eb"
+	fixturePKCS8       = "308184020100301006072a8648ce3d020106052b8104000a046d306b02010104202025a93b0d05584815aae60129e65cc54d2ddb425e3f627acb14c7bacd14ffd9a144034200045636e8301a1dbdc5c060d951406260bf4db76e16e1191fb148874ed7915e481462efa3735306f9d45a382887e4cc5077d800b92f480538c48227914483a434eb"
+	fixturePKIX        = "3056301006072a8648ce3d020106052b8104000a034200045636e8301a1dbdc5c060d951406260bf4db76e16e1191fb148874ed7915e481462efa3735306f9d45a382887e4cc5077d800b92f480538c48227914483a434eb"
+	fixturePKIXCompact = "3036301006072a8648ce3d020106052b8104000a032200035636e8301a1dbdc5c060d951406260bf4db76e16e1191fb148874ed7915e4814"
+	fixtureSignature   = "3046022100ad5333f7ad61366d74504ea3ee6ee9557c9107994c06ffaa8c972a0f4cfdf146022100a6c6fa5c5290b75badf4b51cffb8b7b9308716c870e27e727ab9dde9c52d0c63"
+	fixtureMessage     = "OpenSSL fixture message"
+	fixtureScalar      = "2025a93b0d05584815aae60129e65cc54d2ddb425e3f627acb14c7bacd14ffd9"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	res, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestOpenSSLFixtures(t *testing.T) {
+	t.Parallel()
+
+	sec1 := mustDecodeHex(t, fixtureSEC1)
+	var privKey PrivateKey
+	if n, err := privKey.SetBytesSEC1(sec1); err != nil || n != len(sec1) {
+		t.Fatal("SEC1 private key should parse", err)
+	}
+	if !bytes.Equal(privKey.scalar[:], mustDecodeHex(t, fixtureScalar)) {
+		t.Fatal("wrong secret scalar")
+	}
+	if !bytes.Equal(privKey.BytesSEC1(), sec1) {
+		t.Fatal("SEC1 private key round trip failed")
+	}
+
+	pkcs8 := mustDecodeHex(t, fixturePKCS8)
+	var privKey2 PrivateKey
+	if n, err := privKey2.SetBytesPKCS8(pkcs8); err != nil || n != len(pkcs8) {
+		t.Fatal("PKCS#8 private key should parse", err)
+	}
+	if !bytes.Equal(privKey2.Bytes(), privKey.Bytes()) {
+		t.Fatal("PKCS#8 and SEC1 private keys differ")
+	}
+	if !bytes.Equal(privKey2.BytesPKCS8(), pkcs8) {
+		t.Fatal("PKCS#8 private key round trip failed")
+	}
+
+	pkix := mustDecodeHex(t, fixturePKIX)
+	var publicKey PublicKey
+	if n, err := publicKey.SetBytesPKIX(pkix); err != nil || n != len(pkix) {
+		t.Fatal("PKIX public key should parse", err)
+	}
+	if !publicKey.Equal(&privKey.PublicKey) {
+		t.Fatal("wrong public key")
+	}
+	if !bytes.Equal(publicKey.BytesPKIX(), pkix) {
+		t.Fatal("PKIX public key round trip failed")
+	}
+
+	// the last 33 bytes of the compressed PKIX encoding are the SEC1 point
+	pkixCompact := mustDecodeHex(t, fixturePKIXCompact)
+	var publicKey2 PublicKey
+	if _, err := publicKey2.SetBytesPKIX(pkixCompact); err != nil {
+		t.Fatal("compressed PKIX public key should parse", err)
+	}
+	if !publicKey2.Equal(&publicKey) {
+		t.Fatal("compressed and uncompressed public keys differ")
+	}
+	if !bytes.Equal(publicKey.BytesSEC1(true), pkixCompact[len(pkixCompact)-1-sizeFp:]) {
+		t.Fatal("wrong SEC1 compressed public key")
+	}
+
+	der := mustDecodeHex(t, fixtureSignature)
+	var sig Signature
+	if n, err := sig.SetBytesDER(der); err != nil || n != len(der) {
+		t.Fatal("DER signature should parse", err)
+	}
+	if !bytes.Equal(sig.BytesDER(), der) {
+		t.Fatal("DER signature round trip failed")
+	}
+	valid, err := publicKey.Verify(sig.Bytes(), []byte(fixtureMessage), sha256.New())
+	if err != nil || !valid {
+		t.Fatal("OpenSSL signature should verify")
+	}
+}
+
+func TestWrongCurve(t *testing.T) {
+	t.Parallel()
+
+	// keys on another curve, produced by crypto/x509
+	stdKey, err := stdecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkix, err := x509.MarshalPKIXPublicKey(&stdKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var publicKey PublicKey
+	if _, err := publicKey.SetBytesPKIX(pkix); err != errWrongCurve {
+		t.Fatal("P-256 PKIX public key should be rejected")
+	}
+	sec1, err := x509.MarshalECPrivateKey(stdKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var privKey PrivateKey
+	if _, err := privKey.SetBytesSEC1(sec1); err != errWrongCurve {
+		t.Fatal("P-256 SEC1 private key should be rejected")
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(stdKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := privKey.SetBytesPKCS8(pkcs8); err != errWrongCurve {
+		t.Fatal("P-256 PKCS#8 private key should be rejected")
+	}
+}
@@ -18,6 +18,8 @@ package ecdsa
 
 import (
 	"crypto/subtle"
+	"crypto/x509/pkix"
+	"encoding/asn1"
 	"errors"
 	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
 	"io"
@@ -58,7 +60,7 @@ func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
 	if _, err := pk.A.SetBytes(buf[:sizePublicKey]); err != nil {
 		return 0, err
 	}
-	n += sizeFp
+	n += sizePublicKey
 	return n, nil
 }
 
@@ -164,3 +166,294 @@ func (sig *Signature) SetBytes(buf []byte) (int, error) {
 	n += sizeFr
 	return n, nil
 }
+
+// Standard encodings, for interoperability with crypto/x509, OpenSSL and HSMs.
+//
+// Documentation:
+// - SEC 1, v-2: https://www.secg.org/sec1-v2.pdf
+// - RFC 3279 (DER signatures): https://www.rfc-editor.org/rfc/rfc3279#section-2.2.3
+// - RFC 5480 (PKIX public keys): https://www.rfc-editor.org/rfc/rfc5480
+// - RFC 5915 (SEC1 private keys): https://www.rfc-editor.org/rfc/rfc5915
+// - RFC 5208 (PKCS#8 private keys): https://www.rfc-editor.org/rfc/rfc5208
+
+var errInvalidEncoding = errors.New("invalid encoding")
+var errNotOnCurve = errors.New("point not on curve")
+var errWrongCurve = errors.New("key is not on the secp256k1 curve")
+var errInvalidSecretKey = errors.New("secret scalar must be in [1, r_mod)")
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidNamedCurve     = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+const (
+	sec1CompressedEven = 0x02
+	sec1CompressedOdd  = 0x03
+	sec1Uncompressed   = 0x04
+	ecPrivKeyVersion   = 1
+)
+
+// ecdsaSignature is the ASN.1 structure of a signature
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// ecPrivateKey is the ASN.1 structure of a SEC1 private key
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// pkcs8 is the ASN.1 structure of a PKCS#8 private key
+type pkcs8 struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// publicKeyInfo is the ASN.1 structure of a PKIX public key
+type publicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// BytesDER returns the DER encoding of sig, that is the ASN.1 sequence of the
+// integers r and s (RFC 3279), as crypto/ecdsa.SignASN1 does.
+func (sig *Signature) BytesDER() []byte {
+	r := new(big.Int).SetBytes(sig.R[:])
+	s := new(big.Int).SetBytes(sig.S[:])
+	res, err := asn1.Marshal(ecdsaSignature{R: r, S: s})
+	if err != nil {
+		// can't happen with non-negative integers
+		panic(err)
+	}
+	return res
+}
+
+// SetBytesDER sets sig from its DER encoding, as in RFC 3279.
+// It returns the number of bytes read from buf.
+func (sig *Signature) SetBytesDER(buf []byte) (int, error) {
+	var v ecdsaSignature
+	rest, err := asn1.Unmarshal(buf, &v)
+	if err != nil {
+		return 0, err
+	}
+	if v.R.Sign() < 0 || v.S.Sign() < 0 {
+		return 0, errInvalidEncoding
+	}
+	if v.R.Sign() == 0 || v.S.Sign() == 0 {
+		return 0, errZero
+	}
+	if v.R.Cmp(order) >= 0 {
+		return 0, errRBiggerThanRMod
+	}
+	if v.S.Cmp(order) >= 0 {
+		return 0, errSBiggerThanRMod
+	}
+	v.R.FillBytes(sig.R[:])
+	v.S.FillBytes(sig.S[:])
+	return len(buf) - len(rest), nil
+}
+
+// BytesSEC1 returns the SEC1 encoding of the public key (SEC 1, Version 2.0,
+// Section 2.3.3): 0x02 or 0x03 || x if compressed, 0x04 || x || y otherwise.
+func (pk *PublicKey) BytesSEC1(compressed bool) []byte {
+	x := pk.A.X.Bytes()
+	y := pk.A.Y.Bytes()
+	if compressed {
+		res := make([]byte, 1+sizeFp)
+		res[0] = sec1CompressedEven | (y[sizeFp-1] & 1)
+		copy(res[1:], x[:])
+		return res
+	}
+	res := make([]byte, 1+2*sizeFp)
+	res[0] = sec1Uncompressed
+	copy(res[1:], x[:])
+	copy(res[1+sizeFp:], y[:])
+	return res
+}
+
+// SetBytesSEC1 sets pk from its SEC1 encoding, compressed or not (SEC 1,
+// Version 2.0, Section 2.3.4). The point at infinity is rejected.
+// It returns the number of bytes read from buf.
+func (pk *PublicKey) SetBytesSEC1(buf []byte) (int, error) {
+	if len(buf) < 1+sizeFp {
+		return 0, io.ErrShortBuffer
+	}
+	var p secp256k1.G1Affine
+	if err := p.X.SetBytesCanonical(buf[1 : 1+sizeFp]); err != nil {
+		return 0, err
+	}
+	n := 1 + sizeFp
+	switch buf[0] {
+	case sec1Uncompressed:
+		if len(buf) < 1+2*sizeFp {
+			return 0, io.ErrShortBuffer
+		}
+		if err := p.Y.SetBytesCanonical(buf[1+sizeFp : 1+2*sizeFp]); err != nil {
+			return 0, err
+		}
+		n += sizeFp
+	case sec1CompressedEven, sec1CompressedOdd:
+		// y² = x³ + ax + b
+		a, b := secp256k1.CurveCoefficients()
+		p.Y.Square(&p.X).
+			Add(&p.Y, &a).
+			Mul(&p.Y, &p.X).
+			Add(&p.Y, &b)
+		if p.Y.Sqrt(&p.Y) == nil {
+			return 0, errNotOnCurve
+		}
+		if y := p.Y.Bytes(); y[sizeFp-1]&1 != buf[0]&1 {
+			p.Y.Neg(&p.Y)
+		}
+	default:
+		return 0, errInvalidEncoding
+	}
+	if !p.IsOnCurve() || !p.IsInSubGroup() {
+		return 0, errNotOnCurve
+	}
+	pk.A = p
+	return n, nil
+}
+
+// BytesPKIX returns the DER encoding of the public key as a PKIX
+// SubjectPublicKeyInfo (RFC 5480), as crypto/x509.MarshalPKIXPublicKey does.
+func (pk *PublicKey) BytesPKIX() []byte {
+	paramBytes, _ := asn1.Marshal(oidNamedCurve)
+	pkBytes := pk.BytesSEC1(false)
+	res, err := asn1.Marshal(publicKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPublicKeyECDSA,
+			Parameters: asn1.RawValue{FullBytes: paramBytes},
+		},
+		PublicKey: asn1.BitString{Bytes: pkBytes, BitLength: 8 * len(pkBytes)},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// SetBytesPKIX sets pk from its PKIX SubjectPublicKeyInfo DER encoding
+// (RFC 5480). It returns the number of bytes read from buf.
+func (pk *PublicKey) SetBytesPKIX(buf []byte) (int, error) {
+	var info publicKeyInfo
+	rest, err := asn1.Unmarshal(buf, &info)
+	if err != nil {
+		return 0, err
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return 0, errInvalidEncoding
+	}
+	var oid asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &oid); err != nil {
+		return 0, err
+	}
+	if !oid.Equal(oidNamedCurve) {
+		return 0, errWrongCurve
+	}
+	pkBytes := info.PublicKey.RightAlign()
+	if n, err := pk.SetBytesSEC1(pkBytes); err != nil {
+		return 0, err
+	} else if n != len(pkBytes) {
+		return 0, errInvalidEncoding
+	}
+	return len(buf) - len(rest), nil
+}
+
+// BytesSEC1 returns the DER encoding of the private key as a SEC1
+// ECPrivateKey (RFC 5915), with the curve identifier and the uncompressed
+// public key, as crypto/x509.MarshalECPrivateKey does.
+func (privKey *PrivateKey) BytesSEC1() []byte {
+	return privKey.marshalECPrivateKey(oidNamedCurve)
+}
+
+// SetBytesSEC1 sets privKey from its SEC1 ECPrivateKey DER encoding (RFC 5915).
+// The public key is recomputed from the secret scalar.
+// It returns the number of bytes read from buf.
+func (privKey *PrivateKey) SetBytesSEC1(buf []byte) (int, error) {
+	return privKey.unmarshalECPrivateKey(buf)
+}
+
+// BytesPKCS8 returns the DER encoding of the private key as an unencrypted
+// PKCS#8 PrivateKeyInfo (RFC 5208), as crypto/x509.MarshalPKCS8PrivateKey does.
+func (privKey *PrivateKey) BytesPKCS8() []byte {
+	paramBytes, _ := asn1.Marshal(oidNamedCurve)
+	res, err := asn1.Marshal(pkcs8{
+		Version: 0,
+		Algo: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPublicKeyECDSA,
+			Parameters: asn1.RawValue{FullBytes: paramBytes},
+		},
+		// the curve is already identified in the algorithm parameters
+		PrivateKey: privKey.marshalECPrivateKey(nil),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// SetBytesPKCS8 sets privKey from its unencrypted PKCS#8 PrivateKeyInfo DER
+// encoding (RFC 5208). The public key is recomputed from the secret scalar.
+// It returns the number of bytes read from buf.
+func (privKey *PrivateKey) SetBytesPKCS8(buf []byte) (int, error) {
+	var info pkcs8
+	rest, err := asn1.Unmarshal(buf, &info)
+	if err != nil {
+		return 0, err
+	}
+	if !info.Algo.Algorithm.Equal(oidPublicKeyECDSA) {
+		return 0, errInvalidEncoding
+	}
+	var oid asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(info.Algo.Parameters.FullBytes, &oid); err != nil {
+		return 0, err
+	}
+	if !oid.Equal(oidNamedCurve) {
+		return 0, errWrongCurve
+	}
+	if _, err := privKey.unmarshalECPrivateKey(info.PrivateKey); err != nil {
+		return 0, err
+	}
+	return len(buf) - len(rest), nil
+}
+
+func (privKey *PrivateKey) marshalECPrivateKey(oid asn1.ObjectIdentifier) []byte {
+	pkBytes := privKey.PublicKey.BytesSEC1(false)
+	res, err := asn1.Marshal(ecPrivateKey{
+		Version:       ecPrivKeyVersion,
+		PrivateKey:    privKey.scalar[:],
+		NamedCurveOID: oid,
+		PublicKey:     asn1.BitString{Bytes: pkBytes, BitLength: 8 * len(pkBytes)},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func (privKey *PrivateKey) unmarshalECPrivateKey(buf []byte) (int, error) {
+	var key ecPrivateKey
+	rest, err := asn1.Unmarshal(buf, &key)
+	if err != nil {
+		return 0, err
+	}
+	if key.Version != ecPrivKeyVersion || len(key.PrivateKey) > sizeFr {
+		return 0, errInvalidEncoding
+	}
+	if key.NamedCurveOID != nil && !key.NamedCurveOID.Equal(oidNamedCurve) {
+		return 0, errWrongCurve
+	}
+	k := new(big.Int).SetBytes(key.PrivateKey)
+	if k.Sign() == 0 || k.Cmp(order) >= 0 {
+		return 0, errInvalidSecretKey
+	}
+	// some encoders strip the leading zeros of the scalar
+	k.FillBytes(privKey.scalar[:])
+	privKey.PublicKey.A.ScalarMultiplicationBase(k)
+	return len(buf) - len(rest), nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"errors"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+var errInvalidBatch = errors.New("number of public keys, signatures and messages must match")
+
+// BatchVerify verifies the signatures of messages under publicKeys at once,
+// following the batch verification algorithm of BIP-340.
+//
+// With Rᵢ = lift_x(rᵢ), eᵢ the challenges, a₁ = 1 and aᵢ random 128-bit scalars,
+// it checks that
+//
+//	[-∑aᵢsᵢ]G + ∑[aᵢ]Rᵢ + ∑[aᵢeᵢ]Pᵢ = 0
+//
+// with a single multi-exponentiation. A batch is valid if and only if (with
+// overwhelming probability) each of its signatures is. It returns an error if
+// the slices lengths differ or if a signature is not a valid encoding.
+func BatchVerify(publicKeys []PublicKey, signatures, messages [][]byte, hFunc hash.Hash) (bool, error) {
+	if len(publicKeys) != len(signatures) || len(publicKeys) != len(messages) {
+		return false, errInvalidBatch
+	}
+	n := len(publicKeys)
+	if n == 0 {
+		return true, nil
+	}
+
+	points := make([]secp256k1.G1Affine, 2*n+1)
+	scalars := make([]fr.Element, 2*n+1)
+	var sumAS, a, s fr.Element
+	var buf [16]byte
+	for i := 0; i < n; i++ {
+		var sig Signature
+		if _, err := sig.SetBytes(signatures[i]); err != nil {
+			return false, err
+		}
+		m, err := hashMessage(messages[i], hFunc)
+		if err != nil {
+			return false, err
+		}
+		var r fp.Element
+		r.SetBytes(sig.R[:]) // checked in SetBytes
+		R, ok := liftX(&r)
+		if !ok {
+			return false, nil
+		}
+		s.SetBytes(sig.S[:])
+		pBytes := publicKeys[i].A.X.Bytes()
+		e := challenge(sig.R[:], pBytes[:], m)
+
+		if i == 0 {
+			a.SetOne()
+		} else {
+			if _, err := rand.Read(buf[:]); err != nil {
+				return false, err
+			}
+			a.SetBytes(buf[:])
+		}
+
+		points[2*i] = R
+		scalars[2*i] = a
+		points[2*i+1] = publicKeys[i].A
+		scalars[2*i+1].Mul(&a, &e)
+		s.Mul(&s, &a)
+		sumAS.Add(&sumAS, &s)
+	}
+	_, points[2*n] = secp256k1.Generators()
+	scalars[2*n].Neg(&sumAS)
+
+	var res secp256k1.G1Jac
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false, err
+	}
+	return res.Z.IsZero(), nil
+}
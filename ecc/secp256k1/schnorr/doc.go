@@ -0,0 +1,30 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schnorr provides BIP-340 Schnorr signatures on the secp256k1 curve,
+// and the BIP-341 (Taproot) tweaking of keys.
+//
+// Public keys are x-only: a public key is the x-coordinate of the point with an
+// even y-coordinate, on 32 bytes. Signatures are R||s on 64 bytes, where R is
+// the x-coordinate of the nonce point. Nonces and challenges are derived with
+// the tagged hashes of BIP-340.
+//
+// Documentation:
+//   - BIP-340: https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+//   - BIP-341: https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki
+//
+// # Warning
+//
+// This code has not been audited and is provided as-is. In particular, there is no security guarantees such as constant time implementation or side-channel attack resistance.
+package schnorr
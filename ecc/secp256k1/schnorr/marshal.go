@@ -0,0 +1,121 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+var (
+	errWrongSize        = errors.New("wrong size buffer")
+	errInvalidSecretKey = errors.New("secret key must be in [1, n-1]")
+	errZeroNonce        = errors.New("nonce is zero")
+	errNotOnCurve       = errors.New("x-coordinate is not on the curve")
+	errRBiggerThanPMod  = errors.New("r >= p_mod")
+	errSBiggerThanRMod  = errors.New("s >= r_mod")
+)
+
+// Bytes returns the binary representation of the public key: the
+// x-coordinate of A, in big endian on 32 bytes.
+func (pk *PublicKey) Bytes() []byte {
+	pkBin := pk.A.X.Bytes()
+	return pkBin[:]
+}
+
+// SetBytes sets pk from an x-only public key, in big endian on 32 bytes. It
+// returns an error if x is not reduced modulo p or is not the x-coordinate
+// of a point on the curve (lift_x in BIP-340).
+// It returns the number of bytes read from the buffer.
+func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
+	if len(buf) < SizePublicKey {
+		return 0, io.ErrShortBuffer
+	}
+	var x fp.Element
+	if err := x.SetBytesCanonical(buf[:SizePublicKey]); err != nil {
+		return 0, errNotOnCurve
+	}
+	a, ok := liftX(&x)
+	if !ok {
+		return 0, errNotOnCurve
+	}
+	pk.A = a
+	return SizePublicKey, nil
+}
+
+// Bytes returns the binary representation of pk,
+// as byte array publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+func (privKey *PrivateKey) Bytes() []byte {
+	var res [sizePrivateKey]byte
+	pubkBin := privKey.PublicKey.A.X.Bytes()
+	subtle.ConstantTimeCopy(1, res[:SizePublicKey], pubkBin[:])
+	subtle.ConstantTimeCopy(1, res[SizePublicKey:], privKey.scalar[:])
+	return res[:]
+}
+
+// SetBytes sets pk from buf, where buf is interpreted
+// as publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+// It returns the number byte read.
+func (privKey *PrivateKey) SetBytes(buf []byte) (int, error) {
+	n := 0
+	if len(buf) < sizePrivateKey {
+		return n, io.ErrShortBuffer
+	}
+	if _, err := privKey.PublicKey.SetBytes(buf[:SizePublicKey]); err != nil {
+		return 0, err
+	}
+	n += SizePublicKey
+	subtle.ConstantTimeCopy(1, privKey.scalar[:], buf[n:n+sizeFr])
+	n += sizeFr
+	return n, nil
+}
+
+// Bytes returns the binary representation of sig
+// as a byte array R||S, where R is the x-coordinate of the nonce point.
+func (sig *Signature) Bytes() []byte {
+	var res [SizeSignature]byte
+	subtle.ConstantTimeCopy(1, res[:sizeFp], sig.R[:])
+	subtle.ConstantTimeCopy(1, res[sizeFp:], sig.S[:])
+	return res[:]
+}
+
+// SetBytes sets sig from a buffer in binary, interpreted as R||S. It returns
+// an error if R is not reduced modulo p or if S is not reduced modulo n.
+//
+// It returns the number of bytes read from buf.
+func (sig *Signature) SetBytes(buf []byte) (int, error) {
+	if len(buf) != SizeSignature {
+		return 0, errWrongSize
+	}
+	var r fp.Element
+	if err := r.SetBytesCanonical(buf[:sizeFp]); err != nil {
+		return 0, errRBiggerThanPMod
+	}
+	var s fr.Element
+	if err := s.SetBytesCanonical(buf[sizeFp:]); err != nil {
+		return 0, errSBiggerThanRMod
+	}
+	copy(sig.R[:], buf[:sizeFp])
+	copy(sig.S[:], buf[sizeFp:])
+	return SizeSignature, nil
+}
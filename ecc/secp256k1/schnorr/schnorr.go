@@ -0,0 +1,293 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/consensys/gnark-crypto/signature"
+)
+
+const (
+	sizeFr = fr.Bytes
+	sizeFp = fp.Bytes
+
+	// SizePublicKey size in bytes of an x-only public key
+	SizePublicKey = sizeFp
+
+	// SizeSignature size in bytes of a signature R||s
+	SizeSignature = sizeFp + sizeFr
+
+	// SizeAuxRand size in bytes of the auxiliary randomness used by
+	// SignWithAuxRand
+	SizeAuxRand = 32
+
+	sizePrivateKey = SizePublicKey + sizeFr
+)
+
+// tags of the BIP-340 tagged hashes
+const (
+	tagAux       = "BIP0340/aux"
+	tagNonce     = "BIP0340/nonce"
+	tagChallenge = "BIP0340/challenge"
+)
+
+// PublicKey represents a BIP-340 public key. A always has an even
+// y-coordinate, so that it is determined by its x-coordinate.
+type PublicKey struct {
+	A secp256k1.G1Affine
+}
+
+// PrivateKey represents a BIP-340 private key
+type PrivateKey struct {
+	PublicKey PublicKey
+	scalar    [sizeFr]byte // secret scalar d such that [d]G = A, in big Endian
+}
+
+// Signature represents a BIP-340 signature
+type Signature struct {
+	R [sizeFp]byte // x-coordinate of the nonce point, in big Endian
+	S [sizeFr]byte // in big Endian
+}
+
+// GenerateKey generates a public and private key pair.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	// procedure given in FIPS 186-4, Appendix B.5.1
+	b := make([]byte, fr.Bits/8+8)
+	if _, err := io.ReadFull(rand, b); err != nil {
+		return nil, err
+	}
+	k := new(big.Int).SetBytes(b)
+	n := new(big.Int).Sub(fr.Modulus(), big.NewInt(1))
+	k.Mod(k, n)
+	k.Add(k, big.NewInt(1))
+
+	var secretKey [sizeFr]byte
+	k.FillBytes(secretKey[:])
+	return NewPrivateKey(secretKey[:])
+}
+
+// NewPrivateKey returns the private key of the BIP-340 secret key secretKey,
+// a 32 bytes big endian integer in [1, n-1].
+//
+// As in BIP-340, the secret scalar is negated if needed, so that the public
+// point has an even y-coordinate.
+func NewPrivateKey(secretKey []byte) (*PrivateKey, error) {
+	if len(secretKey) != sizeFr {
+		return nil, errWrongSize
+	}
+	var d fr.Element
+	if err := d.SetBytesCanonical(secretKey); err != nil || d.IsZero() {
+		return nil, errInvalidSecretKey
+	}
+	return newPrivateKey(&d), nil
+}
+
+func newPrivateKey(d *fr.Element) *PrivateKey {
+	var priv PrivateKey
+	var bd big.Int
+	d.BigInt(&bd)
+	priv.PublicKey.A.ScalarMultiplicationBase(&bd)
+	if isOdd(&priv.PublicKey.A.Y) {
+		priv.PublicKey.A.Y.Neg(&priv.PublicKey.A.Y)
+		var negD fr.Element
+		negD.Neg(d)
+		priv.scalar = negD.Bytes()
+	} else {
+		priv.scalar = d.Bytes()
+	}
+	return &priv
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(x signature.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	bpk := pub.Bytes()
+	bxx := xx.Bytes()
+	return subtle.ConstantTimeCompare(bpk, bxx) == 1
+}
+
+// Public returns the public key associated to the private key.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.A.Set(&privKey.PublicKey.A)
+	return &pub
+}
+
+// Sign signs a message following the default signing algorithm of BIP-340,
+// with fresh auxiliary randomness from crypto/rand.
+//
+// If hFunc is not nil, the message signed is the digest hFunc(message).
+// Otherwise message is signed as is; BIP-340 supports messages of any length.
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	var auxRand [SizeAuxRand]byte
+	if _, err := io.ReadFull(rand.Reader, auxRand[:]); err != nil {
+		return nil, err
+	}
+	return privKey.SignWithAuxRand(message, auxRand[:], hFunc)
+}
+
+// SignWithAuxRand signs a message following the default signing algorithm of
+// BIP-340, with the given 32 bytes of auxiliary randomness:
+//
+//	t = bytes(d) ⊕ hash_aux(auxRand)
+//	k = hash_nonce(t || bytes(P) || m) mod n, negated if R = [k]G has an odd y
+//	e = hash_challenge(bytes(R) || bytes(P) || m) mod n
+//	signature = bytes(R) || bytes(k + e⋅d mod n)
+//
+// The signature is a deterministic function of auxRand, which should be fresh
+// randomness; auxRand only hardens signing against side-channel attacks.
+func (privKey *PrivateKey) SignWithAuxRand(message, auxRand []byte, hFunc hash.Hash) ([]byte, error) {
+	if len(auxRand) != SizeAuxRand {
+		return nil, errWrongSize
+	}
+	m, err := hashMessage(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	pBytes := privKey.PublicKey.A.X.Bytes()
+	t := taggedHash(tagAux, auxRand)
+	for i := range t {
+		t[i] ^= privKey.scalar[i]
+	}
+	nonce := taggedHash(tagNonce, t[:], pBytes[:], m)
+	var k fr.Element
+	k.SetBytes(nonce[:])
+	if k.IsZero() {
+		return nil, errZeroNonce
+	}
+
+	var R secp256k1.G1Affine
+	var bk big.Int
+	k.BigInt(&bk)
+	R.ScalarMultiplicationBase(&bk)
+	if isOdd(&R.Y) {
+		k.Neg(&k)
+	}
+
+	var sig Signature
+	sig.R = R.X.Bytes()
+	e := challenge(sig.R[:], pBytes[:], m)
+	var s fr.Element
+	s.SetBytes(privKey.scalar[:])
+	s.Mul(&s, &e).Add(&s, &k)
+	sig.S = s.Bytes()
+
+	return sig.Bytes(), nil
+}
+
+// Verify verifies a signature following BIP-340: with
+// e = hash_challenge(r || bytes(P) || m) mod n, it checks that R = [s]G - [e]P
+// is not the infinity point, has an even y-coordinate and x(R) = r.
+//
+// If hFunc is not nil, the message verified is the digest hFunc(message).
+// It returns an error if the signature is not a valid encoding.
+func (pub *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return false, err
+	}
+	m, err := hashMessage(message, hFunc)
+	if err != nil {
+		return false, err
+	}
+
+	pBytes := pub.A.X.Bytes()
+	e := challenge(sig.R[:], pBytes[:], m)
+	e.Neg(&e)
+	var bs, be big.Int
+	new(fr.Element).SetBytes(sig.S[:]).BigInt(&bs)
+	e.BigInt(&be)
+
+	var _R secp256k1.G1Jac
+	_R.JointScalarMultiplicationBase(&pub.A, &bs, &be)
+	if _R.Z.IsZero() {
+		return false, nil
+	}
+	var R secp256k1.G1Affine
+	R.FromJacobian(&_R)
+	if isOdd(&R.Y) {
+		return false, nil
+	}
+	rBytes := R.X.Bytes()
+	return subtle.ConstantTimeCompare(rBytes[:], sig.R[:]) == 1, nil
+}
+
+// hashMessage returns the message to sign: hFunc(message) if hFunc is not nil,
+// message otherwise
+func hashMessage(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	hFunc.Reset()
+	if _, err := hFunc.Write(message); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
+}
+
+// challenge returns e = hash_challenge(r || p || m) mod n
+func challenge(r, p, m []byte) fr.Element {
+	h := taggedHash(tagChallenge, r, p, m)
+	var e fr.Element
+	e.SetBytes(h[:])
+	return e
+}
+
+// taggedHash returns SHA256(SHA256(tag) || SHA256(tag) || msgs[0] || msgs[1] || ...)
+func taggedHash(tag string, msgs ...[]byte) [sha256.Size]byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, m := range msgs {
+		h.Write(m)
+	}
+	var res [sha256.Size]byte
+	h.Sum(res[:0])
+	return res
+}
+
+// liftX returns the point of x-coordinate x with an even y-coordinate, or
+// false if x is not on the curve
+func liftX(x *fp.Element) (secp256k1.G1Affine, bool) {
+	_, b := secp256k1.CurveCoefficients()
+	var p secp256k1.G1Affine
+	p.X.Set(x)
+	p.Y.Square(x).Mul(&p.Y, x).Add(&p.Y, &b)
+	if p.Y.Sqrt(&p.Y) == nil {
+		return p, false
+	}
+	if isOdd(&p.Y) {
+		p.Y.Neg(&p.Y)
+	}
+	return p, true
+}
+
+func isOdd(y *fp.Element) bool {
+	return y.Bits()[0]&1 == 1
+}
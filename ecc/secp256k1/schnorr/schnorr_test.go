@@ -0,0 +1,436 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"testing"
+)
+
+// test vectors from https://github.com/bitcoin/bips/blob/master/bip-0340/test-vectors.csv
+var bip340Vectors = []struct {
+	secretKey, publicKey, auxRand, message, signature string
+	valid                                             bool
+	comment                                           string
+}{
+	{
+		"0000000000000000000000000000000000000000000000000000000000000003",
+		"F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"E907831F80848D1069A5371B402410364BDF1C5F8307B0084C55F1CE2DCA821525F66A4A85EA8B71E482A74F382D2CE5EBEEE8FDB2172F477DF4900D310536C0",
+		true,
+		"",
+	},
+	{
+		"B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"6896BD60EEAE296DB48A229FF71DFE071BDE413E6D43F917DC8DCF8C78DE33418906D11AC976ABCCB20B091292BFF4EA897EFCB639EA871CFA95F6DE339E4B0A",
+		true,
+		"",
+	},
+	{
+		"C90FDAA22168C234C4C6628B80DC1CD129024E088A67CC74020BBEA63B14E5C9",
+		"DD308AFEC5777E13121FA72B9CC1B7CC0139715309B086C960E18FD969774EB8",
+		"C87AA53824B4D7AE2EB035A2B5BBBCCC080E76CDC6D1692C4B0B62D798E6D906",
+		"7E2D58D8B3BCDF1ABADEC7829054F90DDA9805AAB56C77333024B9D0A508B75C",
+		"5831AAEED7B44BB74E5EAB94BA9D4294C49BCF2A60728D8B4C200F50DD313C1BAB745879A5AD954A72C45A91C3A51D3C7ADEA98D82F8481E0E1E03674A6F3FB7",
+		true,
+		"",
+	},
+	{
+		"0B432B2677937381AEF05BB02A66ECD012773062CF3FA2549E44F58ED2401710",
+		"25D1DFF95105F5253C4022F628A996AD3A0D95FBF21D468A1B33F8C160D8F517",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF",
+		"7EB0509757E246F19449885651611CB965ECC1A187DD51B64FDA1EDC9637D5EC97582B9CB13DB3933705B32BA982AF5AF25FD78881EBB32771FC5922EFC66EA3",
+		true,
+		"test fails if msg is reduced modulo p or n",
+	},
+	{
+		"",
+		"D69C3509BB99E412E68B0FE8544E72837DFA30746D8BE2AA65975F29D22DC7B9",
+		"",
+		"4DF3C3F68FCC83B27E9D42C90431A72499F17875C81A599B566C9889B9696703",
+		"00000000000000000000003B78CE563F89A0ED9414F5AA28AD0D96D6795F9C6376AFB1548AF603B3EB45C9F8207DEE1060CB71C04E80F593060B07D28308D7F4",
+		true,
+		"",
+	},
+	{
+		"",
+		"EEFDEA4CDB677750A420FEE807EACF21EB9898AE79B9768766E4FAA04A2D4A34",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E17776969E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B",
+		false,
+		"public key not on the curve",
+	},
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"FFF97BD5755EEEA420453A14355235D382F6472F8568A18B2F057A14602975563CC27944640AC607CD107AE10923D9EF7A73C643E166BE5EBEAFA34B1AC553E2",
+		false,
+		"has_even_y(R) is false",
+	},
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"1FA62E331EDBC21C394792D2AB1100A7B432B013DF3F6FF4F99FCB33E0E1515F28890B3EDB6E7189B630448B515CE4F8622A954CFE545735AAEA5134FCCDB2BD",
+		false,
+		"negated message",
+	},
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E177769961764B3AA9B2FFCB6EF947B6887A226E8D7C93E00C5ED0C1834FF0D0C2E6DA6",
+		false,
+		"negated s value",
+	},
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"0000000000000000000000000000000000000000000000000000000000000000123DDA8328AF9C23A94C1FEECFD123BA4FB73476F0D594DCB65C6425BD186051",
+		false,
+		"sG - eP is infinite. Test fails in single verification if has_even_y(inf) is defined as true and x(inf) as 0",
+	},
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"00000000000000000000000000000000000000000000000000000000000000017615FBAF5AE28864013C099742DEADB4DBA87F11AC6754F93780D5A1837CF197",
+		false,
+		"sG - eP is infinite. Test fails in single verification if has_even_y(inf) is defined as true and x(inf) as 1",
+	},
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"4A298DACAE57395A15D0795DDBFD1DCB564DA82B0F269BC70A74F8220429BA1D69E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B",
+		false,
+		"sig[0:32] is not an X coordinate on the curve",
+	},
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F69E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B",
+		false,
+		"sig[0:32] is equal to field size",
+	},
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E177769FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141",
+		false,
+		"sig[32:64] is equal to curve order",
+	},
+	{
+		"",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E17776969E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B",
+		false,
+		"public key is not a valid X coordinate because it exceeds the field size",
+	},
+	{
+		"0340034003400340034003400340034003400340034003400340034003400340",
+		"778CAA53B4393AC467774D09497A87224BF9FAB6F6E68B23086497324D6FD117",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"",
+		"71535DB165ECD9FBBC046E5FFAEA61186BB6AD436732FCCC25291A55895464CF6069CE26BF03466228F19A3A62DB8A649F2D560FAC652827D1AF0574E427AB63",
+		true,
+		"message of size 0 (added 2022-12)",
+	},
+	{
+		"0340034003400340034003400340034003400340034003400340034003400340",
+		"778CAA53B4393AC467774D09497A87224BF9FAB6F6E68B23086497324D6FD117",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"11",
+		"08A20A0AFEF64124649232E0693C583AB1B9934AE63B4C3511F3AE1134C6A303EA3173BFEA6683BD101FA5AA5DBC1996FE7CACFC5A577D33EC14564CEC2BACBF",
+		true,
+		"message of size 1 (added 2022-12)",
+	},
+	{
+		"0340034003400340034003400340034003400340034003400340034003400340",
+		"778CAA53B4393AC467774D09497A87224BF9FAB6F6E68B23086497324D6FD117",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0102030405060708090A0B0C0D0E0F1011",
+		"5130F39A4059B43BC7CAC09A19ECE52B5D8699D1A71E3C52DA9AFDB6B50AC370C4A482B77BF960F8681540E25B6771ECE1E5A37FD80E5A51897C5566A97EA5A5",
+		true,
+		"message of size 17 (added 2022-12)",
+	},
+	{
+		"0340034003400340034003400340034003400340034003400340034003400340",
+		"778CAA53B4393AC467774D09497A87224BF9FAB6F6E68B23086497324D6FD117",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"99999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999",
+		"403B12B0D8555A344175EA7EC746566303321E5DBFA8BE6F091635163ECA79A8585ED3E3170807E7C03B720FC54C7B23897FCBA0E9D0B4A06894CFD249F22367",
+		true,
+		"message of size 100 (added 2022-12)",
+	},
+}
+
+func TestBIP340Vectors(t *testing.T) {
+	for i, v := range bip340Vectors {
+		publicKey, _ := hex.DecodeString(v.publicKey)
+		message, _ := hex.DecodeString(v.message)
+		signature, _ := hex.DecodeString(v.signature)
+
+		if v.secretKey != "" {
+			secretKey, _ := hex.DecodeString(v.secretKey)
+			auxRand, _ := hex.DecodeString(v.auxRand)
+			priv, err := NewPrivateKey(secretKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(priv.PublicKey.Bytes(), publicKey) {
+				t.Fatalf("vector %d: wrong public key", i)
+			}
+			sig, err := priv.SignWithAuxRand(message, auxRand, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(sig, signature) {
+				t.Fatalf("vector %d: wrong signature", i)
+			}
+		}
+
+		var pub PublicKey
+		if _, err := pub.SetBytes(publicKey); err != nil {
+			if v.valid {
+				t.Fatalf("vector %d: %v", i, err)
+			}
+			continue
+		}
+		valid, _ := pub.Verify(signature, message, nil)
+		if valid != v.valid {
+			t.Fatalf("vector %d (%s): expected %t, got %t", i, v.comment, v.valid, valid)
+		}
+
+		// a batch made of the vector alone must agree with Verify
+		batchValid, _ := BatchVerify([]PublicKey{pub}, [][]byte{signature}, [][]byte{message}, nil)
+		if batchValid != valid {
+			t.Fatalf("vector %d (%s): batch verification disagrees", i, v.comment)
+		}
+	}
+}
+
+// test vectors from https://github.com/bitcoin/bips/blob/master/bip-0341/wallet-test-vectors.json
+func TestBIP341Tweak(t *testing.T) {
+	vectors := []struct {
+		internalKey, merkleRoot, outputKey string
+		parity                             uint
+	}{
+		{
+			"d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d",
+			"",
+			"53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343",
+			1,
+		},
+		{
+			"187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27",
+			"5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21",
+			"147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3",
+			1,
+		},
+	}
+	for i, v := range vectors {
+		internalKey, _ := hex.DecodeString(v.internalKey)
+		merkleRoot, _ := hex.DecodeString(v.merkleRoot)
+		outputKey, _ := hex.DecodeString(v.outputKey)
+
+		var pub PublicKey
+		if _, err := pub.SetBytes(internalKey); err != nil {
+			t.Fatal(err)
+		}
+		tweaked, parity, err := pub.Tweak(merkleRoot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(tweaked.Bytes(), outputKey) || parity != v.parity {
+			t.Fatalf("vector %d: wrong output key", i)
+		}
+	}
+
+	// key path spending
+	internalSecretKey, _ := hex.DecodeString("6b973d88838f27366ed61c9ad6367663045cb456e28335c109e30717ae0c6baa")
+	tweakedSecretKey, _ := hex.DecodeString("2405b971772ad26915c8dcdf10f238753a9b837e5f8e6a86fd7c0cce5b7296d9")
+	priv, err := NewPrivateKey(internalSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tweakedPriv, err := priv.Tweak(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := NewPrivateKey(tweakedSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(tweakedPriv.Bytes(), expected.Bytes()) {
+		t.Fatal("wrong tweaked private key")
+	}
+	tweakedPub, _, err := priv.PublicKey.Tweak(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tweakedPub.Equal(tweakedPriv.Public()) {
+		t.Fatal("tweaked public and private keys do not match")
+	}
+	msg := []byte("key path spend")
+	sig, err := tweakedPriv.Sign(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := tweakedPub.Verify(sig, msg, nil); err != nil || !ok {
+		t.Fatal("signature with the tweaked key should verify under the output key")
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	t.Parallel()
+	msg := []byte("testing BIP-340 signatures")
+	for i := 0; i < 10; i++ {
+		priv, err := GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub := priv.Public()
+		for _, hFunc := range []hash.Hash{nil, sha256.New()} {
+			sig, err := priv.Sign(msg, hFunc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok, err := pub.Verify(sig, msg, hFunc); err != nil || !ok {
+				t.Fatal("signature should verify")
+			}
+			sig[SizeSignature-1] ^= 1
+			if ok, _ := pub.Verify(sig, msg, hFunc); ok {
+				t.Fatal("altered signature should not verify")
+			}
+		}
+	}
+}
+
+func TestSerialization(t *testing.T) {
+	t.Parallel()
+	priv, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var priv2 PrivateKey
+	if _, err := priv2.SetBytes(priv.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(priv.Bytes(), priv2.Bytes()) {
+		t.Fatal("private key serialization round trip failed")
+	}
+	var pub PublicKey
+	if _, err := pub.SetBytes(priv.PublicKey.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if !pub.A.Equal(&priv.PublicKey.A) {
+		t.Fatal("public key serialization round trip failed")
+	}
+
+	sigBin, err := priv.Sign([]byte("message"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sig.Bytes(), sigBin) {
+		t.Fatal("signature serialization round trip failed")
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	t.Parallel()
+	const n = 16
+	publicKeys := make([]PublicKey, n)
+	signatures := make([][]byte, n)
+	messages := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		priv, err := GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKeys[i] = priv.PublicKey
+		messages[i] = []byte{byte(i)}
+		if signatures[i], err = priv.Sign(messages[i], sha256.New()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if ok, err := BatchVerify(publicKeys, signatures, messages, sha256.New()); err != nil || !ok {
+		t.Fatal("valid batch should verify")
+	}
+	messages[n/2] = []byte("tampered")
+	if ok, _ := BatchVerify(publicKeys, signatures, messages, sha256.New()); ok {
+		t.Fatal("batch with an invalid signature should not verify")
+	}
+	if _, err := BatchVerify(publicKeys, signatures[1:], messages, sha256.New()); err == nil {
+		t.Fatal("batch with mismatched lengths should fail")
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	priv, _ := GenerateKey(rand.Reader)
+	msg := []byte("benchmark")
+	sig, _ := priv.Sign(msg, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = priv.PublicKey.Verify(sig, msg, nil)
+	}
+}
+
+func BenchmarkBatchVerify64(b *testing.B) {
+	const n = 64
+	publicKeys := make([]PublicKey, n)
+	signatures := make([][]byte, n)
+	messages := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		priv, _ := GenerateKey(rand.Reader)
+		publicKeys[i] = priv.PublicKey
+		messages[i] = []byte{byte(i)}
+		signatures[i], _ = priv.Sign(messages[i], nil)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = BatchVerify(publicKeys, signatures, messages, nil)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+// tag of the BIP-341 key tweak
+const tagTapTweak = "TapTweak"
+
+var errInvalidTweak = errors.New("invalid taproot tweak")
+
+// Tweak returns the Taproot output key Q = P + [t]G of BIP-341, with
+// t = hash_TapTweak(bytes(P) || merkleRoot), as an x-only public key, together
+// with the parity of the y-coordinate of Q, needed by script path spends.
+//
+// merkleRoot is the root of the script tree, or empty when the output can only
+// be spent with the key path.
+func (pub *PublicKey) Tweak(merkleRoot []byte) (*PublicKey, uint, error) {
+	t, err := tapTweak(pub, merkleRoot)
+	if err != nil {
+		return nil, 0, err
+	}
+	var bt big.Int
+	t.BigInt(&bt)
+	var Q secp256k1.G1Jac
+	Q.ScalarMultiplicationBase(&bt).AddMixed(&pub.A)
+	if Q.Z.IsZero() {
+		return nil, 0, errInvalidTweak
+	}
+
+	var res PublicKey
+	res.A.FromJacobian(&Q)
+	var parity uint
+	if isOdd(&res.A.Y) {
+		res.A.Y.Neg(&res.A.Y)
+		parity = 1
+	}
+	return &res, parity, nil
+}
+
+// Tweak returns the private key of the Taproot output key
+// privKey.PublicKey.Tweak(merkleRoot), to sign key path spends. Its secret
+// scalar is d + t, with d the secret scalar of privKey and t the BIP-341 tweak.
+func (privKey *PrivateKey) Tweak(merkleRoot []byte) (*PrivateKey, error) {
+	t, err := tapTweak(&privKey.PublicKey, merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	var d fr.Element
+	d.SetBytes(privKey.scalar[:])
+	d.Add(&d, &t)
+	if d.IsZero() {
+		return nil, errInvalidTweak
+	}
+	return newPrivateKey(&d), nil
+}
+
+// tapTweak returns t = hash_TapTweak(bytes(P) || merkleRoot), failing if t is
+// not reduced modulo n
+func tapTweak(pub *PublicKey, merkleRoot []byte) (fr.Element, error) {
+	h := taggedHash(tagTapTweak, pub.Bytes(), merkleRoot)
+	var t fr.Element
+	if err := t.SetBytesCanonical(h[:]); err != nil {
+		return t, errInvalidTweak
+	}
+	return t, nil
+}
@@ -0,0 +1,330 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package vesta
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/vesta/fp"
+
+	"math/big"
+)
+
+//Note: This only works for simple extensions
+
+func g1IsogenyXNumerator(dst *fp.Element, x *fp.Element) {
+	g1EvalPolynomial(dst,
+		false,
+		[]fp.Element{
+			{13833077645384759355, 14120518942617613401, 6148914691236517182, 1537228672809129301},
+			{3737553430635422151, 17804021253108658728, 6700895865114211839, 4374326469249375241},
+			{5059250273826196811, 7501736491999147920, 11707961849471054223, 4225721442258584952},
+			{12494418916648885362, 12846680416677304141, 12297829382473034410, 3074457345618258602},
+		},
+		x)
+}
+
+func g1IsogenyXDenominator(dst *fp.Element, x *fp.Element) {
+	g1EvalPolynomial(dst,
+		true,
+		[]fp.Element{
+			{14234015774389735396, 13134462864343305370, 14903491694697755131, 2814664209048433916},
+			{1562541086686033819, 10863503049219517734, 13137936276691729929, 1138004832908161341},
+		},
+		x)
+}
+
+func g1IsogenyYNumerator(dst *fp.Element, x *fp.Element, y *fp.Element) {
+	var _dst fp.Element
+	g1EvalPolynomial(&_dst,
+		false,
+		[]fp.Element{
+			{14958460940039827444, 2754765690918113131, 14347467612885206831, 2049638230412172401},
+			{11345288168886336471, 3354966629818523716, 6123207431709225130, 3741367671441341474},
+			{16807009644090926758, 14209155109813956662, 5853980924735527111, 4418703730342986428},
+			{4754574908077181308, 18226609290644944984, 10248191152060862008, 4099276460824344803},
+		},
+		x)
+
+	dst.Mul(&_dst, y)
+}
+
+func g1IsogenyYDenominator(dst *fp.Element, x *fp.Element) {
+	g1EvalPolynomial(dst,
+		true,
+		[]fp.Element{
+			{10835632597187430512, 3723015163441368399, 289},
+			{14147278308813549483, 40070791691599090, 7816986936674162163, 3832306608717913846},
+			{16621196137206879081, 8306797363934107687, 10483532378182819086, 4012850258575935964},
+		},
+		x)
+}
+
+func g1Isogeny(p *G1Affine) {
+
+	den := make([]fp.Element, 2)
+
+	g1IsogenyYDenominator(&den[1], &p.X)
+	g1IsogenyXDenominator(&den[0], &p.X)
+
+	g1IsogenyYNumerator(&p.Y, &p.X, &p.Y)
+	g1IsogenyXNumerator(&p.X, &p.X)
+
+	den = fp.BatchInvert(den)
+
+	p.X.Mul(&p.X, &den[0])
+	p.Y.Mul(&p.Y, &den[1])
+}
+
+// g1SqrtRatio computes the square root of u/v and returns 0 iff u/v was indeed a quadratic residue
+// if not, we get sqrt(Z * u / v). Recall that Z is non-residue
+// If v = 0, u/v is meaningless and the output is unspecified, without raising an error.
+// The main idea is that since the computation of the square root involves taking large powers of u/v, the inversion of v can be avoided
+func g1SqrtRatio(z *fp.Element, u *fp.Element, v *fp.Element) uint64 {
+
+	// https://www.ietf.org/archive/id/draft-irtf-cfrg-hash-to-curve-16.html#name-sqrt_ratio-for-any-field
+
+	tv1 := fp.Element{17985549483278292049, 18213300779339099400, 15943582106433663353, 4551530924924225328} //tv1 = c6
+
+	var tv2, tv3, tv4, tv5 fp.Element
+	var exp big.Int
+	// c4 = 4294967295 = 2³² - 1
+	// q is odd so c1 is at least 1.
+	exp.SetBytes([]byte{255, 255, 255, 255})
+
+	tv2.Exp(*v, &exp) // 2. tv2 = vᶜ⁴
+	tv3.Square(&tv2)  // 3. tv3 = tv2²
+	tv3.Mul(&tv3, v)  // 4. tv3 = tv3 * v
+	tv5.Mul(u, &tv3)  // 5. tv5 = u * tv3
+
+	// c3 = 3369993333393829974333376885877453834209946971612708570864021632400
+	exp.SetBytes([]byte{32, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 17, 35, 76, 126, 4, 202, 84, 110, 198, 35, 117, 144})
+
+	tv5.Exp(tv5, &exp)  // 6. tv5 = tv5ᶜ³
+	tv5.Mul(&tv5, &tv2) // 7. tv5 = tv5 * tv2
+	tv2.Mul(&tv5, v)    // 8. tv2 = tv5 * v
+	tv3.Mul(&tv5, u)    // 9. tv3 = tv5 * u
+	tv4.Mul(&tv3, &tv2) // 10. tv4 = tv3 * tv2
+
+	// c5 = 2147483648
+	exp.SetBytes([]byte{128, 0, 0, 0})
+	tv5.Exp(tv4, &exp)      // 11. tv5 = tv4ᶜ⁵
+	isQNr := g1NotOne(&tv5) // 12. isQR = tv5 == 1
+	c7 := fp.Element{15196519823334225291, 18423303042886042978, 3229999199220620821, 2981324872578789896}
+	tv2.Mul(&tv3, &c7)                 // 13. tv2 = tv3 * c7
+	tv5.Mul(&tv4, &tv1)                // 14. tv5 = tv4 * tv1
+	tv3.Select(int(isQNr), &tv3, &tv2) // 15. tv3 = CMOV(tv2, tv3, isQR)
+	tv4.Select(int(isQNr), &tv4, &tv5) // 16. tv4 = CMOV(tv5, tv4, isQR)
+	exp.Lsh(big.NewInt(1), 32-2)       // 18, 19: tv5 = 2ⁱ⁻² for i = c1
+
+	for i := 32; i >= 2; i-- { // 17. for i in (c1, c1 - 1, ..., 2):
+
+		tv5.Exp(tv4, &exp)               // 20.    tv5 = tv4ᵗᵛ⁵
+		nE1 := g1NotOne(&tv5)            // 21.    e1 = tv5 == 1
+		tv2.Mul(&tv3, &tv1)              // 22.    tv2 = tv3 * tv1
+		tv1.Mul(&tv1, &tv1)              // 23.    tv1 = tv1 * tv1    Why not write square?
+		tv5.Mul(&tv4, &tv1)              // 24.    tv5 = tv4 * tv1
+		tv3.Select(int(nE1), &tv3, &tv2) // 25.    tv3 = CMOV(tv2, tv3, e1)
+		tv4.Select(int(nE1), &tv4, &tv5) // 26.    tv4 = CMOV(tv5, tv4, e1)
+
+		if i > 2 {
+			exp.Rsh(&exp, 1) // 18, 19. tv5 = 2ⁱ⁻²
+		}
+	}
+
+	*z = tv3
+	return isQNr
+}
+
+func g1NotOne(x *fp.Element) uint64 {
+
+	var one fp.Element
+	return one.SetOne().NotEqual(x)
+
+}
+
+// g1MulByZ multiplies x by [-13] and stores the result in z
+func g1MulByZ(z *fp.Element, x *fp.Element) {
+
+	var res fp.Element
+	res.Neg(x)
+
+	res.Double(&res)
+	res.Sub(&res, x)
+	res.Double(&res)
+	res.Double(&res)
+	res.Sub(&res, x)
+
+	*z = res
+}
+
+// https://www.ietf.org/archive/id/draft-irtf-cfrg-hash-to-curve-16.html#name-simplified-swu-method
+// MapToCurve1 implements the SSWU map
+// No cofactor clearing or isogeny
+func MapToCurve1(u *fp.Element) G1Affine {
+
+	var sswuIsoCurveCoeffA = fp.Element{16401502074509598816, 11995987059199023178, 5661926572136137742, 2915615352311588021}
+	var sswuIsoCurveCoeffB = fp.Element{16323141667940985917, 12024262797773434324, 18446744073709550938, 4611686018427387903}
+
+	var tv1 fp.Element
+	tv1.Square(u) // 1.  tv1 = u²
+
+	//mul tv1 by Z
+	g1MulByZ(&tv1, &tv1) // 2.  tv1 = Z * tv1
+
+	var tv2 fp.Element
+	tv2.Square(&tv1)    // 3.  tv2 = tv1²
+	tv2.Add(&tv2, &tv1) // 4.  tv2 = tv2 + tv1
+
+	var tv3 fp.Element
+	var tv4 fp.Element
+	tv4.SetOne()
+	tv3.Add(&tv2, &tv4)                // 5.  tv3 = tv2 + 1
+	tv3.Mul(&tv3, &sswuIsoCurveCoeffB) // 6.  tv3 = B * tv3
+
+	tv2NZero := g1NotZero(&tv2)
+
+	// tv4 = Z
+	tv4 = fp.Element{9108462849730019380, 17750677561541807360, 6}
+
+	tv2.Neg(&tv2)
+	tv4.Select(int(tv2NZero), &tv4, &tv2) // 7.  tv4 = CMOV(Z, -tv2, tv2 != 0)
+	tv4.Mul(&tv4, &sswuIsoCurveCoeffA)    // 8.  tv4 = A * tv4
+
+	tv2.Square(&tv3) // 9.  tv2 = tv3²
+
+	var tv6 fp.Element
+	tv6.Square(&tv4) // 10. tv6 = tv4²
+
+	var tv5 fp.Element
+	tv5.Mul(&tv6, &sswuIsoCurveCoeffA) // 11. tv5 = A * tv6
+
+	tv2.Add(&tv2, &tv5) // 12. tv2 = tv2 + tv5
+	tv2.Mul(&tv2, &tv3) // 13. tv2 = tv2 * tv3
+	tv6.Mul(&tv6, &tv4) // 14. tv6 = tv6 * tv4
+
+	tv5.Mul(&tv6, &sswuIsoCurveCoeffB) // 15. tv5 = B * tv6
+	tv2.Add(&tv2, &tv5)                // 16. tv2 = tv2 + tv5
+
+	var x fp.Element
+	x.Mul(&tv1, &tv3) // 17.   x = tv1 * tv3
+
+	var y1 fp.Element
+	gx1NSquare := g1SqrtRatio(&y1, &tv2, &tv6) // 18. (is_gx1_square, y1) = sqrt_ratio(tv2, tv6)
+
+	var y fp.Element
+	y.Mul(&tv1, u) // 19.   y = tv1 * u
+
+	y.Mul(&y, &y1) // 20.   y = y * y1
+
+	x.Select(int(gx1NSquare), &tv3, &x) // 21.   x = CMOV(x, tv3, is_gx1_square)
+	y.Select(int(gx1NSquare), &y1, &y)  // 22.   y = CMOV(y, y1, is_gx1_square)
+
+	y1.Neg(&y)
+	y.Select(int(g1Sgn0(u)^g1Sgn0(&y)), &y, &y1)
+
+	// 23.  e1 = sgn0(u) == sgn0(y)
+	// 24.   y = CMOV(-y, y, e1)
+
+	x.Div(&x, &tv4) // 25.   x = x / tv4
+
+	return G1Affine{x, y}
+}
+
+func g1EvalPolynomial(z *fp.Element, monic bool, coefficients []fp.Element, x *fp.Element) {
+	dst := coefficients[len(coefficients)-1]
+
+	if monic {
+		dst.Add(&dst, x)
+	}
+
+	for i := len(coefficients) - 2; i >= 0; i-- {
+		dst.Mul(&dst, x)
+		dst.Add(&dst, &coefficients[i])
+	}
+
+	z.Set(&dst)
+}
+
+// g1Sgn0 is an algebraic substitute for the notion of sign in ordered fields
+// Namely, every non-zero quadratic residue in a finite field of characteristic =/= 2 has exactly two square roots, one of each sign
+// https://www.ietf.org/archive/id/draft-irtf-cfrg-hash-to-curve-16.html#name-the-sgn0-function
+// The sign of an element is not obviously related to that of its Montgomery form
+func g1Sgn0(z *fp.Element) uint64 {
+
+	nonMont := z.Bits()
+
+	// m == 1
+	return nonMont[0] % 2
+
+}
+
+// MapToG1 invokes the SSWU map, and guarantees that the result is in g1
+func MapToG1(u fp.Element) G1Affine {
+	res := MapToCurve1(&u)
+	//this is in an isogenous curve
+	g1Isogeny(&res)
+	return res
+}
+
+// EncodeToG1 hashes a message to a point on the G1 curve using the SSWU map.
+// It is faster than HashToG1, but the result is not uniformly distributed. Unsuitable as a random oracle.
+// dst stands for "domain separation tag", a string unique to the construction using the hash function
+// https://www.ietf.org/archive/id/draft-irtf-cfrg-hash-to-curve-16.html#roadmap
+func EncodeToG1(msg, dst []byte) (G1Affine, error) {
+
+	var res G1Affine
+	u, err := fp.Hash(msg, dst, 1)
+	if err != nil {
+		return res, err
+	}
+
+	res = MapToCurve1(&u[0])
+
+	//this is in an isogenous curve
+	g1Isogeny(&res)
+	return res, nil
+}
+
+// HashToG1 hashes a message to a point on the G1 curve using the SSWU map.
+// Slower than EncodeToG1, but usable as a random oracle.
+// dst stands for "domain separation tag", a string unique to the construction using the hash function
+// https://www.ietf.org/archive/id/draft-irtf-cfrg-hash-to-curve-16.html#roadmap
+func HashToG1(msg, dst []byte) (G1Affine, error) {
+	u, err := fp.Hash(msg, dst, 2*1)
+	if err != nil {
+		return G1Affine{}, err
+	}
+
+	Q0 := MapToCurve1(&u[0])
+	Q1 := MapToCurve1(&u[1])
+
+	//TODO (perf): Add in E' first, then apply isogeny
+	g1Isogeny(&Q0)
+	g1Isogeny(&Q1)
+
+	var _Q0, _Q1 G1Jac
+	_Q0.FromAffine(&Q0)
+	_Q1.FromAffine(&Q1).AddAssign(&_Q0)
+
+	Q1.FromJacobian(&_Q1)
+	return Q1, nil
+}
+
+func g1NotZero(x *fp.Element) uint64 {
+
+	return x[0] | x[1] | x[2] | x[3]
+
+}
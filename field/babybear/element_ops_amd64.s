@@ -0,0 +1,340 @@
+// +build !purego
+
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+#include "textflag.h"
+#include "funcdata.h"
+
+// addVecAVX512(res, a, b *Element, n uint64) res[0...16*n] = a[0...16*n] + b[0...16*n]
+TEXT ·addVecAVX512(SB), NOSPLIT, $0-32
+	MOVQ         $2013265921, AX
+	VPBROADCASTD AX, Z0
+	MOVQ         res+0(FP), DX
+	MOVQ         a+8(FP), CX
+	MOVQ         b+16(FP), BX
+	MOVQ         n+24(FP), SI
+
+loop_1:
+	TESTQ     SI, SI
+	JEQ       done_2     // n == 0, we are done
+	VMOVDQU32 0(CX), Z1
+	VMOVDQU32 0(BX), Z2
+	VPADDD    Z1, Z2, Z1
+	VPSUBD    Z0, Z1, Z2
+	VPMINUD   Z1, Z2, Z1
+	VMOVDQU32 Z1, 0(DX)
+
+	// increment pointers to visit next block
+	ADDQ $64, DX
+	ADDQ $64, CX
+	ADDQ $64, BX
+	DECQ SI      // decrement n
+	JMP  loop_1
+
+done_2:
+	RET
+
+// subVecAVX512(res, a, b *Element, n uint64) res[0...16*n] = a[0...16*n] - b[0...16*n]
+TEXT ·subVecAVX512(SB), NOSPLIT, $0-32
+	MOVQ         $2013265921, AX
+	VPBROADCASTD AX, Z0
+	MOVQ         res+0(FP), DX
+	MOVQ         a+8(FP), CX
+	MOVQ         b+16(FP), BX
+	MOVQ         n+24(FP), SI
+
+loop_3:
+	TESTQ     SI, SI
+	JEQ       done_4     // n == 0, we are done
+	VMOVDQU32 0(CX), Z1
+	VMOVDQU32 0(BX), Z2
+	VPSUBD    Z2, Z1, Z1
+
+	// if a < b, a - b wraps around and a - b + q < q is the smallest
+	VPADDD    Z0, Z1, Z2
+	VPMINUD   Z1, Z2, Z1
+	VMOVDQU32 Z1, 0(DX)
+
+	// increment pointers to visit next block
+	ADDQ $64, DX
+	ADDQ $64, CX
+	ADDQ $64, BX
+	DECQ SI      // decrement n
+	JMP  loop_3
+
+done_4:
+	RET
+
+// mulVecAVX512(res, a, b *Element, n uint64) res[0...16*n] = a[0...16*n] * b[0...16*n]
+TEXT ·mulVecAVX512(SB), NOSPLIT, $0-32
+	MOVQ         $2013265921, AX
+	VPBROADCASTD AX, Z0
+	MOVQ         $2013265919, AX
+	VPBROADCASTD AX, Z1
+
+	// K3 selects the even 32-bit lanes
+	MOVQ  $0x5555, AX
+	KMOVW AX, K3
+	MOVQ  res+0(FP), DX
+	MOVQ  a+8(FP), CX
+	MOVQ  b+16(FP), BX
+	MOVQ  n+24(FP), SI
+
+loop_5:
+	TESTQ     SI, SI
+	JEQ       done_6    // n == 0, we are done
+	VMOVDQU32 0(CX), Z2
+
+	// VPMULUDQ multiplies the even 32-bit lanes into 64-bit products
+	VPSRLQ    $32, Z2, Z4
+	VMOVDQU32 0(BX), Z3
+	VPSRLQ    $32, Z3, Z5
+	VPMULUDQ  Z2, Z3, Z6
+	VPMULUDQ  Z4, Z5, Z7
+
+	// m = p * qInvNeg mod 2³²; t = (p + m * q) / 2³² < 2q
+	VPMULUDQ Z6, Z1, Z8
+	VPMULUDQ Z7, Z1, Z9
+	VPMULUDQ Z8, Z0, Z8
+	VPMULUDQ Z9, Z0, Z9
+	VPADDQ   Z6, Z8, Z6
+	VPADDQ   Z7, Z9, Z7
+
+	// even results are in the high 32 bits of pEven, odd results in the high 32 bits of pOdd
+	VPSRLQ    $32, Z6, Z6
+	VPBLENDMD Z6, Z7, K3, Z7
+	VPSUBD    Z0, Z7, Z8
+	VPMINUD   Z7, Z8, Z7
+	VMOVDQU32 Z7, 0(DX)
+
+	// increment pointers to visit next block
+	ADDQ $64, DX
+	ADDQ $64, CX
+	ADDQ $64, BX
+	DECQ SI      // decrement n
+	JMP  loop_5
+
+done_6:
+	RET
+
+// scalarMulVecAVX512(res, a, b *Element, n uint64) res[0...16*n] = a[0...16*n] * b
+TEXT ·scalarMulVecAVX512(SB), NOSPLIT, $0-32
+	MOVQ         $2013265921, AX
+	VPBROADCASTD AX, Z0
+	MOVQ         $2013265919, AX
+	VPBROADCASTD AX, Z1
+
+	// K3 selects the even 32-bit lanes
+	MOVQ         $0x5555, AX
+	KMOVW        AX, K3
+	MOVQ         res+0(FP), DX
+	MOVQ         a+8(FP), CX
+	MOVQ         b+16(FP), BX
+	MOVQ         n+24(FP), SI
+	VPBROADCASTD 0(BX), Z3
+
+loop_7:
+	TESTQ     SI, SI
+	JEQ       done_8    // n == 0, we are done
+	VMOVDQU32 0(CX), Z2
+
+	// VPMULUDQ multiplies the even 32-bit lanes into 64-bit products
+	VPSRLQ   $32, Z2, Z4
+	VPMULUDQ Z2, Z3, Z6
+	VPMULUDQ Z4, Z3, Z7
+
+	// m = p * qInvNeg mod 2³²; t = (p + m * q) / 2³² < 2q
+	VPMULUDQ Z6, Z1, Z8
+	VPMULUDQ Z7, Z1, Z9
+	VPMULUDQ Z8, Z0, Z8
+	VPMULUDQ Z9, Z0, Z9
+	VPADDQ   Z6, Z8, Z6
+	VPADDQ   Z7, Z9, Z7
+
+	// even results are in the high 32 bits of pEven, odd results in the high 32 bits of pOdd
+	VPSRLQ    $32, Z6, Z6
+	VPBLENDMD Z6, Z7, K3, Z7
+	VPSUBD    Z0, Z7, Z8
+	VPMINUD   Z7, Z8, Z7
+	VMOVDQU32 Z7, 0(DX)
+
+	// increment pointers to visit next block
+	ADDQ $64, DX
+	ADDQ $64, CX
+	DECQ SI      // decrement n
+	JMP  loop_7
+
+done_8:
+	RET
+
+// addVecAVX2(res, a, b *Element, n uint64) res[0...8*n] = a[0...8*n] + b[0...8*n]
+TEXT ·addVecAVX2(SB), NOSPLIT, $0-32
+	MOVQ         $2013265921, AX
+	MOVD         AX, X0
+	VPBROADCASTD X0, Y0
+	MOVQ         res+0(FP), DX
+	MOVQ         a+8(FP), CX
+	MOVQ         b+16(FP), BX
+	MOVQ         n+24(FP), SI
+
+loop_9:
+	TESTQ   SI, SI
+	JEQ     done_10    // n == 0, we are done
+	VMOVDQU 0(CX), Y1
+	VMOVDQU 0(BX), Y2
+	VPADDD  Y1, Y2, Y1
+	VPSUBD  Y0, Y1, Y2
+	VPMINUD Y1, Y2, Y1
+	VMOVDQU Y1, 0(DX)
+
+	// increment pointers to visit next block
+	ADDQ $32, DX
+	ADDQ $32, CX
+	ADDQ $32, BX
+	DECQ SI      // decrement n
+	JMP  loop_9
+
+done_10:
+	RET
+
+// subVecAVX2(res, a, b *Element, n uint64) res[0...8*n] = a[0...8*n] - b[0...8*n]
+TEXT ·subVecAVX2(SB), NOSPLIT, $0-32
+	MOVQ         $2013265921, AX
+	MOVD         AX, X0
+	VPBROADCASTD X0, Y0
+	MOVQ         res+0(FP), DX
+	MOVQ         a+8(FP), CX
+	MOVQ         b+16(FP), BX
+	MOVQ         n+24(FP), SI
+
+loop_11:
+	TESTQ   SI, SI
+	JEQ     done_12    // n == 0, we are done
+	VMOVDQU 0(CX), Y1
+	VMOVDQU 0(BX), Y2
+	VPSUBD  Y2, Y1, Y1
+
+	// if a < b, a - b wraps around and a - b + q < q is the smallest
+	VPADDD  Y0, Y1, Y2
+	VPMINUD Y1, Y2, Y1
+	VMOVDQU Y1, 0(DX)
+
+	// increment pointers to visit next block
+	ADDQ $32, DX
+	ADDQ $32, CX
+	ADDQ $32, BX
+	DECQ SI      // decrement n
+	JMP  loop_11
+
+done_12:
+	RET
+
+// mulVecAVX2(res, a, b *Element, n uint64) res[0...8*n] = a[0...8*n] * b[0...8*n]
+TEXT ·mulVecAVX2(SB), NOSPLIT, $0-32
+	MOVQ         $2013265921, AX
+	MOVD         AX, X0
+	VPBROADCASTD X0, Y0
+	MOVQ         $2013265919, AX
+	MOVD         AX, X1
+	VPBROADCASTD X1, Y1
+	MOVQ         res+0(FP), DX
+	MOVQ         a+8(FP), CX
+	MOVQ         b+16(FP), BX
+	MOVQ         n+24(FP), SI
+
+loop_13:
+	TESTQ   SI, SI
+	JEQ     done_14   // n == 0, we are done
+	VMOVDQU 0(CX), Y2
+
+	// VPMULUDQ multiplies the even 32-bit lanes into 64-bit products
+	VPSRLQ   $32, Y2, Y4
+	VMOVDQU  0(BX), Y3
+	VPSRLQ   $32, Y3, Y5
+	VPMULUDQ Y2, Y3, Y6
+	VPMULUDQ Y4, Y5, Y7
+
+	// m = p * qInvNeg mod 2³²; t = (p + m * q) / 2³² < 2q
+	VPMULUDQ Y6, Y1, Y8
+	VPMULUDQ Y7, Y1, Y9
+	VPMULUDQ Y8, Y0, Y8
+	VPMULUDQ Y9, Y0, Y9
+	VPADDQ   Y6, Y8, Y6
+	VPADDQ   Y7, Y9, Y7
+
+	// even results are in the high 32 bits of pEven, odd results in the high 32 bits of pOdd
+	VPSRLQ   $32, Y6, Y6
+	VPBLENDD $0x55, Y6, Y7, Y7
+	VPSUBD   Y0, Y7, Y8
+	VPMINUD  Y7, Y8, Y7
+	VMOVDQU  Y7, 0(DX)
+
+	// increment pointers to visit next block
+	ADDQ $32, DX
+	ADDQ $32, CX
+	ADDQ $32, BX
+	DECQ SI      // decrement n
+	JMP  loop_13
+
+done_14:
+	RET
+
+// scalarMulVecAVX2(res, a, b *Element, n uint64) res[0...8*n] = a[0...8*n] * b
+TEXT ·scalarMulVecAVX2(SB), NOSPLIT, $0-32
+	MOVQ         $2013265921, AX
+	MOVD         AX, X0
+	VPBROADCASTD X0, Y0
+	MOVQ         $2013265919, AX
+	MOVD         AX, X1
+	VPBROADCASTD X1, Y1
+	MOVQ         res+0(FP), DX
+	MOVQ         a+8(FP), CX
+	MOVQ         b+16(FP), BX
+	MOVQ         n+24(FP), SI
+	VPBROADCASTD 0(BX), Y3
+
+loop_15:
+	TESTQ   SI, SI
+	JEQ     done_16   // n == 0, we are done
+	VMOVDQU 0(CX), Y2
+
+	// VPMULUDQ multiplies the even 32-bit lanes into 64-bit products
+	VPSRLQ   $32, Y2, Y4
+	VPMULUDQ Y2, Y3, Y6
+	VPMULUDQ Y4, Y3, Y7
+
+	// m = p * qInvNeg mod 2³²; t = (p + m * q) / 2³² < 2q
+	VPMULUDQ Y6, Y1, Y8
+	VPMULUDQ Y7, Y1, Y9
+	VPMULUDQ Y8, Y0, Y8
+	VPMULUDQ Y9, Y0, Y9
+	VPADDQ   Y6, Y8, Y6
+	VPADDQ   Y7, Y9, Y7
+
+	// even results are in the high 32 bits of pEven, odd results in the high 32 bits of pOdd
+	VPSRLQ   $32, Y6, Y6
+	VPBLENDD $0x55, Y6, Y7, Y7
+	VPSUBD   Y0, Y7, Y8
+	VPMINUD  Y7, Y8, Y7
+	VMOVDQU  Y7, 0(DX)
+
+	// increment pointers to visit next block
+	ADDQ $32, DX
+	ADDQ $32, CX
+	DECQ SI      // decrement n
+	JMP  loop_15
+
+done_16:
+	RET
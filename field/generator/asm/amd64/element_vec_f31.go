@@ -0,0 +1,266 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amd64
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/bavard/amd64"
+	"github.com/consensys/gnark-crypto/field/generator/config"
+)
+
+// vectorUnit describes a SIMD instruction set used to process several
+// 31-bit field elements at once, one per 32-bit lane.
+type vectorUnit struct {
+	name    string // function name suffix
+	reg     string // register prefix (Z or Y)
+	nbLanes int    // number of 32-bit lanes in a register
+	mov     string // unaligned load / store instruction
+}
+
+var (
+	avx512 = vectorUnit{name: "AVX512", reg: "Z", nbLanes: 16, mov: "VMOVDQU32"}
+	avx2   = vectorUnit{name: "AVX2", reg: "Y", nbLanes: 8, mov: "VMOVDQU"}
+)
+
+// v returns the i-th vector register of the unit
+func (u vectorUnit) v(i int) string {
+	return fmt.Sprintf("%s%d", u.reg, i)
+}
+
+// GenerateF31 generates assembly code for the vector operations of a field
+// whose modulus fits on 31 bits (see config.FieldConfig.F31).
+//
+// Vectors are processed by blocks of 16 (AVX512) or 8 (AVX2) elements;
+// the caller is responsible for handling the remaining elements.
+func GenerateF31(w io.Writer, F *config.FieldConfig) error {
+	if !F.F31 {
+		return fmt.Errorf("GenerateF31 called on a %d-bit modulus", F.NbBits)
+	}
+	f := NewFFAmd64(w, F)
+	f.WriteLn(bavard.Apache2Header("ConsenSys Software Inc.", 2020))
+
+	f.WriteLn("#include \"textflag.h\"")
+	f.WriteLn("#include \"funcdata.h\"")
+	f.WriteLn("")
+
+	for _, u := range []vectorUnit{avx512, avx2} {
+		f.generateAddVecF31(u)
+		f.generateSubVecF31(u)
+		f.generateMulVecF31(u, false)
+		f.generateMulVecF31(u, true)
+	}
+
+	return nil
+}
+
+// vop writes a vector instruction
+func (f *FFAmd64) vop(instruction string, operands ...string) {
+	f.WriteLn(fmt.Sprintf("    %s %s", instruction, strings.Join(operands, ", ")))
+}
+
+// broadcast sets all the 32-bit lanes of dst to the immediate value c, using tmp
+func (f *FFAmd64) broadcast(u vectorUnit, c uint64, tmp amd64.Register, dst string) {
+	f.MOVQ(fmt.Sprintf("$%d", c), tmp)
+	if u.reg == "Z" {
+		f.vop("VPBROADCASTD", string(tmp), dst)
+		return
+	}
+	// AVX2 can't broadcast from a general purpose register
+	x := "X" + strings.TrimPrefix(dst, u.reg)
+	f.vop("MOVD", string(tmp), x)
+	f.vop("VPBROADCASTD", x, dst)
+}
+
+// vecLoop writes the loop header and footer around body, iterating n times over
+// blocks of u.nbLanes elements pointed by the pointers in ptrs.
+func (f *FFAmd64) vecLoop(u vectorUnit, n amd64.Register, ptrs []amd64.Register, body func()) {
+	loop := f.NewLabel("loop")
+	done := f.NewLabel("done")
+
+	f.LABEL(loop)
+	f.TESTQ(n, n)
+	f.JEQ(done, "n == 0, we are done")
+
+	body()
+
+	f.Comment("increment pointers to visit next block")
+	for _, p := range ptrs {
+		f.ADDQ(fmt.Sprintf("$%d", u.nbLanes*4), p)
+	}
+	f.DECQ(n, "decrement n")
+	f.JMP(loop)
+
+	f.LABEL(done)
+	f.RET()
+}
+
+// reduceLanes sets each lane of a to a - q if a ⩾ q, using t as scratch.
+// a must be < 2q < 2³²
+func (f *FFAmd64) reduceLanes(a, q, t string) {
+	// if a < q, a - q wraps around and is larger than a (as unsigned)
+	f.vop("VPSUBD", q, a, t)
+	f.vop("VPMINUD", a, t, a)
+}
+
+// addVec res = a + b
+// func addVecAVX512(res, a, b *Element, n uint64)
+func (f *FFAmd64) generateAddVecF31(u vectorUnit) {
+	name := "addVec" + u.name
+	f.Comment(fmt.Sprintf("%s(res, a, b *Element, n uint64) res[0...%d*n] = a[0...%d*n] + b[0...%d*n]", name, u.nbLanes, u.nbLanes, u.nbLanes))
+
+	registers := f.FnHeader(name, 0, 32)
+
+	tmp := f.Pop(&registers)
+	addrRes := f.Pop(&registers)
+	addrA := f.Pop(&registers)
+	addrB := f.Pop(&registers)
+	n := f.Pop(&registers)
+
+	q, a, b := u.v(0), u.v(1), u.v(2)
+
+	f.broadcast(u, f.Q[0], tmp, q)
+	f.MOVQ("res+0(FP)", addrRes)
+	f.MOVQ("a+8(FP)", addrA)
+	f.MOVQ("b+16(FP)", addrB)
+	f.MOVQ("n+24(FP)", n)
+
+	f.vecLoop(u, n, []amd64.Register{addrRes, addrA, addrB}, func() {
+		f.vop(u.mov, "0("+string(addrA)+")", a)
+		f.vop(u.mov, "0("+string(addrB)+")", b)
+		f.vop("VPADDD", a, b, a)
+		f.reduceLanes(a, q, b)
+		f.vop(u.mov, a, "0("+string(addrRes)+")")
+	})
+
+	f.Push(&registers, tmp, addrRes, addrA, addrB, n)
+}
+
+// subVec res = a - b
+// func subVecAVX512(res, a, b *Element, n uint64)
+func (f *FFAmd64) generateSubVecF31(u vectorUnit) {
+	name := "subVec" + u.name
+	f.Comment(fmt.Sprintf("%s(res, a, b *Element, n uint64) res[0...%d*n] = a[0...%d*n] - b[0...%d*n]", name, u.nbLanes, u.nbLanes, u.nbLanes))
+
+	registers := f.FnHeader(name, 0, 32)
+
+	tmp := f.Pop(&registers)
+	addrRes := f.Pop(&registers)
+	addrA := f.Pop(&registers)
+	addrB := f.Pop(&registers)
+	n := f.Pop(&registers)
+
+	q, a, b := u.v(0), u.v(1), u.v(2)
+
+	f.broadcast(u, f.Q[0], tmp, q)
+	f.MOVQ("res+0(FP)", addrRes)
+	f.MOVQ("a+8(FP)", addrA)
+	f.MOVQ("b+16(FP)", addrB)
+	f.MOVQ("n+24(FP)", n)
+
+	f.vecLoop(u, n, []amd64.Register{addrRes, addrA, addrB}, func() {
+		f.vop(u.mov, "0("+string(addrA)+")", a)
+		f.vop(u.mov, "0("+string(addrB)+")", b)
+		f.vop("VPSUBD", b, a, a)
+		f.Comment("if a < b, a - b wraps around and a - b + q < q is the smallest")
+		f.vop("VPADDD", q, a, b)
+		f.vop("VPMINUD", a, b, a)
+		f.vop(u.mov, a, "0("+string(addrRes)+")")
+	})
+
+	f.Push(&registers, tmp, addrRes, addrA, addrB, n)
+}
+
+// mulVec res = a * b (Montgomery multiplication)
+// func mulVecAVX512(res, a, b *Element, n uint64)
+//
+// if scalar is set, b points to a single element
+// func scalarMulVecAVX512(res, a, b *Element, n uint64)
+func (f *FFAmd64) generateMulVecF31(u vectorUnit, scalar bool) {
+	name := "mulVec" + u.name
+	if scalar {
+		name = "scalarMulVec" + u.name
+		f.Comment(fmt.Sprintf("%s(res, a, b *Element, n uint64) res[0...%d*n] = a[0...%d*n] * b", name, u.nbLanes, u.nbLanes))
+	} else {
+		f.Comment(fmt.Sprintf("%s(res, a, b *Element, n uint64) res[0...%d*n] = a[0...%d*n] * b[0...%d*n]", name, u.nbLanes, u.nbLanes, u.nbLanes))
+	}
+
+	registers := f.FnHeader(name, 0, 32)
+
+	tmp := f.Pop(&registers)
+	addrRes := f.Pop(&registers)
+	addrA := f.Pop(&registers)
+	addrB := f.Pop(&registers)
+	n := f.Pop(&registers)
+
+	q, qInvNeg := u.v(0), u.v(1)
+	a, b, aOdd, bOdd := u.v(2), u.v(3), u.v(4), u.v(5)
+	pEven, pOdd, mEven, mOdd := u.v(6), u.v(7), u.v(8), u.v(9)
+
+	f.broadcast(u, f.Q[0], tmp, q)
+	f.broadcast(u, f.QInverse[0], tmp, qInvNeg)
+	if u.reg == "Z" {
+		f.Comment("K3 selects the even 32-bit lanes")
+		f.MOVQ("$0x5555", tmp)
+		f.vop("KMOVW", string(tmp), "K3")
+	}
+	f.MOVQ("res+0(FP)", addrRes)
+	f.MOVQ("a+8(FP)", addrA)
+	f.MOVQ("b+16(FP)", addrB)
+	f.MOVQ("n+24(FP)", n)
+
+	ptrs := []amd64.Register{addrRes, addrA, addrB}
+	if scalar {
+		f.vop("VPBROADCASTD", "0("+string(addrB)+")", b)
+		ptrs = ptrs[:2]
+	}
+
+	f.vecLoop(u, n, ptrs, func() {
+		f.vop(u.mov, "0("+string(addrA)+")", a)
+		f.Comment("VPMULUDQ multiplies the even 32-bit lanes into 64-bit products")
+		f.vop("VPSRLQ", "$32", a, aOdd)
+		if scalar {
+			bOdd = b
+		} else {
+			f.vop(u.mov, "0("+string(addrB)+")", b)
+			f.vop("VPSRLQ", "$32", b, bOdd)
+		}
+		f.vop("VPMULUDQ", a, b, pEven)
+		f.vop("VPMULUDQ", aOdd, bOdd, pOdd)
+
+		f.Comment("m = p * qInvNeg mod 2³²; t = (p + m * q) / 2³² < 2q")
+		f.vop("VPMULUDQ", pEven, qInvNeg, mEven)
+		f.vop("VPMULUDQ", pOdd, qInvNeg, mOdd)
+		f.vop("VPMULUDQ", mEven, q, mEven)
+		f.vop("VPMULUDQ", mOdd, q, mOdd)
+		f.vop("VPADDQ", pEven, mEven, pEven)
+		f.vop("VPADDQ", pOdd, mOdd, pOdd)
+
+		f.Comment("even results are in the high 32 bits of pEven, odd results in the high 32 bits of pOdd")
+		f.vop("VPSRLQ", "$32", pEven, pEven)
+		if u.reg == "Z" {
+			f.vop("VPBLENDMD", pEven, pOdd, "K3", pOdd)
+		} else {
+			f.vop("VPBLENDD", "$0x55", pEven, pOdd, pOdd)
+		}
+		f.reduceLanes(pOdd, q, mEven)
+		f.vop(u.mov, pOdd, "0("+string(addrRes)+")")
+	})
+
+	f.Push(&registers, tmp, addrRes, addrA, addrB, n)
+}
@@ -242,3 +242,33 @@ func NewElement(s []string) []big.Int {
 	}
 	return res
 }
+
+// FrobeniusCoefficients returns γₖ = α^(k(p-1)/n) for k = 1..n-1, so that
+// the Frobenius map sends (ⁿ√α)ᵏ to γₖ⋅(ⁿ√α)ᵏ. It panics if n does not divide p-1.
+func (f *Extension) FrobeniusCoefficients() []big.Int {
+	var e big.Int
+	e.Sub(f.Base.ModulusBig, big.NewInt(1))
+	if new(big.Int).Mod(&e, big.NewInt(int64(f.Degree))).BitLen() != 0 {
+		panic("extension degree must divide p-1")
+	}
+	e.Div(&e, big.NewInt(int64(f.Degree)))
+
+	var alpha big.Int
+	alpha.SetInt64(f.RootOf).Mod(&alpha, f.Base.ModulusBig)
+
+	res := make([]big.Int, f.Degree-1)
+	for k := 1; k < f.Degree; k++ {
+		var ek big.Int
+		ek.Mul(&e, big.NewInt(int64(k)))
+		res[k-1].Exp(&alpha, &ek, f.Base.ModulusBig)
+	}
+	return res
+}
+
+// IsIrreducible returns true if Xⁿ - α is irreducible over the base field,
+// that is if α has no prime-degree root for any prime dividing n.
+// Only prime degrees are supported.
+func (f *Extension) IsIrreducible() bool {
+	coeffs := f.FrobeniusCoefficients()
+	return len(coeffs) == 0 || coeffs[0].Cmp(big.NewInt(1)) != 0
+}
@@ -72,6 +72,9 @@ type FieldConfig struct {
 	SqrtSMinusOneOver2Data    *addchain.AddChainData
 	SqrtQ3Mod4ExponentData    *addchain.AddChainData
 	UseAddChain               bool
+	F31                       bool   // indicates the modulus fits on 31 bits; elements are then stored on a uint32
+	Word                      string // type of the words of the element representation
+	ASMVector                 bool   // indicates vector operations are implemented in assembly (AVX2 / AVX512)
 }
 
 // NewFieldConfig returns a data structure with needed information to generate apis for field element
@@ -98,6 +101,15 @@ func NewFieldConfig(packageName, elementName, modulus string, useAddChain bool)
 	F.NbWords = len(bModulus.Bits())
 	F.NbBytes = F.NbWords * 8 // (F.NbBits + 7) / 8
 
+	// small moduli are stored on a single 32-bit word, and use a 32-bit Montgomery constant
+	F.F31 = F.NbBits <= 31
+	F.Word = "uint64"
+	if F.F31 {
+		F.NbBytes = 4
+		F.Word = "uint32"
+	}
+	wordSize := F.wordSize()
+
 	F.NbWordsLastIndex = F.NbWords - 1
 
 	// set q from big int repr
@@ -110,7 +122,7 @@ func NewFieldConfig(packageName, elementName, modulus string, useAddChain bool)
 
 	//  setting qInverse
 	_r := big.NewInt(1)
-	_r.Lsh(_r, uint(F.NbWords)*64)
+	_r.Lsh(_r, uint(F.NbWords*wordSize))
 	_rInv := big.NewInt(1)
 	_qInv := big.NewInt(0)
 	extendedEuclideanAlgo(_r, &bModulus, _rInv, _qInv)
@@ -139,19 +151,19 @@ func NewFieldConfig(packageName, elementName, modulus string, useAddChain bool)
 
 	// rsquare
 	_rSquare := big.NewInt(2)
-	exponent := big.NewInt(int64(F.NbWords) * 64 * 2)
+	exponent := big.NewInt(int64(F.NbWords*wordSize) * 2)
 	_rSquare.Exp(_rSquare, exponent, &bModulus)
 	F.RSquare = toUint64Slice(_rSquare, F.NbWords)
 
 	var one big.Int
 	one.SetUint64(1)
-	one.Lsh(&one, uint(F.NbWords)*64).Mod(&one, &bModulus)
+	one.Lsh(&one, uint(F.NbWords*wordSize)).Mod(&one, &bModulus)
 	F.One = toUint64Slice(&one, F.NbWords)
 
 	{
 		var n big.Int
 		n.SetUint64(13)
-		n.Lsh(&n, uint(F.NbWords)*64).Mod(&n, &bModulus)
+		n.Lsh(&n, uint(F.NbWords*wordSize)).Mod(&n, &bModulus)
 		F.Thirteen = toUint64Slice(&n, F.NbWords)
 	}
 
@@ -240,7 +252,7 @@ func NewFieldConfig(packageName, elementName, modulus string, useAddChain bool)
 			var g big.Int
 			g.Exp(&nonResidue, &s, &bModulus)
 			// store g in montgomery form
-			g.Lsh(&g, uint(F.NbWords)*64).Mod(&g, &bModulus)
+			g.Lsh(&g, uint(F.NbWords*wordSize)).Mod(&g, &bModulus)
 			F.SqrtG = toUint64Slice(&g, F.NbWords)
 
 			// store non residue in montgomery form
@@ -261,9 +273,20 @@ func NewFieldConfig(packageName, elementName, modulus string, useAddChain bool)
 	// asm code generation for moduli with more than 6 words can be optimized further
 	F.ASM = F.NoCarry && F.NbWords <= 12 && F.NbWords > 1
 
+	// 31-bit fields only have vectorized assembly (Vector.Add, Vector.Sub, ...)
+	F.ASMVector = F.F31
+
 	return F, nil
 }
 
+// wordSize returns the size in bits of a word of the element representation
+func (f *FieldConfig) wordSize() int {
+	if f.F31 {
+		return 32
+	}
+	return 64
+}
+
 func toUint64Slice(b *big.Int, nbWords ...int) (s []uint64) {
 	if len(nbWords) > 0 && nbWords[0] > len(b.Bits()) {
 		s = make([]uint64, nbWords[0])
@@ -324,7 +347,7 @@ func (f *FieldConfig) StringToMont(str string) big.Int {
 
 func (f *FieldConfig) ToMont(nonMont big.Int) big.Int {
 	var mont big.Int
-	mont.Lsh(&nonMont, uint(f.NbWords)*64)
+	mont.Lsh(&nonMont, uint(f.NbWords*f.wordSize()))
 	mont.Mod(&mont, f.ModulusBig)
 	return mont
 }
@@ -336,7 +359,7 @@ func (f *FieldConfig) FromMont(nonMont *big.Int, mont *big.Int) *FieldConfig {
 		return f
 	}
 	f.halve(nonMont, mont)
-	for i := 1; i < f.NbWords*64; i++ {
+	for i := 1; i < f.NbWords*f.wordSize(); i++ {
 		f.halve(nonMont, nonMont)
 	}
 
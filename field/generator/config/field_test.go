@@ -43,7 +43,7 @@ func TestIntToMont(t *testing.T) {
 		func(f *FieldConfig) (bool, error) {
 			// test if using the same R
 			i := big.NewInt(1)
-			i.Lsh(i, 64*uint(f.NbWords))
+			i.Lsh(i, uint(f.NbWords*f.wordSize()))
 			*i = f.ToMont(*i)
 
 			err := bigIntMatchUint64Slice(i, f.RSquare)
@@ -52,6 +52,28 @@ func TestIntToMont(t *testing.T) {
 	)
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// moduli of 31 bits or fewer use 32-bit words
+	for _, modulus := range []string{
+		"2013265921", // BabyBear
+		"2130706433", // KoalaBear
+		"2147483647", // Mersenne31
+		"21888242871839275222246405745257275088548364400416034343698204186575808495617",
+	} {
+		f, err := NewFieldConfig("dummy", "DummyElement", modulus, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var r, rSquare big.Int
+		r.Lsh(big.NewInt(1), uint(f.NbWords*f.wordSize())).Mod(&r, f.ModulusBig)
+		rSquare.Mul(&r, &r).Mod(&rSquare, f.ModulusBig)
+		if err := bigIntMatchUint64Slice(&rSquare, f.RSquare); err != nil {
+			t.Fatalf("%s: R² mismatch: %v", modulus, err)
+		}
+		if mont := f.ToMont(r); mont.Cmp(&rSquare) != 0 {
+			t.Fatalf("%s: ToMont(R) should be R²", modulus)
+		}
+	}
 }
 
 func TestBigIntMatchUint64Slice(t *testing.T) {
@@ -258,3 +280,37 @@ func bigIntMatchUint64Slice(aInt *big.Int, a []uint64) error {
 
 	return nil
 }
+
+func TestExtensionFrobeniusCoefficients(t *testing.T) {
+	t.Parallel()
+
+	base, err := NewFieldConfig("goldilocks", "Element", "0xFFFFFFFF00000001", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 7 generates the multiplicative group, 2 is a square but not a cube
+	if f := NewTower(base, 2, 7); !f.IsIrreducible() {
+		t.Fatal("u² - 7 should be irreducible")
+	}
+	if f := NewTower(base, 2, 2); f.IsIrreducible() {
+		t.Fatal("u² - 2 should be reducible")
+	}
+
+	f := NewTower(base, 3, 2)
+	if !f.IsIrreducible() {
+		t.Fatal("v³ - 2 should be irreducible")
+	}
+
+	// γ₁ is a primitive cube root of unity and γ₂ = γ₁²
+	coeffs := f.FrobeniusCoefficients()
+	var c big.Int
+	c.Exp(&coeffs[0], big.NewInt(3), base.ModulusBig)
+	if c.Cmp(big.NewInt(1)) != 0 {
+		t.Fatal("γ₁ should be a cube root of unity")
+	}
+	c.Mul(&coeffs[0], &coeffs[0]).Mod(&c, base.ModulusBig)
+	if c.Cmp(&coeffs[1]) != 0 {
+		t.Fatal("γ₂ should be γ₁²")
+	}
+}
@@ -23,6 +23,10 @@ import (
 //	fp, _ = config.NewField("fp", "Element", fpModulus")
 //	generator.GenerateFF(fp, filepath.Join(baseDir, "fp"))
 func GenerateFF(F *config.FieldConfig, outputDir string) error {
+	if F.F31 {
+		return generateF31(F, outputDir)
+	}
+
 	// source file templates
 	sourceFiles := []string{
 		element.Base,
@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/field/generator/config"
+	"github.com/consensys/gnark-crypto/field/generator/internal/templates/extensions"
+)
+
+// extensionsTemplateData holds the values used by the extensions templates
+type extensionsTemplateData struct {
+	PackageName      string
+	FieldPackagePath string
+	FieldPackageName string
+	ElementName      string
+	Modulus          string
+	ModulusHex       string
+
+	E2RootOf     int64
+	E2NonResidue string
+
+	E3RootOf                int64
+	E3NonResidue            string
+	E3FrobeniusCoefficients []string
+}
+
+// GenerateExtensions will generate go files in outputDir implementing the degree 2
+// and degree 3 extensions e2 and e3 of the field F, whose package is at
+// fieldPackagePath.
+//
+// Example usage
+//
+//	e2 := config.NewTower(goldilocks, 2, 7)
+//	e3 := config.NewTower(goldilocks, 3, 2)
+//	generator.GenerateExtensions(goldilocks, e2, e3, "github.com/consensys/gnark-crypto/field/goldilocks", "../extensions")
+func GenerateExtensions(F *config.FieldConfig, e2, e3 config.Extension, fieldPackagePath, outputDir string) error {
+	if e2.Degree != 2 || e3.Degree != 3 {
+		return errors.New("expected a degree 2 and a degree 3 extension")
+	}
+	if e2.Base != F || e3.Base != F {
+		return errors.New("extensions must be built on top of F")
+	}
+	if e2.RootOf <= 0 || e3.RootOf <= 0 {
+		return errors.New("non-residues must be positive")
+	}
+	if !e2.IsIrreducible() || !e3.IsIrreducible() {
+		return errors.New("extension polynomials must be irreducible")
+	}
+
+	data := extensionsTemplateData{
+		PackageName:      filepath.Base(outputDir),
+		FieldPackagePath: fieldPackagePath,
+		FieldPackageName: F.PackageName,
+		ElementName:      F.ElementName,
+		Modulus:          F.Modulus,
+		ModulusHex:       F.ModulusHex,
+		E2RootOf:         e2.RootOf,
+		E2NonResidue:     F.WriteElement(e2.FromInt64(e2.RootOf)[:1]),
+		E3RootOf:         e3.RootOf,
+		E3NonResidue:     F.WriteElement(e3.FromInt64(e3.RootOf)[:1]),
+	}
+	for _, c := range e3.FrobeniusCoefficients() {
+		data.E3FrobeniusCoefficients = append(data.E3FrobeniusCoefficients, F.WriteElement(config.Element{c}))
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	bavardOpts := []func(*bavard.Bavard) error{
+		bavard.Apache2("ConsenSys Software Inc.", 2020),
+		bavard.Package(data.PackageName),
+		bavard.GeneratedBy("consensys/gnark-crypto"),
+	}
+
+	files := []struct {
+		name      string
+		templates []string
+	}{
+		{"doc.go", []string{extensions.Doc}},
+		{"e2.go", []string{extensions.E2, extensions.Helpers}},
+		{"e3.go", []string{extensions.E3, extensions.Helpers}},
+		{"extensions_test.go", []string{extensions.Test}},
+	}
+	for _, f := range files {
+		if err := bavard.GenerateFromString(filepath.Join(outputDir, f.name), f.templates, data, bavardOpts...); err != nil {
+			return err
+		}
+	}
+
+	// run go fmt on whole directory
+	cmd := exec.Command("gofmt", "-s", "-w", outputDir)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
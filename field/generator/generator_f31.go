@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/field/generator/asm/amd64"
+	"github.com/consensys/gnark-crypto/field/generator/config"
+	"github.com/consensys/gnark-crypto/field/generator/internal/addchain"
+	"github.com/consensys/gnark-crypto/field/generator/internal/templates/element"
+)
+
+// generateF31 generates the go (and .s) files in outputDir for a field whose modulus
+// fits on 31 bits. Elements are stored on a single uint32 word, and vector operations
+// use AVX512 or AVX2 instructions on amd64.
+func generateF31(F *config.FieldConfig, outputDir string) error {
+	eName := strings.ToLower(F.ElementName)
+
+	funcs := template.FuncMap{}
+	if F.UseAddChain {
+		for _, f := range addchain.Functions {
+			funcs[f.Name] = f.Func
+		}
+	}
+	funcs["shorten"] = shorten
+	funcs["ltu64"] = func(a, b uint64) bool {
+		return a < b
+	}
+
+	bavardOpts := []func(*bavard.Bavard) error{
+		bavard.Apache2("ConsenSys Software Inc.", 2020),
+		bavard.Package(F.PackageName),
+		bavard.GeneratedBy("consensys/gnark-crypto"),
+		bavard.Funcs(funcs),
+	}
+	withBuildTag := func(tag string) []func(*bavard.Bavard) error {
+		opts := make([]func(*bavard.Bavard) error, len(bavardOpts), len(bavardOpts)+1)
+		copy(opts, bavardOpts)
+		return append(opts, bavard.BuildTag(tag))
+	}
+
+	type file struct {
+		path      string
+		templates []string
+		opts      []func(*bavard.Bavard) error
+	}
+	files := []file{
+		{eName + ".go", []string{element.BaseF31, element.Exp, element.Conv, element.Sqrt}, bavardOpts},
+		{"vector.go", []string{element.Vector}, bavardOpts},
+		{"doc.go", []string{element.Doc}, bavardOpts},
+		{eName + "_ops_purego.go", []string{element.OpsNoAsmF31}, bavardOpts},
+		{eName + "_test.go", []string{element.TestF31}, bavardOpts},
+		{"vector_test.go", []string{element.TestVector, element.TestVectorF31}, bavardOpts},
+	}
+	if F.UseAddChain {
+		files = append(files, file{eName + "_exp.go", []string{element.FixedExp}, bavardOpts})
+	}
+	if F.ASMVector {
+		files[3].opts = withBuildTag("!amd64 purego")
+		files = append(files,
+			file{eName + "_ops_amd64.go", []string{element.OpsAMD64F31}, withBuildTag("!purego")},
+			file{"vector_amd64_test.go", []string{element.TestVectorAMD64F31}, withBuildTag("!purego")},
+		)
+	}
+
+	for _, f := range files {
+		if err := bavard.GenerateFromString(filepath.Join(outputDir, f.path), f.templates, F, f.opts...); err != nil {
+			return err
+		}
+	}
+
+	if F.ASMVector {
+		pathSrc := filepath.Join(outputDir, eName+"_ops_amd64.s")
+		fmt.Println("generating", pathSrc)
+		f, err := os.Create(pathSrc)
+		if err != nil {
+			return err
+		}
+
+		_, _ = io.WriteString(f, "// +build !purego\n")
+
+		if err := amd64.GenerateF31(f, F); err != nil {
+			_ = f.Close()
+			return err
+		}
+		_ = f.Close()
+
+		// run asmfmt
+		cmd := exec.Command("asmfmt", "-w", pathSrc)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return err
+		}
+	}
+
+	// run go fmt on whole directory
+	cmd := exec.Command("gofmt", "-s", "-w", outputDir)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
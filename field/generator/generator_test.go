@@ -67,6 +67,11 @@ func TestIntegration(t *testing.T) {
 	moduli["small"] = "9459143039767"
 	moduli["small_without_no_carry"] = "18446744073709551557" // 64bits
 
+	// 31-bit moduli (uint32 representation, AVX512 / AVX2 vector ops)
+	moduli["e_babybear"] = "2013265921"
+	moduli["e_koalabear"] = "2130706433"
+	moduli["e_mersenne31"] = "2147483647"
+
 	moduli["e_secp256k1"] = "115792089237316195423570985008687907853269984665640564039457584007908834671663"
 
 	// JUST fails to be nocarry -- only the following two can occur for < 3000 bits
@@ -0,0 +1,565 @@
+package element
+
+// BaseF31 is the equivalent of Base for moduli that fit on 31 bits; elements
+// are stored on a single uint32 word, in Montgomery form with r = 2³².
+const BaseF31 = `
+
+import (
+	"math/big"
+	"math/bits"
+	"io"
+	"crypto/rand"
+	"encoding/binary"
+	"strconv"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/field/hash"
+	"github.com/consensys/gnark-crypto/field/pool"
+	"github.com/bits-and-blooms/bitset"
+)
+
+// {{.ElementName}} represents a field element stored on 1 word (uint32)
+//
+// {{.ElementName}} are assumed to be in Montgomery form in all methods.
+//
+// Modulus q =
+//
+// 	q[base10] = {{.Modulus}}
+// 	q[base16] = 0x{{.ModulusHex}}
+//
+// Warning
+//
+// This code has not been audited and is provided as-is. In particular, there is no security guarantees such as constant time implementation or side-channel attack resistance.
+type {{.ElementName}} [1]uint32
+
+const (
+	Limbs = 1 	// number of 32 bits words needed to represent a {{.ElementName}}
+	Bits = {{.NbBits}} 		// number of bits needed to represent a {{.ElementName}}
+	Bytes = {{.NbBytes}} 	// number of bytes needed to represent a {{.ElementName}}
+)
+
+// Field modulus q
+const (
+	q0 uint32 = {{index $.Q 0}}
+	q uint32 = q0
+)
+
+var q{{.ElementName}} = {{.ElementName}}{
+	q0,
+}
+
+var _modulus big.Int 		// q stored as big.Int
+
+// Modulus returns q as a big.Int
+//
+// 	q[base10] = {{.Modulus}}
+// 	q[base16] = 0x{{.ModulusHex}}
+func Modulus() *big.Int {
+	return new(big.Int).Set(&_modulus)
+}
+
+// q + r'.r = 1, i.e., qInvNeg = - q⁻¹ mod r
+// used for Montgomery reduction
+const qInvNeg uint32 = {{index .QInverse 0}}
+
+func init() {
+	_modulus.SetString("{{.ModulusHex}}", 16)
+}
+
+// New{{.ElementName}} returns a new {{.ElementName}} from a uint64 value
+//
+// it is equivalent to
+// 		var v {{.ElementName}}
+// 		v.SetUint64(...)
+func New{{.ElementName}}(v uint64) {{.ElementName}} {
+	z := {{.ElementName}}{uint32(v % uint64(q0))}
+	z.Mul(&z, &rSquare)
+	return z
+}
+
+// SetUint64 sets z to v and returns z
+func (z *{{.ElementName}}) SetUint64(v uint64) *{{.ElementName}} {
+	//  sets z LSB to v (non-Montgomery form) and convert z to Montgomery form
+	*z = {{.ElementName}}{uint32(v % uint64(q0))}
+	return z.Mul(z, &rSquare) // z.toMont()
+}
+
+// SetInt64 sets z to v and returns z
+func (z *{{.ElementName}}) SetInt64(v int64) *{{.ElementName}} {
+
+	// absolute value of v
+	m := v >> 63
+	z.SetUint64(uint64((v ^ m) - m))
+
+	if m != 0 {
+		// v is negative
+		z.Neg(z)
+	}
+
+	return z
+}
+
+// Set z = x and returns z
+func (z *{{.ElementName}}) Set(x *{{.ElementName}}) *{{.ElementName}} {
+	z[0] = x[0]
+	return z
+}
+
+// SetInterface converts provided interface into {{.ElementName}}
+// returns an error if provided type is not supported
+// supported types:
+//  {{.ElementName}}
+//  *{{.ElementName}}
+//  uint64
+//  int
+//  string (see SetString for valid formats)
+//  *big.Int
+//  big.Int
+//  []byte
+func (z *{{.ElementName}}) SetInterface(i1 interface{}) (*{{.ElementName}}, error) {
+	if i1 == nil {
+		return nil, errors.New("can't set {{.PackageName}}.{{.ElementName}} with <nil>")
+	}
+
+	switch c1 := i1.(type) {
+	case {{.ElementName}}:
+		return z.Set(&c1), nil
+	case *{{.ElementName}}:
+		if c1 == nil {
+			return nil, errors.New("can't set {{.PackageName}}.{{.ElementName}} with <nil>")
+		}
+		return z.Set(c1), nil
+	case uint8:
+		return z.SetUint64(uint64(c1)), nil
+	case uint16:
+		return z.SetUint64(uint64(c1)), nil
+	case uint32:
+		return z.SetUint64(uint64(c1)), nil
+	case uint:
+		return z.SetUint64(uint64(c1)), nil
+	case uint64:
+		return z.SetUint64(c1), nil
+	case int8:
+		return z.SetInt64(int64(c1)), nil
+	case int16:
+		return z.SetInt64(int64(c1)), nil
+	case int32:
+		return z.SetInt64(int64(c1)), nil
+	case int64:
+		return z.SetInt64(c1), nil
+	case int:
+		return z.SetInt64(int64(c1)), nil
+	case string:
+		return z.SetString(c1)
+	case *big.Int:
+		if c1 == nil {
+			return nil, errors.New("can't set {{.PackageName}}.{{.ElementName}} with <nil>")
+		}
+		return z.SetBigInt(c1), nil
+	case big.Int:
+		return z.SetBigInt(&c1), nil
+	case []byte:
+		return z.SetBytes(c1), nil
+	default:
+		return nil, errors.New("can't set {{.PackageName}}.{{.ElementName}} from type " + reflect.TypeOf(i1).String())
+	}
+}
+
+// SetZero z = 0
+func (z *{{.ElementName}}) SetZero() *{{.ElementName}} {
+	z[0] = 0
+	return z
+}
+
+// SetOne z = 1 (in Montgomery form)
+func (z *{{.ElementName}}) SetOne() *{{.ElementName}} {
+	z[0] = {{index $.One 0}}
+	return z
+}
+
+// Div z = x*y⁻¹ (mod q)
+func (z *{{.ElementName}}) Div( x, y *{{.ElementName}}) *{{.ElementName}} {
+	var yInv {{.ElementName}}
+	yInv.Inverse( y)
+	z.Mul( x, &yInv)
+	return z
+}
+
+// Equal returns z == x; constant-time
+func (z *{{.ElementName}}) Equal(x *{{.ElementName}}) bool {
+	return z.NotEqual(x) == 0
+}
+
+// NotEqual returns 0 if and only if z == x; constant-time
+func (z *{{.ElementName}}) NotEqual(x *{{.ElementName}}) uint64 {
+	return uint64(z[0] ^ x[0])
+}
+
+// IsZero returns z == 0
+func (z *{{.ElementName}}) IsZero() bool {
+	return z[0] == 0
+}
+
+// IsOne returns z == 1
+func (z *{{.ElementName}}) IsOne() bool {
+	return z[0] == {{index $.One 0}}
+}
+
+// IsUint64 reports whether z can be represented as an uint64.
+func (z *{{.ElementName}}) IsUint64() bool {
+	return true
+}
+
+// Uint64 returns the uint64 representation of x. If x cannot be represented in a uint64, the result is undefined.
+func (z *{{.ElementName}}) Uint64() uint64 {
+	return uint64(z.Bits()[0])
+}
+
+// FitsOnOneWord reports whether z words (except the least significant word) are 0
+//
+// It is the responsibility of the caller to convert from Montgomery to Regular form if needed.
+func (z *{{.ElementName}}) FitsOnOneWord() bool {
+	return true
+}
+
+// Cmp compares (lexicographic order) z and x and returns:
+//
+//   -1 if z <  x
+//    0 if z == x
+//   +1 if z >  x
+//
+func (z *{{.ElementName}}) Cmp(x *{{.ElementName}}) int {
+	_z := z.Bits()
+	_x := x.Bits()
+	if _z[0] > _x[0] {
+		return 1
+	} else if _z[0] < _x[0] {
+		return -1
+	}
+	return 0
+}
+
+// LexicographicallyLargest returns true if this element is strictly lexicographically
+// larger than its negation, false otherwise
+func (z *{{.ElementName}}) LexicographicallyLargest() bool {
+	// adapted from github.com/zkcrypto/bls12_381
+	// we check if the element is larger than (q-1) / 2
+	// if z - (((q -1) / 2) + 1) have no underflow, then z > (q-1) / 2
+
+	_z := z.Bits()
+
+	var b uint32
+	_, b = bits.Sub32(_z[0], {{index .QMinusOneHalvedP 0}}, 0)
+
+	return b == 0
+}
+
+// SetRandom sets z to a uniform random value in [0, q).
+//
+// This might error only if reading from crypto/rand.Reader errors,
+// in which case, value of z is undefined.
+func (z *{{.ElementName}}) SetRandom() (*{{.ElementName}}, error) {
+	// this code is generated for all modulus
+	// and derived from go/src/crypto/rand/util.go
+
+	// l is the number of bytes needed to reconstruct 1 uint32
+	const l = 4
+
+	// bitLen is the maximum bit length needed to encode a value < q.
+	const bitLen = {{.NbBits}}
+
+	// k is the maximum byte length needed to encode a value < q.
+	const k = (bitLen + 7) / 8
+
+	// b is the number of bits in the most significant byte of q-1.
+	b := uint(bitLen % 8)
+	if b == 0 {
+		b = 8
+	}
+
+	var bytes [l]byte
+
+	for {
+		// note that bytes[k:l] is always 0
+		if _, err := io.ReadFull(rand.Reader, bytes[:k]); err != nil {
+			return nil, err
+		}
+
+		// Clear unused bits in in the most significant byte to increase probability
+		// that the candidate is < q.
+		bytes[k-1] &= uint8(int(1<<b) - 1)
+		z[0] = binary.LittleEndian.Uint32(bytes[:])
+
+		if !z.smallerThanModulus() {
+			continue // ignore the candidate and re-sample
+		}
+
+		return z, nil
+	}
+}
+
+// smallerThanModulus returns true if z < q
+// This is not constant time
+func (z *{{.ElementName}}) smallerThanModulus() bool {
+	return z[0] < q
+}
+
+// One returns 1
+func One() {{.ElementName}} {
+	var one {{.ElementName}}
+	one.SetOne()
+	return one
+}
+
+// Halve sets z to z / 2 (mod q)
+func (z *{{.ElementName}}) Halve() {
+	if z[0]&1 == 1 {
+		// z = z + q; q < 2³¹ so no overflow
+		z[0] += q
+	}
+	// z = z >> 1
+	z[0] >>= 1
+}
+
+// fromMont converts z in place (i.e. mutates) from Montgomery to regular representation
+// sets and returns z = z * 1
+func (z *{{.ElementName}}) fromMont() *{{.ElementName}} {
+	fromMont(z)
+	return z
+}
+
+func fromMont(z *{{.ElementName}}) {
+	z[0] = montReduce(uint64(z[0]))
+}
+
+// Add z = x + y (mod q)
+func (z *{{.ElementName}}) Add(x, y *{{.ElementName}}) *{{.ElementName}} {
+	// x + y < 2q < 2³², no overflow
+	z[0] = x[0] + y[0]
+	if z[0] >= q {
+		z[0] -= q
+	}
+	return z
+}
+
+// Double z = x + x (mod q), aka Lsh 1
+func (z *{{.ElementName}}) Double(x *{{.ElementName}}) *{{.ElementName}} {
+	z[0] = x[0] << 1
+	if z[0] >= q {
+		z[0] -= q
+	}
+	return z
+}
+
+// Sub z = x - y (mod q)
+func (z *{{.ElementName}}) Sub(x, y *{{.ElementName}}) *{{.ElementName}} {
+	var b uint32
+	z[0], b = bits.Sub32(x[0], y[0], 0)
+	if b != 0 {
+		z[0] += q
+	}
+	return z
+}
+
+// Neg z = q - x
+func (z *{{.ElementName}}) Neg(x *{{.ElementName}}) *{{.ElementName}} {
+	if x.IsZero() {
+		z.SetZero()
+		return z
+	}
+	z[0] = q - x[0]
+	return z
+}
+
+// Select is a constant-time conditional move.
+// If c=0, z = x0. Else z = x1
+func (z *{{.ElementName}}) Select(c int, x0 *{{.ElementName}}, x1 *{{.ElementName}}) *{{.ElementName}} {
+	cC := uint32((int64(c) | -int64(c)) >> 63) // "canonicized" into: 0 if c=0, -1 otherwise
+	z[0] = x0[0] ^ cC&(x0[0]^x1[0])
+	return z
+}
+
+// Mul z = x * y (mod q)
+//
+// x and y must be less than q
+func (z *{{.ElementName}}) Mul(x, y *{{.ElementName}}) *{{.ElementName}} {
+	// q < 2³¹ so x * y + m * q < 2⁶⁴ and the Montgomery reduction
+	// of the 64-bit product only needs one conditional subtraction.
+	z[0] = montReduce(uint64(x[0]) * uint64(y[0]))
+	return z
+}
+
+// Square z = x * x (mod q)
+//
+// x must be less than q
+func (z *{{.ElementName}}) Square(x *{{.ElementName}}) *{{.ElementName}} {
+	// see Mul for doc.
+	z[0] = montReduce(uint64(x[0]) * uint64(x[0]))
+	return z
+}
+
+// montReduce returns v * r⁻¹ (mod q) for v < q⋅r
+func montReduce(v uint64) uint32 {
+	m := uint32(v) * qInvNeg
+	t := uint32((v + uint64(m) * uint64(q)) >> 32)
+	if t >= q {
+		t -= q
+	}
+	return t
+}
+
+// MulBy3 x *= 3 (mod q)
+func MulBy3(x *{{.ElementName}}) {
+	var y {{.ElementName}}
+	y.Double(x)
+	x.Add(x, &y)
+}
+
+// MulBy5 x *= 5 (mod q)
+func MulBy5(x *{{.ElementName}}) {
+	var y {{.ElementName}}
+	y.Double(x).Double(&y)
+	x.Add(x, &y)
+}
+
+// MulBy13 x *= 13 (mod q)
+func MulBy13(x *{{.ElementName}}) {
+	var y = {{.ElementName}}{ {{index $.Thirteen 0}} }
+	x.Mul(x, &y)
+}
+
+// Butterfly sets
+//  a = a + b (mod q)
+//  b = a - b (mod q)
+func Butterfly(a, b *{{.ElementName}}) {
+	t := *a
+	a.Add(a, b)
+	b.Sub(&t, b)
+}
+
+// BatchInvert returns a new slice with every element inverted.
+// Uses Montgomery batch inversion trick
+func BatchInvert(a []{{.ElementName}}) []{{.ElementName}} {
+	res := make([]{{.ElementName}}, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	zeroes := bitset.New(uint(len(a)))
+	accumulator := One()
+
+	for i:=0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes.Set(uint(i))
+			continue
+		}
+		res[i] = accumulator
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes.Test(uint(i)) {
+			continue
+		}
+		res[i].Mul(&res[i], &accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	return res
+}
+
+// BitLen returns the minimum number of bits needed to represent z
+// returns 0 if z == 0
+func (z *{{.ElementName}}) BitLen() int {
+	return bits.Len32(z[0])
+}
+
+// Hash msg to count prime field elements.
+// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06#section-5.2
+func Hash(msg, dst []byte, count int) ([]{{.ElementName}}, error) {
+	// 128 bits of security
+	// L = ceil((ceil(log2(p)) + k) / 8), where k is the security parameter = 128
+	const Bytes = 1 + (Bits-1)/8
+	const L = 16 + Bytes
+
+	lenInBytes := count * L
+	pseudoRandomBytes, err := hash.ExpandMsgXmd(msg, dst, lenInBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// get temporary big int from the pool
+	vv := pool.BigInt.Get()
+
+	res := make([]{{.ElementName}}, count)
+	for i := 0; i < count; i++ {
+		vv.SetBytes(pseudoRandomBytes[i*L : (i+1)*L])
+		res[i].SetBigInt(vv)
+	}
+
+	// release object into pool
+	pool.BigInt.Put(vv)
+
+	return res, nil
+}
+
+// Inverse z = x⁻¹ (mod q)
+//
+// if x == 0, sets and returns z = x
+func (z *{{.ElementName}}) Inverse(x *{{.ElementName}}) *{{.ElementName}} {
+	// Algorithm 16 in "Efficient Software-Implementation of Finite Fields with Applications to Cryptography"
+	// q < 2³¹, so none of the additions below overflow.
+	if x.IsZero() {
+		z.SetZero()
+		return z
+	}
+
+	var r, s, u, v uint32
+	u = q
+	s = {{index .RSquare 0}} // s = r²
+	r = 0
+	v = x[0]
+
+	for (u != 1) && (v != 1) {
+		for v&1 == 0 {
+			v >>= 1
+			if s&1 == 1 {
+				s += q
+			}
+			s >>= 1
+		}
+		for u&1 == 0 {
+			u >>= 1
+			if r&1 == 1 {
+				r += q
+			}
+			r >>= 1
+		}
+		if v >= u {
+			v -= u
+			if s < r {
+				s += q
+			}
+			s -= r
+		} else {
+			u -= v
+			if r < s {
+				r += q
+			}
+			r -= s
+		}
+	}
+
+	if u == 1 {
+		z[0] = r
+	} else {
+		z[0] = s
+	}
+
+	return z
+}
+`
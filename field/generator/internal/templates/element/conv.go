@@ -25,6 +25,9 @@ func (z *{{.ElementName}}) String() string {
 // toBigInt returns z as a big.Int in Montgomery form
 func (z *{{.ElementName}}) toBigInt(res *big.Int) *big.Int {
        var b [Bytes]byte
+       {{- if .F31}}
+               binary.BigEndian.PutUint32(b[0:4], z[0])
+       {{- else}}
        {{- range $i := reverse .NbWordsIndexesFull}}
                {{- $j := mul $i 8}}
                {{- $k := sub $.NbWords 1}}
@@ -32,6 +35,7 @@ func (z *{{.ElementName}}) toBigInt(res *big.Int) *big.Int {
                {{- $jj := add $j 8}}
                binary.BigEndian.PutUint64(b[{{$j}}:{{$jj}}], z[{{$k}}])
        {{- end}}
+       {{- end}}
 
        return res.SetBytes(b[:])
 }
@@ -61,12 +65,12 @@ func (z *{{.ElementName}}) Text(base int) string {
 			zzNeg.Neg(z)
 			zzNeg.fromMont()
 			if zzNeg[0] <= maxUint16 && zzNeg[0] != 0 {
-				return "-" + strconv.FormatUint(zzNeg[0], base)
+				return "-" + strconv.FormatUint({{if .F31}}uint64(zzNeg[0]){{else}}zzNeg[0]{{end}}, base)
 			}
 		}
 		{{- end}}
 		zz := z.Bits()
-		return strconv.FormatUint(zz[0], base)
+		return strconv.FormatUint({{if .F31}}uint64(zz[0]){{else}}zz[0]{{end}}, base)
 	{{- else }}
 		if base == 10 {
 			var zzNeg {{.ElementName}}
@@ -103,10 +107,10 @@ func (z {{.ElementName}}) ToBigIntRegular(res *big.Int) *big.Int {
 	return z.toBigInt(res)
 }
 
-// Bits provides access to z by returning its value as a little-endian [{{.NbWords}}]uint64 array. 
+// Bits provides access to z by returning its value as a little-endian [{{.NbWords}}]{{$.Word}} array. 
 // Bits is intended to support implementation of missing low-level {{.ElementName}}
 // functionality outside this package; it should be avoided otherwise.
-func (z *{{.ElementName}}) Bits() [{{.NbWords}}]uint64 {
+func (z *{{.ElementName}}) Bits() [{{.NbWords}}]{{$.Word}} {
 	_z := *z
 	fromMont(&_z)
 	return _z
@@ -204,7 +208,12 @@ func (z *{{.ElementName}}) SetBigInt(v *big.Int) *{{.ElementName}} {
 // setBigInt assumes 0 ⩽ v < q
 func (z *{{.ElementName}}) setBigInt(v *big.Int) *{{.ElementName}} {
 	vBits := v.Bits()
-
+	{{if .F31}}
+	// v < q < 2³¹ fits on a single big.Word
+	if len(vBits) > 0 {
+		z[0] = uint32(vBits[0])
+	}
+	{{- else}}
 	if bits.UintSize == 64 {
 		for i := 0; i < len(vBits); i++ {
 			z[i] = uint64(vBits[i])
@@ -218,6 +227,7 @@ func (z *{{.ElementName}}) setBigInt(v *big.Int) *{{.ElementName}} {
 			}
 		}
 	}
+	{{- end}}
 
 	return z.toMont()
 }
@@ -322,6 +332,9 @@ type bigEndian struct{}
 // If b encodes a value higher than q, Element returns error.
 func (bigEndian) Element(b *[Bytes]byte) ({{.ElementName}}, error) {
 	var z {{.ElementName}}
+	{{- if .F31}}
+		z[0] = binary.BigEndian.Uint32((*b)[0:4])
+	{{- else}}
 	{{- range $i := reverse .NbWordsIndexesFull}}
 		{{- $j := mul $i 8}}
 		{{- $k := sub $.NbWords 1}}
@@ -329,6 +342,7 @@ func (bigEndian) Element(b *[Bytes]byte) ({{.ElementName}}, error) {
 		{{- $jj := add $j 8}}
 		z[{{$k}}] = binary.BigEndian.Uint64((*b)[{{$j}}:{{$jj}}])
 	{{- end}}
+	{{- end}}
 
 	if !z.smallerThanModulus() {
 		return {{.ElementName}}{}, errors.New("invalid {{.PackageName}}.{{.ElementName}} encoding")
@@ -341,6 +355,9 @@ func (bigEndian) Element(b *[Bytes]byte) ({{.ElementName}}, error) {
 func (bigEndian) PutElement(b *[Bytes]byte, e {{.ElementName}})  {
 	e.fromMont()
 
+	{{- if .F31}}
+		binary.BigEndian.PutUint32((*b)[0:4], e[0])
+	{{- else}}
 	{{- range $i := reverse .NbWordsIndexesFull}}
 		{{- $j := mul $i 8}}
 		{{- $k := sub $.NbWords 1}}
@@ -348,6 +365,7 @@ func (bigEndian) PutElement(b *[Bytes]byte, e {{.ElementName}})  {
 		{{- $jj := add $j 8}}
 		binary.BigEndian.PutUint64((*b)[{{$j}}:{{$jj}}], e[{{$k}}])
 	{{- end}}
+	{{- end}}
 }
 
 func (bigEndian) String() string { return "BigEndian" }
@@ -361,11 +379,15 @@ type littleEndian struct{}
 
 func (littleEndian) Element(b *[Bytes]byte) ({{.ElementName}}, error) {
 	var z {{.ElementName}}
+	{{- if .F31}}
+		z[0] = binary.LittleEndian.Uint32((*b)[0:4])
+	{{- else}}
 	{{- range $i := .NbWordsIndexesFull}}
 		{{- $j := mul $i 8}}
 		{{- $jj := add $j 8}}
 		z[{{$i}}] = binary.LittleEndian.Uint64((*b)[{{$j}}:{{$jj}}])
 	{{- end}}
+	{{- end}}
 
 	if !z.smallerThanModulus() {
 		return {{.ElementName}}{}, errors.New("invalid {{.PackageName}}.{{.ElementName}} encoding")
@@ -378,11 +400,15 @@ func (littleEndian) Element(b *[Bytes]byte) ({{.ElementName}}, error) {
 func (littleEndian) PutElement(b *[Bytes]byte, e {{.ElementName}})  {
 	e.fromMont()
 
+	{{- if .F31}}
+		binary.LittleEndian.PutUint32((*b)[0:4], e[0])
+	{{- else}}
 	{{- range $i := .NbWordsIndexesFull}}
 		{{- $j := mul $i 8}}
 		{{- $jj := add $j 8}}
 		binary.LittleEndian.PutUint64((*b)[{{$j}}:{{$jj}}], e[{{$i}}])
 	{{- end}}
+	{{- end}}
 }
 
 func (littleEndian) String() string { return "LittleEndian" }
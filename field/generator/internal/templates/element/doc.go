@@ -8,7 +8,7 @@ const Doc = `
 // The modulus is hardcoded in all the operations.
 // 
 // Field elements are represented as an array, and assumed to be in Montgomery form in all methods:
-// 	type {{.ElementName}} [{{.NbWords}}]uint64
+// 	type {{.ElementName}} [{{.NbWords}}]{{.Word}}
 //
 // Usage
 //
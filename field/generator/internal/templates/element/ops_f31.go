@@ -0,0 +1,140 @@
+package element
+
+// OpsAMD64F31 is included with AMD64 builds of F31 fields; it dispatches the
+// vector operations to the AVX512 or AVX2 assembly implementations.
+const OpsAMD64F31 = `
+
+import "golang.org/x/sys/cpu"
+
+var (
+	supportAvx512 = cpu.X86.HasAVX512F
+	supportAvx2   = cpu.X86.HasAVX2
+)
+
+{{- range $op := list "add" "sub" "mul"}}
+
+//go:noescape
+func {{$op}}VecAVX512(res, a, b *{{$.ElementName}}, n uint64)
+
+//go:noescape
+func {{$op}}VecAVX2(res, a, b *{{$.ElementName}}, n uint64)
+{{- end}}
+
+//go:noescape
+func scalarMulVecAVX512(res, a, b *{{.ElementName}}, n uint64)
+
+//go:noescape
+func scalarMulVecAVX2(res, a, b *{{.ElementName}}, n uint64)
+
+// Add adds two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Add(a, b Vector) {
+	if len(a) != len(b) || len(a) != len(*vector) {
+		panic("vector.Add: vectors don't have the same length")
+	}
+	k := 0
+	if supportAvx512 {
+		// process blocks of 16 elements
+		if k = len(a) &^ 15; k > 0 {
+			addVecAVX512(&(*vector)[0], &a[0], &b[0], uint64(k/16))
+		}
+	} else if supportAvx2 {
+		// process blocks of 8 elements
+		if k = len(a) &^ 7; k > 0 {
+			addVecAVX2(&(*vector)[0], &a[0], &b[0], uint64(k/8))
+		}
+	}
+	addVecGeneric((*vector)[k:], a[k:], b[k:])
+}
+
+// Sub subtracts two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Sub(a, b Vector) {
+	if len(a) != len(b) || len(a) != len(*vector) {
+		panic("vector.Sub: vectors don't have the same length")
+	}
+	k := 0
+	if supportAvx512 {
+		// process blocks of 16 elements
+		if k = len(a) &^ 15; k > 0 {
+			subVecAVX512(&(*vector)[0], &a[0], &b[0], uint64(k/16))
+		}
+	} else if supportAvx2 {
+		// process blocks of 8 elements
+		if k = len(a) &^ 7; k > 0 {
+			subVecAVX2(&(*vector)[0], &a[0], &b[0], uint64(k/8))
+		}
+	}
+	subVecGeneric((*vector)[k:], a[k:], b[k:])
+}
+
+// Mul multiplies two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Mul(a, b Vector) {
+	if len(a) != len(b) || len(a) != len(*vector) {
+		panic("vector.Mul: vectors don't have the same length")
+	}
+	k := 0
+	if supportAvx512 {
+		// process blocks of 16 elements
+		if k = len(a) &^ 15; k > 0 {
+			mulVecAVX512(&(*vector)[0], &a[0], &b[0], uint64(k/16))
+		}
+	} else if supportAvx2 {
+		// process blocks of 8 elements
+		if k = len(a) &^ 7; k > 0 {
+			mulVecAVX2(&(*vector)[0], &a[0], &b[0], uint64(k/8))
+		}
+	}
+	mulVecGeneric((*vector)[k:], a[k:], b[k:])
+}
+
+// ScalarMul multiplies a vector by a scalar element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) ScalarMul(a Vector, b *{{.ElementName}}) {
+	if len(a) != len(*vector) {
+		panic("vector.ScalarMul: vectors don't have the same length")
+	}
+	k := 0
+	if supportAvx512 {
+		// process blocks of 16 elements
+		if k = len(a) &^ 15; k > 0 {
+			scalarMulVecAVX512(&(*vector)[0], &a[0], b, uint64(k/16))
+		}
+	} else if supportAvx2 {
+		// process blocks of 8 elements
+		if k = len(a) &^ 7; k > 0 {
+			scalarMulVecAVX2(&(*vector)[0], &a[0], b, uint64(k/8))
+		}
+	}
+	scalarMulVecGeneric((*vector)[k:], a[k:], b)
+}
+`
+
+// OpsNoAsmF31 is included with non-AMD64 (or purego) builds of F31 fields
+const OpsNoAsmF31 = `
+
+// Add adds two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Add(a, b Vector) {
+	addVecGeneric(*vector, a, b)
+}
+
+// Sub subtracts two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Sub(a, b Vector) {
+	subVecGeneric(*vector, a, b)
+}
+
+// Mul multiplies two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Mul(a, b Vector) {
+	mulVecGeneric(*vector, a, b)
+}
+
+// ScalarMul multiplies a vector by a scalar element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) ScalarMul(a Vector, b *{{.ElementName}}) {
+	scalarMulVecGeneric(*vector, a, b)
+}
+`
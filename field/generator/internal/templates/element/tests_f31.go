@@ -0,0 +1,553 @@
+package element
+
+// TestF31 tests the arithmetic of fields whose modulus fits on 31 bits against math/big
+const TestF31 = `
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+	ggen "github.com/leanovate/gopter/gen"
+
+	"github.com/stretchr/testify/require"
+)
+
+// -------------------------------------------------------------------------------------------------
+// benchmarks
+
+var benchRes{{.ElementName}} {{.ElementName}}
+
+func Benchmark{{toTitle .ElementName}}Add(b *testing.B) {
+	var x {{.ElementName}}
+	x.SetRandom()
+	benchRes{{.ElementName}}.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchRes{{.ElementName}}.Add(&x, &benchRes{{.ElementName}})
+	}
+}
+
+func Benchmark{{toTitle .ElementName}}Sub(b *testing.B) {
+	var x {{.ElementName}}
+	x.SetRandom()
+	benchRes{{.ElementName}}.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchRes{{.ElementName}}.Sub(&x, &benchRes{{.ElementName}})
+	}
+}
+
+func Benchmark{{toTitle .ElementName}}Mul(b *testing.B) {
+	var x {{.ElementName}}
+	x.SetRandom()
+	benchRes{{.ElementName}}.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchRes{{.ElementName}}.Mul(&benchRes{{.ElementName}}, &x)
+	}
+}
+
+func Benchmark{{toTitle .ElementName}}Square(b *testing.B) {
+	benchRes{{.ElementName}}.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchRes{{.ElementName}}.Square(&benchRes{{.ElementName}})
+	}
+}
+
+func Benchmark{{toTitle .ElementName}}Inverse(b *testing.B) {
+	var x {{.ElementName}}
+	x.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchRes{{.ElementName}}.Inverse(&x)
+	}
+}
+
+func Benchmark{{toTitle .ElementName}}Exp(b *testing.B) {
+	var x {{.ElementName}}
+	x.SetRandom()
+	e, _ := rand.Int(rand.Reader, Modulus())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchRes{{.ElementName}}.Exp(x, e)
+	}
+}
+
+func Benchmark{{toTitle .ElementName}}Sqrt(b *testing.B) {
+	var a {{.ElementName}}
+	a.SetUint64(4)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchRes{{.ElementName}}.Sqrt(&a)
+	}
+}
+
+func Benchmark{{toTitle .ElementName}}BatchInvert(b *testing.B) {
+	const n = 1 << 10
+	a := make([]{{.ElementName}}, n)
+	for i := range a {
+		a[i].SetRandom()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchInvert(a)
+	}
+}
+
+// -------------------------------------------------------------------------------------------------
+// tests
+
+const (
+	nbFuzzShort = 200
+	nbFuzz      = 1000
+)
+
+// testPair{{.ElementName}} holds an element and its value as a big.Int, in regular form
+type testPair{{.ElementName}} struct {
+	element {{.ElementName}}
+	bigint  big.Int
+}
+
+// gen returns a generator of random elements, including the edge cases 0, 1 and q-1
+func gen() gopter.Gen {
+	return func(genParams *gopter.GenParameters) *gopter.GenResult {
+		var g testPair{{.ElementName}}
+
+		switch genParams.NextUint64() % 16 {
+		case 0:
+			g.element.SetZero()
+		case 1:
+			g.element.SetOne()
+		case 2:
+			g.element.SetInt64(-1)
+		default:
+			g.element[0] = uint32(genParams.NextUint64() % uint64(q))
+		}
+		g.element.BigInt(&g.bigint)
+
+		return gopter.NewGenResult(g, gopter.NoShrinker)
+	}
+}
+
+func testParameters() *gopter.TestParameters {
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+	return parameters
+}
+
+// isEqual returns true if e encodes the big.Int b (not necessarily reduced)
+func isEqual(e *{{.ElementName}}, b *big.Int) bool {
+	var r big.Int
+	r.Mod(b, Modulus())
+	return e.BigInt(new(big.Int)).Cmp(&r) == 0 && e.smallerThanModulus()
+}
+
+func Test{{toTitle .ElementName}}Arithmetic(t *testing.T) {
+	t.Parallel()
+	parameters := testParameters()
+	properties := gopter.NewProperties(parameters)
+	genA, genB := gen(), gen()
+
+	properties.Property("Add: matches big.Int", prop.ForAll(
+		func(a, b testPair{{.ElementName}}) bool {
+			var c {{.ElementName}}
+			c.Add(&a.element, &b.element)
+			return isEqual(&c, new(big.Int).Add(&a.bigint, &b.bigint))
+		},
+		genA, genB,
+	))
+
+	properties.Property("Sub: matches big.Int", prop.ForAll(
+		func(a, b testPair{{.ElementName}}) bool {
+			var c {{.ElementName}}
+			c.Sub(&a.element, &b.element)
+			return isEqual(&c, new(big.Int).Sub(&a.bigint, &b.bigint))
+		},
+		genA, genB,
+	))
+
+	properties.Property("Mul: matches big.Int", prop.ForAll(
+		func(a, b testPair{{.ElementName}}) bool {
+			var c {{.ElementName}}
+			c.Mul(&a.element, &b.element)
+			return isEqual(&c, new(big.Int).Mul(&a.bigint, &b.bigint))
+		},
+		genA, genB,
+	))
+
+	properties.Property("Div: (a / b) * b == a", prop.ForAll(
+		func(a, b testPair{{.ElementName}}) bool {
+			if b.element.IsZero() {
+				return true
+			}
+			var c {{.ElementName}}
+			c.Div(&a.element, &b.element).Mul(&c, &b.element)
+			return c.Equal(&a.element)
+		},
+		genA, genB,
+	))
+
+	properties.Property("Square: matches big.Int", prop.ForAll(
+		func(a testPair{{.ElementName}}) bool {
+			var c {{.ElementName}}
+			c.Square(&a.element)
+			return isEqual(&c, new(big.Int).Mul(&a.bigint, &a.bigint))
+		},
+		genA,
+	))
+
+	properties.Property("Double: matches big.Int", prop.ForAll(
+		func(a testPair{{.ElementName}}) bool {
+			var c {{.ElementName}}
+			c.Double(&a.element)
+			return isEqual(&c, new(big.Int).Lsh(&a.bigint, 1))
+		},
+		genA,
+	))
+
+	properties.Property("Neg: matches big.Int", prop.ForAll(
+		func(a testPair{{.ElementName}}) bool {
+			var c {{.ElementName}}
+			c.Neg(&a.element)
+			return isEqual(&c, new(big.Int).Neg(&a.bigint))
+		},
+		genA,
+	))
+
+	properties.Property("Halve: 2 * (a / 2) == a", prop.ForAll(
+		func(a testPair{{.ElementName}}) bool {
+			c := a.element
+			c.Halve()
+			c.Double(&c)
+			return c.Equal(&a.element) && c.smallerThanModulus()
+		},
+		genA,
+	))
+
+	properties.Property("MulBy3, MulBy5, MulBy13: match Mul", prop.ForAll(
+		func(a testPair{{.ElementName}}) bool {
+			c3, c5, c13 := a.element, a.element, a.element
+			MulBy3(&c3)
+			MulBy5(&c5)
+			MulBy13(&c13)
+			return isEqual(&c3, new(big.Int).Mul(&a.bigint, big.NewInt(3))) &&
+				isEqual(&c5, new(big.Int).Mul(&a.bigint, big.NewInt(5))) &&
+				isEqual(&c13, new(big.Int).Mul(&a.bigint, big.NewInt(13)))
+		},
+		genA,
+	))
+
+	properties.Property("Butterfly: (a, b) -> (a + b, a - b)", prop.ForAll(
+		func(a, b testPair{{.ElementName}}) bool {
+			c, d := a.element, b.element
+			Butterfly(&c, &d)
+			return isEqual(&c, new(big.Int).Add(&a.bigint, &b.bigint)) &&
+				isEqual(&d, new(big.Int).Sub(&a.bigint, &b.bigint))
+		},
+		genA, genB,
+	))
+
+	properties.Property("Inverse: matches big.Int", prop.ForAll(
+		func(a testPair{{.ElementName}}) bool {
+			var c {{.ElementName}}
+			c.Inverse(&a.element)
+			if a.element.IsZero() {
+				return c.IsZero()
+			}
+			return isEqual(&c, new(big.Int).ModInverse(&a.bigint, Modulus()))
+		},
+		genA,
+	))
+
+	properties.Property("Exp: matches big.Int", prop.ForAll(
+		func(a, b testPair{{.ElementName}}) bool {
+			var c {{.ElementName}}
+			c.Exp(a.element, &b.bigint)
+			return isEqual(&c, new(big.Int).Exp(&a.bigint, &b.bigint, Modulus()))
+		},
+		genA, genB,
+	))
+
+	properties.Property("Legendre and Sqrt: match big.Int", prop.ForAll(
+		func(a testPair{{.ElementName}}) bool {
+			l := a.element.Legendre()
+			if big.Jacobi(&a.bigint, Modulus()) != l {
+				return false
+			}
+			var c {{.ElementName}}
+			if c.Sqrt(&a.element) == nil {
+				return l == -1
+			}
+			c.Square(&c)
+			return l != -1 && c.Equal(&a.element)
+		},
+		genA,
+	))
+
+	properties.Property("Cmp and LexicographicallyLargest: match big.Int", prop.ForAll(
+		func(a, b testPair{{.ElementName}}) bool {
+			var halfQ big.Int
+			halfQ.Rsh(Modulus(), 1)
+			return a.element.Cmp(&b.element) == a.bigint.Cmp(&b.bigint) &&
+				a.element.LexicographicallyLargest() == (a.bigint.Cmp(&halfQ) > 0)
+		},
+		genA, genB,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func Test{{toTitle .ElementName}}Conversions(t *testing.T) {
+	t.Parallel()
+	parameters := testParameters()
+	properties := gopter.NewProperties(parameters)
+	genA := gen()
+
+	properties.Property("SetBigInt(BigInt(a)) == a", prop.ForAll(
+		func(a testPair{{.ElementName}}) bool {
+			var c {{.ElementName}}
+			c.SetBigInt(&a.bigint)
+			return c.Equal(&a.element)
+		},
+		genA,
+	))
+
+	properties.Property("SetBytes(Bytes(a)) == a", prop.ForAll(
+		func(a testPair{{.ElementName}}) bool {
+			var c, d {{.ElementName}}
+			b := a.element.Bytes()
+			c.SetBytes(b[:])
+			if err := d.SetBytesCanonical(b[:]); err != nil {
+				return false
+			}
+			return c.Equal(&a.element) && d.Equal(&a.element) &&
+				new(big.Int).SetBytes(b[:]).Cmp(&a.bigint) == 0
+		},
+		genA,
+	))
+
+	properties.Property("BigEndian and LittleEndian round trip", prop.ForAll(
+		func(a testPair{{.ElementName}}) bool {
+			var b, l [Bytes]byte
+			BigEndian.PutElement(&b, a.element)
+			LittleEndian.PutElement(&l, a.element)
+			c, errB := BigEndian.Element(&b)
+			d, errL := LittleEndian.Element(&l)
+			return errB == nil && errL == nil && c.Equal(&a.element) && d.Equal(&a.element) &&
+				b[0] == l[Bytes-1]
+		},
+		genA,
+	))
+
+	properties.Property("SetString(String(a)) == a and Text matches big.Int", prop.ForAll(
+		func(a testPair{{.ElementName}}) bool {
+			var c {{.ElementName}}
+			if _, err := c.SetString(a.element.String()); err != nil {
+				return false
+			}
+			return c.Equal(&a.element) && a.element.Text(16) == a.bigint.Text(16)
+		},
+		genA,
+	))
+
+	properties.Property("SetUint64 and SetInt64 match big.Int", prop.ForAll(
+		func(v uint64) bool {
+			var a, b {{.ElementName}}
+			a.SetUint64(v)
+			b.SetInt64(int64(v))
+			return isEqual(&a, new(big.Int).SetUint64(v)) && isEqual(&b, big.NewInt(int64(v)))
+		},
+		ggen.UInt64(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func Test{{toTitle .ElementName}}EdgeCases(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+
+	var qMinusOne big.Int
+	qMinusOne.Sub(Modulus(), big.NewInt(1))
+
+	// SetInt64 on the most negative value
+	var a {{.ElementName}}
+	a.SetInt64(-1 << 63)
+	var expected big.Int
+	expected.Lsh(big.NewInt(-1), 63)
+	assert.True(isEqual(&a, &expected))
+
+	// non canonical encodings
+	var b [Bytes]byte
+	for i := range b {
+		b[i] = 0xff
+	}
+	_, err := BigEndian.Element(&b)
+	assert.Error(err, "0xff..ff is not a canonical encoding")
+	_, err = LittleEndian.Element(&b)
+	assert.Error(err, "0xff..ff is not a canonical encoding")
+	assert.Error(a.SetBytesCanonical(b[:]))
+
+	// q - 1 is the largest element
+	a.SetBigInt(&qMinusOne)
+	assert.True(a.LexicographicallyLargest())
+	assert.Equal(0, a.BigInt(new(big.Int)).Cmp(&qMinusOne))
+	var c {{.ElementName}}
+	c.Add(&a, &a)
+	assert.True(isEqual(&c, new(big.Int).Lsh(&qMinusOne, 1)))
+	c.Mul(&a, &a)
+	assert.True(c.IsOne(), "(-1)² == 1")
+
+	// JSON
+	encoded, err := json.Marshal(&a)
+	assert.NoError(err)
+	var decoded {{.ElementName}}
+	assert.NoError(json.Unmarshal(encoded, &decoded))
+	assert.True(decoded.Equal(&a))
+}
+
+func Test{{toTitle .ElementName}}SetRandom(t *testing.T) {
+	t.Parallel()
+	var a {{.ElementName}}
+	for i := 0; i < 1000; i++ {
+		if _, err := a.SetRandom(); err != nil {
+			t.Fatal(err)
+		}
+		if !a.smallerThanModulus() {
+			t.Fatal("SetRandom returned a non reduced element")
+		}
+	}
+}
+
+func Test{{toTitle .ElementName}}BatchInvert(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+
+	const n = 37
+	a := make([]{{.ElementName}}, n)
+	for i := range a {
+		a[i].SetRandom()
+	}
+	a[5].SetZero()
+	a[n-1].SetZero()
+
+	res := BatchInvert(a)
+	for i := range a {
+		var expected {{.ElementName}}
+		expected.Inverse(&a[i])
+		assert.True(expected.Equal(&res[i]), "index %d", i)
+	}
+}
+`
+
+// TestVectorF31 tests the vector operations of fields whose modulus fits on 31 bits
+const TestVectorF31 = `
+
+func TestVectorOps(t *testing.T) {
+	testVectorOps(t)
+}
+
+// testVectorOps checks that vector operations match element wise operations,
+// on all lengths up to a few blocks of (assembly) vector registers.
+func testVectorOps(t *testing.T) {
+	assert := require.New(t)
+
+	for n := 0; n < 70; n++ {
+		a, b := make(Vector, n), make(Vector, n)
+		for i := 0; i < n; i++ {
+			a[i].SetRandom()
+			b[i].SetRandom()
+		}
+		// edge cases
+		if n > 2 {
+			a[0].SetInt64(-1)
+			b[0].SetInt64(-1)
+			a[1].SetZero()
+			b[2].SetInt64(-1)
+		}
+		var s {{.ElementName}}
+		s.SetRandom()
+
+		add, sub, mul, scalarMul := make(Vector, n), make(Vector, n), make(Vector, n), make(Vector, n)
+		add.Add(a, b)
+		sub.Sub(a, b)
+		mul.Mul(a, b)
+		scalarMul.ScalarMul(a, &s)
+
+		for i := 0; i < n; i++ {
+			var e {{.ElementName}}
+			assert.True(e.Add(&a[i], &b[i]).Equal(&add[i]), "Add: n = %d, i = %d", n, i)
+			assert.True(e.Sub(&a[i], &b[i]).Equal(&sub[i]), "Sub: n = %d, i = %d", n, i)
+			assert.True(e.Mul(&a[i], &b[i]).Equal(&mul[i]), "Mul: n = %d, i = %d", n, i)
+			assert.True(e.Mul(&a[i], &s).Equal(&scalarMul[i]), "ScalarMul: n = %d, i = %d", n, i)
+		}
+
+		// in place
+		add.Add(add, b)
+		for i := 0; i < n; i++ {
+			var e {{.ElementName}}
+			e.Add(&a[i], &b[i]).Add(&e, &b[i])
+			assert.True(e.Equal(&add[i]), "in place Add: n = %d, i = %d", n, i)
+		}
+	}
+}
+
+func BenchmarkVectorOps(b *testing.B) {
+	const N = 1 << 20
+	a, c, res := make(Vector, N), make(Vector, N), make(Vector, N)
+	for i := 0; i < N; i++ {
+		a[i].SetRandom()
+		c[i].SetRandom()
+	}
+	var s {{.ElementName}}
+	s.SetRandom()
+
+	b.Run("Add", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.Add(a, c)
+		}
+	})
+	b.Run("Sub", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.Sub(a, c)
+		}
+	})
+	b.Run("Mul", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.Mul(a, c)
+		}
+	})
+	b.Run("ScalarMul", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.ScalarMul(a, &s)
+		}
+	})
+}
+`
+
+// TestVectorAMD64F31 checks the AVX2 code path on machines supporting AVX512
+const TestVectorAMD64F31 = `
+
+import "testing"
+
+func TestVectorOpsAVX2(t *testing.T) {
+	if !supportAvx2 {
+		t.Skip("AVX2 not supported")
+	}
+	saved := supportAvx512
+	supportAvx512 = false
+	defer func() {
+		supportAvx512 = saved
+	}()
+	testVectorOps(t)
+}
+`
@@ -106,6 +106,9 @@ func (vector *Vector) AsyncReadFrom(r io.Reader) (int64, error, chan error) {
 				bstart := i*Bytes
 				bend := bstart + Bytes
 				b := bSlice[bstart:bend]
+				{{- if .F31}}
+					z[0] = binary.BigEndian.Uint32(b[0:4])
+				{{- else}}
 				{{- range $i := reverse .NbWordsIndexesFull}}
 					{{- $j := mul $i 8}}
 					{{- $k := sub $.NbWords 1}}
@@ -113,6 +116,7 @@ func (vector *Vector) AsyncReadFrom(r io.Reader) (int64, error, chan error) {
 					{{- $jj := add $j 8}}
 					z[{{$k}}] = binary.BigEndian.Uint64(b[{{$j}}:{{$jj}}])
 				{{- end}}
+				{{- end}}
 
 				if !z.smallerThanModulus() {
 					atomic.AddUint64(&cptErrors, 1)
@@ -192,8 +196,8 @@ func (vector Vector) Swap(i, j int) {
 }
 
 
-{{/* For 4 elements, we have a special assembly path and copy this in ops_pure.go */}}
-{{- if ne .NbWords 4}}
+{{/* For 4 elements and F31 fields, we have a special assembly path and copy this in ops_pure.go */}}
+{{- if and (ne .NbWords 4) (not .F31)}}
 // Add adds two vectors element-wise and stores the result in self.
 // It panics if the vectors don't have the same length.
 func (vector *Vector) Add(a, b Vector) {
@@ -242,6 +246,18 @@ func scalarMulVecGeneric(res, a Vector, b *{{.ElementName}}) {
 	}
 }
 
+{{- if .F31}}
+
+func mulVecGeneric(res, a, b Vector) {
+	if len(a) != len(b) || len(a) != len(res) {
+		panic("vector.Mul: vectors don't have the same length")
+	}
+	for i := 0; i < len(a); i++ {
+		res[i].Mul(&a[i], &b[i])
+	}
+}
+{{- end}}
+
 // TODO @gbotrel make a public package out of that.
 // execute executes the work function in parallel.
 // this is copy paste from internal/parallel/parallel.go
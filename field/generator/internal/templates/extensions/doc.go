@@ -0,0 +1,23 @@
+package extensions
+
+const Doc = `
+// Package {{.PackageName}} provides degree 2 and degree 3 extensions of {{.FieldPackageName}}.{{.ElementName}}.
+//
+// Both extensions are simple radical extensions of the base field, as described
+// by field/generator/config.Extension:
+//
+// 	E2 = {{.FieldPackageName}}[u] / (u² - {{.E2RootOf}})
+// 	E3 = {{.FieldPackageName}}[v] / (v³ - {{.E3RootOf}})
+//
+// Elements are written A0 + A1⋅u (resp. A0 + A1⋅v + A2⋅v²) with coordinates in the base field.
+//
+// Base field modulus q =
+//
+// 	q[base10] = {{.Modulus}}
+// 	q[base16] = 0x{{.ModulusHex}}
+//
+// Warning
+//
+// This code has not been audited and is provided as-is. In particular, there is no security guarantees such as constant time implementation or side-channel attack resistance.
+package {{.PackageName}}
+`
@@ -0,0 +1,326 @@
+package extensions
+
+const E2 = `
+import (
+	"errors"
+	"math/big"
+
+	fp "{{.FieldPackagePath}}"
+)
+
+// SizeOfE2 is the number of bytes of the binary representation of an E2 element
+const SizeOfE2 = 2 * fp.Bytes
+
+{{- if not (or (eq .E2RootOf 2) (eq .E2RootOf 3) (eq .E2RootOf 5))}}
+
+// e2NonResidue is the non-residue α such that E2 = fp[u]/(u² - α), in Montgomery form
+var e2NonResidue = fp.Element{{.E2NonResidue}}
+{{- end}}
+
+// E2 is a degree two finite field extension of fp.Element: E2 = fp[u]/(u² - {{.E2RootOf}})
+type E2 struct {
+	A0, A1 fp.Element
+}
+
+// Equal returns true if z equals x, false otherwise
+func (z *E2) Equal(x *E2) bool {
+	return z.A0.Equal(&x.A0) && z.A1.Equal(&x.A1)
+}
+
+// SetString sets a E2 element from strings
+func (z *E2) SetString(s1, s2 string) *E2 {
+	z.A0.SetString(s1)
+	z.A1.SetString(s2)
+	return z
+}
+
+// SetZero sets an E2 elmt to zero
+func (z *E2) SetZero() *E2 {
+	z.A0.SetZero()
+	z.A1.SetZero()
+	return z
+}
+
+// Set sets an E2 from x
+func (z *E2) Set(x *E2) *E2 {
+	z.A0 = x.A0
+	z.A1 = x.A1
+	return z
+}
+
+// SetElement sets z to the embedding of x in E2
+func (z *E2) SetElement(x *fp.Element) *E2 {
+	z.A0 = *x
+	z.A1.SetZero()
+	return z
+}
+
+// SetOne sets z to 1 in Montgomery form and returns z
+func (z *E2) SetOne() *E2 {
+	z.A0.SetOne()
+	z.A1.SetZero()
+	return z
+}
+
+// SetRandom sets a0 and a1 to random values
+func (z *E2) SetRandom() (*E2, error) {
+	if _, err := z.A0.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := z.A1.SetRandom(); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// IsZero returns true if z is zero, false otherwise
+func (z *E2) IsZero() bool {
+	return z.A0.IsZero() && z.A1.IsZero()
+}
+
+// IsOne returns true if z is one, false otherwise
+func (z *E2) IsOne() bool {
+	return z.A0.IsOne() && z.A1.IsZero()
+}
+
+// Add adds two elements of E2
+func (z *E2) Add(x, y *E2) *E2 {
+	z.A0.Add(&x.A0, &y.A0)
+	z.A1.Add(&x.A1, &y.A1)
+	return z
+}
+
+// Sub subtracts two elements of E2
+func (z *E2) Sub(x, y *E2) *E2 {
+	z.A0.Sub(&x.A0, &y.A0)
+	z.A1.Sub(&x.A1, &y.A1)
+	return z
+}
+
+// Double doubles an E2 element
+func (z *E2) Double(x *E2) *E2 {
+	z.A0.Double(&x.A0)
+	z.A1.Double(&x.A1)
+	return z
+}
+
+// Neg negates an E2 element
+func (z *E2) Neg(x *E2) *E2 {
+	z.A0.Neg(&x.A0)
+	z.A1.Neg(&x.A1)
+	return z
+}
+
+// String implements Stringer interface for fancy printing
+func (z *E2) String() string {
+	return z.A0.String() + "+" + z.A1.String() + "*u"
+}
+
+// Mul sets z to the E2-product of x,y, returns z
+func (z *E2) Mul(x, y *E2) *E2 {
+	// Karatsuba
+	var a, b, c fp.Element
+	a.Add(&x.A0, &x.A1)
+	b.Add(&y.A0, &y.A1)
+	a.Mul(&a, &b)
+	b.Mul(&x.A0, &y.A0)
+	c.Mul(&x.A1, &y.A1)
+	z.A1.Sub(&a, &b).Sub(&z.A1, &c)
+	mulByNonResidueE2(&c, &c)
+	z.A0.Add(&b, &c)
+	return z
+}
+
+// Square sets z to the E2-product of x,x returns z
+func (z *E2) Square(x *E2) *E2 {
+	// z = (a0² + α⋅a1²) + 2⋅a0⋅a1⋅u
+	var a, b fp.Element
+	a.Square(&x.A0)
+	b.Square(&x.A1)
+	mulByNonResidueE2(&b, &b)
+	z.A1.Mul(&x.A0, &x.A1).Double(&z.A1)
+	z.A0.Add(&a, &b)
+	return z
+}
+
+// MulByElement multiplies an element in E2 by an element in fp
+func (z *E2) MulByElement(x *E2, y *fp.Element) *E2 {
+	var yCopy fp.Element
+	yCopy.Set(y)
+	z.A0.Mul(&x.A0, &yCopy)
+	z.A1.Mul(&x.A1, &yCopy)
+	return z
+}
+
+// MulByNonResidue multiplies a E2 by u
+func (z *E2) MulByNonResidue(x *E2) *E2 {
+	// (a0 + a1⋅u)⋅u = α⋅a1 + a0⋅u
+	a := x.A0
+	mulByNonResidueE2(&z.A0, &x.A1)
+	z.A1 = a
+	return z
+}
+
+// Conjugate conjugates an element in E2
+func (z *E2) Conjugate(x *E2) *E2 {
+	z.A0 = x.A0
+	z.A1.Neg(&x.A1)
+	return z
+}
+
+// Frobenius sets z to the q-th power of x, where q is the base field modulus
+//
+// Since α is not a square, u^q = α^((q-1)/2)⋅u = -u and the Frobenius map is the conjugation.
+func (z *E2) Frobenius(x *E2) *E2 {
+	return z.Conjugate(x)
+}
+
+// norm sets x to the norm of z, a0² - α⋅a1²
+func (z *E2) norm(x *fp.Element) {
+	var tmp fp.Element
+	tmp.Square(&z.A1)
+	mulByNonResidueE2(&tmp, &tmp)
+	x.Square(&z.A0).Sub(x, &tmp)
+}
+
+// Inverse sets z to the E2-inverse of x, returns z
+//
+// if x == 0, sets and returns z = x
+func (z *E2) Inverse(x *E2) *E2 {
+	// 1 / (a0 + a1⋅u) = (a0 - a1⋅u) / (a0² - α⋅a1²)
+	var t fp.Element
+	x.norm(&t)
+	t.Inverse(&t)
+	z.A0.Mul(&x.A0, &t)
+	z.A1.Mul(&x.A1, &t).Neg(&z.A1)
+	return z
+}
+
+// Div divides an element in E2 by an element in E2
+func (z *E2) Div(x *E2, y *E2) *E2 {
+	var r E2
+	r.Inverse(y).Mul(x, &r)
+	return z.Set(&r)
+}
+
+// Exp sets z=xᵏ (mod q²) and returns it
+func (z *E2) Exp(x E2, k *big.Int) *E2 {
+	if k.IsUint64() && k.Uint64() == 0 {
+		return z.SetOne()
+	}
+
+	e := k
+	if k.Sign() == -1 {
+		// negative k, we invert
+		// if k < 0: xᵏ (mod q²) == (x⁻¹)ᵏ (mod q²)
+		x.Inverse(&x)
+
+		// we negate k in a temp big.Int since
+		// Int.Bit(_) of k and -k is different
+		e = new(big.Int).Neg(k)
+	}
+
+	z.SetOne()
+	b := e.Bytes()
+	for i := 0; i < len(b); i++ {
+		w := b[i]
+		for j := 0; j < 8; j++ {
+			z.Square(z)
+			if (w & (0b10000000 >> j)) != 0 {
+				z.Mul(z, &x)
+			}
+		}
+	}
+
+	return z
+}
+
+// Select is conditional move.
+// If cond = 0, it sets z to caseZ and returns it. otherwise caseNz.
+func (z *E2) Select(cond int, caseZ *E2, caseNz *E2) *E2 {
+	z.A0.Select(cond, &caseZ.A0, &caseNz.A0)
+	z.A1.Select(cond, &caseZ.A1, &caseNz.A1)
+	return z
+}
+
+// BatchInvertE2 returns a new slice with every element in a inverted.
+// It uses Montgomery batch inversion trick.
+//
+// if a[i] == 0, returns result[i] = a[i]
+func BatchInvertE2(a []E2) []E2 {
+	res := make([]E2, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	zeroes := make([]bool, len(a))
+	var accumulator E2
+	accumulator.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i].Set(&accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	return res
+}
+
+// Bytes returns the value of z as a big-endian byte array, A0 first.
+func (z *E2) Bytes() (res [SizeOfE2]byte) {
+	b0 := z.A0.Bytes()
+	b1 := z.A1.Bytes()
+	copy(res[:fp.Bytes], b0[:])
+	copy(res[fp.Bytes:], b1[:])
+	return
+}
+
+// SetBytes interprets e as the big-endian encoding of an E2 element (A0 first),
+// and sets z to that value.
+//
+// It returns an error if len(e) != SizeOfE2 or if a coordinate is not
+// the canonical encoding of a base field element.
+func (z *E2) SetBytes(e []byte) error {
+	if len(e) != SizeOfE2 {
+		return errors.New("invalid E2 encoding length")
+	}
+	var res E2
+	if err := res.A0.SetBytesCanonical(e[:fp.Bytes]); err != nil {
+		return err
+	}
+	if err := res.A1.SetBytesCanonical(e[fp.Bytes:]); err != nil {
+		return err
+	}
+	z.Set(&res)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (z *E2) MarshalBinary() ([]byte, error) {
+	b := z.Bytes()
+	return b[:], nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (z *E2) UnmarshalBinary(data []byte) error {
+	return z.SetBytes(data)
+}
+
+// mulByNonResidueE2 sets z to α⋅x where u² = α
+func mulByNonResidueE2(z, x *fp.Element) {
+	{{- template "mulByNonResidue" dict "RootOf" .E2RootOf "Var" "e2NonResidue"}}
+}
+`
@@ -0,0 +1,396 @@
+package extensions
+
+const E3 = `
+import (
+	"errors"
+	"math/big"
+
+	fp "{{.FieldPackagePath}}"
+)
+
+// SizeOfE3 is the number of bytes of the binary representation of an E3 element
+const SizeOfE3 = 3 * fp.Bytes
+
+{{- if not (or (eq .E3RootOf 2) (eq .E3RootOf 3) (eq .E3RootOf 5))}}
+
+// e3NonResidue is the non-residue β such that E3 = fp[v]/(v³ - β), in Montgomery form
+var e3NonResidue = fp.Element{{.E3NonResidue}}
+{{- end}}
+
+// e3FrobeniusCoefficients are β^((q-1)/3) and β^(2(q-1)/3), in Montgomery form
+var e3FrobeniusCoefficients = [2]fp.Element{
+	{{- range .E3FrobeniusCoefficients}}
+	{{.}},
+	{{- end}}
+}
+
+// E3 is a degree three finite field extension of fp.Element: E3 = fp[v]/(v³ - {{.E3RootOf}})
+type E3 struct {
+	A0, A1, A2 fp.Element
+}
+
+// Equal returns true if z equals x, false otherwise
+func (z *E3) Equal(x *E3) bool {
+	return z.A0.Equal(&x.A0) && z.A1.Equal(&x.A1) && z.A2.Equal(&x.A2)
+}
+
+// SetString sets a E3 element from strings
+func (z *E3) SetString(s1, s2, s3 string) *E3 {
+	z.A0.SetString(s1)
+	z.A1.SetString(s2)
+	z.A2.SetString(s3)
+	return z
+}
+
+// SetZero sets an E3 elmt to zero
+func (z *E3) SetZero() *E3 {
+	z.A0.SetZero()
+	z.A1.SetZero()
+	z.A2.SetZero()
+	return z
+}
+
+// Set sets an E3 from x
+func (z *E3) Set(x *E3) *E3 {
+	z.A0 = x.A0
+	z.A1 = x.A1
+	z.A2 = x.A2
+	return z
+}
+
+// SetElement sets z to the embedding of x in E3
+func (z *E3) SetElement(x *fp.Element) *E3 {
+	z.A0 = *x
+	z.A1.SetZero()
+	z.A2.SetZero()
+	return z
+}
+
+// SetOne sets z to 1 in Montgomery form and returns z
+func (z *E3) SetOne() *E3 {
+	z.A0.SetOne()
+	z.A1.SetZero()
+	z.A2.SetZero()
+	return z
+}
+
+// SetRandom sets a0, a1 and a2 to random values
+func (z *E3) SetRandom() (*E3, error) {
+	if _, err := z.A0.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := z.A1.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := z.A2.SetRandom(); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// IsZero returns true if z is zero, false otherwise
+func (z *E3) IsZero() bool {
+	return z.A0.IsZero() && z.A1.IsZero() && z.A2.IsZero()
+}
+
+// IsOne returns true if z is one, false otherwise
+func (z *E3) IsOne() bool {
+	return z.A0.IsOne() && z.A1.IsZero() && z.A2.IsZero()
+}
+
+// Add adds two elements of E3
+func (z *E3) Add(x, y *E3) *E3 {
+	z.A0.Add(&x.A0, &y.A0)
+	z.A1.Add(&x.A1, &y.A1)
+	z.A2.Add(&x.A2, &y.A2)
+	return z
+}
+
+// Sub subtracts two elements of E3
+func (z *E3) Sub(x, y *E3) *E3 {
+	z.A0.Sub(&x.A0, &y.A0)
+	z.A1.Sub(&x.A1, &y.A1)
+	z.A2.Sub(&x.A2, &y.A2)
+	return z
+}
+
+// Double doubles an E3 element
+func (z *E3) Double(x *E3) *E3 {
+	z.A0.Double(&x.A0)
+	z.A1.Double(&x.A1)
+	z.A2.Double(&x.A2)
+	return z
+}
+
+// Neg negates an E3 element
+func (z *E3) Neg(x *E3) *E3 {
+	z.A0.Neg(&x.A0)
+	z.A1.Neg(&x.A1)
+	z.A2.Neg(&x.A2)
+	return z
+}
+
+// String implements Stringer interface for fancy printing
+func (z *E3) String() string {
+	return z.A0.String() + "+(" + z.A1.String() + ")*v+(" + z.A2.String() + ")*v**2"
+}
+
+// Mul sets z to the E3-product of x,y, returns z
+func (z *E3) Mul(x, y *E3) *E3 {
+	// Algorithm 13 from https://eprint.iacr.org/2010/354.pdf
+	var t0, t1, t2, c0, c1, c2, tmp fp.Element
+	t0.Mul(&x.A0, &y.A0)
+	t1.Mul(&x.A1, &y.A1)
+	t2.Mul(&x.A2, &y.A2)
+
+	// c0 = t0 + β⋅((a1+a2)(b1+b2) - t1 - t2)
+	c0.Add(&x.A1, &x.A2)
+	tmp.Add(&y.A1, &y.A2)
+	c0.Mul(&c0, &tmp).Sub(&c0, &t1).Sub(&c0, &t2)
+	mulByNonResidueE3(&c0, &c0)
+	c0.Add(&c0, &t0)
+
+	// c1 = (a0+a1)(b0+b1) - t0 - t1 + β⋅t2
+	c1.Add(&x.A0, &x.A1)
+	tmp.Add(&y.A0, &y.A1)
+	c1.Mul(&c1, &tmp).Sub(&c1, &t0).Sub(&c1, &t1)
+	mulByNonResidueE3(&tmp, &t2)
+	c1.Add(&c1, &tmp)
+
+	// c2 = (a0+a2)(b0+b2) - t0 - t2 + t1
+	c2.Add(&x.A0, &x.A2)
+	tmp.Add(&y.A0, &y.A2)
+	c2.Mul(&c2, &tmp).Sub(&c2, &t0).Sub(&c2, &t2).Add(&c2, &t1)
+
+	z.A0 = c0
+	z.A1 = c1
+	z.A2 = c2
+	return z
+}
+
+// Square sets z to the E3-product of x,x, returns z
+func (z *E3) Square(x *E3) *E3 {
+	// Algorithm 16 from https://eprint.iacr.org/2010/354.pdf
+	var s0, s1, s2, s3, s4 fp.Element
+	s0.Square(&x.A0)
+	s1.Mul(&x.A0, &x.A1).Double(&s1)
+	s2.Sub(&x.A0, &x.A1).Add(&s2, &x.A2).Square(&s2)
+	s3.Mul(&x.A1, &x.A2).Double(&s3)
+	s4.Square(&x.A2)
+
+	// c2 = s1 + s2 + s3 - s0 - s4
+	z.A2.Add(&s1, &s2).Add(&z.A2, &s3).Sub(&z.A2, &s0).Sub(&z.A2, &s4)
+
+	// c0 = s0 + β⋅s3
+	mulByNonResidueE3(&s3, &s3)
+	z.A0.Add(&s0, &s3)
+
+	// c1 = s1 + β⋅s4
+	mulByNonResidueE3(&s4, &s4)
+	z.A1.Add(&s1, &s4)
+
+	return z
+}
+
+// MulByElement multiplies an element in E3 by an element in fp
+func (z *E3) MulByElement(x *E3, y *fp.Element) *E3 {
+	var yCopy fp.Element
+	yCopy.Set(y)
+	z.A0.Mul(&x.A0, &yCopy)
+	z.A1.Mul(&x.A1, &yCopy)
+	z.A2.Mul(&x.A2, &yCopy)
+	return z
+}
+
+// MulByNonResidue multiplies a E3 by v
+func (z *E3) MulByNonResidue(x *E3) *E3 {
+	// (a0 + a1⋅v + a2⋅v²)⋅v = β⋅a2 + a0⋅v + a1⋅v²
+	a0, a1 := x.A0, x.A1
+	mulByNonResidueE3(&z.A0, &x.A2)
+	z.A1 = a0
+	z.A2 = a1
+	return z
+}
+
+// Frobenius sets z to the q-th power of x, where q is the base field modulus
+//
+//	(a0 + a1⋅v + a2⋅v²)^q = a0 + a1⋅β^((q-1)/3)⋅v + a2⋅β^(2(q-1)/3)⋅v²
+func (z *E3) Frobenius(x *E3) *E3 {
+	z.A0 = x.A0
+	z.A1.Mul(&x.A1, &e3FrobeniusCoefficients[0])
+	z.A2.Mul(&x.A2, &e3FrobeniusCoefficients[1])
+	return z
+}
+
+// Inverse sets z to the E3-inverse of x, returns z
+//
+// if x == 0, sets and returns z = x
+func (z *E3) Inverse(x *E3) *E3 {
+	// Algorithm 17 from https://eprint.iacr.org/2010/354.pdf
+	var t0, t1, t2, t3, t4, t5, c0, c1, c2, d1, d2 fp.Element
+	t0.Square(&x.A0)
+	t1.Square(&x.A1)
+	t2.Square(&x.A2)
+	t3.Mul(&x.A0, &x.A1)
+	t4.Mul(&x.A0, &x.A2)
+	t5.Mul(&x.A1, &x.A2)
+
+	// c0 = a0² - β⋅a1⋅a2
+	mulByNonResidueE3(&c0, &t5)
+	c0.Sub(&t0, &c0)
+	// c1 = β⋅a2² - a0⋅a1
+	mulByNonResidueE3(&c1, &t2)
+	c1.Sub(&c1, &t3)
+	// c2 = a1² - a0⋅a2
+	c2.Sub(&t1, &t4)
+
+	// t = a0⋅c0 + β⋅(a2⋅c1 + a1⋅c2)
+	t0.Mul(&x.A0, &c0)
+	d1.Mul(&x.A2, &c1)
+	d2.Mul(&x.A1, &c2)
+	d1.Add(&d1, &d2)
+	mulByNonResidueE3(&d1, &d1)
+	t0.Add(&t0, &d1)
+	t0.Inverse(&t0)
+
+	z.A0.Mul(&c0, &t0)
+	z.A1.Mul(&c1, &t0)
+	z.A2.Mul(&c2, &t0)
+
+	return z
+}
+
+// Div divides an element in E3 by an element in E3
+func (z *E3) Div(x *E3, y *E3) *E3 {
+	var r E3
+	r.Inverse(y).Mul(x, &r)
+	return z.Set(&r)
+}
+
+// Exp sets z=xᵏ (mod q³) and returns it
+func (z *E3) Exp(x E3, k *big.Int) *E3 {
+	if k.IsUint64() && k.Uint64() == 0 {
+		return z.SetOne()
+	}
+
+	e := k
+	if k.Sign() == -1 {
+		// negative k, we invert
+		// if k < 0: xᵏ (mod q³) == (x⁻¹)ᵏ (mod q³)
+		x.Inverse(&x)
+
+		// we negate k in a temp big.Int since
+		// Int.Bit(_) of k and -k is different
+		e = new(big.Int).Neg(k)
+	}
+
+	z.SetOne()
+	b := e.Bytes()
+	for i := 0; i < len(b); i++ {
+		w := b[i]
+		for j := 0; j < 8; j++ {
+			z.Square(z)
+			if (w & (0b10000000 >> j)) != 0 {
+				z.Mul(z, &x)
+			}
+		}
+	}
+
+	return z
+}
+
+// Select is conditional move.
+// If cond = 0, it sets z to caseZ and returns it. otherwise caseNz.
+func (z *E3) Select(cond int, caseZ *E3, caseNz *E3) *E3 {
+	z.A0.Select(cond, &caseZ.A0, &caseNz.A0)
+	z.A1.Select(cond, &caseZ.A1, &caseNz.A1)
+	z.A2.Select(cond, &caseZ.A2, &caseNz.A2)
+	return z
+}
+
+// BatchInvertE3 returns a new slice with every element in a inverted.
+// It uses Montgomery batch inversion trick.
+//
+// if a[i] == 0, returns result[i] = a[i]
+func BatchInvertE3(a []E3) []E3 {
+	res := make([]E3, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	zeroes := make([]bool, len(a))
+	var accumulator E3
+	accumulator.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i].Set(&accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	return res
+}
+
+// Bytes returns the value of z as a big-endian byte array, A0 first.
+func (z *E3) Bytes() (res [SizeOfE3]byte) {
+	b0 := z.A0.Bytes()
+	b1 := z.A1.Bytes()
+	b2 := z.A2.Bytes()
+	copy(res[:fp.Bytes], b0[:])
+	copy(res[fp.Bytes:2*fp.Bytes], b1[:])
+	copy(res[2*fp.Bytes:], b2[:])
+	return
+}
+
+// SetBytes interprets e as the big-endian encoding of an E3 element (A0 first),
+// and sets z to that value.
+//
+// It returns an error if len(e) != SizeOfE3 or if a coordinate is not
+// the canonical encoding of a base field element.
+func (z *E3) SetBytes(e []byte) error {
+	if len(e) != SizeOfE3 {
+		return errors.New("invalid E3 encoding length")
+	}
+	var res E3
+	if err := res.A0.SetBytesCanonical(e[:fp.Bytes]); err != nil {
+		return err
+	}
+	if err := res.A1.SetBytesCanonical(e[fp.Bytes : 2*fp.Bytes]); err != nil {
+		return err
+	}
+	if err := res.A2.SetBytesCanonical(e[2*fp.Bytes:]); err != nil {
+		return err
+	}
+	z.Set(&res)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (z *E3) MarshalBinary() ([]byte, error) {
+	b := z.Bytes()
+	return b[:], nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (z *E3) UnmarshalBinary(data []byte) error {
+	return z.SetBytes(data)
+}
+
+// mulByNonResidueE3 sets z to β⋅x where v³ = β
+func mulByNonResidueE3(z, x *fp.Element) {
+	{{- template "mulByNonResidue" dict "RootOf" .E3RootOf "Var" "e3NonResidue"}}
+}
+`
@@ -0,0 +1,18 @@
+package extensions
+
+// Helpers contains the template blocks shared by the E2 and E3 templates
+const Helpers = `
+{{ define "mulByNonResidue" }}
+	{{- if eq .RootOf 2}}
+	z.Double(x)
+	{{- else if eq .RootOf 3}}
+	z.Set(x)
+	fp.MulBy3(z)
+	{{- else if eq .RootOf 5}}
+	z.Set(x)
+	fp.MulBy5(z)
+	{{- else}}
+	z.Mul(x, &{{.Var}})
+	{{- end}}
+{{- end}}
+`
@@ -0,0 +1,35 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package extensions provides degree 2 and degree 3 extensions of goldilocks.Element.
+//
+// Both extensions are simple radical extensions of the base field, as described
+// by field/generator/config.Extension:
+//
+//	E2 = goldilocks[u] / (u² - 7)
+//	E3 = goldilocks[v] / (v³ - 2)
+//
+// Elements are written A0 + A1⋅u (resp. A0 + A1⋅v + A2⋅v²) with coordinates in the base field.
+//
+// Base field modulus q =
+//
+//	q[base10] = 18446744069414584321
+//	q[base16] = 0xffffffff00000001
+//
+// # Warning
+//
+// This code has not been audited and is provided as-is. In particular, there is no security guarantees such as constant time implementation or side-channel attack resistance.
+package extensions
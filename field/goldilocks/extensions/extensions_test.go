@@ -0,0 +1,701 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package extensions
+
+import (
+	"math/big"
+	"testing"
+
+	fp "github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+// ------------------------------------------------------------
+// tests
+
+const (
+	nbFuzzShort = 10
+	nbFuzz      = 50
+)
+
+func TestE2ReceiverIsOperand(t *testing.T) {
+	t.Parallel()
+	properties := gopter.NewProperties(testParameters())
+
+	genA := GenE2()
+	genB := GenE2()
+
+	properties.Property("Having the receiver as operand (mul) should output the same result", prop.ForAll(
+		func(a, b *E2) bool {
+			var c, d E2
+			d.Set(a)
+			c.Mul(a, b)
+			a.Mul(a, b)
+			b.Mul(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("Having the receiver as operand (square) should output the same result", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			b.Square(a)
+			a.Square(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (inverse) should output the same result", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			b.Inverse(a)
+			a.Inverse(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (mul by non residue) should output the same result", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			b.MulByNonResidue(a)
+			a.MulByNonResidue(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (Frobenius) should output the same result", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			b.Frobenius(a)
+			a.Frobenius(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestE2Ops(t *testing.T) {
+	t.Parallel()
+	properties := gopter.NewProperties(testParameters())
+
+	genA := GenE2()
+	genB := GenE2()
+	genfp := GenFp()
+
+	properties.Property("u² should be equal to 7", prop.ForAll(
+		func() bool {
+			var u, uu, alpha E2
+			u.A1.SetOne()
+			uu.Square(&u)
+			alpha.A0.SetUint64(7)
+			return uu.Equal(&alpha)
+		},
+	))
+
+	properties.Property("sub & add should leave an element invariant", prop.ForAll(
+		func(a, b *E2) bool {
+			var c E2
+			c.Set(a)
+			c.Add(&c, b).Sub(&c, b)
+			return c.Equal(a)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("mul & inverse should leave an element invariant", prop.ForAll(
+		func(a, b *E2) bool {
+			var c, d E2
+			d.Inverse(b)
+			c.Set(a)
+			c.Mul(&c, b).Mul(&c, &d)
+			return c.Equal(a)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("inverse twice should leave an element invariant", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			b.Inverse(a).Inverse(&b)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("square and mul should output the same result", prop.ForAll(
+		func(a *E2) bool {
+			var b, c E2
+			b.Mul(a, a)
+			c.Square(a)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("MulByElement MulByElement inverse should leave an element invariant", prop.ForAll(
+		func(a *E2, b fp.Element) bool {
+			var c E2
+			var d fp.Element
+			d.Inverse(&b)
+			c.MulByElement(a, &b).MulByElement(&c, &d)
+			return c.Equal(a)
+		},
+		genA,
+		genfp,
+	))
+
+	properties.Property("MulByElement should be the same as Mul by the embedded element", prop.ForAll(
+		func(a *E2, b fp.Element) bool {
+			var c, d E2
+			c.MulByElement(a, &b)
+			d.SetElement(&b).Mul(a, &d)
+			return c.Equal(&d)
+		},
+		genA,
+		genfp,
+	))
+
+	properties.Property("MulByNonResidue should be the same as Mul by u", prop.ForAll(
+		func(a *E2) bool {
+			var b, u E2
+			u.A1.SetOne()
+			b.MulByNonResidue(a)
+			u.Mul(a, &u)
+			return b.Equal(&u)
+		},
+		genA,
+	))
+
+	properties.Property("Frobenius should be the same as Exp by q", prop.ForAll(
+		func(a *E2) bool {
+			var b, c E2
+			b.Frobenius(a)
+			c.Exp(*a, fp.Modulus())
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("Exp should be the same as repeated Mul", prop.ForAll(
+		func(a *E2, k uint8) bool {
+			var b, c E2
+			b.Exp(*a, big.NewInt(int64(k)))
+			c.SetOne()
+			for i := 0; i < int(k); i++ {
+				c.Mul(&c, a)
+			}
+			return b.Equal(&c)
+		},
+		genA,
+		gopter.Gen(func(genParams *gopter.GenParameters) *gopter.GenResult {
+			return gopter.NewGenResult(uint8(genParams.NextUint64()), gopter.NoShrinker)
+		}),
+	))
+
+	properties.Property("Exp by -k should be the inverse of Exp by k", prop.ForAll(
+		func(a *E2) bool {
+			var b, c E2
+			k := big.NewInt(1234567)
+			b.Exp(*a, k)
+			c.Exp(*a, k.Neg(k))
+			return b.Mul(&b, &c).IsOne()
+		},
+		genA,
+	))
+
+	properties.Property("Exp by q²-1 should be one", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			k := new(big.Int).Exp(fp.Modulus(), big.NewInt(2), nil)
+			k.Sub(k, big.NewInt(1))
+			b.Exp(*a, k)
+			return b.IsOne()
+		},
+		genA,
+	))
+
+	properties.Property("BatchInvertE2 should output the same result as Inverse", prop.ForAll(
+		func(a, b, c *E2) bool {
+			batch := BatchInvertE2([]E2{*a, *b, {}, *c})
+			var aInv, bInv, cInv E2
+			aInv.Inverse(a)
+			bInv.Inverse(b)
+			cInv.Inverse(c)
+			return batch[0].Equal(&aInv) && batch[1].Equal(&bInv) && batch[2].IsZero() && batch[3].Equal(&cInv)
+		},
+		genA,
+		genA,
+		genA,
+	))
+
+	properties.Property("SetBytes(Bytes()) should leave an element invariant", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			buf := a.Bytes()
+			if err := b.SetBytes(buf[:]); err != nil {
+				return false
+			}
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestE3ReceiverIsOperand(t *testing.T) {
+	t.Parallel()
+	properties := gopter.NewProperties(testParameters())
+
+	genA := GenE3()
+	genB := GenE3()
+
+	properties.Property("Having the receiver as operand (mul) should output the same result", prop.ForAll(
+		func(a, b *E3) bool {
+			var c, d E3
+			d.Set(a)
+			c.Mul(a, b)
+			a.Mul(a, b)
+			b.Mul(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("Having the receiver as operand (square) should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Square(a)
+			a.Square(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (inverse) should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Inverse(a)
+			a.Inverse(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (mul by non residue) should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.MulByNonResidue(a)
+			a.MulByNonResidue(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (Frobenius) should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Frobenius(a)
+			a.Frobenius(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestE3Ops(t *testing.T) {
+	t.Parallel()
+	properties := gopter.NewProperties(testParameters())
+
+	genA := GenE3()
+	genB := GenE3()
+	genfp := GenFp()
+
+	properties.Property("v³ should be equal to 2", prop.ForAll(
+		func() bool {
+			var v, vvv, beta E3
+			v.A1.SetOne()
+			vvv.Square(&v).Mul(&vvv, &v)
+			beta.A0.SetUint64(2)
+			return vvv.Equal(&beta)
+		},
+	))
+
+	properties.Property("sub & add should leave an element invariant", prop.ForAll(
+		func(a, b *E3) bool {
+			var c E3
+			c.Set(a)
+			c.Add(&c, b).Sub(&c, b)
+			return c.Equal(a)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("mul & inverse should leave an element invariant", prop.ForAll(
+		func(a, b *E3) bool {
+			var c, d E3
+			d.Inverse(b)
+			c.Set(a)
+			c.Mul(&c, b).Mul(&c, &d)
+			return c.Equal(a)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("inverse twice should leave an element invariant", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Inverse(a).Inverse(&b)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("square and mul should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b, c E3
+			b.Mul(a, a)
+			c.Square(a)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("MulByElement should be the same as Mul by the embedded element", prop.ForAll(
+		func(a *E3, b fp.Element) bool {
+			var c, d E3
+			c.MulByElement(a, &b)
+			d.SetElement(&b).Mul(a, &d)
+			return c.Equal(&d)
+		},
+		genA,
+		genfp,
+	))
+
+	properties.Property("MulByNonResidue should be the same as Mul by v", prop.ForAll(
+		func(a *E3) bool {
+			var b, v E3
+			v.A1.SetOne()
+			b.MulByNonResidue(a)
+			v.Mul(a, &v)
+			return b.Equal(&v)
+		},
+		genA,
+	))
+
+	properties.Property("Frobenius should be the same as Exp by q", prop.ForAll(
+		func(a *E3) bool {
+			var b, c E3
+			b.Frobenius(a)
+			c.Exp(*a, fp.Modulus())
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("Frobenius applied 3 times should leave an element invariant", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Frobenius(a).Frobenius(&b).Frobenius(&b)
+			return b.Equal(a)
+		},
+		genA,
+	))
+
+	properties.Property("Exp by -k should be the inverse of Exp by k", prop.ForAll(
+		func(a *E3) bool {
+			var b, c E3
+			k := big.NewInt(1234567)
+			b.Exp(*a, k)
+			c.Exp(*a, k.Neg(k))
+			return b.Mul(&b, &c).IsOne()
+		},
+		genA,
+	))
+
+	properties.Property("Exp by q³-1 should be one", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			k := new(big.Int).Exp(fp.Modulus(), big.NewInt(3), nil)
+			k.Sub(k, big.NewInt(1))
+			b.Exp(*a, k)
+			return b.IsOne()
+		},
+		genA,
+	))
+
+	properties.Property("BatchInvertE3 should output the same result as Inverse", prop.ForAll(
+		func(a, b, c *E3) bool {
+			batch := BatchInvertE3([]E3{*a, *b, {}, *c})
+			var aInv, bInv, cInv E3
+			aInv.Inverse(a)
+			bInv.Inverse(b)
+			cInv.Inverse(c)
+			return batch[0].Equal(&aInv) && batch[1].Equal(&bInv) && batch[2].IsZero() && batch[3].Equal(&cInv)
+		},
+		genA,
+		genA,
+		genA,
+	))
+
+	properties.Property("SetBytes(Bytes()) should leave an element invariant", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			buf := a.Bytes()
+			if err := b.SetBytes(buf[:]); err != nil {
+				return false
+			}
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestSetBytesErrors(t *testing.T) {
+	var a E2
+	if err := a.SetBytes(make([]byte, SizeOfE2-1)); err == nil {
+		t.Fatal("expected an error on short input")
+	}
+	buf := make([]byte, SizeOfE2)
+	for i := range buf {
+		buf[i] = 0xff
+	}
+	if err := a.SetBytes(buf); err == nil {
+		t.Fatal("expected an error on non canonical input")
+	}
+
+	var b E3
+	if err := b.SetBytes(make([]byte, SizeOfE3+1)); err == nil {
+		t.Fatal("expected an error on long input")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+//
+// each benchmark runs the operation in the base field, in E2 and in E3.
+
+func BenchmarkMul(b *testing.B) {
+	b.Run("fp", func(b *testing.B) {
+		var x, y fp.Element
+		x.SetRandom()
+		y.SetRandom()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			x.Mul(&x, &y)
+		}
+	})
+	b.Run("E2", func(b *testing.B) {
+		var x, y E2
+		x.SetRandom()
+		y.SetRandom()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			x.Mul(&x, &y)
+		}
+	})
+	b.Run("E3", func(b *testing.B) {
+		var x, y E3
+		x.SetRandom()
+		y.SetRandom()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			x.Mul(&x, &y)
+		}
+	})
+}
+
+func BenchmarkSquare(b *testing.B) {
+	b.Run("fp", func(b *testing.B) {
+		var x fp.Element
+		x.SetRandom()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			x.Square(&x)
+		}
+	})
+	b.Run("E2", func(b *testing.B) {
+		var x E2
+		x.SetRandom()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			x.Square(&x)
+		}
+	})
+	b.Run("E3", func(b *testing.B) {
+		var x E3
+		x.SetRandom()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			x.Square(&x)
+		}
+	})
+}
+
+func BenchmarkInverse(b *testing.B) {
+	b.Run("fp", func(b *testing.B) {
+		var x fp.Element
+		x.SetRandom()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			x.Inverse(&x)
+		}
+	})
+	b.Run("E2", func(b *testing.B) {
+		var x E2
+		x.SetRandom()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			x.Inverse(&x)
+		}
+	})
+	b.Run("E3", func(b *testing.B) {
+		var x E3
+		x.SetRandom()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			x.Inverse(&x)
+		}
+	})
+}
+
+func BenchmarkExp(b *testing.B) {
+	k := new(big.Int).Lsh(big.NewInt(1), 64)
+	k.Sub(k, big.NewInt(1))
+	b.Run("fp", func(b *testing.B) {
+		var x fp.Element
+		x.SetRandom()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			x.Exp(x, k)
+		}
+	})
+	b.Run("E2", func(b *testing.B) {
+		var x E2
+		x.SetRandom()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			x.Exp(x, k)
+		}
+	})
+	b.Run("E3", func(b *testing.B) {
+		var x E3
+		x.SetRandom()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			x.Exp(x, k)
+		}
+	})
+}
+
+func BenchmarkBatchInvert(b *testing.B) {
+	const n = 1 << 10
+	b.Run("fp", func(b *testing.B) {
+		x := make([]fp.Element, n)
+		for i := range x {
+			x[i].SetRandom()
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			fp.BatchInvert(x)
+		}
+	})
+	b.Run("E2", func(b *testing.B) {
+		x := make([]E2, n)
+		for i := range x {
+			x[i].SetRandom()
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			BatchInvertE2(x)
+		}
+	})
+	b.Run("E3", func(b *testing.B) {
+		x := make([]E3, n)
+		for i := range x {
+			x[i].SetRandom()
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			BatchInvertE3(x)
+		}
+	})
+}
+
+// ------------------------------------------------------------
+// generators
+
+func testParameters() *gopter.TestParameters {
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+	return parameters
+}
+
+// GenFp generates an Fp element
+func GenFp() gopter.Gen {
+	return func(genParams *gopter.GenParameters) *gopter.GenResult {
+		var elmt fp.Element
+
+		if _, err := elmt.SetRandom(); err != nil {
+			panic(err)
+		}
+		genResult := gopter.NewGenResult(elmt, gopter.NoShrinker)
+		return genResult
+	}
+}
+
+// GenE2 generates an E2 elmt
+func GenE2() gopter.Gen {
+	return gopter.CombineGens(
+		GenFp(),
+		GenFp(),
+	).Map(func(values []interface{}) *E2 {
+		return &E2{A0: values[0].(fp.Element), A1: values[1].(fp.Element)}
+	})
+}
+
+// GenE3 generates an E3 elmt
+func GenE3() gopter.Gen {
+	return gopter.CombineGens(
+		GenFp(),
+		GenFp(),
+		GenFp(),
+	).Map(func(values []interface{}) *E3 {
+		return &E3{A0: values[0].(fp.Element), A1: values[1].(fp.Element), A2: values[2].(fp.Element)}
+	})
+}
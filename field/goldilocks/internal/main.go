@@ -18,4 +18,13 @@ func main() {
 		panic(err)
 	}
 	fmt.Println("successfully generated goldilocks field")
+
+	// E2 = goldilocks[u]/(u² - 7), E3 = goldilocks[v]/(v³ - 2)
+	e2 := config.NewTower(goldilocks, 2, 7)
+	e3 := config.NewTower(goldilocks, 3, 2)
+	const fieldPackagePath = "github.com/consensys/gnark-crypto/field/goldilocks"
+	if err := generator.GenerateExtensions(goldilocks, e2, e3, fieldPackagePath, "../extensions"); err != nil {
+		panic(err)
+	}
+	fmt.Println("successfully generated goldilocks extensions")
 }
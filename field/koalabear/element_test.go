@@ -0,0 +1,462 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package koalabear
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	ggen "github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+
+	"github.com/stretchr/testify/require"
+)
+
+// -------------------------------------------------------------------------------------------------
+// benchmarks
+
+var benchResElement Element
+
+func BenchmarkElementAdd(b *testing.B) {
+	var x Element
+	x.SetRandom()
+	benchResElement.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchResElement.Add(&x, &benchResElement)
+	}
+}
+
+func BenchmarkElementSub(b *testing.B) {
+	var x Element
+	x.SetRandom()
+	benchResElement.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchResElement.Sub(&x, &benchResElement)
+	}
+}
+
+func BenchmarkElementMul(b *testing.B) {
+	var x Element
+	x.SetRandom()
+	benchResElement.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchResElement.Mul(&benchResElement, &x)
+	}
+}
+
+func BenchmarkElementSquare(b *testing.B) {
+	benchResElement.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchResElement.Square(&benchResElement)
+	}
+}
+
+func BenchmarkElementInverse(b *testing.B) {
+	var x Element
+	x.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchResElement.Inverse(&x)
+	}
+}
+
+func BenchmarkElementExp(b *testing.B) {
+	var x Element
+	x.SetRandom()
+	e, _ := rand.Int(rand.Reader, Modulus())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchResElement.Exp(x, e)
+	}
+}
+
+func BenchmarkElementSqrt(b *testing.B) {
+	var a Element
+	a.SetUint64(4)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchResElement.Sqrt(&a)
+	}
+}
+
+func BenchmarkElementBatchInvert(b *testing.B) {
+	const n = 1 << 10
+	a := make([]Element, n)
+	for i := range a {
+		a[i].SetRandom()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchInvert(a)
+	}
+}
+
+// -------------------------------------------------------------------------------------------------
+// tests
+
+const (
+	nbFuzzShort = 200
+	nbFuzz      = 1000
+)
+
+// testPairElement holds an element and its value as a big.Int, in regular form
+type testPairElement struct {
+	element Element
+	bigint  big.Int
+}
+
+// gen returns a generator of random elements, including the edge cases 0, 1 and q-1
+func gen() gopter.Gen {
+	return func(genParams *gopter.GenParameters) *gopter.GenResult {
+		var g testPairElement
+
+		switch genParams.NextUint64() % 16 {
+		case 0:
+			g.element.SetZero()
+		case 1:
+			g.element.SetOne()
+		case 2:
+			g.element.SetInt64(-1)
+		default:
+			g.element[0] = uint32(genParams.NextUint64() % uint64(q))
+		}
+		g.element.BigInt(&g.bigint)
+
+		return gopter.NewGenResult(g, gopter.NoShrinker)
+	}
+}
+
+func testParameters() *gopter.TestParameters {
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+	return parameters
+}
+
+// isEqual returns true if e encodes the big.Int b (not necessarily reduced)
+func isEqual(e *Element, b *big.Int) bool {
+	var r big.Int
+	r.Mod(b, Modulus())
+	return e.BigInt(new(big.Int)).Cmp(&r) == 0 && e.smallerThanModulus()
+}
+
+func TestElementArithmetic(t *testing.T) {
+	t.Parallel()
+	parameters := testParameters()
+	properties := gopter.NewProperties(parameters)
+	genA, genB := gen(), gen()
+
+	properties.Property("Add: matches big.Int", prop.ForAll(
+		func(a, b testPairElement) bool {
+			var c Element
+			c.Add(&a.element, &b.element)
+			return isEqual(&c, new(big.Int).Add(&a.bigint, &b.bigint))
+		},
+		genA, genB,
+	))
+
+	properties.Property("Sub: matches big.Int", prop.ForAll(
+		func(a, b testPairElement) bool {
+			var c Element
+			c.Sub(&a.element, &b.element)
+			return isEqual(&c, new(big.Int).Sub(&a.bigint, &b.bigint))
+		},
+		genA, genB,
+	))
+
+	properties.Property("Mul: matches big.Int", prop.ForAll(
+		func(a, b testPairElement) bool {
+			var c Element
+			c.Mul(&a.element, &b.element)
+			return isEqual(&c, new(big.Int).Mul(&a.bigint, &b.bigint))
+		},
+		genA, genB,
+	))
+
+	properties.Property("Div: (a / b) * b == a", prop.ForAll(
+		func(a, b testPairElement) bool {
+			if b.element.IsZero() {
+				return true
+			}
+			var c Element
+			c.Div(&a.element, &b.element).Mul(&c, &b.element)
+			return c.Equal(&a.element)
+		},
+		genA, genB,
+	))
+
+	properties.Property("Square: matches big.Int", prop.ForAll(
+		func(a testPairElement) bool {
+			var c Element
+			c.Square(&a.element)
+			return isEqual(&c, new(big.Int).Mul(&a.bigint, &a.bigint))
+		},
+		genA,
+	))
+
+	properties.Property("Double: matches big.Int", prop.ForAll(
+		func(a testPairElement) bool {
+			var c Element
+			c.Double(&a.element)
+			return isEqual(&c, new(big.Int).Lsh(&a.bigint, 1))
+		},
+		genA,
+	))
+
+	properties.Property("Neg: matches big.Int", prop.ForAll(
+		func(a testPairElement) bool {
+			var c Element
+			c.Neg(&a.element)
+			return isEqual(&c, new(big.Int).Neg(&a.bigint))
+		},
+		genA,
+	))
+
+	properties.Property("Halve: 2 * (a / 2) == a", prop.ForAll(
+		func(a testPairElement) bool {
+			c := a.element
+			c.Halve()
+			c.Double(&c)
+			return c.Equal(&a.element) && c.smallerThanModulus()
+		},
+		genA,
+	))
+
+	properties.Property("MulBy3, MulBy5, MulBy13: match Mul", prop.ForAll(
+		func(a testPairElement) bool {
+			c3, c5, c13 := a.element, a.element, a.element
+			MulBy3(&c3)
+			MulBy5(&c5)
+			MulBy13(&c13)
+			return isEqual(&c3, new(big.Int).Mul(&a.bigint, big.NewInt(3))) &&
+				isEqual(&c5, new(big.Int).Mul(&a.bigint, big.NewInt(5))) &&
+				isEqual(&c13, new(big.Int).Mul(&a.bigint, big.NewInt(13)))
+		},
+		genA,
+	))
+
+	properties.Property("Butterfly: (a, b) -> (a + b, a - b)", prop.ForAll(
+		func(a, b testPairElement) bool {
+			c, d := a.element, b.element
+			Butterfly(&c, &d)
+			return isEqual(&c, new(big.Int).Add(&a.bigint, &b.bigint)) &&
+				isEqual(&d, new(big.Int).Sub(&a.bigint, &b.bigint))
+		},
+		genA, genB,
+	))
+
+	properties.Property("Inverse: matches big.Int", prop.ForAll(
+		func(a testPairElement) bool {
+			var c Element
+			c.Inverse(&a.element)
+			if a.element.IsZero() {
+				return c.IsZero()
+			}
+			return isEqual(&c, new(big.Int).ModInverse(&a.bigint, Modulus()))
+		},
+		genA,
+	))
+
+	properties.Property("Exp: matches big.Int", prop.ForAll(
+		func(a, b testPairElement) bool {
+			var c Element
+			c.Exp(a.element, &b.bigint)
+			return isEqual(&c, new(big.Int).Exp(&a.bigint, &b.bigint, Modulus()))
+		},
+		genA, genB,
+	))
+
+	properties.Property("Legendre and Sqrt: match big.Int", prop.ForAll(
+		func(a testPairElement) bool {
+			l := a.element.Legendre()
+			if big.Jacobi(&a.bigint, Modulus()) != l {
+				return false
+			}
+			var c Element
+			if c.Sqrt(&a.element) == nil {
+				return l == -1
+			}
+			c.Square(&c)
+			return l != -1 && c.Equal(&a.element)
+		},
+		genA,
+	))
+
+	properties.Property("Cmp and LexicographicallyLargest: match big.Int", prop.ForAll(
+		func(a, b testPairElement) bool {
+			var halfQ big.Int
+			halfQ.Rsh(Modulus(), 1)
+			return a.element.Cmp(&b.element) == a.bigint.Cmp(&b.bigint) &&
+				a.element.LexicographicallyLargest() == (a.bigint.Cmp(&halfQ) > 0)
+		},
+		genA, genB,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestElementConversions(t *testing.T) {
+	t.Parallel()
+	parameters := testParameters()
+	properties := gopter.NewProperties(parameters)
+	genA := gen()
+
+	properties.Property("SetBigInt(BigInt(a)) == a", prop.ForAll(
+		func(a testPairElement) bool {
+			var c Element
+			c.SetBigInt(&a.bigint)
+			return c.Equal(&a.element)
+		},
+		genA,
+	))
+
+	properties.Property("SetBytes(Bytes(a)) == a", prop.ForAll(
+		func(a testPairElement) bool {
+			var c, d Element
+			b := a.element.Bytes()
+			c.SetBytes(b[:])
+			if err := d.SetBytesCanonical(b[:]); err != nil {
+				return false
+			}
+			return c.Equal(&a.element) && d.Equal(&a.element) &&
+				new(big.Int).SetBytes(b[:]).Cmp(&a.bigint) == 0
+		},
+		genA,
+	))
+
+	properties.Property("BigEndian and LittleEndian round trip", prop.ForAll(
+		func(a testPairElement) bool {
+			var b, l [Bytes]byte
+			BigEndian.PutElement(&b, a.element)
+			LittleEndian.PutElement(&l, a.element)
+			c, errB := BigEndian.Element(&b)
+			d, errL := LittleEndian.Element(&l)
+			return errB == nil && errL == nil && c.Equal(&a.element) && d.Equal(&a.element) &&
+				b[0] == l[Bytes-1]
+		},
+		genA,
+	))
+
+	properties.Property("SetString(String(a)) == a and Text matches big.Int", prop.ForAll(
+		func(a testPairElement) bool {
+			var c Element
+			if _, err := c.SetString(a.element.String()); err != nil {
+				return false
+			}
+			return c.Equal(&a.element) && a.element.Text(16) == a.bigint.Text(16)
+		},
+		genA,
+	))
+
+	properties.Property("SetUint64 and SetInt64 match big.Int", prop.ForAll(
+		func(v uint64) bool {
+			var a, b Element
+			a.SetUint64(v)
+			b.SetInt64(int64(v))
+			return isEqual(&a, new(big.Int).SetUint64(v)) && isEqual(&b, big.NewInt(int64(v)))
+		},
+		ggen.UInt64(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestElementEdgeCases(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+
+	var qMinusOne big.Int
+	qMinusOne.Sub(Modulus(), big.NewInt(1))
+
+	// SetInt64 on the most negative value
+	var a Element
+	a.SetInt64(-1 << 63)
+	var expected big.Int
+	expected.Lsh(big.NewInt(-1), 63)
+	assert.True(isEqual(&a, &expected))
+
+	// non canonical encodings
+	var b [Bytes]byte
+	for i := range b {
+		b[i] = 0xff
+	}
+	_, err := BigEndian.Element(&b)
+	assert.Error(err, "0xff..ff is not a canonical encoding")
+	_, err = LittleEndian.Element(&b)
+	assert.Error(err, "0xff..ff is not a canonical encoding")
+	assert.Error(a.SetBytesCanonical(b[:]))
+
+	// q - 1 is the largest element
+	a.SetBigInt(&qMinusOne)
+	assert.True(a.LexicographicallyLargest())
+	assert.Equal(0, a.BigInt(new(big.Int)).Cmp(&qMinusOne))
+	var c Element
+	c.Add(&a, &a)
+	assert.True(isEqual(&c, new(big.Int).Lsh(&qMinusOne, 1)))
+	c.Mul(&a, &a)
+	assert.True(c.IsOne(), "(-1)² == 1")
+
+	// JSON
+	encoded, err := json.Marshal(&a)
+	assert.NoError(err)
+	var decoded Element
+	assert.NoError(json.Unmarshal(encoded, &decoded))
+	assert.True(decoded.Equal(&a))
+}
+
+func TestElementSetRandom(t *testing.T) {
+	t.Parallel()
+	var a Element
+	for i := 0; i < 1000; i++ {
+		if _, err := a.SetRandom(); err != nil {
+			t.Fatal(err)
+		}
+		if !a.smallerThanModulus() {
+			t.Fatal("SetRandom returned a non reduced element")
+		}
+	}
+}
+
+func TestElementBatchInvert(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+
+	const n = 37
+	a := make([]Element, n)
+	for i := range a {
+		a[i].SetRandom()
+	}
+	a[5].SetZero()
+	a[n-1].SetZero()
+
+	res := BatchInvert(a)
+	for i := range a {
+		var expected Element
+		expected.Inverse(&a[i])
+		assert.True(expected.Equal(&res[i]), "index %d", i)
+	}
+}
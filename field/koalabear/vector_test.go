@@ -0,0 +1,171 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package koalabear
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestVectorSort(t *testing.T) {
+	assert := require.New(t)
+
+	v := make(Vector, 3)
+	v[0].SetUint64(2)
+	v[1].SetUint64(3)
+	v[2].SetUint64(1)
+
+	sort.Sort(v)
+
+	assert.Equal("[1,2,3]", v.String())
+}
+
+func TestVectorRoundTrip(t *testing.T) {
+	assert := require.New(t)
+
+	v1 := make(Vector, 3)
+	v1[0].SetUint64(2)
+	v1[1].SetUint64(3)
+	v1[2].SetUint64(1)
+
+	b, err := v1.MarshalBinary()
+	assert.NoError(err)
+
+	var v2, v3 Vector
+
+	err = v2.UnmarshalBinary(b)
+	assert.NoError(err)
+
+	err = v3.unmarshalBinaryAsync(b)
+	assert.NoError(err)
+
+	assert.True(reflect.DeepEqual(v1, v2))
+	assert.True(reflect.DeepEqual(v3, v2))
+}
+
+func TestVectorEmptyRoundTrip(t *testing.T) {
+	assert := require.New(t)
+
+	v1 := make(Vector, 0)
+
+	b, err := v1.MarshalBinary()
+	assert.NoError(err)
+
+	var v2, v3 Vector
+
+	err = v2.UnmarshalBinary(b)
+	assert.NoError(err)
+
+	err = v3.unmarshalBinaryAsync(b)
+	assert.NoError(err)
+
+	assert.True(reflect.DeepEqual(v1, v2))
+	assert.True(reflect.DeepEqual(v3, v2))
+}
+
+func (vector *Vector) unmarshalBinaryAsync(data []byte) error {
+	r := bytes.NewReader(data)
+	_, err, chErr := vector.AsyncReadFrom(r)
+	if err != nil {
+		return err
+	}
+	return <-chErr
+}
+
+func TestVectorOps(t *testing.T) {
+	testVectorOps(t)
+}
+
+// testVectorOps checks that vector operations match element wise operations,
+// on all lengths up to a few blocks of (assembly) vector registers.
+func testVectorOps(t *testing.T) {
+	assert := require.New(t)
+
+	for n := 0; n < 70; n++ {
+		a, b := make(Vector, n), make(Vector, n)
+		for i := 0; i < n; i++ {
+			a[i].SetRandom()
+			b[i].SetRandom()
+		}
+		// edge cases
+		if n > 2 {
+			a[0].SetInt64(-1)
+			b[0].SetInt64(-1)
+			a[1].SetZero()
+			b[2].SetInt64(-1)
+		}
+		var s Element
+		s.SetRandom()
+
+		add, sub, mul, scalarMul := make(Vector, n), make(Vector, n), make(Vector, n), make(Vector, n)
+		add.Add(a, b)
+		sub.Sub(a, b)
+		mul.Mul(a, b)
+		scalarMul.ScalarMul(a, &s)
+
+		for i := 0; i < n; i++ {
+			var e Element
+			assert.True(e.Add(&a[i], &b[i]).Equal(&add[i]), "Add: n = %d, i = %d", n, i)
+			assert.True(e.Sub(&a[i], &b[i]).Equal(&sub[i]), "Sub: n = %d, i = %d", n, i)
+			assert.True(e.Mul(&a[i], &b[i]).Equal(&mul[i]), "Mul: n = %d, i = %d", n, i)
+			assert.True(e.Mul(&a[i], &s).Equal(&scalarMul[i]), "ScalarMul: n = %d, i = %d", n, i)
+		}
+
+		// in place
+		add.Add(add, b)
+		for i := 0; i < n; i++ {
+			var e Element
+			e.Add(&a[i], &b[i]).Add(&e, &b[i])
+			assert.True(e.Equal(&add[i]), "in place Add: n = %d, i = %d", n, i)
+		}
+	}
+}
+
+func BenchmarkVectorOps(b *testing.B) {
+	const N = 1 << 20
+	a, c, res := make(Vector, N), make(Vector, N), make(Vector, N)
+	for i := 0; i < N; i++ {
+		a[i].SetRandom()
+		c[i].SetRandom()
+	}
+	var s Element
+	s.SetRandom()
+
+	b.Run("Add", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.Add(a, c)
+		}
+	})
+	b.Run("Sub", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.Sub(a, c)
+		}
+	})
+	b.Run("Mul", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.Mul(a, c)
+		}
+	})
+	b.Run("ScalarMul", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.ScalarMul(a, &s)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+//go:build !purego
+// +build !purego
+
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package mersenne31
+
+import "golang.org/x/sys/cpu"
+
+var (
+	supportAvx512 = cpu.X86.HasAVX512F
+	supportAvx2   = cpu.X86.HasAVX2
+)
+
+//go:noescape
+func addVecAVX512(res, a, b *Element, n uint64)
+
+//go:noescape
+func addVecAVX2(res, a, b *Element, n uint64)
+
+//go:noescape
+func subVecAVX512(res, a, b *Element, n uint64)
+
+//go:noescape
+func subVecAVX2(res, a, b *Element, n uint64)
+
+//go:noescape
+func mulVecAVX512(res, a, b *Element, n uint64)
+
+//go:noescape
+func mulVecAVX2(res, a, b *Element, n uint64)
+
+//go:noescape
+func scalarMulVecAVX512(res, a, b *Element, n uint64)
+
+//go:noescape
+func scalarMulVecAVX2(res, a, b *Element, n uint64)
+
+// Add adds two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Add(a, b Vector) {
+	if len(a) != len(b) || len(a) != len(*vector) {
+		panic("vector.Add: vectors don't have the same length")
+	}
+	k := 0
+	if supportAvx512 {
+		// process blocks of 16 elements
+		if k = len(a) &^ 15; k > 0 {
+			addVecAVX512(&(*vector)[0], &a[0], &b[0], uint64(k/16))
+		}
+	} else if supportAvx2 {
+		// process blocks of 8 elements
+		if k = len(a) &^ 7; k > 0 {
+			addVecAVX2(&(*vector)[0], &a[0], &b[0], uint64(k/8))
+		}
+	}
+	addVecGeneric((*vector)[k:], a[k:], b[k:])
+}
+
+// Sub subtracts two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Sub(a, b Vector) {
+	if len(a) != len(b) || len(a) != len(*vector) {
+		panic("vector.Sub: vectors don't have the same length")
+	}
+	k := 0
+	if supportAvx512 {
+		// process blocks of 16 elements
+		if k = len(a) &^ 15; k > 0 {
+			subVecAVX512(&(*vector)[0], &a[0], &b[0], uint64(k/16))
+		}
+	} else if supportAvx2 {
+		// process blocks of 8 elements
+		if k = len(a) &^ 7; k > 0 {
+			subVecAVX2(&(*vector)[0], &a[0], &b[0], uint64(k/8))
+		}
+	}
+	subVecGeneric((*vector)[k:], a[k:], b[k:])
+}
+
+// Mul multiplies two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Mul(a, b Vector) {
+	if len(a) != len(b) || len(a) != len(*vector) {
+		panic("vector.Mul: vectors don't have the same length")
+	}
+	k := 0
+	if supportAvx512 {
+		// process blocks of 16 elements
+		if k = len(a) &^ 15; k > 0 {
+			mulVecAVX512(&(*vector)[0], &a[0], &b[0], uint64(k/16))
+		}
+	} else if supportAvx2 {
+		// process blocks of 8 elements
+		if k = len(a) &^ 7; k > 0 {
+			mulVecAVX2(&(*vector)[0], &a[0], &b[0], uint64(k/8))
+		}
+	}
+	mulVecGeneric((*vector)[k:], a[k:], b[k:])
+}
+
+// ScalarMul multiplies a vector by a scalar element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) ScalarMul(a Vector, b *Element) {
+	if len(a) != len(*vector) {
+		panic("vector.ScalarMul: vectors don't have the same length")
+	}
+	k := 0
+	if supportAvx512 {
+		// process blocks of 16 elements
+		if k = len(a) &^ 15; k > 0 {
+			scalarMulVecAVX512(&(*vector)[0], &a[0], b, uint64(k/16))
+		}
+	} else if supportAvx2 {
+		// process blocks of 8 elements
+		if k = len(a) &^ 7; k > 0 {
+			scalarMulVecAVX2(&(*vector)[0], &a[0], b, uint64(k/8))
+		}
+	}
+	scalarMulVecGeneric((*vector)[k:], a[k:], b)
+}
@@ -1,4 +1,9 @@
-// Package hash provides MiMC hash function defined over implemented curves
+// Package hash provides MiMC, Poseidon and Poseidon2 hash functions defined over implemented curves
+//
+// The Poseidon and Poseidon2 hashers are sponges over the corresponding permutations,
+// see the fr/poseidon and fr/poseidon2 packages of each curve. They are not
+// vulnerable to the length extension attack described below, and share the
+// input format of MiMC.
 //
 // # Length extension attack
 //
@@ -18,12 +18,26 @@ import (
 	"hash"
 
 	bls377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/mimc"
+	poseidonbls377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/poseidon"
+	poseidon2bls377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/poseidon2"
 	bls381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/mimc"
+	poseidonbls381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/poseidon"
+	poseidon2bls381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/poseidon2"
 	bls315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/mimc"
+	poseidonbls315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/poseidon"
+	poseidon2bls315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/poseidon2"
 	bls317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr/mimc"
+	poseidonbls317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr/poseidon"
+	poseidon2bls317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr/poseidon2"
 	bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	poseidonbn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon"
+	poseidon2bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon2"
 	bw633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/mimc"
+	poseidonbw633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/poseidon"
+	poseidon2bw633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/poseidon2"
 	bw761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/mimc"
+	poseidonbw761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/poseidon"
+	poseidon2bw761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/poseidon2"
 )
 
 // Hash defines an unique identifier for a hash function.
@@ -44,17 +58,59 @@ const (
 	MIMC_BLS24_317
 	// MIMC_BW6_633 is the MiMC hash function for the BW6-633 curve.
 	MIMC_BW6_633
+	// POSEIDON_BN254 is the Poseidon hash function for the BN254 curve.
+	POSEIDON_BN254
+	// POSEIDON_BLS12_381 is the Poseidon hash function for the BLS12-381 curve.
+	POSEIDON_BLS12_381
+	// POSEIDON_BLS12_377 is the Poseidon hash function for the BLS12-377 curve.
+	POSEIDON_BLS12_377
+	// POSEIDON_BW6_761 is the Poseidon hash function for the BW6-761 curve.
+	POSEIDON_BW6_761
+	// POSEIDON_BLS24_315 is the Poseidon hash function for the BLS24-315 curve.
+	POSEIDON_BLS24_315
+	// POSEIDON_BLS24_317 is the Poseidon hash function for the BLS24-317 curve.
+	POSEIDON_BLS24_317
+	// POSEIDON_BW6_633 is the Poseidon hash function for the BW6-633 curve.
+	POSEIDON_BW6_633
+	// POSEIDON2_BN254 is the Poseidon2 hash function for the BN254 curve.
+	POSEIDON2_BN254
+	// POSEIDON2_BLS12_381 is the Poseidon2 hash function for the BLS12-381 curve.
+	POSEIDON2_BLS12_381
+	// POSEIDON2_BLS12_377 is the Poseidon2 hash function for the BLS12-377 curve.
+	POSEIDON2_BLS12_377
+	// POSEIDON2_BW6_761 is the Poseidon2 hash function for the BW6-761 curve.
+	POSEIDON2_BW6_761
+	// POSEIDON2_BLS24_315 is the Poseidon2 hash function for the BLS24-315 curve.
+	POSEIDON2_BLS24_315
+	// POSEIDON2_BLS24_317 is the Poseidon2 hash function for the BLS24-317 curve.
+	POSEIDON2_BLS24_317
+	// POSEIDON2_BW6_633 is the Poseidon2 hash function for the BW6-633 curve.
+	POSEIDON2_BW6_633
 )
 
 // size of digests in bytes
 var digestSize = []uint8{
-	MIMC_BN254:     32,
-	MIMC_BLS12_381: 48,
-	MIMC_BLS12_377: 48,
-	MIMC_BW6_761:   96,
-	MIMC_BLS24_315: 48,
-	MIMC_BLS24_317: 48,
-	MIMC_BW6_633:   80,
+	MIMC_BN254:          32,
+	MIMC_BLS12_381:      48,
+	MIMC_BLS12_377:      48,
+	MIMC_BW6_761:        96,
+	MIMC_BLS24_315:      48,
+	MIMC_BLS24_317:      48,
+	MIMC_BW6_633:        80,
+	POSEIDON_BN254:      32,
+	POSEIDON_BLS12_381:  32,
+	POSEIDON_BLS12_377:  32,
+	POSEIDON_BW6_761:    48,
+	POSEIDON_BLS24_315:  32,
+	POSEIDON_BLS24_317:  32,
+	POSEIDON_BW6_633:    40,
+	POSEIDON2_BN254:     32,
+	POSEIDON2_BLS12_381: 32,
+	POSEIDON2_BLS12_377: 32,
+	POSEIDON2_BW6_761:   48,
+	POSEIDON2_BLS24_315: 32,
+	POSEIDON2_BLS24_317: 32,
+	POSEIDON2_BW6_633:   40,
 }
 
 // New initializes the hash function.
@@ -74,8 +130,36 @@ func (m Hash) New() hash.Hash {
 		return bls317.NewMiMC()
 	case MIMC_BW6_633:
 		return bw633.NewMiMC()
+	case POSEIDON_BN254:
+		return poseidonbn254.NewPoseidon()
+	case POSEIDON_BLS12_381:
+		return poseidonbls381.NewPoseidon()
+	case POSEIDON_BLS12_377:
+		return poseidonbls377.NewPoseidon()
+	case POSEIDON_BW6_761:
+		return poseidonbw761.NewPoseidon()
+	case POSEIDON_BLS24_315:
+		return poseidonbls315.NewPoseidon()
+	case POSEIDON_BLS24_317:
+		return poseidonbls317.NewPoseidon()
+	case POSEIDON_BW6_633:
+		return poseidonbw633.NewPoseidon()
+	case POSEIDON2_BN254:
+		return poseidon2bn254.NewPoseidon2()
+	case POSEIDON2_BLS12_381:
+		return poseidon2bls381.NewPoseidon2()
+	case POSEIDON2_BLS12_377:
+		return poseidon2bls377.NewPoseidon2()
+	case POSEIDON2_BW6_761:
+		return poseidon2bw761.NewPoseidon2()
+	case POSEIDON2_BLS24_315:
+		return poseidon2bls315.NewPoseidon2()
+	case POSEIDON2_BLS24_317:
+		return poseidon2bls317.NewPoseidon2()
+	case POSEIDON2_BW6_633:
+		return poseidon2bw633.NewPoseidon2()
 	default:
-		panic("Unknown mimc ID")
+		panic("Unknown hash ID")
 	}
 }
 
@@ -96,8 +180,36 @@ func (m Hash) String() string {
 		return "MIMC_BLS317"
 	case MIMC_BW6_633:
 		return "MIMC_BW633"
+	case POSEIDON_BN254:
+		return "POSEIDON_BN254"
+	case POSEIDON_BLS12_381:
+		return "POSEIDON_BLS381"
+	case POSEIDON_BLS12_377:
+		return "POSEIDON_BLS377"
+	case POSEIDON_BW6_761:
+		return "POSEIDON_BW761"
+	case POSEIDON_BLS24_315:
+		return "POSEIDON_BLS315"
+	case POSEIDON_BLS24_317:
+		return "POSEIDON_BLS317"
+	case POSEIDON_BW6_633:
+		return "POSEIDON_BW633"
+	case POSEIDON2_BN254:
+		return "POSEIDON2_BN254"
+	case POSEIDON2_BLS12_381:
+		return "POSEIDON2_BLS381"
+	case POSEIDON2_BLS12_377:
+		return "POSEIDON2_BLS377"
+	case POSEIDON2_BW6_761:
+		return "POSEIDON2_BW761"
+	case POSEIDON2_BLS24_315:
+		return "POSEIDON2_BLS315"
+	case POSEIDON2_BLS24_317:
+		return "POSEIDON2_BLS317"
+	case POSEIDON2_BW6_633:
+		return "POSEIDON2_BW633"
 	default:
-		panic("Unknown mimc ID")
+		panic("Unknown hash ID")
 	}
 }
 
@@ -0,0 +1,33 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoseidonHashes(t *testing.T) {
+	assert := require.New(t)
+
+	for h := POSEIDON_BN254; h <= POSEIDON2_BW6_633; h++ {
+		hasher := h.New()
+		assert.Equal(h.Size(), hasher.Size(), h.String())
+		_, err := hasher.Write([]byte{1})
+		assert.NoError(err)
+		assert.Len(hasher.Sum(nil), h.Size(), h.String())
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"math/big"
+	"strconv"
 
 	"github.com/consensys/gnark-crypto/field/generator/config"
 )
@@ -15,6 +16,11 @@ type Curve struct {
 	FpModulus    string
 	FrModulus    string
 
+	// FpPackagePath and FrPackagePath, if set, are the import paths of the field
+	// packages of another curve, used instead of generating fp and fr
+	FpPackagePath string
+	FrPackagePath string
+
 	Fp           *config.FieldConfig
 	Fr           *config.FieldConfig
 	FpUnusedBits int
@@ -50,6 +56,27 @@ func (c Curve) Equal(other Curve) bool {
 	return c.Name == other.Name
 }
 
+// HasG2 returns true if the curve has a G2 group, i.e. is pairing-friendly
+func (c Curve) HasG2() bool {
+	return c.G2.PointName != ""
+}
+
+// FpImport returns the import spec of the base field package
+func (c Curve) FpImport() string {
+	if c.FpPackagePath == "" {
+		return strconv.Quote("github.com/consensys/gnark-crypto/ecc/" + c.Name + "/fp")
+	}
+	return "fp " + strconv.Quote(c.FpPackagePath)
+}
+
+// FrImport returns the import spec of the scalar field package
+func (c Curve) FrImport() string {
+	if c.FrPackagePath == "" {
+		return strconv.Quote("github.com/consensys/gnark-crypto/ecc/" + c.Name + "/fr")
+	}
+	return "fr " + strconv.Quote(c.FrPackagePath)
+}
+
 type Point struct {
 	CoordType        string
 	CoordExtDegree   uint8 // value n, such that q = pⁿ
@@ -63,6 +90,23 @@ type Point struct {
 	B                []string //B constant term in Weierstrass form
 }
 
+// HasA returns true if the linear coefficient of the Weierstrass equation is
+// non-zero, in which case the point arithmetic must account for it
+func (p Point) HasA() bool {
+	return len(p.A) != 0 && p.A[0] != "0"
+}
+
+// MaxC returns the largest window size of the multiExp bucket method
+func (p Point) MaxC() int {
+	res := 0
+	for _, c := range p.CRange {
+		if c > res {
+			res = c
+		}
+	}
+	return res
+}
+
 var Curves []Curve
 var TwistedEdwardsCurves []TwistedEdwardsCurve
 
@@ -193,6 +193,7 @@ type HashSuiteInfo struct {
 	Field             *field.Extension
 	FieldCoordName    string
 	Name              string
+	FpImport          string // import spec of the base field package
 	FieldSizeMod256   uint8
 	PrecomputedParams []field.Element // PrecomputedParams[0][n] correspond to integer cₙ₋₁ in std doc
 	// PrecomputedParams[n≥1] correspond to field element c_( len(PrecomputedParams[0]) + n - 1 ) in std doc
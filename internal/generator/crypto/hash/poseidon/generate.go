@@ -0,0 +1,196 @@
+package poseidon
+
+import (
+	"math"
+	"math/big"
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+const (
+	// security level targeted by the round numbers, in bits
+	securityLevel = 128
+
+	// widths of the Poseidon permutation (circomlib supports 1 to 16 inputs)
+	poseidonMinWidth = 2
+	poseidonMaxWidth = 17
+
+	// widths of the Poseidon2 permutation; larger widths need a
+	// dedicated internal matrix.
+	poseidon2MinWidth = 2
+	poseidon2MaxWidth = 3
+)
+
+// circomlib partial round numbers for bn254, indexed by width - 2.
+// The full round number is 8 for all widths.
+var circomPartialRounds = []int{56, 57, 56, 60, 60, 63, 64, 63, 60, 66, 60, 65, 70, 60, 64, 68}
+
+type templateData struct {
+	config.Curve
+	// HasherName is the suffix of the hash.Hash constructor, e.g. NewPoseidon2
+	HasherName      string
+	SBoxDegree      int
+	MinWidth        int
+	MaxWidth        int
+	NbFullRounds    []int // indexed by width - MinWidth
+	NbPartialRounds []int // indexed by width - MinWidth
+}
+
+// Generate generates the Poseidon and Poseidon2 packages on fr, in baseDir/poseidon
+// and baseDir/poseidon2.
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	q := conf.FrInfo.Modulus()
+	alpha := sBoxDegree(q)
+
+	poseidon := templateData{
+		Curve:      conf,
+		HasherName: "Poseidon",
+		SBoxDegree: alpha,
+		MinWidth:   poseidonMinWidth,
+		MaxWidth:   poseidonMaxWidth,
+	}
+	poseidon.Package = "poseidon"
+	for t := poseidon.MinWidth; t <= poseidon.MaxWidth; t++ {
+		rf, rp := roundNumbers(q, t, alpha)
+		if conf.Equal(config.BN254) {
+			// circomlib compatibility
+			rf, rp = 8, circomPartialRounds[t-2]
+		}
+		poseidon.NbFullRounds = append(poseidon.NbFullRounds, rf)
+		poseidon.NbPartialRounds = append(poseidon.NbPartialRounds, rp)
+	}
+
+	poseidon2 := templateData{
+		Curve:      conf,
+		HasherName: "Poseidon2",
+		SBoxDegree: alpha,
+		MinWidth:   poseidon2MinWidth,
+		MaxWidth:   poseidon2MaxWidth,
+	}
+	poseidon2.Package = "poseidon2"
+	for t := poseidon2.MinWidth; t <= poseidon2.MaxWidth; t++ {
+		rf, rp := roundNumbers(q, t, alpha)
+		poseidon2.NbFullRounds = append(poseidon2.NbFullRounds, rf)
+		poseidon2.NbPartialRounds = append(poseidon2.NbPartialRounds, rp)
+	}
+
+	const templateDir = "./crypto/hash/poseidon/template"
+
+	dir := filepath.Join(baseDir, "poseidon")
+	entries := []bavard.Entry{
+		{File: filepath.Join(dir, "doc.go"), Templates: []string{"poseidon/doc.go.tmpl"}},
+		{File: filepath.Join(dir, "poseidon.go"), Templates: []string{"poseidon/poseidon.go.tmpl", "sbox.go.tmpl"}},
+		{File: filepath.Join(dir, "grain.go"), Templates: []string{"grain.go.tmpl"}},
+		{File: filepath.Join(dir, "hash.go"), Templates: []string{"hash.go.tmpl"}},
+		{File: filepath.Join(dir, "options.go"), Templates: []string{"options.go.tmpl"}},
+		{File: filepath.Join(dir, "poseidon_test.go"), Templates: []string{"poseidon/poseidon_test.go.tmpl", "tests.go.tmpl"}},
+	}
+	if err := bgen.Generate(poseidon, poseidon.Package, templateDir, entries...); err != nil {
+		return err
+	}
+
+	dir = filepath.Join(baseDir, "poseidon2")
+	entries = []bavard.Entry{
+		{File: filepath.Join(dir, "doc.go"), Templates: []string{"poseidon2/doc.go.tmpl"}},
+		{File: filepath.Join(dir, "poseidon2.go"), Templates: []string{"poseidon2/poseidon2.go.tmpl", "sbox.go.tmpl"}},
+		{File: filepath.Join(dir, "grain.go"), Templates: []string{"grain.go.tmpl"}},
+		{File: filepath.Join(dir, "hash.go"), Templates: []string{"hash.go.tmpl"}},
+		{File: filepath.Join(dir, "options.go"), Templates: []string{"options.go.tmpl"}},
+		{File: filepath.Join(dir, "poseidon2_test.go"), Templates: []string{"poseidon2/poseidon2_test.go.tmpl", "tests.go.tmpl"}},
+	}
+	return bgen.Generate(poseidon2, poseidon2.Package, templateDir, entries...)
+}
+
+// sBoxDegree returns the smallest α ≥ 3 such that x ↦ x^α is a permutation of 𝔽q,
+// that is gcd(α, q-1) = 1.
+func sBoxDegree(q *big.Int) int {
+	qMinusOne := new(big.Int).Sub(q, big.NewInt(1))
+	var gcd big.Int
+	for alpha := int64(3); ; alpha++ {
+		if gcd.GCD(nil, nil, big.NewInt(alpha), qMinusOne).IsInt64() && gcd.Int64() == 1 {
+			return int(alpha)
+		}
+	}
+}
+
+// roundNumbers returns the number of full and partial rounds of a width t
+// permutation with S-box x^α over 𝔽q, following the reference script
+// calc_round_numbers.py of the Poseidon authors (including the attack of
+// https://eprint.iacr.org/2023/537), with the recommended security margin
+// (+2 full rounds, +7.5% partial rounds). Among the secure choices, the one
+// with the smallest number of S-boxes is returned.
+func roundNumbers(q *big.Int, t, alpha int) (nbFullRounds, nbPartialRounds int) {
+	n := q.BitLen()
+	logQ := log2(q)
+
+	minCost := math.MaxInt
+	for rp := 1; rp < 500; rp++ {
+		for rf := 4; rf < 100; rf += 2 {
+			if !isSecure(logQ, n, t, rf, rp, alpha) {
+				continue
+			}
+			// the cost grows with rf, no need to look further for this rp
+			rfMargin := rf + 2
+			rpMargin := int(math.Ceil(float64(rp) * 1.075))
+			cost := rfMargin*t + rpMargin
+			if cost < minCost || (cost == minCost && rfMargin < nbFullRounds) {
+				nbFullRounds, nbPartialRounds = rfMargin, rpMargin
+				minCost = cost
+			}
+			break
+		}
+	}
+	return
+}
+
+func isSecure(logQ float64, n, t, rf, rp, alpha int) bool {
+	M := float64(securityLevel)
+	T := float64(t)
+	RF := float64(rf)
+	RP := float64(rp)
+	a := float64(alpha)
+	logA := func(x float64) float64 { return math.Log(x) / math.Log(a) }
+
+	// statistical attacks
+	rf1 := 10.0
+	if M <= math.Floor(logQ-(a-1)/2)*(T+1) {
+		rf1 = 6
+	}
+	// interpolation
+	rf2 := 1 + math.Ceil(logA(2)*math.Min(M, float64(n))) + math.Ceil(logA(T)) - RP
+	// Gröbner basis attacks
+	rf3 := logA(2)*math.Min(M, logQ) - RP
+	rf4 := T - 1 + logA(2)*math.Min(M/(T+1), logQ/2) - RP
+	rf5 := (T - 2 + M/(2*math.Log2(a)) - RP) / (T - 1)
+
+	rfMax := math.Max(math.Max(math.Ceil(rf1), math.Ceil(rf2)), math.Max(math.Max(math.Ceil(rf3), math.Ceil(rf4)), math.Ceil(rf5)))
+	if RF < rfMax {
+		return false
+	}
+
+	// https://eprint.iacr.org/2023/537
+	rTemp := math.Floor(T / 3)
+	over := (RF-1)*T + RP + rTemp + rTemp*(RF/2) + RP + a
+	under := rTemp*(RF/2) + RP + a
+	binomLog := log2Binomial(int64(over), int64(under))
+	return math.Ceil(2*binomLog) >= M
+}
+
+// log2 returns log₂(q) as a float.
+func log2(q *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(q).Float64()
+	return math.Log2(f)
+}
+
+// log2Binomial returns log₂(n choose k)
+func log2Binomial(n, k int64) float64 {
+	var b big.Int
+	b.Binomial(n, k)
+	f, _ := new(big.Float).SetInt(&b).Float64()
+	if math.IsInf(f, 0) {
+		return securityLevel + 1
+	}
+	return math.Log2(f)
+}
@@ -2,6 +2,7 @@ package ecc
 
 import (
 	"fmt"
+	"math/big"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -30,9 +31,13 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 			{File: filepath.Join(baseDir, fmt.Sprintf("hash_to_%s_test.go", point.PointName)), Templates: []string{"tests/hash_to_curve.go.tmpl"}}}
 
 		hashConf := suite.GetInfo(conf.Fp, point, conf.Name)
+		hashConf.FpImport = conf.FpImport()
 
 		funcs := make(template.FuncMap)
 		funcs["asElement"] = hashConf.Field.Base.WriteElement
+		funcs["abs"] = func(z big.Int) big.Int {
+			return *new(big.Int).Abs(&z)
+		}
 		bavardOpts := []func(*bavard.Bavard) error{bavard.Funcs(funcs)}
 
 		return bgen.GenerateWithOptions(hashConf, packageName, "./ecc/template", bavardOpts, entries...)
@@ -173,7 +178,7 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		return err
 	}
 
-	// No G2 for secp256k1
+	// secp256k1 has a hand-written marshal.go
 	if conf.Equal(config.SECP256K1) {
 		return nil
 	}
@@ -189,6 +194,11 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		return err
 	}
 
+	// No G2 for curves without pairing
+	if !conf.HasG2() {
+		return nil
+	}
+
 	// G2
 	entries = []bavard.Entry{
 		{File: filepath.Join(baseDir, "g2.go"), Templates: []string{"point.go.tmpl"}},
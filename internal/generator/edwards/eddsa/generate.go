@@ -16,6 +16,7 @@ func Generate(conf config.TwistedEdwardsCurve, baseDir string, bgen *bavard.Batc
 		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
 		{File: filepath.Join(baseDir, "eddsa.go"), Templates: []string{"eddsa.go.tmpl"}},
 		{File: filepath.Join(baseDir, "eddsa_test.go"), Templates: []string{"eddsa.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "batch.go"), Templates: []string{"batch.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
 	}
 	return bgen.Generate(conf, conf.Package, "./edwards/eddsa/template", entries...)
@@ -17,6 +17,23 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		{File: filepath.Join(baseDir, "kzg_test.go"), Templates: []string{"kzg.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
 		{File: filepath.Join(baseDir, "utils.go"), Templates: []string{"utils.go.tmpl"}},
+		{File: filepath.Join(baseDir, "shplonk.go"), Templates: []string{"shplonk.go.tmpl"}},
+		{File: filepath.Join(baseDir, "shplonk_test.go"), Templates: []string{"shplonk.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "fk20.go"), Templates: []string{"fk20.go.tmpl"}},
+		{File: filepath.Join(baseDir, "fk20_test.go"), Templates: []string{"fk20.test.go.tmpl"}},
+	}
+	if err := bgen.Generate(conf, conf.Package, "./kzg/template/", entries...); err != nil {
+		return err
+	}
+
+	// powers-of-tau ceremony producing a kzg SRS
+	conf.Package = "mpc"
+	mpcDir := filepath.Join(baseDir, "mpc")
+	entries = []bavard.Entry{
+		{File: filepath.Join(mpcDir, "doc.go"), Templates: []string{"mpc/doc.go.tmpl"}},
+		{File: filepath.Join(mpcDir, "mpc.go"), Templates: []string{"mpc/mpc.go.tmpl"}},
+		{File: filepath.Join(mpcDir, "mpc_test.go"), Templates: []string{"mpc/mpc.test.go.tmpl"}},
+		{File: filepath.Join(mpcDir, "marshal.go"), Templates: []string{"mpc/marshal.go.tmpl"}},
 	}
 	return bgen.Generate(conf, conf.Package, "./kzg/template/", entries...)
 
@@ -12,6 +12,7 @@ import (
 	field "github.com/consensys/gnark-crypto/field/generator/config"
 	"github.com/consensys/gnark-crypto/internal/generator/config"
 	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/mimc"
+	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/poseidon"
 	"github.com/consensys/gnark-crypto/internal/generator/ecc"
 	"github.com/consensys/gnark-crypto/internal/generator/ecdsa"
 	"github.com/consensys/gnark-crypto/internal/generator/edwards"
@@ -22,6 +23,7 @@ import (
 	"github.com/consensys/gnark-crypto/internal/generator/hash_to_field"
 	"github.com/consensys/gnark-crypto/internal/generator/iop"
 	"github.com/consensys/gnark-crypto/internal/generator/kzg"
+	"github.com/consensys/gnark-crypto/internal/generator/merkle"
 	"github.com/consensys/gnark-crypto/internal/generator/pairing"
 	"github.com/consensys/gnark-crypto/internal/generator/pedersen"
 	"github.com/consensys/gnark-crypto/internal/generator/permutation"
@@ -61,10 +63,14 @@ func main() {
 			conf.Fr, err = field.NewFieldConfig("fr", "Element", conf.FrModulus, !conf.Equal(config.STARK_CURVE))
 			assertNoError(err)
 
-			conf.FpUnusedBits = 64 - (conf.Fp.NbBits % 64)
+			conf.FpUnusedBits = (64 - (conf.Fp.NbBits % 64)) % 64
 
-			assertNoError(generator.GenerateFF(conf.Fr, filepath.Join(curveDir, "fr")))
-			assertNoError(generator.GenerateFF(conf.Fp, filepath.Join(curveDir, "fp")))
+			if conf.FrPackagePath == "" {
+				assertNoError(generator.GenerateFF(conf.Fr, filepath.Join(curveDir, "fr")))
+			}
+			if conf.FpPackagePath == "" {
+				assertNoError(generator.GenerateFF(conf.Fp, filepath.Join(curveDir, "fp")))
+			}
 
 			// generate ecdsa
 			assertNoError(ecdsa.Generate(conf, curveDir, bgen))
@@ -76,7 +82,7 @@ func main() {
 			// generate G1, G2, multiExp, ...
 			assertNoError(ecc.Generate(conf, curveDir, bgen))
 
-			if conf.Equal(config.SECP256K1) {
+			if !conf.HasG2() {
 				return
 			}
 
@@ -111,6 +117,12 @@ func main() {
 			// generate mimc on fr
 			assertNoError(mimc.Generate(conf, filepath.Join(curveDir, "fr", "mimc"), bgen))
 
+			// generate poseidon and poseidon2 on fr
+			assertNoError(poseidon.Generate(conf, filepath.Join(curveDir, "fr"), bgen))
+
+			// generate merkle trees on fr
+			assertNoError(merkle.Generate(conf, filepath.Join(curveDir, "fr", "merkle"), bgen))
+
 			frInfo := config.FieldDependency{
 				FieldPackagePath: "github.com/consensys/gnark-crypto/ecc/" + conf.Name + "/fr",
 				FieldPackageName: "fr",
@@ -168,6 +180,13 @@ func main() {
 
 			// generate eddsa on companion curves
 			assertNoError(eddsa.Generate(conf, curveDir, bgen))
+
+			if conf.Package == "bandersnatch" {
+				// generate the scalar field, used by the ipa package
+				fr, err := field.NewFieldConfig("fr", "Element", conf.Order, true)
+				assertNoError(err)
+				assertNoError(generator.GenerateFF(fr, filepath.Join(curveDir, "fr")))
+			}
 		}(conf)
 
 	}
@@ -177,6 +196,36 @@ func main() {
 		defer wg.Done()
 		assertNoError(test_vector_utils.GenerateRationals(bgen))
 	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// generate the base field (2²⁵⁵-19) and the scalar field of ed25519
+		curveDir := filepath.Join(baseDir, "ecc", "ed25519")
+		fp, err := field.NewFieldConfig("fp", "Element", "57896044618658097711785492504343953926634992332820282019728792003956564819949", true)
+		assertNoError(err)
+		assertNoError(generator.GenerateFF(fp, filepath.Join(curveDir, "fp")))
+		fr, err := field.NewFieldConfig("fr", "Element", "7237005577332262213973186563042994240857116359379907606001950938285454250989", true)
+		assertNoError(err)
+		assertNoError(generator.GenerateFF(fr, filepath.Join(curveDir, "fr")))
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// generate fft and polynomial on goldilocks
+		fieldDir := filepath.Join(baseDir, "field", "goldilocks")
+		assertNoError(fft.Generate(config.Curve{Name: "goldilocks"}, filepath.Join(fieldDir, "fft"), bgen))
+
+		goldilocksInfo := config.FieldDependency{
+			FieldPackagePath: "github.com/consensys/gnark-crypto/field/goldilocks",
+			FieldPackageName: "goldilocks",
+			ElementType:      "goldilocks.Element",
+		}
+		assertNoError(polynomial.Generate(goldilocksInfo, filepath.Join(fieldDir, "polynomial"), true, bgen))
+	}()
 	wg.Wait()
 
 	// format the whole directory
@@ -0,0 +1,24 @@
+package merkle
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+
+	conf.Package = "merkle"
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "merkle.go"), Templates: []string{"merkle.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hasher.go"), Templates: []string{"hasher.go.tmpl"}},
+		{File: filepath.Join(baseDir, "options.go"), Templates: []string{"options.go.tmpl"}},
+		{File: filepath.Join(baseDir, "proof.go"), Templates: []string{"proof.go.tmpl"}},
+		{File: filepath.Join(baseDir, "merkle_test.go"), Templates: []string{"merkle.test.go.tmpl"}},
+	}
+
+	return bgen.Generate(conf, conf.Package, "./merkle/template/", entries...)
+
+}